@@ -5,6 +5,7 @@
 package amg88xx // import "tinygo.org/x/drivers/amg88xx"
 
 import (
+	"encoding/binary"
 	"time"
 
 	"tinygo.org/x/drivers"
@@ -17,6 +18,8 @@ type Device struct {
 	data            []uint8
 	interruptMode   InterruptMode
 	interruptEnable uint8
+	frameRate       uint8
+	lastFrame       time.Time
 }
 
 type InterruptMode uint8
@@ -46,17 +49,63 @@ func (d *Device) Configure(cfg Config) {
 	time.Sleep(100 * time.Millisecond)
 }
 
-// ReadPixels returns the 64 values (8x8 grid) of the sensor converted to  millicelsius
+// ReadPixels returns the 64 values (8x8 grid) of the sensor converted to
+// millicelsius. Values above 32.767°C saturate, use ReadFrame for the full
+// range of the sensor.
 func (d *Device) ReadPixels(buffer *[64]int16) {
-	d.bus.ReadRegister(uint8(d.Address), PIXEL_OFFSET, d.data)
-	for i := 0; i < 64; i++ {
-		buffer[i] = int16((uint16(d.data[2*i+1]) << 8) | uint16(d.data[2*i]))
-		if (buffer[i] & (1 << 11)) > 0 { // temperature negative
-			buffer[i] &= ^(1 << 11)
-			buffer[i] = -buffer[i]
+	var frame [64]int32
+	d.ReadFrame(&frame)
+	for i, v := range frame {
+		if v > 32767 {
+			v = 32767
 		}
-		buffer[i] *= PIXEL_TEMP_CONVERSION
+		buffer[i] = int16(v)
+	}
+}
+
+// ReadFrame reads the 64 values (8x8 grid) of the sensor in a single burst
+// and converts them to millicelsius.
+func (d *Device) ReadFrame(frame *[64]int32) {
+	d.bus.ReadRegister(uint8(d.Address), PIXEL_OFFSET, d.data)
+	for i := range frame {
+		frame[i] = pixelToMilliCelsius(uint16(d.data[2*i+1])<<8 | uint16(d.data[2*i]))
+	}
+	d.lastFrame = time.Now()
+}
+
+// NextFrame reads a frame like ReadFrame, but only once the sensor has
+// produced a new one since the previous read at the configured frame rate.
+// It returns false without touching the bus otherwise, so it can be called
+// from a busy main loop to stream frames at 10 fps.
+func (d *Device) NextFrame(frame *[64]int32) bool {
+	interval := 100 * time.Millisecond
+	if d.frameRate == FPS_1 {
+		interval = time.Second
+	}
+	if !d.lastFrame.IsZero() && time.Since(d.lastFrame) < interval {
+		return false
+	}
+	d.ReadFrame(frame)
+	return true
+}
+
+// pixelToMilliCelsius converts a 12-bit two's complement pixel value with a
+// resolution of 0.25°C to millicelsius.
+func pixelToMilliCelsius(raw uint16) int32 {
+	return int32(int16(raw<<4)>>4) * PIXEL_TEMP_CONVERSION
+}
+
+// milliCelsiusToPixel converts millicelsius to a 12-bit two's complement
+// value as used by the interrupt level registers.
+func milliCelsiusToPixel(value int32) uint16 {
+	value /= PIXEL_TEMP_CONVERSION
+	if value < -2048 {
+		value = -2048
+	}
+	if value > 2047 {
+		value = 2047
 	}
+	return uint16(value) & 0x0FFF
 }
 
 // SetPCTL sets the PCTL
@@ -71,54 +120,42 @@ func (d *Device) SetReset(rst uint8) {
 
 // SetFrameRate configures the frame rate
 func (d *Device) SetFrameRate(framerate uint8) {
+	d.frameRate = framerate & 0x01
 	d.bus.WriteRegister(uint8(d.Address), FPSC, []byte{framerate & 0x01})
 }
 
-// SetMovingAverageMode sets the moving average mode
+// SetMovingAverageMode enables or disables the twice moving average output
+// mode, which halves the noise at the cost of a slower response.
 func (d *Device) SetMovingAverageMode(mode bool) {
 	var value uint8
 	if mode {
 		value = 1
 	}
+	// the AVE register is write protected, the unlock sequence is taken from
+	// the Grid-EYE specification
+	d.bus.WriteRegister(uint8(d.Address), AVE_UNLOCK, []byte{0x50})
+	d.bus.WriteRegister(uint8(d.Address), AVE_UNLOCK, []byte{0x45})
+	d.bus.WriteRegister(uint8(d.Address), AVE_UNLOCK, []byte{0x57})
 	d.bus.WriteRegister(uint8(d.Address), AVE, []byte{value << 5})
+	d.bus.WriteRegister(uint8(d.Address), AVE_UNLOCK, []byte{0x00})
 }
 
-// SetInterruptLevels sets the interrupt levels
-func (d *Device) SetInterruptLevels(high int16, low int16) {
+// SetInterruptLevels sets the interrupt levels in millicelsius, with a
+// hysteresis of 95% of the high level.
+func (d *Device) SetInterruptLevels(high int32, low int32) {
 	d.SetInterruptLevelsHysteresis(high, low, (high*95)/100)
 }
 
-// SetInterruptLevelsHysteresis sets the interrupt levels with hysteresis
-func (d *Device) SetInterruptLevelsHysteresis(high int16, low int16, hysteresis int16) {
-	high = high / PIXEL_TEMP_CONVERSION
-	if high < -4095 {
-		high = -4095
+// SetInterruptLevelsHysteresis sets the interrupt levels and hysteresis in
+// millicelsius. In DIFFERENCE mode they are relative to the previous frame.
+func (d *Device) SetInterruptLevelsHysteresis(high int32, low int32, hysteresis int32) {
+	var data [6]uint8
+	for i, v := range [3]int32{high, low, hysteresis} {
+		raw := milliCelsiusToPixel(v)
+		data[2*i] = uint8(raw)
+		data[2*i+1] = uint8(raw >> 8)
 	}
-	if high > 4095 {
-		high = 4095
-	}
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8(high & 0xFF)})
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8((high & 0xFF) >> 4)})
-
-	low = low / PIXEL_TEMP_CONVERSION
-	if low < -4095 {
-		low = -4095
-	}
-	if low > 4095 {
-		low = 4095
-	}
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8(low & 0xFF)})
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8((low & 0xFF) >> 4)})
-
-	hysteresis = hysteresis / PIXEL_TEMP_CONVERSION
-	if hysteresis < -4095 {
-		hysteresis = -4095
-	}
-	if hysteresis > 4095 {
-		hysteresis = 4095
-	}
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8(hysteresis & 0xFF)})
-	d.bus.WriteRegister(uint8(d.Address), INTHL, []byte{uint8((hysteresis & 0xFF) >> 4)})
+	d.bus.WriteRegister(uint8(d.Address), INTHL, data[:])
 }
 
 // EnableInterrupt enables the interrupt pin on the device
@@ -146,14 +183,41 @@ func (d *Device) GetInterrupt() []uint8 {
 	return data
 }
 
+// ReadInterruptTable reads which pixels triggered the interrupt, one bit per
+// pixel.
+func (d *Device) ReadInterruptTable() uint64 {
+	var data [8]uint8
+	d.bus.ReadRegister(uint8(d.Address), INT_OFFSET, data[:])
+	return binary.LittleEndian.Uint64(data[:])
+}
+
+// ReadStatus reads the status register, see the STATUS_ constants.
+func (d *Device) ReadStatus() uint8 {
+	data := []uint8{0}
+	d.bus.ReadRegister(uint8(d.Address), STAT, data)
+	return data[0]
+}
+
+// ClearStatus clears the given status flags.
+func (d *Device) ClearStatus(flags uint8) {
+	d.bus.WriteRegister(uint8(d.Address), SCLR, []byte{flags})
+}
+
 // ClearInterrupt clears any triggered interrupts
 func (d *Device) ClearInterrupt() {
 	d.SetReset(FLAG_RESET)
 }
 
-// ReadThermistor reads the onboard thermistor
-func (d *Device) ReadThermistor() int16 {
+// ReadThermistor reads the onboard thermistor in millicelsius, from -128°C
+// to 128°C.
+func (d *Device) ReadThermistor() int32 {
 	data := make([]uint8, 2)
 	d.bus.ReadRegister(uint8(d.Address), TTHL, data)
-	return (int16((uint16(data[1])<<8)|uint16(data[0])) * THERMISTOR_CONVERSION) / 10
+	// 12-bit sign and magnitude with a resolution of 0.0625°C
+	raw := (uint16(data[1])<<8 | uint16(data[0])) & 0x07FF
+	value := int32(raw) * THERMISTOR_CONVERSION / 10
+	if data[1]&0x08 != 0 {
+		value = -value
+	}
+	return value
 }
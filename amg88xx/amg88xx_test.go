@@ -0,0 +1,50 @@
+package amg88xx
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestPixelConversion(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(pixelToMilliCelsius(0x0000), qt.Equals, int32(0))
+	c.Assert(pixelToMilliCelsius(0x0064), qt.Equals, int32(25000))
+	c.Assert(pixelToMilliCelsius(0x01F4), qt.Equals, int32(125000))
+	c.Assert(pixelToMilliCelsius(0x0FFF), qt.Equals, int32(-250))
+	c.Assert(pixelToMilliCelsius(0x0F9C), qt.Equals, int32(-25000))
+
+	c.Assert(milliCelsiusToPixel(25000), qt.Equals, uint16(0x0064))
+	c.Assert(milliCelsiusToPixel(-250), qt.Equals, uint16(0x0FFF))
+	c.Assert(milliCelsiusToPixel(1000000), qt.Equals, uint16(0x07FF))
+}
+
+func TestSetInterruptLevels(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressHigh)
+	dev := New(bus)
+
+	dev.SetInterruptLevelsHysteresis(30000, -10000, 1000)
+	c.Assert(fake.Registers[INTHL:IHYSH+1], qt.DeepEquals, []uint8{0x78, 0x00, 0xD8, 0x0F, 0x04, 0x00})
+}
+
+func TestReadThermistor(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(AddressHigh)
+	dev := New(bus)
+
+	fake.Registers[TTHL] = 0x90
+	fake.Registers[TTHH] = 0x01
+	c.Assert(dev.ReadThermistor(), qt.Equals, int32(25000))
+
+	fake.Registers[TTHH] = 0x09
+	c.Assert(dev.ReadThermistor(), qt.Equals, int32(-25000))
+
+	// above 32.767°C
+	fake.Registers[TTHL] = 0x80
+	fake.Registers[TTHH] = 0x02
+	c.Assert(dev.ReadThermistor(), qt.Equals, int32(40000))
+}
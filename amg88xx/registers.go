@@ -21,6 +21,7 @@ const (
 	TTHL         = 0x0E
 	TTHH         = 0x0F
 	INT_OFFSET   = 0x010
+	AVE_UNLOCK   = 0x1F
 	PIXEL_OFFSET = 0x80
 
 	// power modes
@@ -37,6 +38,11 @@ const (
 	FPS_10 = 0x00
 	FPS_1  = 0x01
 
+	// status flags
+	STATUS_INTF    = 0x02 // interrupt outbreak
+	STATUS_OVF_IRS = 0x04 // temperature output overflow
+	STATUS_OVF_THS = 0x08 // thermistor output overflow
+
 	// interrupt modes
 	DIFFERENCE     InterruptMode = 0x00
 	ABSOLUTE_VALUE InterruptMode = 0x01
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/events"
 )
 
 // Device wraps an I2C connection to a APDS-9960 device.
@@ -16,6 +17,7 @@ type Device struct {
 	Address uint8
 	mode    uint8
 	gesture gestureData
+	intKind events.Kind
 }
 
 // Configuration for APDS-9960 device.
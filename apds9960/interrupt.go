@@ -0,0 +1,55 @@
+package apds9960
+
+import "tinygo.org/x/drivers/events"
+
+// interrupt enable bits
+const (
+	enablePIEN = 1 << 5
+	gconf4GIEN = 1 << 1
+)
+
+// EnableProximityInterrupt raises the INT pin (active low) when the raw
+// proximity count is below low or above high for persistence consecutive
+// cycles (0 raises it on every cycle, up to 15). Call it after
+// EnableProximity. Use ClearInterrupts to release the pin.
+func (d *Device) EnableProximityInterrupt(low, high, persistence uint8) {
+	d.bus.WriteRegister(d.Address, APDS9960_PILT_REG, []byte{low})
+	d.bus.WriteRegister(d.Address, APDS9960_PIHT_REG, []byte{high})
+	d.updateRegister(APDS9960_PERS_REG, 0xF0, persistence<<4)
+	d.updateRegister(APDS9960_ENABLE_REG, enablePIEN, enablePIEN)
+	d.intKind = events.Threshold
+}
+
+// EnableGestureInterrupt raises the INT pin (active low) when gesture data is
+// available. Call it after EnableGesture. The pin is released once the data
+// has been read by GestureAvailable.
+func (d *Device) EnableGestureInterrupt() {
+	d.updateRegister(APDS9960_GCONF4_REG, gconf4GIEN, gconf4GIEN)
+	d.intKind = events.Gesture
+}
+
+// DisableInterrupts disables the proximity and gesture interrupts.
+func (d *Device) DisableInterrupts() {
+	d.updateRegister(APDS9960_ENABLE_REG, enablePIEN, 0)
+	d.updateRegister(APDS9960_GCONF4_REG, gconf4GIEN, 0)
+	d.intKind = events.KindNone
+}
+
+// ClearInterrupts clears all pending non-gesture interrupts.
+func (d *Device) ClearInterrupts() {
+	d.bus.WriteRegister(d.Address, APDS9960_AICLEAR_REG, nil)
+}
+
+// InterruptEvent returns the event to post when the INT pin falls, for use
+// with events.Dispatcher.Attach. Its kind depends on the enabled interrupt.
+func (d *Device) InterruptEvent(source uint8) events.Event {
+	return events.Event{Kind: d.intKind, Source: source}
+}
+
+// updateRegister changes the bits in mask of a register to value.
+func (d *Device) updateRegister(reg uint8, mask, value uint8) {
+	data := []byte{0}
+	d.bus.ReadRegister(d.Address, reg, data)
+	data[0] = data[0]&^mask | value&mask
+	d.bus.WriteRegister(d.Address, reg, data)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
 )
 
 // SamplingMode is the sampling's resolution of the measurement
@@ -47,7 +48,7 @@ func (d *Device) RawSensorData() uint16 {
 }
 
 // Illuminance returns the adjusted value in mlx (milliLux)
-func (d *Device) Illuminance() int32 {
+func (d *Device) Illuminance() units.MilliLux {
 
 	lux := uint32(d.RawSensorData())
 	var coef uint32
@@ -60,7 +61,26 @@ func (d *Device) Illuminance() int32 {
 	}
 	// 100 * coef * lux * (5/6)
 	// 5/6 = measurement accuracy as per the datasheet
-	return int32(250 * coef * lux / 3)
+	return units.MilliLux(250 * coef * lux / 3)
+}
+
+// Sleep powers the sensor down, or powers it up again in the current mode.
+// It implements drivers.PowerManaged.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		return d.bus.Tx(d.Address, []byte{POWER_DOWN}, nil)
+	}
+	if err := d.bus.Tx(d.Address, []byte{POWER_ON}, nil); err != nil {
+		return err
+	}
+	d.SetMode(d.mode)
+	return nil
+}
+
+// WakeLatency returns the maximum time until the first measurement is
+// available after waking up, in high resolution mode.
+func (d *Device) WakeLatency() time.Duration {
+	return 180 * time.Millisecond
 }
 
 // SetMode changes the reading mode for the sensor
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
 )
 
 // calibrationCoefficients reads at startup and stores the calibration coefficients
@@ -55,6 +56,7 @@ type Device struct {
 	Address                 uint16
 	calibrationCoefficients calibrationCoefficients
 	Config                  Config
+	wakeMode                Mode // mode restored by Sleep(false)
 }
 
 // New creates a new BME280 connection. The I2C bus must already be
@@ -176,26 +178,43 @@ func (d *Device) SetMode(mode Mode) {
 			byte(d.Config.Mode)})
 }
 
+// Sleep puts the device in sleep mode, or returns it to the mode it was in
+// before. It implements drivers.PowerManaged.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		if d.Config.Mode != ModeSleep {
+			d.wakeMode = d.Config.Mode
+		}
+		d.SetMode(ModeSleep)
+	} else if d.Config.Mode == ModeSleep {
+		if d.wakeMode == ModeSleep {
+			d.wakeMode = ModeNormal
+		}
+		d.SetMode(d.wakeMode)
+	}
+	return nil
+}
+
 // ReadTemperature returns the temperature in celsius milli degrees (°C/1000)
-func (d *Device) ReadTemperature() (int32, error) {
+func (d *Device) ReadTemperature() (units.MilliCelsius, error) {
 	data, err := d.readData()
 	if err != nil {
 		return 0, err
 	}
 
 	temp, _ := d.calculateTemp(data)
-	return temp, nil
+	return units.MilliCelsius(temp), nil
 }
 
 // ReadPressure returns the pressure in milli pascals mPa
-func (d *Device) ReadPressure() (int32, error) {
+func (d *Device) ReadPressure() (units.MilliPascal, error) {
 	data, err := d.readData()
 	if err != nil {
 		return 0, err
 	}
 	_, tFine := d.calculateTemp(data)
 	pressure := d.calculatePressure(data, tFine)
-	return pressure, nil
+	return units.MilliPascal(pressure), nil
 }
 
 // ReadHumidity returns the relative humidity in hundredths of a percent
@@ -7,6 +7,14 @@ import (
 	"tinygo.org/x/drivers"
 )
 
+// startupPolicy bounds the wait for commands and power up, which take 30ms or
+// so, so that a missing or wedged device doesn't hang the program.
+var startupPolicy = drivers.RetryPolicy{
+	Backoff:    time.Millisecond,
+	MaxBackoff: 5 * time.Millisecond,
+	Timeout:    200 * time.Millisecond,
+}
+
 // DeviceSPI is the SPI interface to a BMI160 accelerometer/gyroscope. There is
 // also an I2C interface, but it is not yet supported.
 type DeviceSPI struct {
@@ -47,24 +55,24 @@ func (d *DeviceSPI) Configure() error {
 
 	// Power up the accelerometer. 0b0001_00nn is the command format, with 0b01
 	// indicating normal mode.
-	d.runCommand(0b0001_0001)
+	if err := d.runCommand(0b0001_0001); err != nil {
+		return err
+	}
 
 	// Power up the gyroscope. 0b0001_01nn is the command format, with 0b01
 	// indicating normal mode.
-	d.runCommand(0b0001_0101)
+	if err := d.runCommand(0b0001_0101); err != nil {
+		return err
+	}
 
 	// Wait until the device is fully initialized. Even after the command has
 	// finished, the gyroscope may not be fully powered on. Therefore, wait
 	// until we get an expected value.
 	// This takes 30ms or so.
-	for {
+	return startupPolicy.Until(func() (bool, error) {
 		// Wait for the acc_pmu_status and gyr_pmu_status to both be 0b01.
-		if d.readRegister(reg_PMU_STATUS) == 0b0001_0100 {
-			break
-		}
-	}
-
-	return nil
+		return d.readRegister(reg_PMU_STATUS) == 0b0001_0100, nil
+	})
 }
 
 // Connected check whether the device appears to be properly connected. It reads
@@ -76,8 +84,7 @@ func (d *DeviceSPI) Connected() bool {
 // Reset restores the device to the state after power up. This can be useful to
 // easily disable the accelerometer and gyroscope to reduce current consumption.
 func (d *DeviceSPI) Reset() error {
-	d.runCommand(0xB6) // softreset
-	return nil
+	return d.runCommand(0xB6) // softreset
 }
 
 // ReadTemperature returns the temperature in celsius milli degrees (°C/1000).
@@ -182,14 +189,12 @@ func (d *DeviceSPI) ReadRotation() (x int32, y int32, z int32, err error) {
 
 // runCommand runs a BMI160 command through the CMD register. It waits for the
 // command to complete before returning.
-func (d *DeviceSPI) runCommand(command uint8) {
+func (d *DeviceSPI) runCommand(command uint8) error {
 	d.writeRegister(reg_CMD, command)
-	for {
-		response := d.readRegister(reg_CMD)
-		if response == 0 {
-			return // command was completed
-		}
-	}
+	return startupPolicy.Until(func() (bool, error) {
+		// the register is cleared when the command was completed
+		return d.readRegister(reg_CMD) == 0, nil
+	})
 }
 
 // readRegister reads from a single BMI160 register. It should only be used for
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
 )
 
 // OversamplingMode is the oversampling ratio of the temperature or pressure measurement.
@@ -132,7 +133,7 @@ func (d *Device) PrintCali() {
 }
 
 // ReadTemperature returns the temperature in celsius milli degrees (°C/1000).
-func (d *Device) ReadTemperature() (temperature int32, err error) {
+func (d *Device) ReadTemperature() (temperature units.MilliCelsius, err error) {
 	data, err := d.readData(REG_TEMP, 3)
 	if err != nil {
 		return
@@ -150,12 +151,12 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 
 	// Convert from degrees to milli degrees by multiplying by 10.
 	// Will output 30250 milli degrees celsius for 30.25 degrees celsius
-	temperature = 10 * ((tFine*5 + 128) >> 8)
+	temperature = units.MilliCelsius(10 * ((tFine*5 + 128) >> 8))
 	return
 }
 
 // ReadPressure returns the pressure in milli pascals (mPa).
-func (d *Device) ReadPressure() (pressure int32, err error) {
+func (d *Device) ReadPressure() (pressure units.MilliPascal, err error) {
 	// First 3 bytes are Pressure, last 3 bytes are Temperature
 	data, err := d.readData(REG_PRES, 6)
 	if err != nil {
@@ -198,7 +199,7 @@ func (d *Device) ReadPressure() (pressure int32, err error) {
 	var1 = (int32(d.cali.p9) * int32(((p>>3)*(p>>3))>>13)) >> 12
 	var2 = (int32(p>>2) * int32(d.cali.p8)) >> 13
 
-	return 1000 * (int32(p) + ((var1 + var2 + int32(d.cali.p7)) >> 4)), nil
+	return units.MilliPascal(1000 * (int32(p) + ((var1 + var2 + int32(d.cali.p7)) >> 4))), nil
 }
 
 // readData reads n number of bytes of the specified register
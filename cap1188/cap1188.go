@@ -0,0 +1,228 @@
+// Package cap1188 implements a driver for the Microchip CAP1188 8-channel
+// capacitive touch sensor with LED drivers.
+//
+// Datasheet: https://ww1.microchip.com/downloads/en/DeviceDoc/CAP1188%20.pdf
+//
+// A touch sets the input status and asserts the ALERT (interrupt) pin until
+// the INT bit is cleared, which Poll uses to only access the bus when a
+// touch or release happened.
+package cap1188 // import "tinygo.org/x/drivers/cap1188"
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers"
+)
+
+var errNotFound = errors.New("cap1188: device not found")
+
+// Sensitivity is the amplification of the touch measurement, higher is more
+// sensitive.
+type Sensitivity uint8
+
+const (
+	Sensitivity128X Sensitivity = iota
+	Sensitivity64X
+	Sensitivity32X // power-on default
+	Sensitivity16X
+	Sensitivity8X
+	Sensitivity4X
+	Sensitivity2X
+	Sensitivity1X
+)
+
+// TouchHandler is called by Poll when an input is touched or released.
+type TouchHandler func(input uint8, touched bool)
+
+// Config holds the configuration of the CAP1188.
+type Config struct {
+	// Inputs enables the inputs in the bit mask. Zero enables all eight.
+	Inputs uint8
+
+	// Sensitivity of the touch detection. Zero is the most sensitive
+	// setting, Sensitivity128X.
+	Sensitivity Sensitivity
+
+	// MaxTouches blocks all touches when more than this many inputs (1 to
+	// 4) are touched at once, against water or a palm on the sensor. Zero
+	// disables the blocking.
+	MaxTouches uint8
+
+	// LinkLEDs drives the LED outputs from the touch state of the matching
+	// inputs instead of SetLEDs.
+	LinkLEDs bool
+
+	// NoRepeat only signals the first touch of an input that is held,
+	// instead of repeating the interrupt every 175ms.
+	NoRepeat bool
+}
+
+// Device wraps an I2C connection to a CAP1188 device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+
+	handler TouchHandler
+	touched uint8 // input state as of the last Poll
+
+	// hasInt is set when the ALERT pin is connected, in which case Poll
+	// only reads the inputs after intPending was set by the interrupt.
+	hasInt     bool
+	intPending bool
+
+	buf [2]byte
+}
+
+// New creates a new CAP1188 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+	}
+}
+
+// Connected returns whether a CAP1188 has been found.
+func (d *Device) Connected() bool {
+	id, err := d.read(regProductID)
+	if err != nil || id != productID {
+		return false
+	}
+	id, err = d.read(regManufacturerID)
+	return err == nil && id == manufacturerID
+}
+
+// Configure checks the device and sets it up with cfg.
+func (d *Device) Configure(cfg Config) error {
+	if !d.Connected() {
+		return errNotFound
+	}
+	inputs := cfg.Inputs
+	if inputs == 0 {
+		inputs = 0xFF
+	}
+	if err := d.write(regSensorInputEnable, inputs); err != nil {
+		return err
+	}
+	if err := d.write(regInterruptEnable, inputs); err != nil {
+		return err
+	}
+	// keep the default base shift of 0xF
+	if err := d.write(regSensitivity, uint8(cfg.Sensitivity&7)<<4|0x0F); err != nil {
+		return err
+	}
+	var multi uint8
+	if cfg.MaxTouches > 0 {
+		n := cfg.MaxTouches
+		if n > 4 {
+			n = 4
+		}
+		multi = multipleTouchBLK | (n-1)<<2
+	}
+	if err := d.write(regMultipleTouch, multi); err != nil {
+		return err
+	}
+	var link uint8
+	if cfg.LinkLEDs {
+		link = inputs
+	}
+	if err := d.write(regLEDLinking, link); err != nil {
+		return err
+	}
+	var repeat uint8
+	if !cfg.NoRepeat {
+		repeat = inputs
+	}
+	if err := d.write(regRepeatRateEnable, repeat); err != nil {
+		return err
+	}
+	// recalibrate the enabled inputs for the new settings
+	return d.write(regCalibrate, inputs)
+}
+
+// Touched returns the inputs that are touched as a bit mask and clears the
+// interrupt.
+func (d *Device) Touched() (uint8, error) {
+	status, err := d.read(regSensorInputStatus)
+	if err != nil {
+		return 0, err
+	}
+	// The status bits stay set until INT is cleared, even after a release.
+	main, err := d.read(regMainControl)
+	if err != nil {
+		return 0, err
+	}
+	if main&mainControlINT != 0 {
+		if err := d.write(regMainControl, main&^mainControlINT); err != nil {
+			return 0, err
+		}
+	}
+	return status, nil
+}
+
+// SetTouchHandler sets the function called by Poll for touches and releases,
+// nil disables it.
+func (d *Device) SetTouchHandler(h TouchHandler) {
+	d.handler = h
+}
+
+// Poll reads the inputs and invokes the touch handler for each input that was
+// touched or released since the last Poll. With the ALERT pin connected, the
+// inputs are only read after an interrupt. Call it regularly from the main
+// loop, not from an interrupt handler.
+func (d *Device) Poll() error {
+	if d.hasInt && !d.intPending {
+		return nil
+	}
+	d.intPending = false
+	touched, err := d.Touched()
+	if err != nil {
+		return err
+	}
+	changed := touched ^ d.touched
+	d.touched = touched
+	if d.handler == nil {
+		return nil
+	}
+	for input := uint8(0); changed != 0; input++ {
+		mask := uint8(1) << input
+		if changed&mask != 0 {
+			changed &^= mask
+			d.handler(input, touched&mask != 0)
+		}
+	}
+	return nil
+}
+
+// SetLEDs turns on the LED outputs in the bit mask and off the others. LEDs
+// linked to their input are not affected.
+func (d *Device) SetLEDs(mask uint8) error {
+	return d.write(regLEDOutput, mask)
+}
+
+// Sleep puts the device in deep sleep, where it stops sensing and only
+// answers on the bus.
+func (d *Device) Sleep(sleep bool) error {
+	main, err := d.read(regMainControl)
+	if err != nil {
+		return err
+	}
+	if sleep {
+		main |= mainControlDSLP
+	} else {
+		main &^= mainControlDSLP
+	}
+	return d.write(regMainControl, main)
+}
+
+func (d *Device) read(reg uint8) (uint8, error) {
+	err := d.bus.ReadRegister(uint8(d.Address), reg, d.buf[:1])
+	return d.buf[0], err
+}
+
+func (d *Device) write(reg, value uint8) error {
+	d.buf[0] = value
+	return d.bus.WriteRegister(uint8(d.Address), reg, d.buf[:1])
+}
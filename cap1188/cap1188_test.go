@@ -0,0 +1,75 @@
+package cap1188
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func newFake(c *qt.C) (*tester.I2CBus, *tester.I2CDevice8) {
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(Address)
+	fake.Registers[regProductID] = productID
+	fake.Registers[regManufacturerID] = manufacturerID
+	return bus, fake
+}
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus, fake := newFake(c)
+
+	d := New(bus)
+	err := d.Configure(Config{
+		Inputs:      0x0F,
+		Sensitivity: Sensitivity8X,
+		MaxTouches:  2,
+		LinkLEDs:    true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fake.Registers[regSensorInputEnable], qt.Equals, uint8(0x0F))
+	c.Assert(fake.Registers[regSensitivity], qt.Equals, uint8(0x4F))
+	c.Assert(fake.Registers[regMultipleTouch], qt.Equals, uint8(0x84))
+	c.Assert(fake.Registers[regLEDLinking], qt.Equals, uint8(0x0F))
+	c.Assert(fake.Registers[regRepeatRateEnable], qt.Equals, uint8(0x0F))
+
+	fake.Registers[regProductID] = 0
+	c.Assert(d.Configure(Config{}), qt.Equals, errNotFound)
+}
+
+func TestPoll(t *testing.T) {
+	c := qt.New(t)
+	bus, fake := newFake(c)
+
+	type event struct {
+		Input   uint8
+		Touched bool
+	}
+	var events []event
+	d := New(bus)
+	d.SetTouchHandler(func(input uint8, touched bool) {
+		events = append(events, event{input, touched})
+	})
+
+	fake.Registers[regSensorInputStatus] = 0x05
+	fake.Registers[regMainControl] = mainControlINT
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(events, qt.DeepEquals, []event{{0, true}, {2, true}})
+	c.Assert(fake.Registers[regMainControl], qt.Equals, uint8(0))
+
+	events = nil
+	fake.Registers[regSensorInputStatus] = 0x04
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(events, qt.DeepEquals, []event{{0, false}})
+
+	// with the interrupt connected, nothing is read until it fires
+	d.hasInt = true
+	events = nil
+	fake.Registers[regSensorInputStatus] = 0
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(events, qt.HasLen, 0)
+	d.intPending = true
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(events, qt.DeepEquals, []event{{2, false}})
+}
@@ -0,0 +1,22 @@
+//go:build tinygo
+
+package cap1188
+
+import "machine"
+
+// ConfigureInterrupt sets up intPin, connected to the ALERT output, to flag
+// touches and releases for Poll. ALERT is active low and open-drain by
+// default, so the pin is pulled up.
+func (d *Device) ConfigureInterrupt(intPin machine.Pin) error {
+	intPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	err := intPin.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+		d.intPending = true
+	})
+	if err != nil {
+		return err
+	}
+	d.hasInt = true
+	// Pick up touches that happened before the interrupt was enabled.
+	d.intPending = true
+	return nil
+}
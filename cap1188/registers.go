@@ -0,0 +1,47 @@
+package cap1188
+
+// Address is the default I2C address, selected by the resistor on the
+// ADDR_COMM pin. The Adafruit breakout uses it with the AD pin unconnected.
+const Address = 0x29
+
+// Registers
+const (
+	regMainControl        = 0x00
+	regGeneralStatus      = 0x02
+	regSensorInputStatus  = 0x03
+	regLEDStatus          = 0x04
+	regNoiseFlag          = 0x0A
+	regDeltaCount         = 0x10
+	regSensitivity        = 0x1F
+	regConfig             = 0x20
+	regSensorInputEnable  = 0x21
+	regSensorInputConfig  = 0x22
+	regSensorInputConfig2 = 0x23
+	regAveraging          = 0x24
+	regCalibrate          = 0x26
+	regInterruptEnable    = 0x27
+	regRepeatRateEnable   = 0x28
+	regMultipleTouch      = 0x2A
+	regRecalibration      = 0x2F
+	regThreshold          = 0x30
+	regConfig2            = 0x44
+	regLEDOutputType      = 0x71
+	regLEDLinking         = 0x72
+	regLEDPolarity        = 0x73
+	regLEDOutput          = 0x74
+	regProductID          = 0xFD
+	regManufacturerID     = 0xFE
+	regRevision           = 0xFF
+)
+
+// Well-known values
+const (
+	productID      = 0x50
+	manufacturerID = 0x5D
+
+	mainControlINT   = 0x01
+	mainControlSTBY  = 0x20
+	mainControlDSLP  = 0x10
+	multipleTouchBLK = 0x80
+	config2INTRelN   = 0x01 // disables the interrupt on release
+)
@@ -0,0 +1,96 @@
+package dali
+
+// Command is a standard command sent to control gear with the selector bit
+// set. Commands 0x20 to 0x81 are configuration commands, which Command sends
+// twice as required by the standard.
+type Command uint8
+
+// Arc power commands.
+const (
+	CmdOff            Command = 0x00
+	CmdUp             Command = 0x01
+	CmdDown           Command = 0x02
+	CmdStepUp         Command = 0x03
+	CmdStepDown       Command = 0x04
+	CmdRecallMaxLevel Command = 0x05
+	CmdRecallMinLevel Command = 0x06
+	CmdStepDownAndOff Command = 0x07
+	CmdOnAndStepUp    Command = 0x08
+	CmdGoToLastActive Command = 0x0A
+	CmdGoToScene      Command = 0x10 // + scene 0-15
+)
+
+// Configuration commands.
+const (
+	CmdReset                  Command = 0x20
+	CmdStoreActualLevelInDTR  Command = 0x21
+	CmdStoreDTRAsMaxLevel     Command = 0x2A
+	CmdStoreDTRAsMinLevel     Command = 0x2B
+	CmdStoreDTRAsFailureLevel Command = 0x2C
+	CmdStoreDTRAsPowerOnLevel Command = 0x2D
+	CmdStoreDTRAsFadeTime     Command = 0x2E
+	CmdStoreDTRAsFadeRate     Command = 0x2F
+	CmdStoreDTRAsScene        Command = 0x40 // + scene 0-15
+	CmdRemoveFromScene        Command = 0x50 // + scene 0-15
+	CmdAddToGroup             Command = 0x60 // + group 0-15
+	CmdRemoveFromGroup        Command = 0x70 // + group 0-15
+	CmdStoreDTRAsShortAddress Command = 0x80
+	CmdEnableWriteMemory      Command = 0x81
+)
+
+// Query commands, answered with a backward frame.
+const (
+	CmdQueryStatus              Command = 0x90
+	CmdQueryControlGear         Command = 0x91
+	CmdQueryLampFailure         Command = 0x92
+	CmdQueryLampPowerOn         Command = 0x93
+	CmdQueryLimitError          Command = 0x94
+	CmdQueryResetState          Command = 0x95
+	CmdQueryMissingShortAddress Command = 0x96
+	CmdQueryVersionNumber       Command = 0x97
+	CmdQueryContentDTR          Command = 0x98
+	CmdQueryDeviceType          Command = 0x99
+	CmdQueryPhysicalMinimum     Command = 0x9A
+	CmdQueryPowerFailure        Command = 0x9B
+	CmdQueryActualLevel         Command = 0xA0
+	CmdQueryMaxLevel            Command = 0xA1
+	CmdQueryMinLevel            Command = 0xA2
+	CmdQueryPowerOnLevel        Command = 0xA3
+	CmdQueryFailureLevel        Command = 0xA4
+	CmdQueryFadeTimeRate        Command = 0xA5
+	CmdQuerySceneLevel          Command = 0xB0 // + scene 0-15
+	CmdQueryGroupsLow           Command = 0xC0
+	CmdQueryGroupsHigh          Command = 0xC1
+	CmdQueryRandomAddressH      Command = 0xC2
+	CmdQueryRandomAddressM      Command = 0xC3
+	CmdQueryRandomAddressL      Command = 0xC4
+)
+
+// isConfig reports whether a command must be repeated within 100ms to take
+// effect.
+func (c Command) isConfig() bool {
+	return c >= 0x20 && c <= 0x81
+}
+
+// Special commands, sent in place of the address byte.
+const (
+	specialTerminate           = 0xA1
+	specialDTR                 = 0xA3
+	specialInitialise          = 0xA5 // sent twice
+	specialRandomise           = 0xA7 // sent twice
+	specialCompare             = 0xA9
+	specialWithdraw            = 0xAB
+	specialSearchAddrH         = 0xB1
+	specialSearchAddrM         = 0xB3
+	specialSearchAddrL         = 0xB5
+	specialProgramShortAddress = 0xB7
+	specialVerifyShortAddress  = 0xB9
+	specialQueryShortAddress   = 0xBB
+	specialPhysicalSelection   = 0xBD
+	specialEnableDeviceType    = 0xC1
+	specialDTR1                = 0xC3
+	specialDTR2                = 0xC5
+)
+
+// Answer sent by control gear for yes/no queries.
+const answerYes = 0xFF
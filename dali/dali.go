@@ -0,0 +1,105 @@
+// Package dali implements a DALI (IEC 62386) bus master for controlling
+// dimmable LED drivers, ballasts and other lighting control gear.
+//
+// The bus is driven and sampled through GPIO pins attached to a DALI
+// physical layer interface. Frames are Manchester encoded at 1200 bit/s.
+//
+// Reference: https://www.dali-alliance.org/dali/
+package dali // import "tinygo.org/x/drivers/dali"
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errFraming         = errors.New("dali: invalid backward frame")
+	errInvalidAddress  = errors.New("dali: invalid address")
+	errVerifyFailed    = errors.New("dali: short address verification failed")
+	errNoAddressesLeft = errors.New("dali: no short addresses left")
+)
+
+const (
+	// te is the duration of half a bit at 1200 bit/s.
+	te = time.Second / 2400
+
+	// forwardBits and backwardBits are the data bits after the start bit.
+	forwardBits  = 16
+	backwardBits = 8
+
+	// settlingTime is the minimum idle time between frames.
+	settlingTime = 22 * te
+
+	// backwardTimeout is how long after a forward frame an answer may start,
+	// counted from the end of the last data bit.
+	backwardTimeout = 4*te + 22*te
+
+	// randomiseTime is how long control gear needs to generate a new random
+	// address.
+	randomiseTime = 100 * time.Millisecond
+)
+
+// Address selects the control gear a frame is sent to.
+type Address uint8
+
+// Broadcast addresses all control gear on the bus.
+const Broadcast Address = 0xFE
+
+// ShortAddress returns the address of the control gear with short address
+// a (0-63).
+func ShortAddress(a uint8) Address {
+	return Address(a&0x3F) << 1
+}
+
+// GroupAddress returns the address of control gear in group g (0-15).
+func GroupAddress(g uint8) Address {
+	return 0x80 | Address(g&0x0F)<<1
+}
+
+// manchester returns the bus levels of each half bit of a frame with a start
+// bit and n data bits sent MSB first. A one is sent as low followed by high,
+// a zero as high followed by low.
+func manchester(dst []bool, frame uint32, n int) []bool {
+	dst = append(dst[:0], false, true) // start bit
+	for i := n - 1; i >= 0; i-- {
+		bit := frame&(1<<i) != 0
+		dst = append(dst, !bit, bit)
+	}
+	return dst
+}
+
+// searcher is the part of the bus used by the random address search.
+type searcher interface {
+	setSearchAddress(addr uint32) error
+	compare() (bool, error)
+}
+
+// findLowest returns the lowest random address of all control gear that is
+// initialised and not withdrawn, using a binary search on the search address.
+// The search address is left at the result.
+func findLowest(s searcher) (uint32, bool, error) {
+	low, high := uint32(0), uint32(0xFFFFFF)
+	if err := s.setSearchAddress(high); err != nil {
+		return 0, false, err
+	}
+	found, err := s.compare()
+	if err != nil || !found {
+		return 0, false, err
+	}
+	for low < high {
+		mid := low + (high-low)/2
+		if err := s.setSearchAddress(mid); err != nil {
+			return 0, false, err
+		}
+		yes, err := s.compare()
+		if err != nil {
+			return 0, false, err
+		}
+		if yes {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, true, s.setSearchAddress(low)
+}
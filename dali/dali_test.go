@@ -0,0 +1,63 @@
+package dali
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestAddress(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(ShortAddress(5), qt.Equals, Address(0x0A))
+	c.Assert(GroupAddress(3), qt.Equals, Address(0x86))
+}
+
+func TestManchester(t *testing.T) {
+	c := qt.New(t)
+	var buf [2 * (1 + forwardBits)]bool
+	levels := manchester(buf[:], 0xFF00|0xA5, forwardBits)
+	c.Assert(levels, qt.HasLen, 34)
+	// start bit and first data bit (1) are low then high
+	c.Assert(levels[:4], qt.DeepEquals, []bool{false, true, false, true})
+	// last two bits of 0xA5 are 0 then 1
+	c.Assert(levels[30:], qt.DeepEquals, []bool{true, false, false, true})
+}
+
+// fakeGear answers compare with yes if any random address is at or below
+// the search address.
+type fakeGear struct {
+	random   []uint32
+	search   uint32
+	compares int
+}
+
+func (g *fakeGear) setSearchAddress(addr uint32) error {
+	g.search = addr
+	return nil
+}
+
+func (g *fakeGear) compare() (bool, error) {
+	g.compares++
+	for _, r := range g.random {
+		if r <= g.search {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestFindLowest(t *testing.T) {
+	c := qt.New(t)
+	g := &fakeGear{random: []uint32{0x123456, 0x00ABCD, 0xFFFFFF}}
+	addr, found, err := findLowest(g)
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.IsTrue)
+	c.Assert(addr, qt.Equals, uint32(0x00ABCD))
+	c.Assert(g.search, qt.Equals, uint32(0x00ABCD))
+	c.Assert(g.compares, qt.Equals, 25)
+
+	g = &fakeGear{}
+	_, found, err = findLowest(g)
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.IsFalse)
+}
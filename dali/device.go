@@ -0,0 +1,242 @@
+//go:build tinygo
+
+package dali
+
+import (
+	"machine"
+	"time"
+)
+
+// Config holds the polarity of the physical layer interface.
+type Config struct {
+	// InvertTX drives the TX pin high to pull the bus low, as with the
+	// common transistor or optocoupler interfaces.
+	InvertTX bool
+
+	// InvertRX reads the RX pin as high while the bus is low.
+	InvertRX bool
+}
+
+// Device is a DALI bus master.
+type Device struct {
+	tx, rx   machine.Pin
+	invertTX bool
+	invertRX bool
+
+	// idle is the earliest time the next forward frame may start.
+	idle time.Time
+
+	// search is the search address last sent, to skip unchanged bytes.
+	search      uint32
+	searchValid bool
+
+	levels [2 * (1 + forwardBits)]bool
+}
+
+// New returns a new DALI master using the given transmit and receive pins.
+func New(tx, rx machine.Pin) *Device {
+	return &Device{
+		tx: tx,
+		rx: rx,
+	}
+}
+
+// Configure sets up the pins and releases the bus.
+func (d *Device) Configure(cfg Config) {
+	d.invertTX = cfg.InvertTX
+	d.invertRX = cfg.InvertRX
+	d.tx.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	d.rx.Configure(machine.PinConfig{Mode: machine.PinInput})
+	d.setBus(true)
+	d.idle = time.Now().Add(settlingTime)
+}
+
+// Send sends a raw forward frame.
+func (d *Device) Send(addr, data uint8) {
+	d.send(uint32(addr)<<8 | uint32(data))
+}
+
+// Query sends a raw forward frame and waits for a backward frame. It returns
+// false if there was no answer.
+func (d *Device) Query(addr, data uint8) (uint8, bool, error) {
+	d.send(uint32(addr)<<8 | uint32(data))
+	return d.receive()
+}
+
+// DirectArcPower sets the light output of the addressed gear to level
+// (0-254), using the configured fade time.
+func (d *Device) DirectArcPower(a Address, level uint8) {
+	d.Send(uint8(a), level)
+}
+
+// Command sends a command to the addressed gear. Configuration commands are
+// sent twice.
+func (d *Device) Command(a Address, cmd Command) {
+	d.Send(uint8(a)|1, uint8(cmd))
+	if cmd.isConfig() {
+		d.Send(uint8(a)|1, uint8(cmd))
+	}
+}
+
+// QueryCommand sends a query command to the addressed gear and returns the
+// answer. It returns false if there was no answer, which means "no" for
+// yes/no queries.
+func (d *Device) QueryCommand(a Address, cmd Command) (uint8, bool, error) {
+	return d.Query(uint8(a)|1, uint8(cmd))
+}
+
+// SetDTR loads the data transfer register of all gear, used as the value of
+// the following store commands.
+func (d *Device) SetDTR(value uint8) {
+	d.Send(specialDTR, value)
+}
+
+// Commission assigns short addresses to control gear using the random
+// address search. If all is true every gear is readdressed, otherwise only
+// gear without a short address. Addresses are assigned from first upwards.
+// It returns the number of gear that was addressed.
+func (d *Device) Commission(all bool, first uint8) (int, error) {
+	if first > 63 {
+		return 0, errInvalidAddress
+	}
+	init := uint8(0xFF) // gear without a short address
+	if all {
+		init = 0x00
+	}
+	d.Send(specialInitialise, init)
+	d.Send(specialInitialise, init)
+	d.Send(specialRandomise, 0)
+	d.Send(specialRandomise, 0)
+	time.Sleep(randomiseTime)
+	d.searchValid = false
+
+	n := 0
+	var err error
+	for addr := first; ; addr++ {
+		var found bool
+		_, found, err = findLowest(d)
+		if err != nil || !found {
+			break
+		}
+		if addr > 63 {
+			err = errNoAddressesLeft
+			break
+		}
+		short := uint8(ShortAddress(addr)) | 1
+		d.Send(specialProgramShortAddress, short)
+		var yes bool
+		_, yes, err = d.Query(specialVerifyShortAddress, short)
+		if err != nil {
+			break
+		}
+		if !yes {
+			err = errVerifyFailed
+			break
+		}
+		d.Send(specialWithdraw, 0)
+		n++
+	}
+	d.Send(specialTerminate, 0)
+	return n, err
+}
+
+func (d *Device) setSearchAddress(addr uint32) error {
+	if !d.searchValid || d.search>>16 != addr>>16 {
+		d.Send(specialSearchAddrH, uint8(addr>>16))
+	}
+	if !d.searchValid || uint8(d.search>>8) != uint8(addr>>8) {
+		d.Send(specialSearchAddrM, uint8(addr>>8))
+	}
+	if !d.searchValid || uint8(d.search) != uint8(addr) {
+		d.Send(specialSearchAddrL, uint8(addr))
+	}
+	d.search = addr
+	d.searchValid = true
+	return nil
+}
+
+func (d *Device) compare() (bool, error) {
+	_, yes, err := d.Query(specialCompare, 0)
+	if err == errFraming {
+		// Several gear answering at once corrupt the frame, which still
+		// means yes.
+		return true, nil
+	}
+	return yes, err
+}
+
+// send transmits a forward frame.
+func (d *Device) send(frame uint32) {
+	levels := manchester(d.levels[:], frame, forwardBits)
+	for time.Now().Before(d.idle) {
+	}
+	t := time.Now()
+	for _, level := range levels {
+		d.setBus(level)
+		t = t.Add(te)
+		for time.Now().Before(t) {
+		}
+	}
+	d.setBus(true)
+	d.idle = t.Add(settlingTime)
+}
+
+// receive reads a backward frame. Each bit is resynchronised on its mid-bit
+// transition, so the gear clock may deviate within the allowed tolerance.
+func (d *Device) receive() (uint8, bool, error) {
+	start := time.Now()
+	defer func() {
+		d.idle = time.Now().Add(settlingTime)
+	}()
+
+	// Start bit: falling edge, then rising edge in the middle.
+	for d.bus() {
+		if time.Since(start) > backwardTimeout {
+			return 0, false, nil
+		}
+	}
+	mid, ok := d.waitLevel(true, time.Now().Add(te*3/2))
+	if !ok {
+		return 0, false, errFraming
+	}
+
+	var value uint8
+	for i := 0; i < backwardBits; i++ {
+		// Sample the first half of the bit, then wait for the transition.
+		for time.Since(mid) < te*3/2 {
+		}
+		first := d.bus()
+		mid, ok = d.waitLevel(!first, mid.Add(te*5/2))
+		if !ok {
+			return 0, false, errFraming
+		}
+		value <<= 1
+		if !first {
+			value |= 1
+		}
+	}
+	return value, true, nil
+}
+
+// waitLevel waits for the bus to reach level and returns the time it did.
+func (d *Device) waitLevel(level bool, deadline time.Time) (time.Time, bool) {
+	for {
+		now := time.Now()
+		if d.bus() == level {
+			return now, true
+		}
+		if now.After(deadline) {
+			return now, false
+		}
+	}
+}
+
+// setBus drives the bus low or releases it high.
+func (d *Device) setBus(high bool) {
+	d.tx.Set(high != d.invertTX)
+}
+
+// bus returns the bus level.
+func (d *Device) bus() bool {
+	return d.rx.Get() != d.invertRX
+}
@@ -0,0 +1,66 @@
+package datalogger
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// Every block starts with a header, followed by whole records. A block is
+// only written once, so a crash can at most lose the block being written,
+// which fails its CRC on the next start.
+//
+//	0  magic    uint16
+//	2  format   uint8
+//	3  flags    uint8
+//	4  file     uint16
+//	6  length   uint16, bytes of records after the header
+//	8  sequence uint32, incremented for every block written
+//	12 crc      uint32, IEEE CRC of the header up to here and the records
+const headerSize = 16
+
+const blockMagic = 0xD10C
+
+// flagFirst marks the first block of a file.
+const flagFirst = 0x01
+
+type header struct {
+	format Format
+	flags  uint8
+	file   uint16
+	length uint16
+	seq    uint32
+}
+
+// seal writes h to the start of block and computes the CRC over the header
+// and h.length bytes of records.
+func (h header) seal(block []byte) {
+	binary.LittleEndian.PutUint16(block[0:], blockMagic)
+	block[2] = uint8(h.format)
+	block[3] = h.flags
+	binary.LittleEndian.PutUint16(block[4:], h.file)
+	binary.LittleEndian.PutUint16(block[6:], h.length)
+	binary.LittleEndian.PutUint32(block[8:], h.seq)
+	crc := crc32.ChecksumIEEE(block[:12])
+	crc = crc32.Update(crc, crc32.IEEETable, block[headerSize:headerSize+int(h.length)])
+	binary.LittleEndian.PutUint32(block[12:], crc)
+}
+
+// parseHeader returns the header of block and whether the block is valid.
+func parseHeader(block []byte) (header, bool) {
+	if binary.LittleEndian.Uint16(block[0:]) != blockMagic {
+		return header{}, false
+	}
+	h := header{
+		format: Format(block[2]),
+		flags:  block[3],
+		file:   binary.LittleEndian.Uint16(block[4:]),
+		length: binary.LittleEndian.Uint16(block[6:]),
+		seq:    binary.LittleEndian.Uint32(block[8:]),
+	}
+	if headerSize+int(h.length) > len(block) {
+		return header{}, false
+	}
+	crc := crc32.ChecksumIEEE(block[:12])
+	crc = crc32.Update(crc, crc32.IEEETable, block[headerSize:headerSize+int(h.length)])
+	return h, crc == binary.LittleEndian.Uint32(block[12:])
+}
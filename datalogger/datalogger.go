@@ -0,0 +1,402 @@
+// Package datalogger samples sensors on a schedule and appends timestamped
+// records to a block device, like an SD card (sdcard.Device) or SPI flash
+// (flash.Device), without a filesystem.
+//
+// The log is a ring of blocks in a region of the device: when it is full, the
+// oldest blocks are overwritten. Each block carries a sequence number and a
+// CRC, so that after a reset or power loss the logger finds the last valid
+// block and continues after it, in a new file. Records are buffered in RAM
+// until a block is full or Sync is called, which is when they are safe.
+//
+// Records are stored as CSV text or in a compact binary format, and Export
+// writes the whole log as CSV, for example to a serial port.
+package datalogger // import "tinygo.org/x/drivers/datalogger"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errRegion    = errors.New("datalogger: region too small")
+	errBlockSize = errors.New("datalogger: unsupported block size")
+	errRecord    = errors.New("datalogger: record larger than a block")
+	errColumns   = errors.New("datalogger: no columns")
+)
+
+// BlockDevice is the storage the log is written to. It is implemented by
+// sdcard.Device and flash.Device.
+type BlockDevice interface {
+	ReadAt(buf []byte, off int64) (int, error)
+	WriteAt(buf []byte, off int64) (int, error)
+	Size() int64
+	WriteBlockSize() int64
+	EraseBlockSize() int64
+	EraseBlocks(start, len int64) error
+}
+
+// Format is the encoding of the records.
+type Format uint8
+
+const (
+	// CSV stores one line of text per record: the time in milliseconds
+	// since the Unix epoch followed by the values. The first block of each
+	// file starts with a line of column names.
+	CSV Format = iota + 1
+
+	// Binary stores the time as a varint delta from the previous record in
+	// the block and the values as zigzag varints, usually 2 to 4 bytes per
+	// value.
+	Binary
+)
+
+// Column is a value recorded with every sample.
+type Column struct {
+	// Name of the column in the CSV header.
+	Name string
+
+	// Sensor is updated with Measurement before Value is called. Columns
+	// sharing a sensor update it once per sample. It may be nil for values
+	// that need no update.
+	Sensor      drivers.Sensor
+	Measurement drivers.Measurement
+
+	// Value returns the value to record, usually a method value like
+	// sensor.Temperature.
+	Value func() int32
+}
+
+// Config holds the configuration of the Logger.
+type Config struct {
+	// Format of the records, defaults to CSV.
+	Format Format
+
+	// Interval between samples taken by Poll.
+	Interval time.Duration
+
+	// Offset and Length select the region of the device used for the log,
+	// aligned to erase blocks. A zero Length uses the rest of the device.
+	Offset int64
+	Length int64
+
+	// Now returns the time stamp of records, defaults to time.Now. Set it
+	// to read a real-time clock when the system clock is not set.
+	Now func() time.Time
+}
+
+type update struct {
+	sensor drivers.Sensor
+	which  drivers.Measurement
+}
+
+// Logger writes sensor records to a BlockDevice.
+type Logger struct {
+	dev     BlockDevice
+	columns []Column
+	updates []update
+	cfg     Config
+
+	blockSize int64
+	eraseSize int64
+	start     int64 // region, in bytes
+	blocks    int64 // region size, in blocks
+
+	block  []byte
+	hdr    header
+	pos    int64 // block index being filled
+	last   int64 // time stamp of the previous record in block, in ms
+	values []int32
+	next   time.Time
+}
+
+// New returns a Logger recording columns to dev.
+//
+// This function only creates the Logger object, it does not touch the device.
+func New(dev BlockDevice, columns []Column) *Logger {
+	l := &Logger{
+		dev:     dev,
+		columns: columns,
+		values:  make([]int32, len(columns)),
+	}
+	for _, c := range columns {
+		if c.Sensor == nil {
+			continue
+		}
+		found := false
+		for i := range l.updates {
+			if l.updates[i].sensor == c.Sensor {
+				l.updates[i].which |= c.Measurement
+				found = true
+			}
+		}
+		if !found {
+			l.updates = append(l.updates, update{c.Sensor, c.Measurement})
+		}
+	}
+	return l
+}
+
+// Configure finds the end of an existing log in the region and starts a new
+// file after it.
+func (l *Logger) Configure(cfg Config) error {
+	if len(l.columns) == 0 {
+		return errColumns
+	}
+	if cfg.Format == 0 {
+		cfg.Format = CSV
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	l.cfg = cfg
+
+	l.blockSize = l.dev.WriteBlockSize()
+	l.eraseSize = l.dev.EraseBlockSize()
+	if l.blockSize < 64 || l.blockSize > 4096 || l.eraseSize%l.blockSize != 0 {
+		return errBlockSize
+	}
+	l.start = (cfg.Offset + l.eraseSize - 1) / l.eraseSize * l.eraseSize
+	length := cfg.Length
+	if length == 0 {
+		length = l.dev.Size() - l.start
+	}
+	length = length / l.eraseSize * l.eraseSize
+	l.blocks = length / l.blockSize
+	// one erase block is kept erased ahead of the write position
+	if length < 2*l.eraseSize {
+		return errRegion
+	}
+	if l.block == nil || int64(len(l.block)) != l.blockSize {
+		l.block = make([]byte, l.blockSize)
+	}
+
+	last, h, err := l.findEnd()
+	if err != nil {
+		return err
+	}
+	if last < 0 {
+		l.pos = 0
+		l.hdr = header{format: cfg.Format, flags: flagFirst, file: 1}
+		if err := l.erase(0); err != nil {
+			return err
+		}
+	} else {
+		l.pos = (last + 1) % l.blocks
+		l.hdr = header{format: cfg.Format, flags: flagFirst, file: h.file + 1, seq: h.seq + 1}
+		if err := l.repair(); err != nil {
+			return err
+		}
+	}
+	l.resetBlock()
+	l.next = time.Time{}
+	return nil
+}
+
+// findEnd returns the index and header of the last valid block, or -1 if
+// the region holds no log. Blocks from the start of the region up to the
+// last one written have consecutive sequence numbers, which is found with a
+// binary search.
+//
+// On flash, the erase block holding block 0 is erased ahead of time when the
+// log wraps, so until block 0 is written again the last block is found in
+// the run starting at the erase block with the highest sequence number.
+func (l *Logger) findEnd() (int64, header, error) {
+	first, ok, err := l.readHeader(0)
+	if err != nil {
+		return -1, header{}, err
+	}
+	start := int64(0)
+	if !ok {
+		if l.eraseSize == l.blockSize {
+			return -1, header{}, nil
+		}
+		step := l.eraseSize / l.blockSize
+		for i := step; i < l.blocks; i += step {
+			h, valid, err := l.readHeader(i)
+			if err != nil {
+				return -1, header{}, err
+			}
+			if valid && (!ok || int32(h.seq-first.seq) > 0) {
+				start, first, ok = i, h, true
+			}
+		}
+		if !ok {
+			return -1, header{}, nil
+		}
+	}
+	lo, hi := start, l.blocks // block lo is part of the run, hi is not
+	last := first
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		h, ok, err := l.readHeader(mid)
+		if err != nil {
+			return -1, header{}, err
+		}
+		if ok && h.seq == first.seq+uint32(mid-start) {
+			lo = mid
+			last = h
+		} else {
+			hi = mid
+		}
+	}
+	return lo, last, nil
+}
+
+// readHeader reads block i and returns its header if it is valid.
+func (l *Logger) readHeader(i int64) (header, bool, error) {
+	if _, err := l.dev.ReadAt(l.block, l.start+i*l.blockSize); err != nil {
+		return header{}, false, err
+	}
+	h, ok := parseHeader(l.block)
+	return h, ok, nil
+}
+
+// Poll takes a sample when the interval has elapsed since the previous one.
+// Call it regularly from the main loop.
+func (l *Logger) Poll() error {
+	now := l.cfg.Now()
+	if now.Before(l.next) {
+		return nil
+	}
+	l.next = l.next.Add(l.cfg.Interval)
+	if l.next.Before(now) {
+		// don't try to catch up after a long pause
+		l.next = now.Add(l.cfg.Interval)
+	}
+	return l.Sample()
+}
+
+// Sample updates the sensors and appends a record now.
+func (l *Logger) Sample() error {
+	var err error
+	for _, u := range l.updates {
+		if uerr := u.sensor.Update(u.which); uerr != nil && err == nil {
+			err = uerr
+		}
+	}
+	for i, c := range l.columns {
+		l.values[i] = c.Value()
+	}
+	if aerr := l.Append(l.cfg.Now(), l.values); aerr != nil {
+		return aerr
+	}
+	// the record is kept even if a sensor failed
+	return err
+}
+
+// Append appends a record with the given time stamp and values, one for each
+// column.
+func (l *Logger) Append(t time.Time, values []int32) error {
+	ms := t.UnixNano() / int64(time.Millisecond)
+	used := l.encode(l.block[headerSize+int(l.hdr.length):], ms, values, l.hdr.length == 0)
+	if used < 0 {
+		if l.hdr.length == 0 {
+			return errRecord
+		}
+		if err := l.Sync(); err != nil {
+			return err
+		}
+		used = l.encode(l.block[headerSize+int(l.hdr.length):], ms, values, true)
+		if used < 0 {
+			return errRecord
+		}
+	}
+	l.hdr.length += uint16(used)
+	l.last = ms
+	return nil
+}
+
+// Sync writes the buffered records to the device. Each call uses a new
+// block, so calling it after every record wastes space: it is done
+// automatically when a block is full.
+func (l *Logger) Sync() error {
+	if l.hdr.length == 0 {
+		return nil
+	}
+	off := l.start + l.pos*l.blockSize
+	next := (l.pos + 1) % l.blocks
+	if (next*l.blockSize)%l.eraseSize == 0 {
+		// keep the next erase block erased ahead of the write position
+		if err := l.erase(next); err != nil {
+			return err
+		}
+	}
+	l.hdr.seal(l.block)
+	if _, err := l.dev.WriteAt(l.block, off); err != nil {
+		return err
+	}
+	l.pos = next
+	l.hdr.seq++
+	l.hdr.flags &^= flagFirst
+	l.resetBlock()
+	return nil
+}
+
+// Rotate syncs the buffered records and starts a new file.
+func (l *Logger) Rotate() error {
+	if err := l.Sync(); err != nil {
+		return err
+	}
+	l.hdr.file++
+	l.hdr.flags |= flagFirst
+	l.resetBlock()
+	return nil
+}
+
+// File returns the number of the file being written.
+func (l *Logger) File() uint16 {
+	return l.hdr.file
+}
+
+// erase erases the erase block starting at block i. Devices whose erase
+// block is a single write block, like SD cards, are overwritten in place
+// without erasing.
+func (l *Logger) erase(i int64) error {
+	if l.eraseSize == l.blockSize {
+		return nil
+	}
+	return l.dev.EraseBlocks((l.start+i*l.blockSize)/l.eraseSize, 1)
+}
+
+// repair makes sure the block at the write position can be written after a
+// restart. A write interrupted by a reset leaves a partly written block that
+// flash can't overwrite, so the erase block holding it is erased and the
+// valid blocks before it are written back.
+func (l *Logger) repair() error {
+	if l.eraseSize == l.blockSize {
+		return nil
+	}
+	off := l.start + l.pos*l.blockSize
+	if _, err := l.dev.ReadAt(l.block, off); err != nil {
+		return err
+	}
+	blank := true
+	for _, b := range l.block {
+		if b != 0xFF {
+			blank = false
+			break
+		}
+	}
+	if blank {
+		return nil
+	}
+	first := (off - l.start) / l.eraseSize * l.eraseSize
+	keep := make([]byte, off-l.start-first)
+	if _, err := l.dev.ReadAt(keep, l.start+first); err != nil {
+		return err
+	}
+	if err := l.erase(first / l.blockSize); err != nil {
+		return err
+	}
+	_, err := l.dev.WriteAt(keep, l.start+first)
+	return err
+}
+
+func (l *Logger) resetBlock() {
+	l.hdr.length = 0
+	if l.hdr.flags&flagFirst != 0 && l.hdr.format == CSV {
+		l.hdr.length = uint16(l.csvHeader(l.block[headerSize:]))
+	}
+}
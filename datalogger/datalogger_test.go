@@ -0,0 +1,235 @@
+package datalogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers"
+)
+
+// flashDevice is a NOR flash in memory: writes can only clear bits and
+// erasing sets a whole erase block to 0xFF.
+type flashDevice struct {
+	mem []byte
+}
+
+func newFlash(size int) *flashDevice {
+	return &flashDevice{mem: bytes.Repeat([]byte{0xFF}, size)}
+}
+
+func (f *flashDevice) ReadAt(buf []byte, off int64) (int, error) {
+	return copy(buf, f.mem[off:]), nil
+}
+
+func (f *flashDevice) WriteAt(buf []byte, off int64) (int, error) {
+	for i, b := range buf {
+		f.mem[off+int64(i)] &= b
+	}
+	return len(buf), nil
+}
+
+func (f *flashDevice) Size() int64           { return int64(len(f.mem)) }
+func (f *flashDevice) WriteBlockSize() int64 { return 128 }
+func (f *flashDevice) EraseBlockSize() int64 { return 512 }
+
+func (f *flashDevice) EraseBlocks(start, n int64) error {
+	for i := start * 512; i < (start+n)*512; i++ {
+		f.mem[i] = 0xFF
+	}
+	return nil
+}
+
+type fakeSensor struct {
+	updates int
+	which   drivers.Measurement
+	temp    int32
+	hum     int32
+}
+
+func (s *fakeSensor) Update(which drivers.Measurement) error {
+	s.updates++
+	s.which = which
+	return nil
+}
+
+func newLogger(dev BlockDevice, s *fakeSensor) *Logger {
+	return New(dev, []Column{
+		{Name: "temp", Sensor: s, Measurement: drivers.Temperature, Value: func() int32 { return s.temp }},
+		{Name: "hum", Sensor: s, Measurement: drivers.Humidity, Value: func() int32 { return s.hum }},
+	})
+}
+
+// clock returns a Now function advancing by step on every call.
+func clock(start time.Time, step time.Duration) func() time.Time {
+	t := start.Add(-step)
+	return func() time.Time {
+		t = t.Add(step)
+		return t
+	}
+}
+
+func TestCSV(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(4096)
+	s := &fakeSensor{temp: 21500, hum: 4000}
+	l := newLogger(dev, s)
+	now := time.Unix(1700000000, 0)
+	c.Assert(l.Configure(Config{Interval: time.Second, Now: func() time.Time { return now }}), qt.IsNil)
+
+	c.Assert(l.Poll(), qt.IsNil)
+	c.Assert(s.updates, qt.Equals, 1)
+	c.Assert(s.which, qt.Equals, drivers.Temperature|drivers.Humidity)
+	now = now.Add(500 * time.Millisecond)
+	c.Assert(l.Poll(), qt.IsNil) // not due yet
+	c.Assert(s.updates, qt.Equals, 1)
+	now = now.Add(500 * time.Millisecond)
+	s.temp = -1250
+	c.Assert(l.Poll(), qt.IsNil)
+
+	var out bytes.Buffer
+	c.Assert(l.Export(&out), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, "") // not synced
+	c.Assert(l.Sync(), qt.IsNil)
+	c.Assert(l.Export(&out), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, "time,temp,hum\n1700000000000,21500,4000\n1700000001000,-1250,4000\n")
+}
+
+func TestResume(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(4096)
+	s := &fakeSensor{}
+	cfg := Config{Format: Binary, Now: clock(time.Unix(1000, 0), time.Second)}
+
+	l := newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.File(), qt.Equals, uint16(1))
+	for i := 0; i < 30; i++ {
+		s.temp = int32(i * 1000)
+		c.Assert(l.Sample(), qt.IsNil)
+	}
+	c.Assert(l.Sync(), qt.IsNil)
+	// the last sample is lost in a reset before it was synced
+	c.Assert(l.Sample(), qt.IsNil)
+
+	l = newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.File(), qt.Equals, uint16(2))
+	c.Assert(l.Sample(), qt.IsNil)
+	c.Assert(l.Sync(), qt.IsNil)
+
+	var out bytes.Buffer
+	c.Assert(l.Export(&out), qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	c.Assert(lines, qt.HasLen, 1+30+1+1)
+	c.Assert(lines[0], qt.Equals, "time,temp,hum")
+	c.Assert(lines[1], qt.Equals, "1000000,0,0")
+	c.Assert(lines[30], qt.Equals, "1029000,29000,0")
+	c.Assert(lines[31], qt.Equals, "time,temp,hum")
+	c.Assert(lines[32], qt.Equals, "1031000,29000,0")
+}
+
+func TestWrap(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(4096)
+	s := &fakeSensor{}
+	// 4 erase blocks of 4 write blocks each
+	cfg := Config{Format: CSV, Offset: 1024, Length: 2048, Now: clock(time.Unix(0, 0), time.Millisecond)}
+
+	l := newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	for i := 0; i < 40; i++ {
+		s.temp = int32(i)
+		c.Assert(l.Sample(), qt.IsNil)
+		c.Assert(l.Sync(), qt.IsNil) // one block per record
+	}
+	// outside of the region
+	c.Assert(dev.mem[:1024], qt.DeepEquals, bytes.Repeat([]byte{0xFF}, 1024))
+
+	l = newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.pos, qt.Equals, int64(40%16))
+
+	var out bytes.Buffer
+	c.Assert(l.Export(&out), qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// the erase block ahead of the write position is kept erased
+	c.Assert(lines, qt.HasLen, 12)
+	c.Assert(lines[0], qt.Equals, "28,28,0")
+	c.Assert(lines[11], qt.Equals, "39,39,0")
+}
+
+func TestRestartAfterWrap(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(2048)
+	s := &fakeSensor{}
+	cfg := Config{Format: CSV, Now: clock(time.Unix(0, 0), time.Millisecond)}
+
+	l := newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.Rotate(), qt.IsNil)
+	c.Assert(l.Rotate(), qt.IsNil)
+	c.Assert(l.File(), qt.Equals, uint16(3))
+	// the two files took a block each, 16 blocks in all
+	for i := 0; i < 14; i++ {
+		s.temp = int32(i)
+		c.Assert(l.Sample(), qt.IsNil)
+		c.Assert(l.Sync(), qt.IsNil)
+	}
+	// the erase block holding block 0 was erased ahead of the write position
+	c.Assert(l.pos, qt.Equals, int64(0))
+
+	l = newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.File(), qt.Equals, uint16(4))
+	c.Assert(l.pos, qt.Equals, int64(0))
+	c.Assert(l.Sample(), qt.IsNil)
+	c.Assert(l.Sync(), qt.IsNil)
+	c.Assert(l.hdr.seq, qt.Equals, uint32(17))
+
+	var out bytes.Buffer
+	c.Assert(l.Export(&out), qt.IsNil)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	c.Assert(lines, qt.HasLen, 12+2)
+	c.Assert(lines[0], qt.Equals, "2,2,0")
+	c.Assert(lines[11], qt.Equals, "13,13,0")
+	c.Assert(lines[12], qt.Equals, "time,temp,hum")
+	c.Assert(lines[13], qt.Equals, "14,13,0")
+}
+
+func TestTornWrite(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(4096)
+	s := &fakeSensor{}
+	cfg := Config{Format: CSV, Now: clock(time.Unix(0, 0), time.Millisecond)}
+
+	l := newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	for i := 0; i < 2; i++ {
+		c.Assert(l.Sample(), qt.IsNil)
+		c.Assert(l.Sync(), qt.IsNil)
+	}
+	// a reset in the middle of writing block 2
+	dev.mem[2*128+5] = 0x00
+
+	l = newLogger(dev, s)
+	c.Assert(l.Configure(cfg), qt.IsNil)
+	c.Assert(l.pos, qt.Equals, int64(2))
+	c.Assert(l.Sample(), qt.IsNil)
+	c.Assert(l.Sync(), qt.IsNil)
+
+	var out bytes.Buffer
+	c.Assert(l.Export(&out), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, "time,temp,hum\n0,0,0\n1,0,0\ntime,temp,hum\n2,0,0\n")
+}
+
+func TestConfigureErrors(t *testing.T) {
+	c := qt.New(t)
+	dev := newFlash(4096)
+	c.Assert(New(dev, nil).Configure(Config{}), qt.Equals, errColumns)
+	l := newLogger(dev, &fakeSensor{})
+	c.Assert(l.Configure(Config{Length: 512}), qt.Equals, errRegion)
+}
@@ -0,0 +1,135 @@
+package datalogger
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// encode appends a record to buf and returns its size, or -1 if it doesn't
+// fit. The first record of a block stores the absolute time stamp.
+func (l *Logger) encode(buf []byte, ms int64, values []int32, first bool) int {
+	var tmp [binary.MaxVarintLen64]byte
+	n := 0
+	put := func(b []byte) bool {
+		if n+len(b) > len(buf) {
+			return false
+		}
+		n += copy(buf[n:], b)
+		return true
+	}
+	switch l.hdr.format {
+	case Binary:
+		t := ms
+		if !first {
+			t = ms - l.last
+		}
+		if !put(tmp[:binary.PutVarint(tmp[:], t)]) {
+			return -1
+		}
+		for _, v := range values {
+			if !put(tmp[:binary.PutVarint(tmp[:], int64(v))]) {
+				return -1
+			}
+		}
+	default:
+		if !put(strconv.AppendInt(tmp[:0], ms, 10)) {
+			return -1
+		}
+		for _, v := range values {
+			if !put([]byte{','}) || !put(strconv.AppendInt(tmp[:0], int64(v), 10)) {
+				return -1
+			}
+		}
+		if !put([]byte{'\n'}) {
+			return -1
+		}
+	}
+	return n
+}
+
+// csvHeader writes the line of column names to buf and returns its size.
+func (l *Logger) csvHeader(buf []byte) int {
+	n := copy(buf, "time")
+	for _, c := range l.columns {
+		if n+1+len(c.Name)+1 > len(buf) {
+			break
+		}
+		buf[n] = ','
+		n++
+		n += copy(buf[n:], c.Name)
+	}
+	if n < len(buf) {
+		buf[n] = '\n'
+		n++
+	}
+	return n
+}
+
+// Export writes the log as CSV to w, oldest record first. Records that are
+// not synced yet are not included.
+func (l *Logger) Export(w io.Writer) error {
+	block := make([]byte, l.blockSize)
+	header := make([]byte, l.blockSize)
+	values := make([]int32, len(l.columns))
+	var line []byte
+	for i := int64(1); i <= l.blocks; i++ {
+		pos := (l.pos + i) % l.blocks
+		if pos == l.pos {
+			break
+		}
+		if _, err := l.dev.ReadAt(block, l.start+pos*l.blockSize); err != nil {
+			return err
+		}
+		h, ok := parseHeader(block)
+		if !ok {
+			continue
+		}
+		records := block[headerSize : headerSize+int(h.length)]
+		if h.format == CSV {
+			if _, err := w.Write(records); err != nil {
+				return err
+			}
+			continue
+		}
+		if h.flags&flagFirst != 0 {
+			if _, err := w.Write(header[:l.csvHeader(header)]); err != nil {
+				return err
+			}
+		}
+		var t int64
+		first := true
+	records:
+		for len(records) > 0 {
+			d, n := binary.Varint(records)
+			if n <= 0 {
+				break
+			}
+			records = records[n:]
+			if first {
+				t = d
+				first = false
+			} else {
+				t += d
+			}
+			for j := range values {
+				v, n := binary.Varint(records)
+				if n <= 0 {
+					break records
+				}
+				records = records[n:]
+				values[j] = int32(v)
+			}
+			line = strconv.AppendInt(line[:0], t, 10)
+			for _, v := range values {
+				line = append(line, ',')
+				line = strconv.AppendInt(line, int64(v), 10)
+			}
+			line = append(line, '\n')
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
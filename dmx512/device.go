@@ -0,0 +1,140 @@
+//go:build tinygo
+
+package dmx512
+
+import (
+	"machine"
+	"runtime"
+	"time"
+)
+
+// UART is the serial port used to send frames. It is implemented by the
+// machine.UART type, which should be configured for 250000 baud with 2 stop
+// bits where the hardware allows it.
+type UART interface {
+	Write([]byte) (int, error)
+	SetBaudRate(br uint32)
+}
+
+// Device is a DMX512 transmitter.
+type Device struct {
+	uart  UART
+	de    machine.Pin
+	slots int
+
+	// Universe holds the channel values sent in the next frame.
+	Universe Universe
+
+	frame   Universe
+	brk     [1]byte
+	idle    time.Time
+	running bool
+	stop    chan struct{}
+}
+
+// New returns a new DMX512 transmitter. dePin drives the DE pin of the RS-485
+// transceiver and is held high, pass machine.NoPin if it is wired high.
+func New(uart UART, dePin machine.Pin) *Device {
+	return &Device{
+		uart: uart,
+		de:   dePin,
+	}
+}
+
+// Configure sets up the transmitter and enables the RS-485 driver.
+func (d *Device) Configure(cfg Config) error {
+	if cfg.Slots == 0 {
+		cfg.Slots = Channels
+	}
+	if cfg.Slots < MinSlots || cfg.Slots > Channels {
+		return errInvalidSlots
+	}
+	d.slots = cfg.Slots
+	d.Universe[0] = cfg.StartCode
+
+	if d.de != machine.NoPin {
+		d.de.Configure(machine.PinConfig{Mode: machine.PinOutput})
+		d.de.High()
+	}
+	d.uart.SetBaudRate(baudRate)
+	return nil
+}
+
+// Set sets channel ch (1-512) to value for the next frame.
+func (d *Device) Set(ch int, value byte) error {
+	return d.Universe.Set(ch, value)
+}
+
+// Send transmits a single frame with the current channel values. It returns
+// once the frame has been handed to the UART, which may still be sending the
+// last slots.
+func (d *Device) Send() {
+	// Take a copy so channels can be changed while the frame goes out.
+	d.frame = d.Universe
+
+	// Wait for the previous frame to leave the UART before changing the
+	// baud rate.
+	for time.Now().Before(d.idle) {
+	}
+
+	d.uart.SetBaudRate(breakBaudRate)
+	start := time.Now()
+	d.uart.Write(d.brk[:])
+	for time.Since(start) < breakTime {
+	}
+	d.uart.SetBaudRate(baudRate)
+
+	start = time.Now()
+	d.uart.Write(d.frame[:1+d.slots])
+	d.idle = start.Add(time.Duration(1+d.slots) * slotTime)
+}
+
+// Start sends frames continuously in a background goroutine, one every
+// interval. The interval is clamped to the frame duration and to the one
+// second maximum of the specification. Changes to Universe are picked up by
+// the next frame.
+func (d *Device) Start(interval time.Duration) {
+	if d.running {
+		return
+	}
+	if shortest := FrameDuration(d.slots); interval < shortest {
+		interval = shortest
+	}
+	if interval > maxFrameInterval {
+		interval = maxFrameInterval
+	}
+	d.running = true
+	d.stop = make(chan struct{})
+	go d.run(interval, d.stop)
+}
+
+// Stop stops sending frames. Receivers hold their last values for a while
+// before they consider the signal lost.
+func (d *Device) Stop() {
+	if !d.running {
+		return
+	}
+	d.running = false
+	close(d.stop)
+}
+
+func (d *Device) run(interval time.Duration, stop chan struct{}) {
+	next := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		d.Send()
+		next = next.Add(interval)
+		wait := time.Until(next)
+		if wait <= 0 {
+			// Fell behind, restart the schedule from now.
+			next = time.Now()
+			runtime.Gosched()
+			continue
+		}
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,104 @@
+// Package dmx512 implements a DMX512 transmitter for stage lighting fixtures,
+// dimmers and other DMX controlled equipment, using a UART and an RS-485
+// transceiver.
+//
+// Specification: ANSI E1.11 (USITT DMX512-A)
+//
+// The break and mark-after-break are generated on the UART itself by sending
+// a zero byte at a lower baud rate, so no pin muxing is needed. The UART must
+// support changing the baud rate on the fly.
+package dmx512 // import "tinygo.org/x/drivers/dmx512"
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errInvalidChannel = errors.New("dmx512: channel out of range")
+	errInvalidSlots   = errors.New("dmx512: number of slots out of range")
+)
+
+const (
+	// Channels is the number of channels (slots) in a universe.
+	Channels = 512
+
+	// MinSlots is the smallest number of slots in a frame that keeps the
+	// frame long enough for all receivers.
+	MinSlots = 24
+
+	// StartCodeDimmer is the null start code used for dimmer and fixture
+	// data.
+	StartCodeDimmer = 0x00
+
+	baudRate = 250000
+
+	// A zero byte at this rate holds the line low for 9 bits (100µs, break
+	// minimum 88µs), followed by an 11µs stop bit (mark after break minimum
+	// 8µs).
+	breakBaudRate = 90000
+
+	// slotTime is the duration of one slot with 2 stop bits.
+	slotTime = 11 * time.Second / baudRate
+
+	// breakTime is the duration of the break byte including its stop bit.
+	breakTime = 10 * time.Second / breakBaudRate
+
+	// maxFrameInterval is the longest time between breaks allowed by the
+	// specification.
+	maxFrameInterval = time.Second
+)
+
+// Universe holds the start code and the values of the 512 channels.
+type Universe [1 + Channels]byte
+
+// Set sets channel ch (1-512) to value.
+func (u *Universe) Set(ch int, value byte) error {
+	if ch < 1 || ch > Channels {
+		return errInvalidChannel
+	}
+	u[ch] = value
+	return nil
+}
+
+// Get returns the value of channel ch (1-512), or 0 if ch is out of range.
+func (u *Universe) Get(ch int) byte {
+	if ch < 1 || ch > Channels {
+		return 0
+	}
+	return u[ch]
+}
+
+// SetRange copies values into consecutive channels starting at ch (1-512).
+func (u *Universe) SetRange(ch int, values []byte) error {
+	if ch < 1 || ch-1+len(values) > Channels {
+		return errInvalidChannel
+	}
+	copy(u[ch:], values)
+	return nil
+}
+
+// Clear sets all channels to 0.
+func (u *Universe) Clear() {
+	for i := 1; i < len(u); i++ {
+		u[i] = 0
+	}
+}
+
+// Config holds the transmitter configuration.
+type Config struct {
+	// Slots is the number of channels sent in each frame, MinSlots to
+	// Channels. Sending fewer slots allows a higher refresh rate when only
+	// the first channels are used. Defaults to Channels.
+	Slots int
+
+	// StartCode is sent before the channel data. Defaults to
+	// StartCodeDimmer.
+	StartCode byte
+}
+
+// FrameDuration returns how long it takes to send a frame with the given
+// number of slots, including the break, mark after break and start code.
+func FrameDuration(slots int) time.Duration {
+	return breakTime + time.Duration(1+slots)*slotTime
+}
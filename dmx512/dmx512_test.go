@@ -0,0 +1,33 @@
+package dmx512
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestUniverse(t *testing.T) {
+	c := qt.New(t)
+	var u Universe
+	c.Assert(u.Set(1, 10), qt.IsNil)
+	c.Assert(u.Set(512, 20), qt.IsNil)
+	c.Assert(u.Set(0, 1), qt.Equals, errInvalidChannel)
+	c.Assert(u.Set(513, 1), qt.Equals, errInvalidChannel)
+	c.Assert(u[0], qt.Equals, byte(0))
+	c.Assert(u.Get(1), qt.Equals, byte(10))
+	c.Assert(u.Get(512), qt.Equals, byte(20))
+
+	c.Assert(u.SetRange(510, []byte{1, 2, 3}), qt.IsNil)
+	c.Assert(u.SetRange(511, []byte{1, 2, 3}), qt.Equals, errInvalidChannel)
+	c.Assert(u[510:], qt.DeepEquals, []byte{1, 2, 3})
+
+	u.Clear()
+	c.Assert(u, qt.Equals, Universe{})
+}
+
+func TestFrameDuration(t *testing.T) {
+	c := qt.New(t)
+	// a full universe refreshes at about 44Hz
+	c.Assert(FrameDuration(Channels), qt.Equals, 111111*time.Nanosecond+513*44*time.Microsecond)
+}
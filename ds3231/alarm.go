@@ -0,0 +1,237 @@
+package ds3231
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errInvalidAlarmMode = errors.New("ds3231: alarm mode not supported by this alarm")
+	errConversionBusy   = errors.New("ds3231: temperature conversion timeout")
+)
+
+// AlarmMode selects which fields of the alarm time must match the current
+// time for the alarm to trigger.
+type AlarmMode uint8
+
+const (
+	// AlarmEverySecond triggers once per second (alarm 1 only).
+	AlarmEverySecond AlarmMode = iota
+	// AlarmEveryMinute triggers once per minute at 00 seconds (alarm 2 only).
+	AlarmEveryMinute
+	// AlarmMatchSeconds triggers when the seconds match (alarm 1 only).
+	AlarmMatchSeconds
+	// AlarmMatchMinutes triggers when the minutes (and seconds) match.
+	AlarmMatchMinutes
+	// AlarmMatchHours triggers when the hours, minutes (and seconds) match.
+	AlarmMatchHours
+	// AlarmMatchDate triggers when the day of the month and time match.
+	AlarmMatchDate
+	// AlarmMatchWeekday triggers when the day of the week and time match.
+	AlarmMatchWeekday
+)
+
+// SQWRate is the frequency of the square wave on the INT/SQW pin.
+type SQWRate uint8
+
+const (
+	SQW1Hz    SQWRate = 0
+	SQW1024Hz SQWRate = 1
+	SQW4096Hz SQWRate = 2
+	SQW8192Hz SQWRate = 3
+)
+
+// Alarm register bits.
+const (
+	alarmMask = 1 << 7 // AxMx: ignore this field when matching
+	alarmDY   = 1 << 6 // match day of week rather than date
+)
+
+// SetAlarm1 sets alarm 1 to the second, minute, hour and day of t, matched
+// according to mode. The day of the week is stored in the same way as
+// SetTime, from 1 (Monday) to 7 (Sunday). The alarm flag is cleared.
+func (d *Device) SetAlarm1(t time.Time, mode AlarmMode) error {
+	data := []byte{
+		uint8ToBCD(uint8(t.Second())),
+		uint8ToBCD(uint8(t.Minute())),
+		uint8ToBCD(uint8(t.Hour())),
+		uint8ToBCD(uint8(t.Day())),
+	}
+	switch mode {
+	case AlarmEverySecond:
+		data[0] |= alarmMask
+		fallthrough
+	case AlarmMatchSeconds:
+		data[1] |= alarmMask
+		fallthrough
+	case AlarmMatchMinutes:
+		data[2] |= alarmMask
+		fallthrough
+	case AlarmMatchHours:
+		data[3] |= alarmMask
+	case AlarmMatchDate:
+	case AlarmMatchWeekday:
+		data[3] = weekday(t) | alarmDY
+	default:
+		return errInvalidAlarmMode
+	}
+	err := d.bus.WriteRegister(uint8(d.Address), REG_ALARMONE, data)
+	if err != nil {
+		return err
+	}
+	return d.ClearAlarmFlags(AlarmFlag_Alarm1)
+}
+
+// SetAlarm2 sets alarm 2 to the minute, hour and day of t, matched according
+// to mode. Alarm 2 has no seconds and triggers at 00 seconds. The alarm flag
+// is cleared.
+func (d *Device) SetAlarm2(t time.Time, mode AlarmMode) error {
+	data := []byte{
+		uint8ToBCD(uint8(t.Minute())),
+		uint8ToBCD(uint8(t.Hour())),
+		uint8ToBCD(uint8(t.Day())),
+	}
+	switch mode {
+	case AlarmEveryMinute:
+		data[0] |= alarmMask
+		fallthrough
+	case AlarmMatchMinutes:
+		data[1] |= alarmMask
+		fallthrough
+	case AlarmMatchHours:
+		data[2] |= alarmMask
+	case AlarmMatchDate:
+	case AlarmMatchWeekday:
+		data[2] = weekday(t) | alarmDY
+	default:
+		return errInvalidAlarmMode
+	}
+	err := d.bus.WriteRegister(uint8(d.Address), REG_ALARMTWO, data)
+	if err != nil {
+		return err
+	}
+	return d.ClearAlarmFlags(AlarmFlag_Alarm2)
+}
+
+// SetAlarmInterrupts enables the alarm interrupt on the INT/SQW pin for the
+// alarms in flags (AlarmFlag_Alarm1, AlarmFlag_Alarm2 or both) and disables
+// it for the others. The pin is active-low and stays low until the flags are
+// cleared. Enabling any alarm interrupt stops the square wave output.
+func (d *Device) SetAlarmInterrupts(flags uint8) error {
+	flags &= AlarmFlag_AlarmBoth
+	set := flags << A1IE
+	if flags != 0 {
+		set |= 1 << INTCN
+	}
+	return d.updateRegister(REG_CONTROL, 1<<A1IE|1<<A2IE, set)
+}
+
+// ReadAlarmFlags returns the alarms that have triggered since their flags
+// were last cleared, as AlarmFlag_Alarm1 and AlarmFlag_Alarm2 bits.
+func (d *Device) ReadAlarmFlags() (uint8, error) {
+	data := []byte{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
+	return data[0] & AlarmFlag_AlarmBoth, err
+}
+
+// ClearAlarmFlags clears the flags of the given alarms, which releases the
+// INT/SQW pin.
+func (d *Device) ClearAlarmFlags(flags uint8) error {
+	return d.updateRegister(REG_STATUS, (flags&AlarmFlag_AlarmBoth)<<A1F, 0)
+}
+
+// SetSquareWave outputs a square wave with the given rate on the INT/SQW pin,
+// which disables the alarm interrupt output. If battery is set the square
+// wave keeps running on battery backup.
+func (d *Device) SetSquareWave(rate SQWRate, battery bool) error {
+	set := uint8(rate&3) << RS1
+	if battery {
+		set |= 1 << BBSQW
+	}
+	return d.updateRegister(REG_CONTROL, 1<<INTCN|3<<RS1|1<<BBSQW, set)
+}
+
+// DisableSquareWave switches the INT/SQW pin back to alarm interrupts.
+func (d *Device) DisableSquareWave() error {
+	return d.updateRegister(REG_CONTROL, 1<<BBSQW, 1<<INTCN)
+}
+
+// Set32kHzOutput enables or disables the 32kHz output pin.
+func (d *Device) Set32kHzOutput(enable bool) error {
+	if enable {
+		return d.updateRegister(REG_STATUS, 0, 1<<EN32KHZ)
+	}
+	return d.updateRegister(REG_STATUS, 1<<EN32KHZ, 0)
+}
+
+// SetAgingOffset trims the oscillator frequency. Each step is about 0.1ppm,
+// positive values slow the clock down. A temperature conversion is started
+// so the new offset applies right away.
+func (d *Device) SetAgingOffset(offset int8) error {
+	err := d.bus.WriteRegister(uint8(d.Address), REG_AGING, []byte{uint8(offset)})
+	if err != nil {
+		return err
+	}
+	return d.startConversion()
+}
+
+// ReadAgingOffset returns the oscillator trim set by SetAgingOffset.
+func (d *Device) ReadAgingOffset() (int8, error) {
+	data := []byte{0}
+	err := d.bus.ReadRegister(uint8(d.Address), REG_AGING, data)
+	return int8(data[0]), err
+}
+
+// ConvertTemperature forces a temperature conversion and waits for it to
+// finish, so that ReadTemperature returns a fresh value. The device converts
+// on its own every 64 seconds.
+func (d *Device) ConvertTemperature() error {
+	if err := d.startConversion(); err != nil {
+		return err
+	}
+	return d.waitNotBusy(REG_CONTROL, 1<<CONV)
+}
+
+// startConversion starts a temperature conversion once the device is not
+// busy with its own.
+func (d *Device) startConversion() error {
+	if err := d.waitNotBusy(REG_STATUS, 1<<BSY); err != nil {
+		return err
+	}
+	return d.updateRegister(REG_CONTROL, 0, 1<<CONV)
+}
+
+// waitNotBusy waits for bit in reg to clear. A conversion takes up to 200ms.
+func (d *Device) waitNotBusy(reg, bit uint8) error {
+	data := []byte{0}
+	for i := 0; i < 25; i++ {
+		err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+		if err != nil {
+			return err
+		}
+		if data[0]&bit == 0 {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return errConversionBusy
+}
+
+// updateRegister clears and then sets bits of a register.
+func (d *Device) updateRegister(reg, clear, set uint8) error {
+	data := []byte{0}
+	err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+	if err != nil {
+		return err
+	}
+	data[0] = data[0]&^clear | set
+	return d.bus.WriteRegister(uint8(d.Address), reg, data)
+}
+
+// weekday returns the day of the week of t, 1 (Monday) to 7 (Sunday).
+func weekday(t time.Time) uint8 {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return uint8(t.Weekday())
+}
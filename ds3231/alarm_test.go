@@ -0,0 +1,74 @@
+package ds3231
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestSetAlarm1(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	fdev.Registers[REG_STATUS] = 1<<A1F | 1<<A2F
+	d := New(bus)
+
+	// Sunday 2024-03-10 07:30:15
+	at := time.Date(2024, 3, 10, 7, 30, 15, 0, time.UTC)
+	c.Assert(d.SetAlarm1(at, AlarmMatchHours), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARMONE:REG_ALARMONE+4], qt.DeepEquals, []uint8{0x15, 0x30, 0x07, 0x80 | 0x10})
+	c.Assert(fdev.Registers[REG_STATUS], qt.Equals, uint8(1<<A2F))
+
+	c.Assert(d.SetAlarm1(at, AlarmMatchWeekday), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARMONE+3], qt.Equals, uint8(0x40|7))
+	// the same day of the week as SetTime
+	c.Assert(d.SetTime(at), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMEDATE+3], qt.Equals, uint8(7))
+
+	c.Assert(d.SetAlarm1(at, AlarmEverySecond), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARMONE:REG_ALARMONE+4], qt.DeepEquals, []uint8{0x95, 0xB0, 0x87, 0x90})
+
+	c.Assert(d.SetAlarm1(at, AlarmEveryMinute), qt.Equals, errInvalidAlarmMode)
+}
+
+func TestSetAlarm2(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	d := New(bus)
+
+	at := time.Date(2024, 3, 12, 22, 45, 0, 0, time.UTC)
+	c.Assert(d.SetAlarm2(at, AlarmMatchDate), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARMTWO:REG_ALARMTWO+3], qt.DeepEquals, []uint8{0x45, 0x22, 0x12})
+
+	c.Assert(d.SetAlarm2(at, AlarmEveryMinute), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARMTWO:REG_ALARMTWO+3], qt.DeepEquals, []uint8{0xC5, 0xA2, 0x92})
+
+	c.Assert(d.SetAlarm2(at, AlarmMatchSeconds), qt.Equals, errInvalidAlarmMode)
+}
+
+func TestControlBits(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	// power-on default: INTCN set, alarms disabled
+	fdev.Registers[REG_CONTROL] = 1 << INTCN
+	d := New(bus)
+
+	c.Assert(d.SetAlarmInterrupts(AlarmFlag_Alarm2), qt.IsNil)
+	c.Assert(fdev.Registers[REG_CONTROL], qt.Equals, uint8(1<<INTCN|1<<A2IE))
+
+	c.Assert(d.SetSquareWave(SQW4096Hz, true), qt.IsNil)
+	c.Assert(fdev.Registers[REG_CONTROL], qt.Equals, uint8(1<<BBSQW|1<<RS2|1<<A2IE))
+
+	c.Assert(d.DisableSquareWave(), qt.IsNil)
+	c.Assert(fdev.Registers[REG_CONTROL], qt.Equals, uint8(1<<INTCN|1<<RS2|1<<A2IE))
+
+	c.Assert(d.SetAgingOffset(-3), qt.IsNil)
+	offset, err := d.ReadAgingOffset()
+	c.Assert(err, qt.IsNil)
+	c.Assert(offset, qt.Equals, int8(-3))
+}
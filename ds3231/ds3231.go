@@ -84,6 +84,9 @@ func (d *Device) SetRunning(isRunning bool) error {
 // flag in its leap year calculation, so it will incorrectly identify the year
 // 2100 as a leap year, causing it to increment from 2100-02-28 to 2100-02-29
 // instead of 2100-03-01.
+//
+// The day of the week is stored from 1 (Monday) to 7 (Sunday), the way
+// alarms with AlarmMatchWeekday match it.
 func (d *Device) SetTime(dt time.Time) error {
 	data := []byte{0}
 	err := d.bus.ReadRegister(uint8(d.Address), REG_STATUS, data)
@@ -112,7 +115,7 @@ func (d *Device) SetTime(dt time.Time) error {
 		centuryFlag = 1 << 7
 	}
 
-	data[3] = uint8ToBCD(uint8(dt.Weekday()))
+	data[3] = weekday(dt)
 	data[4] = uint8ToBCD(uint8(dt.Day()))
 	data[5] = uint8ToBCD(uint8(dt.Month()) | centuryFlag)
 	data[6] = uint8ToBCD(year)
@@ -18,18 +18,20 @@
 package espat // import "tinygo.org/x/drivers/espat"
 
 import (
+	"bytes"
 	"errors"
 	"strconv"
-	"strings"
 	"time"
 
 	"tinygo.org/x/drivers"
 	"tinygo.org/x/drivers/net"
+	"tinygo.org/x/drivers/uartbuf"
 )
 
 // Device wraps UART connection to the ESP8266/ESP32.
 type Device struct {
 	bus drivers.UART
+	rx  *uartbuf.Reader
 
 	// command responses that come back from the ESP8266/ESP32
 	response []byte
@@ -43,7 +45,12 @@ var ActiveDevice *Device
 
 // New returns a new espat driver. Pass in a fully configured UART bus.
 func New(b drivers.UART) *Device {
-	return &Device{bus: b, response: make([]byte, 512), socketdata: make([]byte, 0, 1024)}
+	return &Device{
+		bus:        b,
+		rx:         uartbuf.NewReader(b, 256),
+		response:   make([]byte, 512),
+		socketdata: make([]byte, 0, 1024),
+	}
 }
 
 // Configure sets up the device for communication.
@@ -71,7 +78,7 @@ func (d *Device) Write(b []byte) (n int, err error) {
 
 // Read raw bytes from the UART.
 func (d *Device) Read(b []byte) (n int, err error) {
-	return d.bus.Read(b)
+	return d.rx.Read(b)
 }
 
 // how long in milliseconds to pause after sending AT commands
@@ -147,75 +154,90 @@ func (d *Device) ReadSocket(b []byte) (n int, err error) {
 	return count, nil
 }
 
-// Response gets the next response bytes from the ESP8266/ESP32.
-// The call will retry for up to timeout milliseconds before returning nothing.
+// Response gets the next response bytes from the ESP8266/ESP32, up to the
+// final "OK", "ERROR" or "FAIL" line or the ">" prompt to send data. If socket
+// data ("+IPD") arrives first, it is stored for ReadSocket and nil is
+// returned.
+// The call will wait for up to timeout milliseconds before returning nothing.
 func (d *Device) Response(timeout int) ([]byte, error) {
-	// read data
-	var size int
-	var start, end int
-	pause := 100 // pause to wait for 100 ms
-	retries := timeout / pause
-
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+	var end, lineStart int
 	for {
-		size = d.bus.Buffered()
-
-		if size > 0 {
-			end += size
-			d.bus.Read(d.response[start:end])
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, errors.New("response timeout error:" + string(d.response[:end]))
+		}
+		d.rx.Timeout = remaining
+		data, err := d.rx.ReadUntilAny("\n:>")
+		switch err {
+		case nil, uartbuf.ErrBufferFull:
+		case uartbuf.ErrTimeout:
+			return nil, errors.New("response timeout error:" + string(d.response[:end]))
+		default:
+			return nil, err
+		}
+		if end+len(data) > len(d.response) {
+			// response too long, only keep the current line
+			end = copy(d.response, d.response[lineStart:end])
+			lineStart = 0
+		}
+		end += copy(d.response[end:], data)
+		line := d.response[lineStart:end]
 
-			// if "+IPD" then read socket data
-			if strings.Contains(string(d.response[:end]), "+IPD") {
+		switch line[len(line)-1] {
+		case ':':
+			if bytes.HasPrefix(line, []byte("+IPD,")) {
 				// handle socket data
-				return nil, d.parseIPD(end)
+				return nil, d.readIPD(line)
 			}
-
-			// if "OK" then the command worked
-			if strings.Contains(string(d.response[:end]), "OK") {
-				return d.response[start:end], nil
+		case '>':
+			if len(line) == 1 {
+				// ready to receive data
+				return d.response[:end], nil
 			}
-
-			// if "Error" then the command failed
-			if strings.Contains(string(d.response[:end]), "ERROR") {
-				return d.response[start:end], errors.New("response error:" + string(d.response[start:end]))
+		case '\n':
+			line = bytes.TrimSpace(line)
+			switch {
+			case bytes.HasSuffix(line, []byte("OK")):
+				// the command worked
+				return d.response[:end], nil
+			case bytes.Equal(line, []byte("ERROR")), bytes.Equal(line, []byte("FAIL")):
+				// the command failed
+				return d.response[:end], errors.New("response error:" + string(d.response[:end]))
 			}
-
-			// if anything else, then keep reading data in?
-			start = end
-		}
-
-		// wait longer?
-		retries--
-		if retries == 0 {
-			return nil, errors.New("response timeout error:" + string(d.response[start:end]))
+			lineStart = end
 		}
-
-		time.Sleep(time.Duration(pause) * time.Millisecond)
 	}
 }
 
-func (d *Device) parseIPD(end int) error {
-	// find the "+IPD," to get length
-	s := strings.Index(string(d.response[:end]), "+IPD,")
-
-	// find the ":"
-	e := strings.Index(string(d.response[:end]), ":")
-
-	// find the data length
-	val := string(d.response[s+5 : e])
-
-	// TODO: verify count
-	_, err := strconv.Atoi(val)
+// readIPD reads the socket data announced by a "+IPD,[<id>,]<length>:" header.
+func (d *Device) readIPD(header []byte) error {
+	// the length is the last field of the header
+	fields := header[5 : len(header)-1]
+	if i := bytes.LastIndexByte(fields, ','); i >= 0 {
+		fields = fields[i+1:]
+	}
+	count, err := strconv.Atoi(string(fields))
 	if err != nil {
 		// not expected data here. what to do?
 		return err
 	}
 
 	// load up the socket data
-	d.socketdata = append(d.socketdata, d.response[e+1:end]...)
-	return nil
+	start := len(d.socketdata)
+	if start+count <= cap(d.socketdata) {
+		d.socketdata = d.socketdata[:start+count]
+	} else {
+		d.socketdata = append(d.socketdata, make([]byte, count)...)
+	}
+	err = d.rx.ReadFull(d.socketdata[start:])
+	if err != nil {
+		d.socketdata = d.socketdata[:start]
+	}
+	return err
 }
 
 // IsSocketDataAvailable returns of there is socket data available
 func (d *Device) IsSocketDataAvailable() bool {
-	return len(d.socketdata) > 0 || d.bus.Buffered() > 0
+	return len(d.socketdata) > 0 || d.rx.Buffered() > 0
 }
@@ -0,0 +1,64 @@
+package espat
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeUART records everything written to it and replays canned responses.
+type fakeUART struct {
+	tx bytes.Buffer
+	rx bytes.Buffer
+}
+
+func (u *fakeUART) Read(b []byte) (int, error)  { return u.rx.Read(b) }
+func (u *fakeUART) Write(b []byte) (int, error) { return u.tx.Write(b) }
+func (u *fakeUART) Buffered() int               { return u.rx.Len() }
+
+func TestResponse(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := New(uart)
+
+	uart.rx.WriteString("AT+CIFSR\r\n+CIFSR:STAIP,\"192.168.1.2\"\r\n\r\nOK\r\nrest")
+	r, err := d.Response(100)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(r), qt.Equals, "AT+CIFSR\r\n+CIFSR:STAIP,\"192.168.1.2\"\r\n\r\nOK\r\n")
+
+	uart.rx.WriteString("\r\nERROR\r\n")
+	r, err = d.Response(100)
+	c.Assert(err, qt.ErrorMatches, "response error:rest\r\nERROR\r\n")
+	c.Assert(string(r), qt.Equals, "rest\r\nERROR\r\n")
+
+	_, err = d.Response(10)
+	c.Assert(err, qt.ErrorMatches, "response timeout error:")
+}
+
+func TestResponseSocketData(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := New(uart)
+
+	uart.rx.WriteString("\r\n+IPD,7:ab\r\ncdeCLOSED\r\n")
+	r, err := d.Response(100)
+	c.Assert(err, qt.IsNil)
+	c.Assert(r, qt.IsNil)
+	c.Assert(d.IsSocketDataAvailable(), qt.IsTrue)
+
+	b := make([]byte, 16)
+	n, err := d.ReadSocket(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b[:n]), qt.Equals, "ab\r\ncde")
+}
+
+func TestStartSocketSend(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := New(uart)
+
+	uart.rx.WriteString("AT+CIPSEND=4\r\n\r\nOK\r\n> ")
+	c.Assert(d.StartSocketSend(4), qt.IsNil)
+	c.Assert(uart.tx.String(), qt.Equals, "AT+CIPSEND=4\r\n")
+}
@@ -1,6 +1,7 @@
 package espat
 
 import (
+	"bytes"
 	"errors"
 	"strconv"
 	"strings"
@@ -128,10 +129,14 @@ func (d *Device) StartSocketSend(size int) error {
 	// when ">" is received, it indicates
 	// ready to receive data
 	r, err := d.Response(2000)
+	if err == nil && !bytes.Contains(r, []byte(">")) {
+		// the prompt follows the "OK"
+		r, err = d.Response(2000)
+	}
 	if err != nil {
 		return err
 	}
-	if strings.Contains(string(r), ">") {
+	if bytes.Contains(r, []byte(">")) {
 		return nil
 	}
 	return errors.New("StartSocketSend error:" + string(r))
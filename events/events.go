@@ -0,0 +1,170 @@
+// Package events provides a common way for drivers to report hardware
+// interrupts, like data-ready, threshold, touch or received IR commands, as
+// typed events.
+//
+// Interrupt handlers must be short and must not allocate, block or use a bus.
+// They Post an Event to a Dispatcher, which is safe to call from interrupt
+// context. The application calls Dispatch from its main loop (or a
+// goroutine), which runs the handlers subscribed to each pending event outside
+// of interrupt context, where they may read the device that raised it.
+package events // import "tinygo.org/x/drivers/events"
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the type of an event.
+type Kind uint8
+
+// Event kinds raised by drivers. Applications may define their own kinds
+// starting at KindUser.
+const (
+	KindNone  Kind = iota
+	DataReady      // new measurement available
+	Threshold      // value crossed a configured threshold
+	Motion         // motion or free-fall detected
+	Click          // single or double tap
+	Touch          // touch pressed or released
+	Gesture        // gesture detected
+	IRCommand      // infrared command received, Value holds the code
+	KindUser  Kind = 128
+)
+
+// maxSubscribed is the number of handlers a Dispatcher can hold.
+const maxSubscribed = 8
+
+// Event is a single hardware event.
+type Event struct {
+	Kind Kind
+
+	// Source identifies the device that raised the event, when several
+	// devices post to the same Dispatcher. It is chosen by the application.
+	Source uint8
+
+	// Flags and Value carry driver specific data, documented by the driver
+	// that posts the event.
+	Flags uint16
+	Value uint32
+}
+
+// Handler handles an event outside of interrupt context.
+type Handler func(Event)
+
+type subscription struct {
+	kind    Kind
+	handler Handler
+}
+
+type slot struct {
+	seq   uint32
+	event Event
+}
+
+// Dispatcher is a fixed size queue of events between interrupt handlers and
+// the main loop. Post may be called concurrently from several interrupt
+// handlers, Dispatch from a single goroutine.
+type Dispatcher struct {
+	slots   []slot
+	mask    uint32 // len(slots)-1, a power of two so counters wrap with the index
+	head    uint32 // next slot to post to
+	tail    uint32 // next slot to dispatch
+	dropped uint32
+	subs    [maxSubscribed]subscription
+	nsubs   int
+}
+
+// New returns a dispatcher that can hold size pending events, rounded up to
+// a power of two.
+func New(size int) *Dispatcher {
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	d := &Dispatcher{slots: make([]slot, n), mask: uint32(n - 1)}
+	for i := range d.slots {
+		d.slots[i].seq = uint32(i)
+	}
+	return d
+}
+
+// Subscribe registers handler for events of the given kind, or for all kinds
+// if kind is KindNone. Handlers run in the order they were subscribed. It
+// returns false if too many handlers are subscribed. Subscribe must not be
+// called from interrupt context.
+func (d *Dispatcher) Subscribe(kind Kind, handler Handler) bool {
+	if d.nsubs == len(d.subs) {
+		return false
+	}
+	d.subs[d.nsubs] = subscription{kind: kind, handler: handler}
+	d.nsubs++
+	return true
+}
+
+// Post queues an event. It is safe to call from interrupt context. It returns
+// false and counts the event as dropped if the queue is full.
+func (d *Dispatcher) Post(e Event) bool {
+	for {
+		pos := atomic.LoadUint32(&d.head)
+		s := &d.slots[pos&d.mask]
+		seq := atomic.LoadUint32(&s.seq)
+		switch diff := int32(seq - pos); {
+		case diff == 0:
+			if atomic.CompareAndSwapUint32(&d.head, pos, pos+1) {
+				s.event = e
+				atomic.StoreUint32(&s.seq, pos+1)
+				return true
+			}
+		case diff < 0:
+			atomic.AddUint32(&d.dropped, 1)
+			return false
+		}
+		// another handler posted in the meantime, retry
+	}
+}
+
+// next removes the oldest event from the queue.
+func (d *Dispatcher) next() (Event, bool) {
+	s := &d.slots[d.tail&d.mask]
+	if int32(atomic.LoadUint32(&s.seq)-(d.tail+1)) < 0 {
+		return Event{}, false
+	}
+	e := s.event
+	atomic.StoreUint32(&s.seq, d.tail+d.mask+1)
+	d.tail++
+	return e, true
+}
+
+// Dispatch runs the subscribed handlers for all pending events and returns
+// the number of events dispatched.
+func (d *Dispatcher) Dispatch() int {
+	n := 0
+	for {
+		e, ok := d.next()
+		if !ok {
+			return n
+		}
+		n++
+		for _, s := range d.subs[:d.nsubs] {
+			if s.kind == KindNone || s.kind == e.Kind {
+				s.handler(e)
+			}
+		}
+	}
+}
+
+// Run dispatches events forever, polling the queue at the given interval
+// while it is empty. Start it in its own goroutine, or call Dispatch from
+// the main loop instead.
+func (d *Dispatcher) Run(interval time.Duration) {
+	for {
+		if d.Dispatch() == 0 {
+			time.Sleep(interval)
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+func (d *Dispatcher) Dropped() uint32 {
+	return atomic.LoadUint32(&d.dropped)
+}
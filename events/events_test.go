@@ -0,0 +1,85 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDispatch(t *testing.T) {
+	c := qt.New(t)
+	d := New(4)
+	var ready, all []Event
+	c.Assert(d.Subscribe(DataReady, func(e Event) { ready = append(ready, e) }), qt.IsTrue)
+	c.Assert(d.Subscribe(KindNone, func(e Event) { all = append(all, e) }), qt.IsTrue)
+
+	c.Assert(d.Post(Event{Kind: DataReady, Source: 1}), qt.IsTrue)
+	c.Assert(d.Post(Event{Kind: Threshold, Value: 42}), qt.IsTrue)
+	c.Assert(d.Dispatch(), qt.Equals, 2)
+	c.Assert(ready, qt.DeepEquals, []Event{{Kind: DataReady, Source: 1}})
+	c.Assert(all, qt.HasLen, 2)
+	c.Assert(all[1].Value, qt.Equals, uint32(42))
+	c.Assert(d.Dispatch(), qt.Equals, 0)
+}
+
+func TestDropped(t *testing.T) {
+	c := qt.New(t)
+	d := New(2)
+	// wrap around the queue a few times
+	for i := 0; i < 5; i++ {
+		c.Assert(d.Post(Event{Kind: Touch}), qt.IsTrue)
+		c.Assert(d.Post(Event{Kind: Touch}), qt.IsTrue)
+		c.Assert(d.Post(Event{Kind: Touch}), qt.IsFalse)
+		c.Assert(d.Dispatch(), qt.Equals, 2)
+	}
+	c.Assert(d.Dropped(), qt.Equals, uint32(5))
+}
+
+func TestCounterWrap(t *testing.T) {
+	c := qt.New(t)
+	d := New(3)
+	c.Assert(d.slots, qt.HasLen, 4)
+	// start just before the counters wrap
+	start := ^uint32(0) - 5
+	d.head, d.tail = start, start
+	for pos := start; pos != start+4; pos++ {
+		d.slots[pos&d.mask].seq = pos
+	}
+	var values []uint32
+	d.Subscribe(KindNone, func(e Event) { values = append(values, e.Value) })
+	for i := uint32(0); i < 5; i++ {
+		c.Assert(d.Post(Event{Value: 2 * i}), qt.IsTrue)
+		c.Assert(d.Post(Event{Value: 2*i + 1}), qt.IsTrue)
+		c.Assert(d.Dispatch(), qt.Equals, 2)
+	}
+	c.Assert(values, qt.DeepEquals, []uint32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	c.Assert(d.Dropped(), qt.Equals, uint32(0))
+}
+
+func TestConcurrentPost(t *testing.T) {
+	c := qt.New(t)
+	d := New(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(source uint8) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				d.Post(Event{Kind: DataReady, Source: source, Value: uint32(j)})
+			}
+		}(uint8(i))
+	}
+	wg.Wait()
+
+	last := map[uint8]uint32{}
+	n := 0
+	d.Subscribe(KindNone, func(e Event) {
+		if n := last[e.Source]; e.Value != 0 {
+			c.Assert(e.Value, qt.Equals, n+1)
+		}
+		last[e.Source] = e.Value
+	})
+	n = d.Dispatch()
+	c.Assert(n, qt.Equals, 800)
+}
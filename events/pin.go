@@ -0,0 +1,13 @@
+//go:build tinygo
+
+package events
+
+import "machine"
+
+// Attach posts e whenever the given change happens on pin, for drivers that
+// signal events on an interrupt pin. The pin must be configured as an input.
+func (d *Dispatcher) Attach(pin machine.Pin, change machine.PinChange, e Event) error {
+	return pin.SetInterrupt(change, func(machine.Pin) {
+		d.Post(e)
+	})
+}
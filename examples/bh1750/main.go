@@ -15,7 +15,7 @@ func main() {
 
 	for {
 		lux := sensor.Illuminance()
-		println("Illuminance:", lux, "lx")
+		println("Illuminance:", lux.String())
 
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -22,9 +22,9 @@ func main() {
 
 	for {
 		temp, _ := sensor.ReadTemperature()
-		println("Temperature:", strconv.FormatFloat(float64(temp)/1000, 'f', 2, 64), "°C")
+		println("Temperature:", temp.String())
 		press, _ := sensor.ReadPressure()
-		println("Pressure:", strconv.FormatFloat(float64(press)/100000, 'f', 2, 64), "hPa")
+		println("Pressure:", press.String())
 		hum, _ := sensor.ReadHumidity()
 		println("Humidity:", strconv.FormatFloat(float64(hum)/100, 'f', 2, 64), "%")
 		alt, _ := sensor.ReadAltitude()
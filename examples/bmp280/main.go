@@ -30,14 +30,14 @@ func main() {
 			println("Error reading temperature")
 		}
 		// Temperature in degrees Celsius
-		fmt.Printf("Temperature: %.2f °C\n", float32(t)/1000)
+		fmt.Printf("Temperature: %.2f °C\n", t.Celsius())
 
 		p, err := sensor.ReadPressure()
 		if err != nil {
 			println("Error reading pressure")
 		}
 		// Pressure in hectoPascal
-		fmt.Printf("Pressure: %.2f hPa\n", float32(p)/100000)
+		fmt.Printf("Pressure: %.2f hPa\n", p.Hectopascal())
 
 		time.Sleep(5 * time.Second)
 	}
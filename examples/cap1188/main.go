@@ -0,0 +1,39 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/cap1188"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	sensor := cap1188.New(machine.I2C0)
+	err := sensor.Configure(cap1188.Config{
+		Sensitivity: cap1188.Sensitivity32X,
+		MaxTouches:  2,
+		LinkLEDs:    true,
+	})
+	if err != nil {
+		println(err.Error())
+		return
+	}
+	if err := sensor.ConfigureInterrupt(machine.D2); err != nil {
+		println("no interrupt, polling:", err.Error())
+	}
+	sensor.SetTouchHandler(func(input uint8, touched bool) {
+		if touched {
+			println("touched", input+1)
+		} else {
+			println("released", input+1)
+		}
+	})
+
+	for {
+		if err := sensor.Poll(); err != nil {
+			println("read error:", err.Error())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
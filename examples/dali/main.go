@@ -0,0 +1,35 @@
+// Addresses all unaddressed LED drivers on the bus, then dims them up and
+// down together.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/dali"
+)
+
+func main() {
+	bus := dali.New(machine.D2, machine.D3)
+	bus.Configure(dali.Config{InvertTX: true})
+
+	n, err := bus.Commission(false, 0)
+	if err != nil {
+		println("commission:", err.Error())
+	}
+	println("addressed", n, "new control gear")
+
+	for a := uint8(0); a < 64; a++ {
+		level, ok, err := bus.QueryCommand(dali.ShortAddress(a), dali.CmdQueryActualLevel)
+		if err == nil && ok {
+			println("gear", a, "level", level)
+		}
+	}
+
+	for {
+		bus.DirectArcPower(dali.Broadcast, 254)
+		time.Sleep(2 * time.Second)
+		bus.DirectArcPower(dali.Broadcast, 85)
+		time.Sleep(2 * time.Second)
+	}
+}
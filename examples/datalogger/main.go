@@ -0,0 +1,64 @@
+// Logs the temperature, humidity and pressure of a BME280 to an SD card every
+// 10 seconds. Press enter on the serial console to print the log as CSV.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/datalogger"
+	"tinygo.org/x/drivers/sdcard"
+)
+
+func main() {
+	time.Sleep(2 * time.Second)
+
+	machine.I2C0.Configure(machine.I2CConfig{})
+	sensor := bme280.New(machine.I2C0)
+	sensor.Configure()
+
+	sd := sdcard.New(&machine.SPI0, machine.SPI0_SCK_PIN, machine.SPI0_SDO_PIN, machine.SPI0_SDI_PIN, machine.D10)
+	if err := sd.Configure(); err != nil {
+		println("sdcard:", err.Error())
+		return
+	}
+
+	logger := datalogger.New(&sd, []datalogger.Column{
+		{Name: "temperature", Value: func() int32 {
+			t, _ := sensor.ReadTemperature()
+			return int32(t)
+		}},
+		{Name: "humidity", Value: func() int32 {
+			h, _ := sensor.ReadHumidity()
+			return h
+		}},
+		{Name: "pressure", Value: func() int32 {
+			p, _ := sensor.ReadPressure()
+			return int32(p / 1000)
+		}},
+	})
+	err := logger.Configure(datalogger.Config{
+		Format:   datalogger.Binary,
+		Interval: 10 * time.Second,
+		// the first 64MB of the card
+		Length: 64 << 20,
+	})
+	if err != nil {
+		println("datalogger:", err.Error())
+		return
+	}
+	println("logging to file", logger.File())
+
+	for {
+		if err := logger.Poll(); err != nil {
+			println("datalogger:", err.Error())
+		}
+		if machine.Serial.Buffered() > 0 {
+			machine.Serial.ReadByte()
+			logger.Sync()
+			logger.Export(machine.Serial)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,37 @@
+// Fades an RGB fixture on channels 1-3 through red, green and blue.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/dmx512"
+)
+
+func main() {
+	uart := machine.UART1
+	uart.Configure(machine.UARTConfig{
+		BaudRate: 250000,
+		TX:       machine.UART_TX_PIN,
+		RX:       machine.UART_RX_PIN,
+	})
+
+	dmx := dmx512.New(uart, machine.D2)
+	err := dmx.Configure(dmx512.Config{})
+	if err != nil {
+		println(err.Error())
+		return
+	}
+	dmx.Start(25 * time.Millisecond)
+
+	for color := 1; ; color = color%3 + 1 {
+		for v := 0; v < 256; v++ {
+			dmx.Set(color, byte(v))
+			time.Sleep(5 * time.Millisecond)
+		}
+		for v := 255; v >= 0; v-- {
+			dmx.Set(color, byte(v))
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
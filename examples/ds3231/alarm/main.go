@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/ds3231"
+)
+
+// intPin is connected to INT/SQW on the DS3231, which is open-drain.
+const intPin = machine.D2
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+
+	rtc := ds3231.New(machine.I2C0)
+	rtc.Configure()
+	rtc.SetTime(time.Date(2019, 12, 5, 20, 34, 50, 0, time.UTC))
+
+	// Alarm 1 at every 30 seconds past the minute, alarm 2 every minute.
+	rtc.SetAlarm1(time.Date(2019, 12, 5, 0, 0, 30, 0, time.UTC), ds3231.AlarmMatchSeconds)
+	rtc.SetAlarm2(time.Time{}, ds3231.AlarmEveryMinute)
+	rtc.SetAlarmInterrupts(ds3231.AlarmFlag_AlarmBoth)
+
+	intPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+
+	for {
+		// INT/SQW stays low until the alarm flags are cleared.
+		if !intPin.Get() {
+			flags, err := rtc.ReadAlarmFlags()
+			if err != nil {
+				fmt.Println("Error reading alarms:", err)
+				continue
+			}
+			dt, _ := rtc.ReadTime()
+			if flags&ds3231.AlarmFlag_Alarm1 != 0 {
+				fmt.Printf("%02d:%02d:%02d alarm 1\r\n", dt.Hour(), dt.Minute(), dt.Second())
+			}
+			if flags&ds3231.AlarmFlag_Alarm2 != 0 {
+				fmt.Printf("%02d:%02d:%02d alarm 2\r\n", dt.Hour(), dt.Minute(), dt.Second())
+			}
+			rtc.ClearAlarmFlags(flags)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/events"
+	"tinygo.org/x/drivers/irremote"
+	"tinygo.org/x/drivers/lis3dh"
+)
+
+const (
+	sourceAccel = iota
+	sourceIR
+)
+
+func main() {
+	dispatcher := events.New(16)
+
+	machine.I2C0.Configure(machine.I2CConfig{SCL: machine.GP5, SDA: machine.GP4})
+	accel := lis3dh.New(machine.I2C0)
+	accel.Configure()
+	accel.EnableMotionInterrupt(500000, 2) // 0.5g
+
+	int1 := machine.GP6
+	int1.Configure(machine.PinConfig{Mode: machine.PinInputPulldown})
+	if err := dispatcher.Attach(int1, machine.PinRising, accel.InterruptEvent(sourceAccel)); err != nil {
+		println("could not attach INT1:", err.Error())
+	}
+
+	ir := irremote.NewReceiver(machine.GP3)
+	ir.Configure()
+	ir.SetDispatcher(dispatcher, sourceIR)
+
+	dispatcher.Subscribe(events.Motion, func(e events.Event) {
+		// outside of interrupt context, so the bus may be used
+		src := accel.ReadInterruptSource()
+		x, y, z, _ := accel.ReadAcceleration()
+		println("motion", src, x, y, z)
+	})
+	dispatcher.Subscribe(events.IRCommand, func(e events.Event) {
+		data := irremote.DataFromEvent(e)
+		println("ir", data.Address, data.Command, data.Flags&irremote.DataFlagIsRepeat != 0)
+	})
+
+	for {
+		dispatcher.Dispatch()
+		if n := dispatcher.Dropped(); n > 0 {
+			println("dropped events:", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
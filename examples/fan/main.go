@@ -0,0 +1,30 @@
+// Runs a 4-wire fan at 40% duty for a few seconds, then holds it at 1200 RPM.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/fan"
+)
+
+func main() {
+	f := fan.New(machine.PWM0, machine.GP0, machine.GP2)
+	if err := f.Configure(fan.Config{}); err != nil {
+		println(err.Error())
+		return
+	}
+
+	f.SetDuty(40)
+	for i := 0; i < 10; i++ {
+		time.Sleep(500 * time.Millisecond)
+		println("duty", f.Duty(), "% rpm", f.Update())
+	}
+
+	f.SetRPM(1200)
+	for {
+		time.Sleep(500 * time.Millisecond)
+		rpm := f.Update()
+		println("duty", f.Duty(), "% rpm", rpm)
+	}
+}
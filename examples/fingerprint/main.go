@@ -0,0 +1,51 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/fingerprint"
+)
+
+func main() {
+	machine.UART1.Configure(machine.UARTConfig{BaudRate: 57600})
+	sensor := fingerprint.New(machine.UART1)
+	if err := sensor.Configure(fingerprint.Config{}); err != nil {
+		println("fingerprint sensor not detected:", err.Error())
+		return
+	}
+
+	count, _ := sensor.TemplateCount()
+	println("templates stored:", count)
+
+	if count == 0 {
+		println("place a finger on the sensor to enroll it as #1")
+		sensor.SetAuraLED(fingerprint.LEDBreathing, 100, fingerprint.LEDPurple, 0)
+		if err := sensor.Enroll(1, 10*time.Second); err != nil {
+			println("enroll failed:", err.Error())
+			return
+		}
+		println("enrolled")
+	}
+
+	for {
+		sensor.SetAuraLED(fingerprint.LEDBreathing, 100, fingerprint.LEDBlue, 0)
+		if err := sensor.CaptureImage(); err != nil {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		if err := sensor.ConvertImage(fingerprint.Slot1); err != nil {
+			println(err.Error())
+			continue
+		}
+		id, score, err := sensor.Search(fingerprint.Slot1, 0, 200)
+		if err != nil {
+			sensor.SetAuraLED(fingerprint.LEDFlashing, 50, fingerprint.LEDRed, 3)
+			println(err.Error())
+		} else {
+			sensor.SetAuraLED(fingerprint.LEDOn, 0, fingerprint.LEDPurple, 0)
+			println("found #", id, "score", score)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/gps"
+)
+
+var pinPPS = machine.D2
+
+func main() {
+	println("GPS PPS Example")
+	machine.UART1.Configure(machine.UARTConfig{BaudRate: 9600})
+	ublox := gps.NewUART(machine.UART1)
+	parser := gps.NewParser()
+
+	var pps gps.PPS
+	if err := gps.AttachPPS(&pps, pinPPS); err != nil {
+		println(err.Error())
+		return
+	}
+
+	for {
+		s, err := ublox.NextSentence()
+		if err != nil {
+			continue
+		}
+		fix, err := parser.Parse(s)
+		if err != nil || !fix.Valid || s[3:6] != "RMC" {
+			continue
+		}
+		if !pps.Sync(fix.Time) {
+			println("no PPS pulse")
+			continue
+		}
+		now, _ := pps.Now()
+		println("UTC:", now.Format(time.RFC3339Nano))
+	}
+}
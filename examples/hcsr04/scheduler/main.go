@@ -0,0 +1,35 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/hcsr04"
+)
+
+func main() {
+	left := hcsr04.New(machine.D10, machine.D9)
+	right := hcsr04.New(machine.D12, machine.D11)
+	for _, sensor := range []*hcsr04.Device{&left, &right} {
+		sensor.Configure()
+		if err := sensor.ConfigureInterrupt(); err != nil {
+			println("could not configure echo interrupt:", err.Error())
+			return
+		}
+	}
+
+	scheduler := hcsr04.NewScheduler(&left, &right)
+
+	println("Ultrasonic starts")
+	for {
+		switch scheduler.Update() {
+		case 0:
+			println("Left:", scheduler.Distance(0), "mm")
+		case 1:
+			println("Right:", scheduler.Distance(1), "mm")
+		}
+
+		// other work goes here, ranging doesn't block
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/i2crecover"
+)
+
+func main() {
+	config := machine.I2CConfig{
+		SCL: machine.GP5,
+		SDA: machine.GP4,
+	}
+	if i2crecover.Stuck(config.SCL, config.SDA) {
+		println("I2C bus stuck, recovering")
+	}
+	if err := i2crecover.RecoverBus(machine.I2C0, config); err != nil {
+		println("could not recover I2C bus:", err.Error())
+	}
+
+	data := []byte{0}
+	for {
+		if err := machine.I2C0.Tx(0x68, []byte{0x00}, data); err != nil {
+			println("transfer failed:", err.Error())
+			i2crecover.RecoverBus(machine.I2C0, config)
+		} else {
+			println("register 0x00:", data[0])
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -26,38 +26,8 @@ func main() {
 	}
 
 	for {
-		microvolts := dev.Voltage()
-		microamps := dev.Current()
-		microwatts := dev.Power()
-
-		println(fmtD(microvolts, 4, 3), "mV,", fmtD(microamps, 4, 3), "mA,", fmtD(microwatts, 4, 3), "mW")
+		println(dev.Voltage().String(), dev.Current().String(), dev.Power().String())
 
 		time.Sleep(10 * time.Millisecond)
 	}
 }
-
-func fmtD(val int32, i int, f int) string {
-	result := make([]byte, i+f+1)
-	neg := false
-
-	if val < 0 {
-		val = -val
-		neg = true
-	}
-
-	for p := len(result) - 1; p >= 0; p-- {
-		result[p] = byte(int32('0') + (val % 10))
-		val = val / 10
-
-		if p == i+1 && p > 0 {
-			p--
-			result[p] = '.'
-		}
-	}
-
-	if neg {
-		result[0] = '-'
-	}
-
-	return string(result)
-}
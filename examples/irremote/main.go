@@ -53,6 +53,8 @@ func main() {
 	setupPins()
 	ir.SetCommandHandler(irCallback)
 	for {
+		// report the frames which end once the line has been idle
+		ir.Poll()
 		time.Sleep(time.Millisecond * 10)
 	}
 }
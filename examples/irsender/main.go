@@ -0,0 +1,32 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/irremote"
+)
+
+var (
+	pinIROut = machine.GP16 // IR LED, through a transistor
+	ir       irremote.SenderDevice
+)
+
+func main() {
+	ir = irremote.NewSender(machine.PWM0, pinIROut)
+	if err := ir.Configure(); err != nil {
+		println(err.Error())
+		return
+	}
+
+	// Philips amplifier: volume up, address 16, command 16
+	toggle := false
+	for {
+		// a new key press flips the toggle bit, the repeats keep it
+		toggle = !toggle
+		for i := 0; i < 3; i++ {
+			ir.SendRC5(16, 16, toggle)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers/mcp23017"
+)
+
+// intPin is connected to INTA on the MCP23017.
+const intPin = machine.D2
+
+func main() {
+	err := machine.I2C0.Configure(machine.I2CConfig{
+		Frequency: machine.TWI_FREQ_400KHZ,
+	})
+	if err != nil {
+		panic(err)
+	}
+	dev, err := mcp23017.NewI2C(machine.I2C0, 0x20)
+	if err != nil {
+		panic(err)
+	}
+	// All pins are inputs with pull-ups, for buttons to ground.
+	if err := dev.SetModes([]mcp23017.PinMode{mcp23017.Input | mcp23017.Pullup}); err != nil {
+		panic(err)
+	}
+	// Signal changes on any pin of either port on INTA.
+	if err := dev.SetInterruptConfig(mcp23017.InterruptConfig{Mirror: true}); err != nil {
+		panic(err)
+	}
+	if err := dev.SetInterrupts(^mcp23017.Pins(0), 0, 0); err != nil {
+		panic(err)
+	}
+
+	changed := make(chan struct{}, 1)
+	intPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	intPin.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	// Clear any interrupt pending from before the handler was installed.
+	dev.ReadInterrupt()
+	for range changed {
+		flags, captured, err := dev.ReadInterrupt()
+		if err != nil {
+			println("error:", err.Error())
+			continue
+		}
+		for i := 0; i < mcp23017.PinCount; i++ {
+			if flags.Get(i) {
+				println("pin", i, "changed to", captured.Get(i))
+			}
+		}
+	}
+}
@@ -9,8 +9,9 @@ import (
 )
 
 var (
-	spi   = machine.SPI0
-	csPin = machine.D5
+	spi    = machine.SPI0
+	csPin  = machine.D5
+	intPin = machine.D6
 )
 
 func main() {
@@ -26,19 +27,34 @@ func main() {
 	if err != nil {
 		failMessage(err.Error())
 	}
+	err = can.ConfigureInterrupt(intPin)
+	if err != nil {
+		failMessage(err.Error())
+	}
 
+	out := mcp2515.Frame{
+		ID:   0x18DAF110,
+		Ext:  true,
+		Dlc:  8,
+		Data: [8]byte{0x00, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA},
+	}
+	var in mcp2515.Frame
 	for {
 		err := can.Tx(0x111, 8, []byte{0x00, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA})
 		if err != nil {
 			failMessage(err.Error())
 		}
-		if can.Received() {
-			msg, err := can.Rx()
-			if err != nil {
-				failMessage(err.Error())
-			}
-			fmt.Printf("CAN-ID: %03X dlc: %d data: ", msg.ID, msg.Dlc)
-			for _, b := range msg.Data {
+		err = can.SendFrame(&out)
+		if err != nil {
+			failMessage(err.Error())
+		}
+		err = can.Poll()
+		if err != nil {
+			failMessage(err.Error())
+		}
+		for can.ReadFrame(&in) {
+			fmt.Printf("CAN-ID: %03X dlc: %d data: ", in.ID, in.Dlc)
+			for _, b := range in.Data[:in.Dlc] {
 				fmt.Printf("%02X ", b)
 			}
 			fmt.Print("\r\n")
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/mcp251xfd"
+)
+
+var (
+	spi   = machine.SPI0
+	csPin = machine.D5
+)
+
+func main() {
+	spi.Configure(machine.SPIConfig{
+		Frequency: 10000000,
+		SCK:       machine.SPI0_SCK_PIN,
+		SDO:       machine.SPI0_SDO_PIN,
+		SDI:       machine.SPI0_SDI_PIN,
+		Mode:      0})
+	can := mcp251xfd.New(spi, csPin)
+	err := can.Configure(mcp251xfd.Config{
+		BitRate:     500000,
+		DataBitRate: 2000000,
+		TxEventSize: 4,
+	})
+	if err != nil {
+		failMessage(err.Error())
+	}
+
+	out := mcp251xfd.Frame{
+		ID:     0x123,
+		FD:     true,
+		BRS:    true,
+		Length: 16,
+	}
+	for i := range out.Data[:out.Length] {
+		out.Data[i] = byte(i)
+	}
+	var in mcp251xfd.Frame
+	var ev mcp251xfd.TxEvent
+	for {
+		err := can.Tx(&out)
+		if err != nil {
+			println(err.Error())
+		}
+		time.Sleep(100 * time.Millisecond)
+		for {
+			ok, err := can.ReadTxEvent(&ev)
+			if err != nil {
+				failMessage(err.Error())
+			}
+			if !ok {
+				break
+			}
+			fmt.Printf("sent %03X seq %d\r\n", ev.ID, ev.Seq)
+		}
+		for {
+			ok, err := can.Rx(&in)
+			if err != nil {
+				failMessage(err.Error())
+			}
+			if !ok {
+				break
+			}
+			fmt.Printf("%03X [%2d] % X\r\n", in.ID, in.Length, in.Data[:in.Length])
+		}
+	}
+}
+
+func failMessage(msg string) {
+	for {
+		println(msg)
+		time.Sleep(1 * time.Second)
+	}
+}
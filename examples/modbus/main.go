@@ -0,0 +1,46 @@
+// Reads the voltage and power registers of an energy meter and toggles a
+// relay coil on another slave.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/modbus"
+)
+
+const (
+	meterAddress = 1
+	relayAddress = 2
+)
+
+func main() {
+	uart := machine.UART1
+	uart.Configure(machine.UARTConfig{
+		BaudRate: 9600,
+		TX:       machine.UART_TX_PIN,
+		RX:       machine.UART_RX_PIN,
+	})
+
+	bus := modbus.New(uart, machine.D2)
+	bus.Configure(modbus.Config{BaudRate: 9600})
+
+	var regs [2]uint16
+	relay := false
+	for {
+		err := bus.ReadInputRegisters(meterAddress, 0x0000, regs[:])
+		if err != nil {
+			println("read:", err.Error())
+		} else {
+			println("voltage:", regs[0], "power:", regs[1])
+		}
+
+		relay = !relay
+		err = bus.WriteSingleCoil(relayAddress, 0x0000, relay)
+		if err != nil {
+			println("write:", err.Error())
+		}
+
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,43 @@
+// Prints button presses on pins 0-3 of a PCF8574 and lights an LED on pin 7
+// while any button is held.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/pcf8574"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	dev := pcf8574.New(machine.I2C0)
+	if err := dev.Configure(); err != nil {
+		println(err.Error())
+		return
+	}
+	if err := dev.ConfigureInterrupt(machine.D2); err != nil {
+		println(err.Error())
+		return
+	}
+	dev.SetDebounce(20 * time.Millisecond)
+
+	for pin := uint8(0); pin < 4; pin++ {
+		dev.SetCallback(pin, pcf8574.EdgeBoth, func(pin uint8, value bool) {
+			if value {
+				println("button", pin, "released")
+			} else {
+				println("button", pin, "pressed")
+			}
+			// Buttons pull low when pressed, the LED is active-low.
+			dev.Set(7, dev.Inputs()&0x0F == 0x0F)
+		})
+	}
+
+	for {
+		if err := dev.Poll(); err != nil {
+			println(err.Error())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/lis3dh"
+	"tinygo.org/x/drivers/probe"
+	_ "tinygo.org/x/drivers/probe/all"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	time.Sleep(2 * time.Second)
+
+	for _, addr := range probe.Scan(machine.I2C0) {
+		println("device at address", addr)
+	}
+
+	var sensor *bme280.Device
+	var accel *lis3dh.Device
+	for _, found := range probe.Detect(machine.I2C0) {
+		println("found", found.Name, "at address", found.Address)
+		switch dev := found.Device.(type) {
+		case *bme280.Device:
+			dev.Configure()
+			sensor = dev
+		case *lis3dh.Device:
+			dev.Configure()
+			accel = dev
+		}
+	}
+
+	for {
+		if sensor != nil {
+			temp, _ := sensor.ReadTemperature()
+			println("temperature", temp)
+		}
+		if accel != nil {
+			x, y, z, _ := accel.ReadAcceleration()
+			println("acceleration", x, y, z)
+		}
+		time.Sleep(time.Second)
+	}
+}
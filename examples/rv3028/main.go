@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/rv3028"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{Frequency: machine.TWI_FREQ_400KHZ})
+
+	rtc := rv3028.New(machine.I2C0)
+	lost, err := rtc.LostPower()
+	if err != nil {
+		fmt.Println("Error reading RTC:", err)
+	}
+	if lost {
+		rtc.SetTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		rtc.SetUnixTime(uint32(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Unix()))
+		// Keep the clock running from a supercap charged through 5k.
+		rtc.SetBackupSwitchover(rv3028.BackupLevel, rv3028.TrickleCharge5k)
+		rtc.SaveConfig()
+	}
+	rtc.Configure()
+
+	rtc.SetTimer(5*time.Second, true)
+
+	for {
+		t, _ := rtc.ReadTime()
+		unix, _ := rtc.ReadUnixTime()
+		fmt.Printf("%s (unix %d)\r\n", t.String(), unix)
+		if rtc.TimerTriggered() {
+			fmt.Printf("timer expired\r\n")
+			rtc.ClearTimer()
+		}
+		time.Sleep(time.Second)
+	}
+}
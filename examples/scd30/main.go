@@ -0,0 +1,45 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/scd30"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{Frequency: 50000})
+	sensor := scd30.New(machine.I2C0)
+	if err := sensor.Configure(); err != nil {
+		println(err.Error())
+		return
+	}
+	if err := sensor.SetMeasurementInterval(2 * time.Second); err != nil {
+		println(err.Error())
+	}
+	// compensate for the pressure at sea level
+	if err := sensor.StartContinuousMeasurement(1013); err != nil {
+		println(err.Error())
+		return
+	}
+
+	for {
+		time.Sleep(time.Second)
+		ok, err := sensor.DataReady()
+		if err != nil {
+			println("read error:", err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := sensor.ReadData(); err != nil {
+			println("read error:", err.Error())
+			continue
+		}
+		co2, _ := sensor.ReadCO2()
+		temp, _ := sensor.ReadTemperature()
+		hum, _ := sensor.ReadHumidity()
+		println("CO2", co2, "ppm", "temperature", temp.String(), "humidity", hum/100, "%")
+	}
+}
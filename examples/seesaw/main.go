@@ -0,0 +1,36 @@
+// Reads the Adafruit ANO rotary encoder breakout, which has a rotary encoder
+// and five buttons on seesaw pins 1 to 5.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/seesaw"
+)
+
+const buttons = 1<<1 | 1<<2 | 1<<3 | 1<<4 | 1<<5
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	dev := seesaw.New(machine.I2C0)
+	if err := dev.Configure(); err != nil {
+		println(err.Error())
+		return
+	}
+	product, _, _ := dev.Version()
+	println("seesaw product", product)
+
+	dev.SetPinMode(buttons, seesaw.PinInputPullup)
+
+	for {
+		pos, err := dev.EncoderPosition(0)
+		if err != nil {
+			println("read error:", err.Error())
+		}
+		// buttons are active low
+		pins, _ := dev.DigitalRead(buttons)
+		println("position", pos, "pressed", buttons&^pins)
+		time.Sleep(100 * time.Millisecond)
+	}
+}
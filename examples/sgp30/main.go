@@ -0,0 +1,40 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/sgp30"
+)
+
+// baseline would be loaded from non-volatile memory, zero if none was saved.
+var baseline sgp30.Baseline
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	sensor := sgp30.New(machine.I2C0)
+	if err := sensor.Configure(); err != nil {
+		println(err.Error())
+		return
+	}
+	if baseline != (sgp30.Baseline{}) {
+		sensor.SetBaseline(baseline)
+	}
+	// 22°C at 45% relative humidity, normally read from a humidity sensor
+	sensor.SetHumidity(22000, 4500)
+
+	for i := 1; ; i++ {
+		co2eq, tvoc, err := sensor.MeasureAirQuality()
+		if err != nil {
+			println("read error:", err.Error())
+		} else {
+			println("CO2eq", co2eq, "ppm", "TVOC", tvoc, "ppb")
+		}
+		if i%3600 == 0 && sensor.BaselineValid() {
+			// save hourly
+			baseline, _ = sensor.Baseline()
+			println("baseline", baseline.CO2eq, baseline.TVOC)
+		}
+		time.Sleep(time.Second)
+	}
+}
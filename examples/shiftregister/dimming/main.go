@@ -0,0 +1,40 @@
+// Fades two cascaded 74HC595 registers in and out by driving their OE pin with
+// PWM, while walking a pattern across the 16 outputs.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/shiftregister"
+)
+
+func main() {
+	d := shiftregister.New(
+		shiftregister.SIXTEEN_BITS,
+		machine.GP2, // latch, ST_CP (12)
+		machine.GP3, // clock, SH_CP (11)
+		machine.GP4, // data, DS (14)
+	)
+	d.Configure()
+	// OE (13) of both registers on GP0, PWM slice 0 channel A
+	if err := d.ConfigureDimming(machine.PWM0, machine.GP0); err != nil {
+		println(err.Error())
+		return
+	}
+
+	pin := 0
+	for {
+		d.SetPins(0, 0xFFFF)
+		d.GetShiftPin(pin).High()
+		pin = (pin + 1) % 16
+		for b := 0; b < 256; b += 8 {
+			d.SetBrightness(uint8(b))
+			time.Sleep(10 * time.Millisecond)
+		}
+		for b := 255; b >= 0; b -= 8 {
+			d.SetBrightness(uint8(b))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/mcp3008"
+	"tinygo.org/x/drivers/softspi"
+)
+
+func main() {
+	spi := softspi.New()
+	spi.Configure(softspi.Config{
+		Frequency: 500000,
+		SCK:       machine.D2,
+		SDO:       machine.D3,
+		SDI:       machine.D4,
+		Mode:      0,
+	})
+
+	adc := mcp3008.New(spi, machine.D5)
+	adc.Configure()
+
+	for {
+		val, err := adc.Read(0)
+		if err != nil {
+			println("read error:", err.Error())
+		} else {
+			println("channel 0:", val)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,47 @@
+// Prints the soil moisture every second. Hold the button while powering up
+// with the probe in air, then dip it in water and press the button again to
+// calibrate.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/soilmoisture"
+)
+
+const button = machine.D2
+
+func main() {
+	machine.InitADC()
+	adc := machine.ADC{Pin: machine.A0}
+	adc.Configure(machine.ADCConfig{})
+	button.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+
+	sensor := soilmoisture.New(adc)
+	sensor.Configure(soilmoisture.Config{
+		Samples: 32,
+		Save: func(cal soilmoisture.Calibration) error {
+			// Write these bytes to flash or EEPROM and pass them back
+			// through Calibration.UnmarshalBinary on the next boot.
+			b, _ := cal.MarshalBinary()
+			println("calibration:", b[0], b[1], b[2], b[3])
+			return nil
+		},
+	})
+
+	if !button.Get() {
+		sensor.CalibrateAir()
+		println("dry point stored, dip the probe in water and press the button")
+		for !button.Get() {
+		}
+		for button.Get() {
+		}
+		sensor.CalibrateWater()
+	}
+
+	for {
+		println("moisture:", sensor.ReadPercent(), "%")
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/st7565"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 8000000,
+	})
+	display := st7565.New(machine.SPI0, machine.D5, machine.D6, machine.D7)
+	display.Configure(st7565.Config{
+		Contrast: 0x18,
+	})
+
+	w, h := display.Size()
+	on := color.RGBA{255, 255, 255, 255}
+	off := color.RGBA{0, 0, 0, 255}
+
+	// frame
+	for x := int16(0); x < w; x++ {
+		display.SetPixel(x, 0, on)
+		display.SetPixel(x, h-1, on)
+	}
+	for y := int16(0); y < h; y++ {
+		display.SetPixel(0, y, on)
+		display.SetPixel(w-1, y, on)
+	}
+	display.Display()
+
+	// a bouncing dot, only the changed columns are sent
+	x, y := int16(10), int16(10)
+	dx, dy := int16(1), int16(1)
+	for {
+		display.SetPixel(x, y, off)
+		if x+dx <= 0 || x+dx >= w-1 {
+			dx = -dx
+		}
+		if y+dy <= 0 || y+dy >= h-1 {
+			dy = -dy
+		}
+		x += dx
+		y += dy
+		display.SetPixel(x, y, on)
+		display.Display()
+		time.Sleep(20 * time.Millisecond)
+	}
+}
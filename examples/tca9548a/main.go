@@ -0,0 +1,27 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/tca9548a"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	mux := tca9548a.New(machine.I2C0)
+
+	// Two sensors with the same address, on channels 0 and 1.
+	indoor := bme280.New(mux.Channel(0))
+	outdoor := bme280.New(mux.Channel(1))
+	indoor.Configure()
+	outdoor.Configure()
+
+	for {
+		in, _ := indoor.ReadTemperature()
+		out, _ := outdoor.ReadTemperature()
+		println("indoor", in, "outdoor", out)
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/tcs34725"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{})
+	sensor := tcs34725.New(machine.I2C0)
+	err := sensor.Configure(tcs34725.Config{
+		IntegrationTime: 154 * time.Millisecond,
+		Gain:            tcs34725.Gain4X,
+	})
+	if err != nil {
+		println(err.Error())
+		return
+	}
+
+	for {
+		time.Sleep(sensor.IntegrationTime())
+		r, g, b, c, err := sensor.ReadRaw()
+		if err != nil {
+			println("read error:", err.Error())
+			continue
+		}
+		println("R", r, "G", g, "B", b, "C", c)
+		if lux, err := sensor.Lux(r, g, b, c); err == nil {
+			println("illuminance", lux.String())
+		}
+		println("color temperature", tcs34725.ColorTemperature(r, g, b, c), "K")
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/tlc5947"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 1000000,
+		Mode:      0,
+	})
+	leds := tlc5947.New(machine.SPI0, machine.D5, 1)
+
+	// fade the channels up one after the other
+	for {
+		for ch := 0; ch < leds.NumChannels(); ch++ {
+			for v := 0; v <= tlc5947.MaxValue; v += 0x100 {
+				leds.Set(ch, uint16(v))
+				leds.Update()
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		leds.SetAll(0)
+		leds.Update()
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/tlc59711"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 1000000,
+		Mode:      0,
+	})
+	leds := tlc59711.New(machine.SPI0, 1)
+	leds.SetBrightness(0x40, 0x40, 0x40)
+
+	colors := []color.RGBA{
+		{R: 0xFF},
+		{G: 0xFF},
+		{B: 0xFF},
+		{R: 0xFF, G: 0xFF, B: 0xFF},
+	}
+	for i := 0; ; i++ {
+		// each device drives 4 RGB LEDs
+		for led := 0; led < leds.NumChannels()/3; led++ {
+			leds.SetRGB(led, colors[(i+led)%len(colors)])
+		}
+		leds.Update()
+		time.Sleep(500 * time.Millisecond)
+	}
+}
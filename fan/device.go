@@ -0,0 +1,161 @@
+//go:build tinygo
+
+package fan
+
+import (
+	"machine"
+	"sync/atomic"
+	"time"
+)
+
+// PWM is the interface necessary for controlling the fan speed.
+type PWM interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (channel uint8, err error)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+// Device is a 4-wire PWM fan.
+type Device struct {
+	pwm     PWM
+	channel uint8
+	pwmPin  machine.Pin
+	tach    machine.Pin
+
+	ppr      uint8
+	inverted bool
+	minDuty  uint8
+	duty     uint8
+
+	pulses     uint32 // incremented by the tachometer interrupt
+	lastPulses uint32
+	lastTime   time.Time
+	rpm        uint32
+
+	target uint32 // RPM held by Update, 0 for manual control
+	pid    pid
+}
+
+// New returns a new fan driver. tachPin may be machine.NoPin for fans
+// without a tachometer wire, in which case RPM always returns 0.
+func New(pwm PWM, pwmPin, tachPin machine.Pin) *Device {
+	return &Device{
+		pwm:    pwm,
+		pwmPin: pwmPin,
+		tach:   tachPin,
+	}
+}
+
+// Configure sets up the PWM output and the tachometer input. The fan starts
+// at full speed, which is also what it does with the PWM input disconnected.
+func (d *Device) Configure(cfg Config) error {
+	if cfg.Frequency == 0 {
+		cfg.Frequency = 25000
+	}
+	if cfg.PulsesPerRevolution == 0 {
+		cfg.PulsesPerRevolution = 2
+	}
+	if cfg.MinDuty == 0 {
+		cfg.MinDuty = 20
+	}
+	if cfg.Kp == 0 && cfg.Ki == 0 && cfg.Kd == 0 {
+		cfg.Kp, cfg.Ki = 0.01, 0.02
+	}
+	d.ppr = cfg.PulsesPerRevolution
+	d.inverted = cfg.Inverted
+	d.minDuty = cfg.MinDuty
+	d.pid = pid{
+		kp:  cfg.Kp,
+		ki:  cfg.Ki,
+		kd:  cfg.Kd,
+		min: float32(cfg.MinDuty),
+		max: 100,
+	}
+
+	err := d.pwm.Configure(machine.PWMConfig{
+		Period: uint64(1e9 / cfg.Frequency),
+	})
+	if err != nil {
+		return err
+	}
+	d.channel, err = d.pwm.Channel(d.pwmPin)
+	if err != nil {
+		return err
+	}
+	d.SetDuty(100)
+
+	if d.tach != machine.NoPin {
+		// The tachometer output is open-collector.
+		d.tach.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+		err = d.tach.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+			atomic.AddUint32(&d.pulses, 1)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	d.lastTime = time.Now()
+	return nil
+}
+
+// SetDuty sets the PWM duty cycle in percent (0-100) and stops holding an
+// RPM. Most fans keep turning at their minimum speed below about 20%.
+func (d *Device) SetDuty(percent uint8) {
+	d.target = 0
+	d.setDuty(percent)
+}
+
+// Duty returns the current duty cycle in percent.
+func (d *Device) Duty() uint8 {
+	return d.duty
+}
+
+// SetRPM holds the fan at the given speed. Update must be called regularly
+// for the speed to be regulated. A target of 0 stops the fan.
+func (d *Device) SetRPM(target uint32) {
+	if target == 0 {
+		d.SetDuty(0)
+		return
+	}
+	if d.target == 0 {
+		d.pid.reset(float32(d.duty))
+	}
+	d.target = target
+}
+
+// RPM returns the speed measured by the last Update.
+func (d *Device) RPM() uint32 {
+	return d.rpm
+}
+
+// Update measures the speed from the tachometer pulses since the previous
+// call and, when holding an RPM, adjusts the duty cycle. Call it every 100ms
+// to 1s: longer intervals give more stable readings at low speeds.
+func (d *Device) Update() uint32 {
+	now := time.Now()
+	pulses := atomic.LoadUint32(&d.pulses)
+	elapsed := now.Sub(d.lastTime)
+	d.rpm = rpm(pulses-d.lastPulses, elapsed, d.ppr)
+	d.lastPulses = pulses
+	d.lastTime = now
+
+	if d.target != 0 {
+		out := d.pid.update(float32(d.target), float32(d.rpm), float32(elapsed)/float32(time.Second))
+		d.setDuty(uint8(out + 0.5))
+	}
+	return d.rpm
+}
+
+func (d *Device) setDuty(percent uint8) {
+	if percent > 100 {
+		percent = 100
+	}
+	d.duty = percent
+	top := d.pwm.Top()
+	value := uint32(uint64(top) * uint64(percent) / 100)
+	if d.inverted {
+		value = top - value
+	}
+	d.pwm.Set(d.channel, value)
+}
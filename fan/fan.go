@@ -0,0 +1,87 @@
+// Package fan provides a driver for 4-wire PC fans, which take a 25kHz PWM
+// speed input and report their speed with a tachometer output.
+//
+// Specification: https://noctua.at/pub/media/wysiwyg/Noctua_PWM_specifications_white_paper.pdf
+//
+// The speed can be set directly as a PWM duty cycle, or held at a target RPM
+// with a PID controller that adjusts the duty cycle from the measured speed.
+package fan // import "tinygo.org/x/drivers/fan"
+
+import "time"
+
+// Config holds the fan configuration.
+type Config struct {
+	// Frequency of the PWM output in Hz. Defaults to 25kHz as required by
+	// the specification.
+	Frequency uint32
+
+	// PulsesPerRevolution of the tachometer output. Defaults to 2.
+	PulsesPerRevolution uint8
+
+	// Inverted is set when the PWM pin drives the fan's PWM input through an
+	// inverting transistor stage.
+	Inverted bool
+
+	// MinDuty is the lowest duty cycle in percent used while holding an RPM,
+	// as many fans stall or ignore lower duty cycles. Defaults to 20.
+	MinDuty uint8
+
+	// Kp, Ki and Kd are the gains of the RPM hold controller, in percent of
+	// duty cycle per RPM of error (per second for Ki, times seconds for Kd).
+	// Defaults to Kp 0.01, Ki 0.02 and Kd 0.
+	Kp, Ki, Kd float32
+}
+
+// rpm calculates the speed from the tachometer pulses counted in elapsed.
+func rpm(pulses uint32, elapsed time.Duration, ppr uint8) uint32 {
+	if elapsed <= 0 || ppr == 0 {
+		return 0
+	}
+	return uint32(uint64(pulses) * uint64(time.Minute) / (uint64(elapsed) * uint64(ppr)))
+}
+
+// pid is a PID controller with output limits and anti-windup.
+type pid struct {
+	kp, ki, kd     float32
+	min, max       float32
+	integral, prev float32
+	primed         bool
+}
+
+// reset clears the controller state, starting the integral at the current
+// output so that enabling the controller doesn't cause a jump.
+func (p *pid) reset(output float32) {
+	p.integral = output
+	p.primed = false
+}
+
+// update returns the new output for the error between setpoint and
+// measurement, dt seconds after the previous update.
+func (p *pid) update(setpoint, measured, dt float32) float32 {
+	err := setpoint - measured
+	var derivative float32
+	if p.primed && dt > 0 {
+		derivative = (err - p.prev) / dt
+	}
+	p.prev = err
+	p.primed = true
+
+	// Limit the integral to the output range, so it doesn't wind up while
+	// the fan can't follow.
+	integral := p.integral + p.ki*err*dt
+	if integral > p.max {
+		integral = p.max
+	} else if integral < p.min {
+		integral = p.min
+	}
+	p.integral = integral
+
+	out := p.kp*err + integral + p.kd*derivative
+	if out > p.max {
+		return p.max
+	}
+	if out < p.min {
+		return p.min
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package fan
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRPM(t *testing.T) {
+	c := qt.New(t)
+	// 2 pulses per revolution, 40 pulses in 1s is 1200 RPM
+	c.Assert(rpm(40, time.Second, 2), qt.Equals, uint32(1200))
+	c.Assert(rpm(10, 250*time.Millisecond, 2), qt.Equals, uint32(1200))
+	c.Assert(rpm(10, 0, 2), qt.Equals, uint32(0))
+}
+
+func TestPIDConverges(t *testing.T) {
+	c := qt.New(t)
+	p := pid{kp: 0.01, ki: 0.02, min: 20, max: 100}
+	p.reset(50)
+
+	// A fan whose speed is proportional to the duty cycle, 20 RPM per
+	// percent, reaching the new speed within one update.
+	duty := float32(50)
+	for i := 0; i < 200; i++ {
+		speed := duty * 20
+		duty = p.update(1500, speed, 0.5)
+	}
+	c.Assert(duty > 74 && duty < 76, qt.IsTrue, qt.Commentf("duty %f", duty))
+}
+
+func TestPIDLimits(t *testing.T) {
+	c := qt.New(t)
+	p := pid{kp: 0.01, ki: 0.02, min: 20, max: 100}
+	p.reset(50)
+	// An unreachable target saturates without winding up the integral.
+	for i := 0; i < 100; i++ {
+		c.Assert(p.update(10000, 0, 1), qt.Equals, float32(100))
+	}
+	c.Assert(p.integral, qt.Equals, float32(100))
+	// so it comes back down right away once overshooting
+	c.Assert(p.update(0, 1000, 1), qt.Equals, float32(70))
+}
@@ -0,0 +1,503 @@
+// Package fingerprint provides a driver for R503, R307 and compatible optical
+// and capacitive fingerprint modules that talk the Synochip/ZhianTec packet
+// protocol over UART.
+//
+// Datasheet R503: https://cdn-shop.adafruit.com/product-files/4651/R503+fingerprint+module+user+manual.pdf
+// Datasheet R307: https://cdn.sparkfun.com/assets/learn_tutorials/4/5/1/R307_fingerprint_module_user_manual.pdf
+package fingerprint // import "tinygo.org/x/drivers/fingerprint"
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/uartbuf"
+)
+
+var (
+	errTimeout        = errors.New("fingerprint: timeout waiting for module")
+	errInvalidPacket  = errors.New("fingerprint: invalid packet received")
+	errChecksum       = errors.New("fingerprint: packet checksum mismatch")
+	errBufferTooSmall = errors.New("fingerprint: buffer too small for template")
+	errPacketSize     = errors.New("fingerprint: invalid data packet size")
+)
+
+// Error is a confirmation code returned by the module when a command did not
+// succeed.
+type Error uint8
+
+// Confirmation codes returned by the module.
+const (
+	ErrPacketReceive    Error = 0x01
+	ErrNoFinger         Error = 0x02
+	ErrImageFail        Error = 0x03
+	ErrImageMessy       Error = 0x06
+	ErrFeatureFail      Error = 0x07
+	ErrNoMatch          Error = 0x08
+	ErrNotFound         Error = 0x09
+	ErrEnrollMismatch   Error = 0x0A
+	ErrBadLocation      Error = 0x0B
+	ErrDatabaseRead     Error = 0x0C
+	ErrUploadFeature    Error = 0x0D
+	ErrPacketResponse   Error = 0x0E
+	ErrUpload           Error = 0x0F
+	ErrDelete           Error = 0x10
+	ErrDatabaseClear    Error = 0x11
+	ErrWrongPassword    Error = 0x13
+	ErrInvalidImage     Error = 0x15
+	ErrFlash            Error = 0x18
+	ErrInvalidRegister  Error = 0x1A
+	ErrAddressCode      Error = 0x20
+	ErrPasswordRequired Error = 0x21
+)
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	switch e {
+	case ErrPacketReceive:
+		return "fingerprint: error receiving packet"
+	case ErrNoFinger:
+		return "fingerprint: no finger on sensor"
+	case ErrImageFail:
+		return "fingerprint: failed to capture image"
+	case ErrImageMessy:
+		return "fingerprint: image too messy"
+	case ErrFeatureFail:
+		return "fingerprint: too few feature points"
+	case ErrNoMatch:
+		return "fingerprint: fingers do not match"
+	case ErrNotFound:
+		return "fingerprint: no matching template found"
+	case ErrEnrollMismatch:
+		return "fingerprint: failed to combine character files"
+	case ErrBadLocation:
+		return "fingerprint: page ID beyond library"
+	case ErrDatabaseRead:
+		return "fingerprint: error reading template from library"
+	case ErrUploadFeature:
+		return "fingerprint: error uploading template"
+	case ErrPacketResponse:
+		return "fingerprint: module cannot receive data packets"
+	case ErrUpload:
+		return "fingerprint: error uploading image"
+	case ErrDelete:
+		return "fingerprint: failed to delete template"
+	case ErrDatabaseClear:
+		return "fingerprint: failed to clear library"
+	case ErrWrongPassword:
+		return "fingerprint: wrong password"
+	case ErrInvalidImage:
+		return "fingerprint: no valid image in buffer"
+	case ErrFlash:
+		return "fingerprint: error writing flash"
+	case ErrInvalidRegister:
+		return "fingerprint: invalid register number"
+	case ErrAddressCode:
+		return "fingerprint: wrong address code"
+	case ErrPasswordRequired:
+		return "fingerprint: password must be verified"
+	}
+	return "fingerprint: unknown error"
+}
+
+// Config holds the address and password of the module, both of which are
+// stored in the module's flash.
+type Config struct {
+	// Address of the module. Defaults to DefaultAddress.
+	Address uint32
+
+	// Password of the module. Defaults to DefaultPassword.
+	Password uint32
+}
+
+// SystemParameters is the basic configuration of the module as reported by
+// ReadSystemParameters.
+type SystemParameters struct {
+	Status        uint16
+	SystemID      uint16
+	Capacity      uint16
+	SecurityLevel uint16
+	Address       uint32
+	PacketSize    uint16 // data packet payload in bytes
+	BaudRate      uint32
+}
+
+// Device wraps a UART connection to a fingerprint module.
+type Device struct {
+	uart     drivers.UART
+	rx       *uartbuf.Reader
+	address  uint32
+	password uint32
+	buf      [packetHeaderSize + maxPayloadSize + checksumSize]byte
+
+	// Timeout is the maximum time to wait for a response from the module.
+	Timeout time.Duration
+}
+
+// New creates a new fingerprint module connection. The UART must already be
+// configured, usually at 57600 baud.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(uart drivers.UART) *Device {
+	return &Device{
+		uart:     uart,
+		rx:       uartbuf.NewReader(uart, packetHeaderSize+maxPayloadSize+checksumSize),
+		address:  DefaultAddress,
+		password: DefaultPassword,
+		Timeout:  time.Second,
+	}
+}
+
+// Configure sets the address and password used to talk to the module and
+// verifies the password, which also confirms that the module is connected.
+func (d *Device) Configure(cfg Config) error {
+	if cfg.Address != 0 {
+		d.address = cfg.Address
+	}
+	d.password = cfg.Password
+	return d.VerifyPassword()
+}
+
+// VerifyPassword sends the configured password to the module. Modules with a
+// non-default password refuse all other commands until this succeeds.
+func (d *Device) VerifyPassword() error {
+	var p [4]byte
+	binary.BigEndian.PutUint32(p[:], d.password)
+	_, err := d.command(cmdVfyPwd, p[:]...)
+	return err
+}
+
+// SetPassword changes the password stored in the module.
+func (d *Device) SetPassword(password uint32) error {
+	var p [4]byte
+	binary.BigEndian.PutUint32(p[:], password)
+	if _, err := d.command(cmdSetPwd, p[:]...); err != nil {
+		return err
+	}
+	d.password = password
+	return nil
+}
+
+// SetAddress changes the address stored in the module.
+func (d *Device) SetAddress(address uint32) error {
+	var a [4]byte
+	binary.BigEndian.PutUint32(a[:], address)
+	if _, err := d.command(cmdSetAddr, a[:]...); err != nil {
+		return err
+	}
+	d.address = address
+	return nil
+}
+
+// ReadSystemParameters returns the basic configuration of the module.
+func (d *Device) ReadSystemParameters() (p SystemParameters, err error) {
+	data, err := d.command(cmdReadSysPara)
+	if err != nil {
+		return p, err
+	}
+	if len(data) < 16 {
+		return p, errInvalidPacket
+	}
+	p.Status = binary.BigEndian.Uint16(data[0:])
+	p.SystemID = binary.BigEndian.Uint16(data[2:])
+	p.Capacity = binary.BigEndian.Uint16(data[4:])
+	p.SecurityLevel = binary.BigEndian.Uint16(data[6:])
+	p.Address = binary.BigEndian.Uint32(data[8:])
+	p.PacketSize = 32 << binary.BigEndian.Uint16(data[12:])
+	p.BaudRate = 9600 * uint32(binary.BigEndian.Uint16(data[14:]))
+	return p, nil
+}
+
+// SetSystemParameter writes one of the ParamXXX system parameters.
+func (d *Device) SetSystemParameter(param, value uint8) error {
+	_, err := d.command(cmdSetSysPara, param, value)
+	return err
+}
+
+// CaptureImage takes an image of the finger on the sensor and stores it in
+// the image buffer. It returns ErrNoFinger if there is no finger present.
+func (d *Device) CaptureImage() error {
+	_, err := d.command(cmdGenImg)
+	return err
+}
+
+// ConvertImage generates a character file from the image buffer and stores
+// it in the given slot (Slot1 or Slot2).
+func (d *Device) ConvertImage(slot uint8) error {
+	_, err := d.command(cmdImg2Tz, slot)
+	return err
+}
+
+// CreateModel combines the character files in Slot1 and Slot2 into a
+// template, stored back in both slots.
+func (d *Device) CreateModel() error {
+	_, err := d.command(cmdRegModel)
+	return err
+}
+
+// StoreModel saves the template in slot to the library at location id.
+func (d *Device) StoreModel(slot uint8, id uint16) error {
+	_, err := d.command(cmdStore, slot, byte(id>>8), byte(id))
+	return err
+}
+
+// LoadModel loads the template at location id in the library into slot.
+func (d *Device) LoadModel(slot uint8, id uint16) error {
+	_, err := d.command(cmdLoadChar, slot, byte(id>>8), byte(id))
+	return err
+}
+
+// DeleteModel deletes count templates starting at location id.
+func (d *Device) DeleteModel(id, count uint16) error {
+	_, err := d.command(cmdDeleteChar, byte(id>>8), byte(id), byte(count>>8), byte(count))
+	return err
+}
+
+// EmptyDatabase deletes all templates from the library.
+func (d *Device) EmptyDatabase() error {
+	_, err := d.command(cmdEmpty)
+	return err
+}
+
+// TemplateCount returns the number of templates stored in the library.
+func (d *Device) TemplateCount() (uint16, error) {
+	data, err := d.command(cmdTemplateNum)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 2 {
+		return 0, errInvalidPacket
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
+// ReadIndexTable reads which of the 256 template locations of the given
+// index page are in use, one bit per location.
+func (d *Device) ReadIndexTable(page uint8, table *[32]byte) error {
+	data, err := d.command(cmdReadIndexTable, page)
+	if err != nil {
+		return err
+	}
+	if len(data) < len(table) {
+		return errInvalidPacket
+	}
+	copy(table[:], data)
+	return nil
+}
+
+// Match compares the character files in Slot1 and Slot2 and returns the
+// matching score. It returns ErrNoMatch if they do not match.
+func (d *Device) Match() (score uint16, err error) {
+	data, err := d.command(cmdMatch)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) < 2 {
+		return 0, errInvalidPacket
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
+// Search looks for the character file in slot in the library between
+// locations start and start+count. It returns the matching location and
+// score, or ErrNotFound.
+func (d *Device) Search(slot uint8, start, count uint16) (id, score uint16, err error) {
+	data, err := d.command(cmdSearch, slot, byte(start>>8), byte(start), byte(count>>8), byte(count))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(data) < 4 {
+		return 0, 0, errInvalidPacket
+	}
+	return binary.BigEndian.Uint16(data[0:]), binary.BigEndian.Uint16(data[2:]), nil
+}
+
+// Enroll guides a single finger through two captures and stores the
+// resulting template at location id. The finger must be placed, lifted, and
+// placed again; each wait is bounded by timeout.
+func (d *Device) Enroll(id uint16, timeout time.Duration) error {
+	for _, slot := range []uint8{Slot1, Slot2} {
+		if err := d.waitFinger(true, timeout); err != nil {
+			return err
+		}
+		if err := d.ConvertImage(slot); err != nil {
+			return err
+		}
+		if slot == Slot1 {
+			if err := d.waitFinger(false, timeout); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.CreateModel(); err != nil {
+		return err
+	}
+	return d.StoreModel(Slot1, id)
+}
+
+// waitFinger polls the sensor until a finger is present (captured into the
+// image buffer) or absent.
+func (d *Device) waitFinger(present bool, timeout time.Duration) error {
+	start := time.Now()
+	for time.Since(start) < timeout {
+		err := d.CaptureImage()
+		switch {
+		case present && err == nil:
+			return nil
+		case !present && err == ErrNoFinger:
+			return nil
+		case err != nil && err != ErrNoFinger && err != ErrImageFail:
+			return err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return errTimeout
+}
+
+// ReadModel uploads the character file or template in slot to buf and returns
+// the number of bytes read. Templates are usually 512 or 768 bytes.
+func (d *Device) ReadModel(slot uint8, buf []byte) (n int, err error) {
+	if _, err = d.command(cmdUpChar, slot); err != nil {
+		return 0, err
+	}
+	for {
+		pid, data, err := d.readPacket()
+		if err != nil {
+			return n, err
+		}
+		if pid != packetData && pid != packetEndData {
+			return n, errInvalidPacket
+		}
+		if n+len(data) > len(buf) {
+			return n, errBufferTooSmall
+		}
+		n += copy(buf[n:], data)
+		if pid == packetEndData {
+			return n, nil
+		}
+	}
+}
+
+// WriteModel downloads a template previously read with ReadModel into slot,
+// split into data packets of packetSize bytes (see SystemParameters), from 1
+// to 256.
+func (d *Device) WriteModel(slot uint8, data []byte, packetSize int) error {
+	if packetSize < 1 || packetSize > maxPayloadSize {
+		return errPacketSize
+	}
+	if _, err := d.command(cmdDownChar, slot); err != nil {
+		return err
+	}
+	for len(data) > 0 {
+		chunk := data
+		pid := uint8(packetEndData)
+		if len(chunk) > packetSize {
+			chunk = chunk[:packetSize]
+			pid = packetData
+		}
+		if err := d.writePacket(pid, chunk); err != nil {
+			return err
+		}
+		data = data[len(chunk):]
+	}
+	return nil
+}
+
+// SetAuraLED controls the LED ring found on the R503. Speed is used by the
+// breathing and flashing modes, cycles is the number of repetitions for the
+// flashing mode (0 for infinite).
+func (d *Device) SetAuraLED(mode LEDMode, speed uint8, color LEDColor, cycles uint8) error {
+	_, err := d.command(cmdAuraLedConfig, uint8(mode), speed, uint8(color), cycles)
+	return err
+}
+
+// SetLED turns the backlight of modules without an aura LED ring on or off.
+func (d *Device) SetLED(on bool) error {
+	cmd := uint8(cmdLEDOff)
+	if on {
+		cmd = cmdLEDOn
+	}
+	_, err := d.command(cmd)
+	return err
+}
+
+// command sends a command packet and waits for the acknowledge packet. It
+// returns the acknowledge payload following the confirmation code, which is
+// only valid until the next command.
+func (d *Device) command(cmd uint8, params ...byte) ([]byte, error) {
+	var p [8]byte
+	p[0] = cmd
+	n := 1 + copy(p[1:], params)
+	if err := d.writePacket(packetCommand, p[:n]); err != nil {
+		return nil, err
+	}
+	pid, data, err := d.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if pid != packetAck || len(data) < 1 {
+		return nil, errInvalidPacket
+	}
+	if data[0] != 0 {
+		return nil, Error(data[0])
+	}
+	return data[1:], nil
+}
+
+// writePacket frames payload with the header, address and checksum and
+// sends it to the module.
+func (d *Device) writePacket(pid uint8, payload []byte) error {
+	b := d.buf[:]
+	binary.BigEndian.PutUint16(b[0:], startCode)
+	binary.BigEndian.PutUint32(b[2:], d.address)
+	b[6] = pid
+	binary.BigEndian.PutUint16(b[7:], uint16(len(payload)+checksumSize))
+	n := packetHeaderSize + copy(b[packetHeaderSize:], payload)
+	binary.BigEndian.PutUint16(b[n:], checksum(b[6:n]))
+	_, err := d.uart.Write(b[:n+checksumSize])
+	return err
+}
+
+// packetFraming describes the packets sent by the module: a start code, the
+// address and packet identifier and a length that includes the checksum.
+var packetFraming = uartbuf.Framing{
+	Sync:         []byte{startCode >> 8, startCode & 0xFF},
+	LengthOffset: 7,
+	LengthSize:   2,
+}
+
+// readPacket receives a single packet from the module and returns its
+// identifier and payload, which is only valid until the next packet. Any
+// noise before the start code is skipped.
+func (d *Device) readPacket() (pid uint8, payload []byte, err error) {
+	d.rx.Timeout = d.Timeout
+	b, err := d.rx.ReadPacket(packetFraming)
+	switch err {
+	case nil:
+	case uartbuf.ErrTimeout:
+		return 0, nil, errTimeout
+	case uartbuf.ErrPacketTooLong:
+		return 0, nil, errInvalidPacket
+	default:
+		return 0, nil, err
+	}
+	end := len(b)
+	if end < packetHeaderSize+checksumSize {
+		return 0, nil, errInvalidPacket
+	}
+	if binary.BigEndian.Uint16(b[end-checksumSize:]) != checksum(b[6:end-checksumSize]) {
+		return 0, nil, errChecksum
+	}
+	return b[6], b[packetHeaderSize : end-checksumSize], nil
+}
+
+// checksum returns the packet checksum: the 16-bit sum of the packet
+// identifier, length and payload bytes.
+func checksum(b []byte) uint16 {
+	var sum uint16
+	for _, c := range b {
+		sum += uint16(c)
+	}
+	return sum
+}
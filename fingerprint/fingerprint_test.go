@@ -0,0 +1,67 @@
+package fingerprint
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeUART records everything written to it and replays canned responses.
+type fakeUART struct {
+	tx bytes.Buffer
+	rx bytes.Buffer
+}
+
+func (u *fakeUART) Read(b []byte) (int, error)  { return u.rx.Read(b) }
+func (u *fakeUART) Write(b []byte) (int, error) { return u.tx.Write(b) }
+func (u *fakeUART) Buffered() int               { return u.rx.Len() }
+
+func TestVerifyPasswordPacket(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	// acknowledge with confirmation code 0
+	uart.rx.Write([]byte{0xEF, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x07, 0x00, 0x03, 0x00, 0x00, 0x0A})
+	d := New(uart)
+	c.Assert(d.Configure(Config{}), qt.IsNil)
+	c.Assert(uart.tx.Bytes(), qt.DeepEquals, []byte{
+		0xEF, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x01, 0x00, 0x07, 0x13, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1B,
+	})
+}
+
+func TestSearchResult(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	// noise followed by an ack with page 5, score 100
+	uart.rx.Write([]byte{0x00, 0xEF, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x07, 0x00, 0x07, 0x00, 0x00, 0x05, 0x00, 0x64, 0x00, 0x77})
+	d := New(uart)
+	id, score, err := d.Search(Slot1, 0, 200)
+	c.Assert(err, qt.IsNil)
+	c.Assert(id, qt.Equals, uint16(5))
+	c.Assert(score, qt.Equals, uint16(100))
+}
+
+func TestConfirmationCode(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	uart.rx.Write([]byte{0xEF, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x07, 0x00, 0x03, 0x02, 0x00, 0x0C})
+	d := New(uart)
+	c.Assert(d.CaptureImage(), qt.Equals, ErrNoFinger)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	uart.rx.Write([]byte{0xEF, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0x07, 0x00, 0x03, 0x00, 0x00, 0x0B})
+	d := New(uart)
+	c.Assert(d.CaptureImage(), qt.Equals, errChecksum)
+}
+
+func TestWriteModelPacketSize(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := New(uart)
+	c.Assert(d.WriteModel(Slot1, make([]byte, 512), 0), qt.Equals, errPacketSize)
+	c.Assert(d.WriteModel(Slot1, make([]byte, 512), 257), qt.Equals, errPacketSize)
+	c.Assert(uart.tx.Len(), qt.Equals, 0)
+}
@@ -0,0 +1,87 @@
+package fingerprint
+
+// Default module address and password. Both can be changed on the module with
+// SetAddress and SetPassword, in which case the new values must be passed to
+// Configure.
+const (
+	DefaultAddress  = 0xFFFFFFFF
+	DefaultPassword = 0x00000000
+)
+
+// Packet framing.
+const (
+	startCode = 0xEF01
+
+	packetCommand = 0x01 // command packet from host
+	packetData    = 0x02 // data packet, more to follow
+	packetAck     = 0x07 // acknowledge packet from module
+	packetEndData = 0x08 // last data packet
+
+	// header (2) + address (4) + identifier (1) + length (2)
+	packetHeaderSize = 9
+	checksumSize     = 2
+
+	// Largest payload the module sends in a single packet (data packet
+	// length is configurable up to 256 bytes).
+	maxPayloadSize = 256
+)
+
+// Instruction codes.
+const (
+	cmdGenImg          = 0x01
+	cmdImg2Tz          = 0x02
+	cmdMatch           = 0x03
+	cmdSearch          = 0x04
+	cmdRegModel        = 0x05
+	cmdStore           = 0x06
+	cmdLoadChar        = 0x07
+	cmdUpChar          = 0x08
+	cmdDownChar        = 0x09
+	cmdDeleteChar      = 0x0C
+	cmdEmpty           = 0x0D
+	cmdSetSysPara      = 0x0E
+	cmdReadSysPara     = 0x0F
+	cmdSetPwd          = 0x12
+	cmdVfyPwd          = 0x13
+	cmdSetAddr         = 0x15
+	cmdTemplateNum     = 0x1D
+	cmdReadIndexTable  = 0x1F
+	cmdAuraLedConfig   = 0x35
+	cmdLEDOn           = 0x50
+	cmdLEDOff          = 0x51
+	cmdHighSpeedSearch = 0x1B
+)
+
+// Character buffers (slots) used while enrolling and searching.
+const (
+	Slot1 = 1
+	Slot2 = 2
+)
+
+// System parameter numbers for SetSystemParameter.
+const (
+	ParamBaudRate      = 4 // N, baud rate is N * 9600
+	ParamSecurityLevel = 5 // 1 (lowest) to 5 (highest)
+	ParamPacketSize    = 6 // 0: 32 bytes, 1: 64, 2: 128, 3: 256
+)
+
+// LEDMode is the effect used by the R503 aura LED ring.
+type LEDMode uint8
+
+const (
+	LEDBreathing  LEDMode = 0x01
+	LEDFlashing   LEDMode = 0x02
+	LEDOn         LEDMode = 0x03
+	LEDOff        LEDMode = 0x04
+	LEDGradualOn  LEDMode = 0x05
+	LEDGradualOff LEDMode = 0x06
+)
+
+// LEDColor is the color of the R503 aura LED ring.
+type LEDColor uint8
+
+const (
+	LEDRed    LEDColor = 0x01
+	LEDBlue   LEDColor = 0x02
+	LEDPurple LEDColor = 0x03
+)
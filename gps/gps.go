@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/uartbuf"
 )
 
 var (
@@ -18,6 +19,9 @@ var (
 	errInvalidGGASentence        = errors.New("invalid GGA NMEA sentence")
 	errInvalidRMCSentence        = errors.New("invalid RMC NMEA sentence")
 	errInvalidGLLSentence        = errors.New("invalid GLL NMEA sentence")
+	errInvalidGSVSentence        = errors.New("invalid GSV NMEA sentence")
+	errInvalidGSASentence        = errors.New("invalid GSA NMEA sentence")
+	errInvalidVTGSentence        = errors.New("invalid VTG NMEA sentence")
 )
 
 type GPSError struct {
@@ -46,39 +50,50 @@ const (
 	minimumNMEALength = 7
 	startingDelimiter = '$'
 	checksumDelimiter = '*'
+
+	// pollInterval is how often to check for received data.
+	pollInterval = 20 * time.Millisecond
 )
 
 // Device wraps a connection to a GPS device.
 type Device struct {
-	buffer   []byte
-	bufIdx   int
-	sentence strings.Builder
-	uart     drivers.UART
-	bus      drivers.I2C
-	address  uint16
+	rx        *uartbuf.Reader
+	sentence  strings.Builder
+	ubxBuffer []byte
+	uart      drivers.UART
+	bus       drivers.I2C
+	address   uint16
 }
 
 // NewUART creates a new UART GPS connection. The UART must already be configured.
 func NewUART(uart drivers.UART) Device {
 	return Device{
-		uart:     uart,
-		buffer:   make([]byte, bufferSize),
-		bufIdx:   bufferSize,
-		sentence: strings.Builder{},
+		uart:      uart,
+		rx:        newReader(uart),
+		sentence:  strings.Builder{},
+		ubxBuffer: make([]byte, 0, ubxMaxPayload+8),
 	}
 }
 
 // NewI2C creates a new I2C GPS connection.
 func NewI2C(bus drivers.I2C) Device {
 	return Device{
-		bus:      bus,
-		address:  I2C_ADDRESS,
-		buffer:   make([]byte, bufferSize),
-		bufIdx:   bufferSize,
-		sentence: strings.Builder{},
+		bus:       bus,
+		address:   I2C_ADDRESS,
+		rx:        newReader(&i2cStream{bus: bus, address: I2C_ADDRESS}),
+		sentence:  strings.Builder{},
+		ubxBuffer: make([]byte, 0, ubxMaxPayload+8),
 	}
 }
 
+// newReader returns a reader large enough for UBX messages, that waits for
+// data without a timeout.
+func newReader(src uartbuf.Source) *uartbuf.Reader {
+	rx := uartbuf.NewReader(src, ubxMaxPayload+8)
+	rx.Poll = pollInterval
+	return rx
+}
+
 // NextSentence returns the next valid NMEA sentence from the GPS device.
 func (gps *Device) NextSentence() (sentence string, err error) {
 	sentence = gps.readNextSentence()
@@ -91,17 +106,22 @@ func (gps *Device) NextSentence() (sentence string, err error) {
 // readNextSentence returns the next sentence from the GPS device.
 func (gps *Device) readNextSentence() (sentence string) {
 	gps.sentence.Reset()
-	var b byte = ' '
 
-	for b != startingDelimiter {
-		b = gps.readNextByte()
+	// skip to the start of the sentence
+	for {
+		if _, err := gps.rx.ReadUntil(startingDelimiter); err != uartbuf.ErrBufferFull {
+			break
+		}
 	}
-
-	for b != checksumDelimiter {
-		gps.sentence.WriteByte(b)
-		b = gps.readNextByte()
+	gps.sentence.WriteByte(startingDelimiter)
+
+	for {
+		data, err := gps.rx.ReadUntil(checksumDelimiter)
+		gps.sentence.Write(data)
+		if err != uartbuf.ErrBufferFull {
+			break
+		}
 	}
-	gps.sentence.WriteByte(b)
 	gps.sentence.WriteByte(gps.readNextByte())
 	gps.sentence.WriteByte(gps.readNextByte())
 
@@ -110,43 +130,34 @@ func (gps *Device) readNextSentence() (sentence string) {
 }
 
 func (gps *Device) readNextByte() (b byte) {
-	gps.bufIdx += 1
-	if gps.bufIdx >= bufferSize {
-		gps.fillBuffer()
-	}
-	return gps.buffer[gps.bufIdx]
+	b, _ = gps.rx.ReadByte()
+	return b
 }
 
-func (gps *Device) fillBuffer() {
-	if gps.uart != nil {
-		gps.uartFillBuffer()
-	} else {
-		gps.i2cFillBuffer()
-	}
+// i2cStream reads the data stream of a u-blox receiver over I2C.
+type i2cStream struct {
+	bus     drivers.I2C
+	address uint16
 }
 
-func (gps *Device) uartFillBuffer() {
-	for gps.uart.Buffered() < bufferSize {
-		time.Sleep(100 * time.Millisecond)
-	}
-	gps.uart.Read(gps.buffer[0:bufferSize])
-	gps.bufIdx = 0
+// Buffered returns how many bytes of GPS data are currently available.
+func (s *i2cStream) Buffered() int {
+	var lengthBytes [2]byte
+	s.bus.Tx(s.address, []byte{BYTES_AVAIL_REG}, lengthBytes[0:2])
+	return int(lengthBytes[0])*256 + int(lengthBytes[1])
 }
 
-func (gps *Device) i2cFillBuffer() {
-	for gps.available() < bufferSize {
-		time.Sleep(100 * time.Millisecond)
+// Read reads the available GPS data, up to len(b) bytes.
+func (s *i2cStream) Read(b []byte) (int, error) {
+	n := s.Buffered()
+	if n > len(b) {
+		n = len(b)
 	}
-	gps.bus.Tx(gps.address, []byte{DATA_STREAM_REG}, gps.buffer[0:bufferSize])
-	gps.bufIdx = 0
-}
-
-// Available returns how many bytes of GPS data are currently available.
-func (gps *Device) available() (available int) {
-	var lengthBytes [2]byte
-	gps.bus.Tx(gps.address, []byte{BYTES_AVAIL_REG}, lengthBytes[0:2])
-	available = int(lengthBytes[0])*256 + int(lengthBytes[1])
-	return available
+	if n == 0 {
+		return 0, nil
+	}
+	err := s.bus.Tx(s.address, []byte{DATA_STREAM_REG}, b[:n])
+	return n, err
 }
 
 // WriteBytes sends data/commands to the GPS device
@@ -8,6 +8,55 @@ import (
 
 // Parser for GPS NMEA sentences.
 type Parser struct {
+	// satellites is built up from GSV and GSA sentences as they arrive.
+	satellites Satellites
+	// lastType is the type of the previously parsed sentence, used to group
+	// consecutive GSA sentences from multi-constellation receivers.
+	lastType string
+}
+
+// maxSatellites is the maximum number of satellites tracked in a Satellites
+// snapshot.
+const maxSatellites = 32
+
+// Satellite is a satellite in view, as reported by GSV sentences.
+type Satellite struct {
+	// Talker is the constellation the satellite belongs to, for example "GP"
+	// for GPS or "GL" for GLONASS.
+	Talker [2]byte
+
+	// PRN is the satellite ID.
+	PRN int16
+
+	// Elevation in degrees, 0-90.
+	Elevation int16
+
+	// Azimuth in degrees from true north, 0-359.
+	Azimuth int16
+
+	// SNR is the signal to noise ratio in dB-Hz, or 0 if not tracked.
+	SNR int16
+}
+
+// Satellites is a snapshot of the satellites in view and the quality of the
+// current fix.
+type Satellites struct {
+	// InView holds NumInView satellites, from GSV sentences.
+	InView    [maxSatellites]Satellite
+	NumInView int
+
+	// Used holds the PRNs of the NumUsed satellites used in the fix, from GSA
+	// sentences.
+	Used    [maxSatellites]int16
+	NumUsed int
+
+	// FixType is 1 for no fix, 2 for a 2D fix and 3 for a 3D fix.
+	FixType uint8
+
+	// Dilution of precision (position, horizontal and vertical).
+	PDOP float32
+	HDOP float32
+	VDOP float32
 }
 
 // Fix is a GPS location fix
@@ -30,10 +79,10 @@ type Fix struct {
 	// Satellites is the number of visible satellites, but is only returned for GGA sentences.
 	Satellites int16
 
-	// Speed based on reported movement. Only returned for RMC sentences.
+	// Speed based on reported movement. Only returned for RMC and VTG sentences.
 	Speed float32
 
-	// Heading based on reported movement. Only returned for RMC sentences.
+	// Heading based on reported movement. Only returned for RMC and VTG sentences.
 	Heading float32
 }
 
@@ -42,7 +91,16 @@ func NewParser() Parser {
 	return Parser{}
 }
 
+// Satellites returns a snapshot of the satellites in view and the dilution of
+// precision, as gathered from the GSV and GSA sentences parsed so far.
+func (parser *Parser) Satellites() Satellites {
+	return parser.satellites
+}
+
 // Parse parses a NMEA sentence looking for fix info.
+//
+// GSV and GSA sentences do not carry a position, they update the snapshot
+// returned by Satellites instead and return a Fix that is not Valid.
 func (parser *Parser) Parse(sentence string) (Fix, error) {
 	var fix Fix
 	if sentence == "" {
@@ -52,6 +110,8 @@ func (parser *Parser) Parse(sentence string) (Fix, error) {
 		return fix, errInvalidNMEASentenceLength
 	}
 	typ := sentence[3:6]
+	lastType := parser.lastType
+	parser.lastType = typ
 	switch typ {
 	case "GGA":
 		// https://docs.novatel.com/OEM7/Content/Logs/GPGGA.htm
@@ -98,12 +158,108 @@ func (parser *Parser) Parse(sentence string) (Fix, error) {
 		date := findDate(fields[9])
 		fix.Time = fix.Time.AddDate(date.Year(), int(date.Month()), date.Day())
 
+		return fix, nil
+	case "VTG":
+		// https://docs.novatel.com/OEM7/Content/Logs/GPVTG.htm
+		fields := strings.Split(stripChecksum(sentence), ",")
+		if len(fields) < 9 {
+			return fix, errInvalidVTGSentence
+		}
+
+		fix.Heading = findHeading(fields[1])
+		fix.Speed = findSpeed(fields[5])
+
+		return fix, nil
+	case "GSV":
+		// https://docs.novatel.com/OEM7/Content/Logs/GPGSV.htm
+		fields := strings.Split(stripChecksum(sentence), ",")
+		if len(fields) < 4 {
+			return fix, errInvalidGSVSentence
+		}
+
+		sats := &parser.satellites
+		talker := [2]byte{sentence[1], sentence[2]}
+		if fields[2] == "1" {
+			// First sentence of a new set, drop the previous set for this
+			// constellation.
+			n := 0
+			for _, sat := range sats.InView[:sats.NumInView] {
+				if sat.Talker != talker {
+					sats.InView[n] = sat
+					n++
+				}
+			}
+			sats.NumInView = n
+		}
+		// Up to four satellites per sentence, optionally followed by a
+		// signal ID (NMEA 4.10).
+		for i := 4; i+4 <= len(fields) && sats.NumInView < maxSatellites; i += 4 {
+			sats.InView[sats.NumInView] = Satellite{
+				Talker:    talker,
+				PRN:       findInt16(fields[i]),
+				Elevation: findInt16(fields[i+1]),
+				Azimuth:   findInt16(fields[i+2]),
+				SNR:       findInt16(fields[i+3]),
+			}
+			sats.NumInView++
+		}
+
+		return fix, nil
+	case "GSA":
+		// https://docs.novatel.com/OEM7/Content/Logs/GPGSA.htm
+		fields := strings.Split(stripChecksum(sentence), ",")
+		if len(fields) < 18 {
+			return fix, errInvalidGSASentence
+		}
+
+		sats := &parser.satellites
+		if lastType != "GSA" {
+			// Multi-constellation receivers send one GSA sentence per
+			// constellation in a row, only start over for a new group.
+			sats.NumUsed = 0
+		}
+		for _, prn := range fields[3:15] {
+			if prn != "" && sats.NumUsed < maxSatellites {
+				sats.Used[sats.NumUsed] = findInt16(prn)
+				sats.NumUsed++
+			}
+		}
+		sats.FixType = uint8(findInt16(fields[2]))
+		sats.PDOP = findDOP(fields[15])
+		sats.HDOP = findDOP(fields[16])
+		sats.VDOP = findDOP(fields[17])
+
 		return fix, nil
 	}
 
 	return fix, newGPSError(errUnknownNMEASentence, sentence, typ)
 }
 
+// stripChecksum removes the trailing *hh checksum from an NMEA sentence, so
+// the last field can be parsed like any other.
+func stripChecksum(sentence string) string {
+	if i := strings.IndexByte(sentence, checksumDelimiter); i >= 0 {
+		return sentence[:i]
+	}
+	return sentence
+}
+
+// findInt16 returns a small integer field such as a satellite PRN, or 0 if the
+// field is empty.
+func findInt16(val string) int16 {
+	v, _ := strconv.ParseInt(val, 10, 16)
+	return int16(v)
+}
+
+// findDOP returns a dilution of precision from a GSA NMEA sentence.
+func findDOP(val string) float32 {
+	if len(val) > 0 {
+		var v, _ = strconv.ParseFloat(val, 32)
+		return float32(v)
+	}
+	return 0
+}
+
 // findTime returns the time from an NMEA sentence:
 // $--GGA,hhmmss.ss,,,,,,,,,,,,,*xx
 func findTime(val string) time.Time {
@@ -12,7 +12,7 @@ func TestParseUnknownSentence(t *testing.T) {
 
 	p := NewParser()
 
-	val := "$GPGSV,3,1,09,07,14,317,22,08,31,284,25,10,32,133,39,16,85,232,29*7F"
+	val := "$GPTXT,01,01,02,ANTSTATUS=OK*3B"
 	_, err := p.Parse(val)
 	c.Assert(err.Error(), qt.Contains, "unsupported NMEA sentence type")
 }
@@ -86,6 +86,83 @@ func TestParseRMC(t *testing.T) {
 	c.Assert(fix.Longitude, qt.Equals, float32(-114.03067779541016))
 }
 
+func TestParseVTG(t *testing.T) {
+	c := qt.New(t)
+
+	p := NewParser()
+
+	val := "$GPVTG,054.7,T"
+	_, err := p.Parse(val)
+	if err != errInvalidVTGSentence {
+		t.Error("should have errInvalidVTGSentence error")
+	}
+
+	val = "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K,A*25"
+	fix, err := p.Parse(val)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fix.Heading, qt.Equals, float32(54.7))
+	c.Assert(fix.Speed, qt.Equals, float32(5.5))
+}
+
+func TestParseGSV(t *testing.T) {
+	c := qt.New(t)
+
+	p := NewParser()
+
+	for _, val := range []string{
+		"$GPGSV,3,1,09,07,14,317,22,08,31,284,25,10,32,133,39,16,85,232,29*7F",
+		"$GPGSV,3,2,09,18,40,058,,20,35,295,41,21,68,102,44,26,54,068,35*7C",
+		"$GPGSV,3,3,09,27,06,254,*43",
+		"$GLGSV,1,1,02,65,22,158,30,66,61,231,*6B",
+	} {
+		_, err := p.Parse(val)
+		c.Assert(err, qt.IsNil)
+	}
+
+	sats := p.Satellites()
+	c.Assert(sats.NumInView, qt.Equals, 11)
+	c.Assert(sats.InView[0], qt.Equals, Satellite{Talker: [2]byte{'G', 'P'}, PRN: 7, Elevation: 14, Azimuth: 317, SNR: 22})
+	c.Assert(sats.InView[4].SNR, qt.Equals, int16(0))
+	c.Assert(sats.InView[8].PRN, qt.Equals, int16(27))
+	c.Assert(sats.InView[9].Talker, qt.Equals, [2]byte{'G', 'L'})
+
+	// a new GPS set replaces the previous GPS satellites only
+	_, err := p.Parse("$GPGSV,1,1,01,07,15,318,23*70")
+	c.Assert(err, qt.IsNil)
+	sats = p.Satellites()
+	c.Assert(sats.NumInView, qt.Equals, 3)
+	c.Assert(sats.InView[2].PRN, qt.Equals, int16(7))
+}
+
+func TestParseGSA(t *testing.T) {
+	c := qt.New(t)
+
+	p := NewParser()
+
+	val := "$GNGSA,A,3,04,05"
+	_, err := p.Parse(val)
+	if err != errInvalidGSASentence {
+		t.Error("should have errInvalidGSASentence error")
+	}
+
+	p.Parse("$GNGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*27")
+	p.Parse("$GNGSA,A,3,65,66,,,,,,,,,,,2.5,1.3,2.1*2D")
+	sats := p.Satellites()
+	c.Assert(sats.FixType, qt.Equals, uint8(3))
+	c.Assert(sats.NumUsed, qt.Equals, 7)
+	c.Assert(sats.Used[:sats.NumUsed], qt.DeepEquals, []int16{4, 5, 9, 12, 24, 65, 66})
+	c.Assert(sats.PDOP, qt.Equals, float32(2.5))
+	c.Assert(sats.HDOP, qt.Equals, float32(1.3))
+	c.Assert(sats.VDOP, qt.Equals, float32(2.1))
+
+	// a GSA after another sentence type starts a new group
+	p.Parse("$GPRMC,203522.00,A,5109.0262308,N,11401.8407342,W,0.004,133.4,130522,0.0,E,D*2B")
+	p.Parse("$GNGSA,A,2,04,,,,,,,,,,,,3.1,2.0,2.4*2D")
+	sats = p.Satellites()
+	c.Assert(sats.FixType, qt.Equals, uint8(2))
+	c.Assert(sats.Used[:sats.NumUsed], qt.DeepEquals, []int16{4})
+}
+
 func TestTime(t *testing.T) {
 	c := qt.New(t)
 
@@ -0,0 +1,65 @@
+package gps
+
+import "time"
+
+// ppsMaxAge is how long after a pulse a time message is still considered to
+// belong to that pulse. Receivers send the time message for a second a few
+// hundred milliseconds after its pulse.
+const ppsMaxAge = 950 * time.Millisecond
+
+// PPS pairs the pulse-per-second output of a GPS receiver with the time
+// message (NMEA or UBX) that follows it. The rising edge of the pulse marks
+// the start of a UTC second much more precisely than the serial messages, so
+// once paired, Now returns UTC with the accuracy of the local clock rather
+// than of the UART.
+//
+// Call Pulse from the PPS pin interrupt (see AttachPPS) and Sync with the
+// time of each parsed fix.
+type PPS struct {
+	pulse int64 // local time of the last pulse in nanoseconds, written by the interrupt, see Sync
+
+	local time.Time // local time of the last paired pulse
+	utc   time.Time // UTC time of the last paired pulse
+}
+
+// Pulse records a pulse at the given local time. It is safe to call from an
+// interrupt handler.
+func (pps *PPS) Pulse(local time.Time) {
+	pps.pulse = local.UnixNano()
+}
+
+// sync pairs utc with the pulse at the local time pulse, as Sync does at the
+// local time now.
+func (pps *PPS) sync(utc time.Time, pulse int64, now time.Time) bool {
+	if pulse == 0 {
+		return false
+	}
+	local := time.Unix(0, pulse)
+	if age := now.Sub(local); age < 0 || age > ppsMaxAge {
+		return false
+	}
+	// The message reports the second that started at the pulse.
+	pps.utc = utc.Truncate(time.Second)
+	pps.local = local
+	return true
+}
+
+// Now returns the current UTC time derived from the last paired pulse, and
+// false if no pulse has been paired yet.
+func (pps *PPS) Now() (time.Time, bool) {
+	return pps.at(time.Now())
+}
+
+func (pps *PPS) at(now time.Time) (time.Time, bool) {
+	if pps.utc.IsZero() {
+		return time.Time{}, false
+	}
+	return pps.utc.Add(now.Sub(pps.local)), true
+}
+
+// LastPulse returns the UTC time of the last paired pulse, which is exactly
+// on a second boundary. This is the value to write to an RTC at the moment
+// of the pulse.
+func (pps *PPS) LastPulse() time.Time {
+	return pps.utc
+}
@@ -0,0 +1,33 @@
+package gps
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPPSSync(t *testing.T) {
+	c := qt.New(t)
+
+	var pps PPS
+	local := time.Date(2000, 1, 1, 0, 0, 10, 0, time.UTC)
+	utc := time.Date(2022, time.May, 13, 20, 35, 22, 0, time.UTC)
+
+	_, ok := pps.at(local)
+	c.Assert(ok, qt.IsFalse)
+	c.Assert(pps.sync(utc, pps.pulse, local), qt.IsFalse)
+
+	pps.Pulse(local)
+	// message arrives 300ms after the pulse, with centiseconds
+	c.Assert(pps.sync(utc.Add(10*time.Millisecond), pps.pulse, local.Add(300*time.Millisecond)), qt.IsTrue)
+	c.Assert(pps.LastPulse(), qt.Equals, utc)
+
+	now, ok := pps.at(local.Add(1234567 * time.Microsecond))
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(now, qt.Equals, utc.Add(1234567*time.Microsecond))
+
+	// a message long after the last pulse is not paired
+	c.Assert(pps.sync(utc.Add(5*time.Second), pps.pulse, local.Add(5*time.Second)), qt.IsFalse)
+	c.Assert(pps.LastPulse(), qt.Equals, utc)
+}
@@ -0,0 +1,30 @@
+//go:build tinygo
+
+package gps
+
+import (
+	"machine"
+	"runtime/interrupt"
+	"time"
+)
+
+// AttachPPS configures pin as an input and timestamps each rising edge of the
+// receiver's PPS output into pps.
+func AttachPPS(pps *PPS, pin machine.Pin) error {
+	pin.Configure(machine.PinConfig{Mode: machine.PinInput})
+	return pin.SetInterrupt(machine.PinRising, func(machine.Pin) {
+		pps.Pulse(time.Now())
+	})
+}
+
+// Sync pairs the UTC time from a time message with the pulse that preceded
+// it. It returns false when there was no recent pulse, for example when the
+// receiver has no fix and does not output PPS.
+func (pps *PPS) Sync(utc time.Time) bool {
+	// The interrupt may write the 64-bit time of a pulse in the middle of
+	// reading it.
+	mask := interrupt.Disable()
+	pulse := pps.pulse
+	interrupt.Restore(mask)
+	return pps.sync(utc, pulse, time.Now())
+}
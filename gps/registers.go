@@ -11,7 +11,3 @@ const (
 	BYTES_AVAIL_REG = 0xfd
 	DATA_STREAM_REG = 0xff
 )
-
-const (
-	bufferSize = 100
-)
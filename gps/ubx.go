@@ -0,0 +1,317 @@
+package gps
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers/uartbuf"
+)
+
+// UBX protocol reference:
+// https://content.u-blox.com/sites/default/files/products/documents/u-blox8-M8_ReceiverDescrProtSpec_UBX-13003221.pdf
+
+var (
+	errUBXChecksum     = errors.New("invalid UBX message checksum")
+	errUBXTooLong      = errors.New("UBX message too long")
+	errUBXNak          = errors.New("UBX command rejected by receiver")
+	errUBXNoAck        = errors.New("no UBX acknowledge from receiver")
+	errInvalidNAVPVT   = errors.New("invalid UBX NAV-PVT message")
+	errInvalidUBXParam = errors.New("invalid UBX parameter")
+)
+
+// UBX framing.
+const (
+	ubxSync1 = 0xB5
+	ubxSync2 = 0x62
+
+	ubxMaxPayload = 256
+	ubxAckTimeout = time.Second
+)
+
+// UBX message classes and IDs.
+const (
+	UBX_CLASS_NAV = 0x01
+	UBX_CLASS_ACK = 0x05
+	UBX_CLASS_CFG = 0x06
+
+	UBX_NAV_PVT = 0x07
+
+	UBX_ACK_NAK = 0x00
+	UBX_ACK_ACK = 0x01
+
+	UBX_CFG_MSG  = 0x01
+	UBX_CFG_RATE = 0x08
+	UBX_CFG_NAV5 = 0x24
+	UBX_CFG_GNSS = 0x3E
+)
+
+// DynamicModel is the platform model used by the navigation engine. It
+// changes the filtering and sanity checks applied to fixes.
+type DynamicModel uint8
+
+const (
+	DynamicModelPortable   DynamicModel = 0
+	DynamicModelStationary DynamicModel = 2
+	DynamicModelPedestrian DynamicModel = 3
+	DynamicModelAutomotive DynamicModel = 4
+	DynamicModelSea        DynamicModel = 5
+	DynamicModelAirborne1g DynamicModel = 6
+	DynamicModelAirborne2g DynamicModel = 7
+	DynamicModelAirborne4g DynamicModel = 8
+	DynamicModelWrist      DynamicModel = 9
+)
+
+// Constellation is a bitmask of satellite systems, used with
+// SetConstellations.
+type Constellation uint8
+
+const (
+	ConstellationGPS Constellation = 1 << iota
+	ConstellationSBAS
+	ConstellationGalileo
+	ConstellationBeiDou
+	ConstellationIMES
+	ConstellationQZSS
+	ConstellationGLONASS
+)
+
+// gnssChannels holds the minimum and maximum tracking channels reserved for
+// each constellation in CFG-GNSS, indexed by u-blox gnssId.
+var gnssChannels = [...][2]uint8{
+	{8, 16}, // GPS
+	{1, 3},  // SBAS
+	{4, 8},  // Galileo
+	{8, 16}, // BeiDou
+	{0, 8},  // IMES
+	{0, 3},  // QZSS
+	{8, 14}, // GLONASS
+}
+
+// UBXMessage is a single binary UBX protocol message.
+type UBXMessage struct {
+	Class   byte
+	ID      byte
+	Payload []byte
+}
+
+// PVT is a navigation solution as reported by the UBX NAV-PVT message.
+type PVT struct {
+	// Time is the UTC time of the solution. Only meaningful if TimeValid.
+	Time      time.Time
+	TimeValid bool
+
+	// FixType is 0: no fix, 1: dead reckoning, 2: 2D, 3: 3D, 4: GNSS + dead
+	// reckoning, 5: time only.
+	FixType uint8
+
+	// FixOK is set when the fix is within the configured accuracy masks.
+	FixOK bool
+
+	// Satellites is the number of satellites used in the solution.
+	Satellites uint8
+
+	// Latitude and Longitude in degrees.
+	Latitude  float32
+	Longitude float32
+
+	// Height above the ellipsoid and above mean sea level in millimeters.
+	Height    int32
+	HeightMSL int32
+
+	// Horizontal and vertical accuracy estimates in millimeters.
+	HorizontalAccuracy uint32
+	VerticalAccuracy   uint32
+
+	// GroundSpeed in millimeters per second.
+	GroundSpeed int32
+
+	// Heading of motion in degrees.
+	Heading float32
+
+	// PDOP is the position dilution of precision.
+	PDOP float32
+}
+
+// Fix converts the navigation solution into a Fix, in the same units as
+// returned by the NMEA Parser.
+func (p PVT) Fix() Fix {
+	return Fix{
+		Valid:      p.FixOK && p.FixType >= 2,
+		Time:       p.Time,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+		Altitude:   p.HeightMSL / 1000,
+		Satellites: int16(p.Satellites),
+		Speed:      float32(p.GroundSpeed) * 0.001943844, // mm/s to knots
+		Heading:    p.Heading,
+	}
+}
+
+// ParseNAVPVT decodes the payload of a UBX NAV-PVT message.
+func ParseNAVPVT(msg UBXMessage) (PVT, error) {
+	var pvt PVT
+	b := msg.Payload
+	if msg.Class != UBX_CLASS_NAV || msg.ID != UBX_NAV_PVT || len(b) < 92 {
+		return pvt, errInvalidNAVPVT
+	}
+	valid := b[11]
+	pvt.TimeValid = valid&0x03 == 0x03
+	pvt.Time = time.Date(int(binary.LittleEndian.Uint16(b[4:])), time.Month(b[6]), int(b[7]),
+		int(b[8]), int(b[9]), int(b[10]), 0, time.UTC)
+	// nano is a signed correction to the rounded seconds above
+	pvt.Time = pvt.Time.Add(time.Duration(int32(binary.LittleEndian.Uint32(b[16:]))))
+	pvt.FixType = b[20]
+	pvt.FixOK = b[21]&0x01 != 0
+	pvt.Satellites = b[23]
+	pvt.Longitude = float32(int32(binary.LittleEndian.Uint32(b[24:]))) / 1e7
+	pvt.Latitude = float32(int32(binary.LittleEndian.Uint32(b[28:]))) / 1e7
+	pvt.Height = int32(binary.LittleEndian.Uint32(b[32:]))
+	pvt.HeightMSL = int32(binary.LittleEndian.Uint32(b[36:]))
+	pvt.HorizontalAccuracy = binary.LittleEndian.Uint32(b[40:])
+	pvt.VerticalAccuracy = binary.LittleEndian.Uint32(b[44:])
+	pvt.GroundSpeed = int32(binary.LittleEndian.Uint32(b[60:]))
+	pvt.Heading = float32(int32(binary.LittleEndian.Uint32(b[64:]))) / 1e5
+	pvt.PDOP = float32(binary.LittleEndian.Uint16(b[76:])) / 100
+	return pvt, nil
+}
+
+// ubxChecksum computes the 8-bit Fletcher checksum over the class, ID,
+// length and payload of a UBX message.
+func ubxChecksum(b []byte) (ckA, ckB byte) {
+	for _, c := range b {
+		ckA += c
+		ckB += ckA
+	}
+	return ckA, ckB
+}
+
+// WriteUBX frames and sends a UBX message to the GPS device.
+func (gps *Device) WriteUBX(class, id byte, payload []byte) error {
+	if len(payload) > ubxMaxPayload {
+		return errUBXTooLong
+	}
+	b := gps.ubxBuffer[:0]
+	b = append(b, ubxSync1, ubxSync2, class, id, byte(len(payload)), byte(len(payload)>>8))
+	b = append(b, payload...)
+	ckA, ckB := ubxChecksum(b[2:])
+	b = append(b, ckA, ckB)
+	gps.WriteBytes(b)
+	return nil
+}
+
+// ubxFraming describes UBX messages: two sync bytes, class and ID, a little
+// endian payload length and a two byte checksum after the payload.
+var ubxFraming = uartbuf.Framing{
+	Sync:         []byte{ubxSync1, ubxSync2},
+	LengthOffset: 4,
+	LengthSize:   2,
+	LittleEndian: true,
+	Trailer:      2,
+}
+
+// NextUBX returns the next valid UBX message from the GPS device, skipping
+// any NMEA sentences in between. The payload is only valid until the next
+// read from the GPS device.
+func (gps *Device) NextUBX() (msg UBXMessage, err error) {
+	b, err := gps.rx.ReadPacket(ubxFraming)
+	if err == uartbuf.ErrPacketTooLong {
+		return msg, errUBXTooLong
+	}
+	if err != nil {
+		return msg, err
+	}
+	n := len(b) - 2
+	ckA, ckB := ubxChecksum(b[2:n])
+	if b[n] != ckA || b[n+1] != ckB {
+		return msg, errUBXChecksum
+	}
+	msg.Class = b[2]
+	msg.ID = b[3]
+	msg.Payload = b[6:n]
+	return msg, nil
+}
+
+// SendUBXCommand sends a UBX CFG message and waits for the receiver to
+// acknowledge it.
+func (gps *Device) SendUBXCommand(class, id byte, payload []byte) error {
+	if err := gps.WriteUBX(class, id, payload); err != nil {
+		return err
+	}
+	// the reader waits for messages at most until the acknowledge is overdue
+	defer func() { gps.rx.Timeout = 0 }()
+	start := time.Now()
+	for {
+		remaining := ubxAckTimeout - time.Since(start)
+		if remaining <= 0 {
+			return errUBXNoAck
+		}
+		gps.rx.Timeout = remaining
+		msg, err := gps.NextUBX()
+		if err == uartbuf.ErrTimeout {
+			return errUBXNoAck
+		}
+		if err != nil || msg.Class != UBX_CLASS_ACK || len(msg.Payload) < 2 {
+			continue
+		}
+		if msg.Payload[0] != class || msg.Payload[1] != id {
+			continue
+		}
+		if msg.ID == UBX_ACK_NAK {
+			return errUBXNak
+		}
+		return nil
+	}
+}
+
+// SetMessageRate sets how often the given UBX message is output on the
+// current port, in navigation solutions (0 disables the message). Use
+// SetMessageRate(UBX_CLASS_NAV, UBX_NAV_PVT, 1) to receive NAV-PVT for
+// every fix.
+func (gps *Device) SetMessageRate(class, id, rate byte) error {
+	return gps.SendUBXCommand(UBX_CLASS_CFG, UBX_CFG_MSG, []byte{class, id, rate})
+}
+
+// SetUpdateRate sets the interval between navigation solutions. Most
+// receivers support 1 Hz to 10 Hz, some up to 25 Hz.
+func (gps *Device) SetUpdateRate(interval time.Duration) error {
+	ms := interval / time.Millisecond
+	if ms < 25 || ms > 0xFFFF {
+		return errInvalidUBXParam
+	}
+	var payload [6]byte
+	binary.LittleEndian.PutUint16(payload[0:], uint16(ms))
+	binary.LittleEndian.PutUint16(payload[2:], 1) // one measurement per solution
+	binary.LittleEndian.PutUint16(payload[4:], 1) // align to GPS time
+	return gps.SendUBXCommand(UBX_CLASS_CFG, UBX_CFG_RATE, payload[:])
+}
+
+// SetDynamicModel sets the platform model used by the navigation engine.
+// Use DynamicModelAirborne1g or higher above the COCOM altitude limit.
+func (gps *Device) SetDynamicModel(model DynamicModel) error {
+	var payload [36]byte
+	payload[0] = 0x01 // only apply the dynamic model setting
+	payload[2] = byte(model)
+	return gps.SendUBXCommand(UBX_CLASS_CFG, UBX_CFG_NAV5, payload[:])
+}
+
+// SetConstellations enables the given satellite systems and disables all
+// others. Not every receiver supports every combination; unsupported ones
+// are rejected with an error.
+func (gps *Device) SetConstellations(c Constellation) error {
+	var payload [4 + 8*len(gnssChannels)]byte
+	payload[2] = 0xFF // use all available tracking channels
+	payload[3] = byte(len(gnssChannels))
+	for i, ch := range gnssChannels {
+		block := payload[4+8*i:]
+		block[0] = byte(i)
+		block[1] = ch[0]
+		block[2] = ch[1]
+		flags := uint32(0x00010000) // L1 signal
+		if c&(1<<i) != 0 {
+			flags |= 0x01
+		}
+		binary.LittleEndian.PutUint32(block[4:], flags)
+	}
+	return gps.SendUBXCommand(UBX_CLASS_CFG, UBX_CFG_GNSS, payload[:])
+}
@@ -0,0 +1,126 @@
+package gps
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type fakeUART struct {
+	bytes.Buffer
+}
+
+func (u *fakeUART) Buffered() int { return u.Len() }
+
+func TestWriteUBX(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := NewUART(uart)
+
+	// CFG-RATE, 200ms measurement interval
+	err := d.WriteUBX(UBX_CLASS_CFG, UBX_CFG_RATE, []byte{0xC8, 0x00, 0x01, 0x00, 0x01, 0x00})
+	c.Assert(err, qt.IsNil)
+	c.Assert(uart.Bytes(), qt.DeepEquals, []byte{
+		0xB5, 0x62, 0x06, 0x08, 0x06, 0x00, 0xC8, 0x00, 0x01, 0x00, 0x01, 0x00, 0xDE, 0x6A,
+	})
+}
+
+func TestNextUBX(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := NewUART(uart)
+
+	// an NMEA sentence followed by ACK-ACK for CFG-RATE
+	uart.WriteString("$GPTXT,01,01,02,ANTSTATUS=OK*3B\r\n")
+	uart.Write([]byte{0xB5, 0x62, 0x05, 0x01, 0x02, 0x00, 0x06, 0x08, 0x16, 0x3F})
+	msg, err := d.NextUBX()
+	c.Assert(err, qt.IsNil)
+	c.Assert(msg.Class, qt.Equals, byte(UBX_CLASS_ACK))
+	c.Assert(msg.ID, qt.Equals, byte(0x01))
+	c.Assert(msg.Payload, qt.DeepEquals, []byte{0x06, 0x08})
+
+	uart.Write([]byte{0xB5, 0x62, 0x05, 0x01, 0x02, 0x00, 0x06, 0x08, 0x16, 0x40})
+	_, err = d.NextUBX()
+	c.Assert(err, qt.Equals, errUBXChecksum)
+}
+
+// silentUART is a receiver that never answers.
+type silentUART struct{}
+
+func (silentUART) Read(p []byte) (int, error)  { return 0, nil }
+func (silentUART) Write(p []byte) (int, error) { return len(p), nil }
+func (silentUART) Buffered() int               { return 0 }
+
+func TestSendUBXCommand(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := NewUART(uart)
+
+	// the command is read back, followed by the NAK of another command and
+	// the ACK-ACK of CFG-RATE
+	uart.Write([]byte{0xB5, 0x62, 0x05, 0x00, 0x02, 0x00, 0x06, 0x01, 0x0E, 0x33})
+	uart.Write([]byte{0xB5, 0x62, 0x05, 0x01, 0x02, 0x00, 0x06, 0x08, 0x16, 0x3F})
+	c.Assert(d.SetUpdateRate(200*time.Millisecond), qt.IsNil)
+
+	d = NewUART(silentUART{})
+	start := time.Now()
+	c.Assert(d.SetUpdateRate(200*time.Millisecond), qt.Equals, errUBXNoAck)
+	c.Assert(time.Since(start) < 2*ubxAckTimeout, qt.IsTrue)
+	c.Assert(d.rx.Timeout, qt.Equals, time.Duration(0))
+}
+
+func TestNextSentence(t *testing.T) {
+	c := qt.New(t)
+	uart := &fakeUART{}
+	d := NewUART(uart)
+
+	uart.WriteString("77\r\n$GPTXT,01,01,02,ANTSTATUS=OK*3B\r\n")
+	sentence, err := d.NextSentence()
+	c.Assert(err, qt.IsNil)
+	c.Assert(sentence, qt.Equals, "$GPTXT,01,01,02,ANTSTATUS=OK*3B")
+}
+
+func TestParseNAVPVT(t *testing.T) {
+	c := qt.New(t)
+
+	payload := make([]byte, 92)
+	binary.LittleEndian.PutUint16(payload[4:], 2022)
+	payload[6] = 5
+	payload[7] = 13
+	payload[8] = 20
+	payload[9] = 35
+	payload[10] = 22
+	payload[11] = 0x07
+	binary.LittleEndian.PutUint32(payload[16:], uint32(500000000))
+	payload[20] = 3
+	payload[21] = 0x01
+	payload[23] = 11
+	binary.LittleEndian.PutUint32(payload[24:], uint32(1140306407)) // 114.0306407 E
+	lat := int32(-511504364)                                        // 51.1504364 S
+	binary.LittleEndian.PutUint32(payload[28:], uint32(lat))
+	binary.LittleEndian.PutUint32(payload[36:], 255747)
+	binary.LittleEndian.PutUint32(payload[60:], 1000)
+	binary.LittleEndian.PutUint32(payload[64:], 13340000)
+	binary.LittleEndian.PutUint16(payload[76:], 135)
+
+	pvt, err := ParseNAVPVT(UBXMessage{Class: UBX_CLASS_NAV, ID: UBX_NAV_PVT, Payload: payload})
+	c.Assert(err, qt.IsNil)
+	c.Assert(pvt.TimeValid, qt.IsTrue)
+	c.Assert(pvt.Time, qt.Equals, time.Date(2022, time.May, 13, 20, 35, 22, 500000000, time.UTC))
+	c.Assert(pvt.FixType, qt.Equals, uint8(3))
+	c.Assert(pvt.Satellites, qt.Equals, uint8(11))
+	c.Assert(pvt.Latitude, qt.Equals, float32(-51.1504364))
+	c.Assert(pvt.Longitude, qt.Equals, float32(114.0306407))
+	c.Assert(pvt.Heading, qt.Equals, float32(133.4))
+	c.Assert(pvt.PDOP, qt.Equals, float32(1.35))
+
+	fix := pvt.Fix()
+	c.Assert(fix.Valid, qt.IsTrue)
+	c.Assert(fix.Altitude, qt.Equals, int32(255))
+
+	_, err = ParseNAVPVT(UBXMessage{Class: UBX_CLASS_NAV, ID: UBX_NAV_PVT, Payload: payload[:40]})
+	c.Assert(err, qt.Equals, errInvalidNAVPVT)
+}
@@ -0,0 +1,130 @@
+package hcsr04
+
+import (
+	"machine"
+	"time"
+)
+
+// Measurement states of the asynchronous API.
+const (
+	stateIdle uint8 = iota
+	stateTriggered
+	stateEcho
+	stateDone
+)
+
+// CycleTime is the minimum time between two triggers, so that echoes of a
+// measurement don't disturb the next one. It also applies between sensors
+// facing the same direction.
+const CycleTime = 60 * time.Millisecond
+
+// ConfigureInterrupt sets up a pin change interrupt on the echo pin, which is
+// needed for Trigger and Result. The interrupt handler timestamps both edges
+// of the echo pulse, so measuring doesn't busy-wait the CPU.
+func (d *Device) ConfigureInterrupt() error {
+	return d.echo.SetInterrupt(machine.PinToggle, d.handleEcho)
+}
+
+func (d *Device) handleEcho(pin machine.Pin) {
+	now := time.Now()
+	switch d.state {
+	case stateTriggered:
+		if pin.Get() {
+			d.rise = now
+			d.state = stateEcho
+		}
+	case stateEcho:
+		if !pin.Get() {
+			d.fall = now
+			d.state = stateDone
+		}
+	}
+}
+
+// Trigger starts a measurement. Use Result to collect it.
+func (d *Device) Trigger() {
+	d.state = stateIdle
+	d.trigger.High()
+	time.Sleep(10 * time.Microsecond)
+	d.triggered = time.Now()
+	d.state = stateTriggered
+	d.trigger.Low()
+}
+
+// Result returns the distance in mm measured after the last Trigger. ready is
+// false while the measurement is in progress. A distance of 0 means that no
+// echo was received within range.
+func (d *Device) Result() (distance int32, ready bool) {
+	pulse, ready := d.PulseResult()
+	return pulseToDistance(pulse), ready
+}
+
+// PulseResult returns the echo pulse (roundtrip) in microseconds measured
+// after the last Trigger, like Result.
+func (d *Device) PulseResult() (pulse int32, ready bool) {
+	switch d.state {
+	case stateIdle:
+		return 0, true
+	case stateDone:
+		return int32(d.fall.Sub(d.rise).Microseconds()), true
+	}
+	// The sensor raises echo a few hundred µs after the trigger and drops it
+	// after at most ~38ms without an object in range.
+	if time.Since(d.triggered) > CycleTime {
+		d.state = stateIdle
+		return 0, true
+	}
+	return 0, false
+}
+
+// Scheduler measures with several sensors in turn, one at a time, so that
+// they don't pick up each other's echoes.
+type Scheduler struct {
+	sensors   []*Device
+	distances []int32
+	current   int
+	next      time.Time
+	running   bool
+}
+
+// NewScheduler returns a scheduler for the given sensors, which must be
+// configured with Configure and ConfigureInterrupt.
+func NewScheduler(sensors ...*Device) *Scheduler {
+	return &Scheduler{
+		sensors:   sensors,
+		distances: make([]int32, len(sensors)),
+	}
+}
+
+// Update collects the result of the running measurement and triggers the
+// next sensor once CycleTime has passed. Call it regularly from the main
+// loop; it never blocks for longer than the 10µs trigger pulse. It returns
+// the index of the sensor whose distance was updated, or -1.
+func (s *Scheduler) Update() int {
+	if len(s.sensors) == 0 {
+		return -1
+	}
+	updated := -1
+	if s.running {
+		distance, ready := s.sensors[s.current].Result()
+		if !ready {
+			return -1
+		}
+		s.distances[s.current] = distance
+		updated = s.current
+		s.current = (s.current + 1) % len(s.sensors)
+		s.running = false
+	}
+	if time.Now().After(s.next) {
+		s.next = time.Now().Add(CycleTime)
+		s.sensors[s.current].Trigger()
+		s.running = true
+	}
+	return updated
+}
+
+// Distance returns the last distance in mm measured by sensor i, 0 if out of
+// range.
+func (s *Scheduler) Distance(i int) int32 {
+	return s.distances[i]
+}
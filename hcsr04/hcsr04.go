@@ -15,6 +15,12 @@ const TIMEOUT = 23324 // max sensing distance (4m)
 type Device struct {
 	trigger machine.Pin
 	echo    machine.Pin
+
+	// asynchronous measurement, see Trigger
+	state     uint8
+	triggered time.Time
+	rise      time.Time
+	fall      time.Time
 }
 
 // New returns a new ultrasonic driver given 2 pins
@@ -33,8 +39,11 @@ func (d *Device) Configure() {
 
 // ReadDistance returns the distance of the object in mm
 func (d *Device) ReadDistance() int32 {
-	pulse := d.ReadPulse()
+	return pulseToDistance(d.ReadPulse())
+}
 
+// pulseToDistance converts the echo pulse to the distance in mm
+func pulseToDistance(pulse int32) int32 {
 	// sound speed is 343000 mm/s
 	// pulse is roundtrip measured in microseconds
 	// distance = velocity * time
@@ -77,5 +86,4 @@ func (d *Device) ReadPulse() int32 {
 			i = 0
 		}
 	}
-	return 0
 }
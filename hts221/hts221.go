@@ -144,27 +144,22 @@ func (d *Device) waitForOneShot(filter uint8) error {
 	}
 
 	// wait until one shot (one conversion) is ready to go
-	data[0] = 1
-	for {
-		d.bus.ReadRegister(d.Address, HTS221_CTRL2_REG, data)
-		if data[0]&0x01 == 0 {
-			break
-		}
+	err := drivers.DefaultRetryPolicy.Until(func() (bool, error) {
+		err := d.bus.ReadRegister(d.Address, HTS221_CTRL2_REG, data)
+		return data[0]&0x01 == 0, err
+	})
+	if err != nil {
+		return err
 	}
 
 	// trigger one shot
 	d.bus.WriteRegister(d.Address, HTS221_CTRL2_REG, []byte{0x01})
 
 	// wait until conversion completed
-	data[0] = 0
-	for {
-		d.bus.ReadRegister(d.Address, HTS221_STATUS_REG, data)
-		if data[0]&filter == filter {
-			break
-		}
-	}
-
-	return nil
+	return drivers.DefaultRetryPolicy.Until(func() (bool, error) {
+		err := d.bus.ReadRegister(d.Address, HTS221_STATUS_REG, data)
+		return data[0]&filter == filter, err
+	})
 }
 
 func readUint(msb byte, lsb byte) uint16 {
@@ -0,0 +1,164 @@
+package drivers
+
+import "encoding/binary"
+
+// I2CDevice is a single device on an I2C bus. It provides register access with
+// 8-bit or 16-bit register addresses and multi-byte values in either byte
+// order, and wraps bus errors in an I2CError identifying the device and
+// register.
+type I2CDevice struct {
+	Bus     I2C
+	Address uint16
+
+	// Register16 selects 16-bit register addresses, sent most significant
+	// byte first. By default register addresses are 8 bits.
+	Register16 bool
+
+	// Order is the byte order of multi-byte register values. Defaults to
+	// big-endian if nil.
+	Order binary.ByteOrder
+
+	buf [10]byte
+}
+
+// NewI2CDevice returns a device with 8-bit register addresses and big-endian
+// values at the given address.
+func NewI2CDevice(bus I2C, address uint16) I2CDevice {
+	return I2CDevice{
+		Bus:     bus,
+		Address: address,
+	}
+}
+
+// I2CError is returned by I2CDevice methods when the bus reports an error.
+type I2CError struct {
+	Address  uint16
+	Register uint16
+	Write    bool
+	Err      error
+
+	register16 bool
+}
+
+// Error implements the error interface.
+func (e *I2CError) Error() string {
+	op := "read"
+	if e.Write {
+		op = "write"
+	}
+	digits := 2
+	if e.register16 {
+		digits = 4
+	}
+	return "i2c device 0x" + hex(e.Address, 2) + ": " + op + " register 0x" + hex(e.Register, digits) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying bus error.
+func (e *I2CError) Unwrap() error {
+	return e.Err
+}
+
+// hex formats v as a hexadecimal number with at least the given number of
+// digits.
+func hex(v uint16, digits int) string {
+	const hexDigits = "0123456789abcdef"
+	var b [4]byte
+	i := len(b)
+	for v != 0 || len(b)-i < digits {
+		i--
+		b[i] = hexDigits[v&0xF]
+		v >>= 4
+	}
+	return string(b[i:])
+}
+
+func (d *I2CDevice) wrap(reg uint16, write bool, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &I2CError{Address: d.Address, Register: reg, Write: write, Err: err, register16: d.Register16}
+}
+
+func (d *I2CDevice) order() binary.ByteOrder {
+	if d.Order == nil {
+		return binary.BigEndian
+	}
+	return d.Order
+}
+
+// ReadRegister reads len(buf) bytes starting at register reg.
+func (d *I2CDevice) ReadRegister(reg uint16, buf []byte) error {
+	if !d.Register16 {
+		return d.wrap(reg, false, d.Bus.ReadRegister(uint8(d.Address), uint8(reg), buf))
+	}
+	w := d.buf[:2]
+	binary.BigEndian.PutUint16(w, reg)
+	return d.wrap(reg, false, d.Bus.Tx(d.Address, w, buf))
+}
+
+// WriteRegister writes buf starting at register reg.
+func (d *I2CDevice) WriteRegister(reg uint16, buf []byte) error {
+	if !d.Register16 {
+		return d.wrap(reg, true, d.Bus.WriteRegister(uint8(d.Address), uint8(reg), buf))
+	}
+	// register address and data must go out in a single transaction
+	var w []byte
+	if len(buf)+2 <= len(d.buf) {
+		w = d.buf[:2+len(buf)]
+	} else {
+		w = make([]byte, 2+len(buf))
+	}
+	binary.BigEndian.PutUint16(w, reg)
+	copy(w[2:], buf)
+	return d.wrap(reg, true, d.Bus.Tx(d.Address, w, nil))
+}
+
+// ReadUint8 reads a single byte register.
+func (d *I2CDevice) ReadUint8(reg uint16) (uint8, error) {
+	b := d.buf[2:3]
+	err := d.ReadRegister(reg, b)
+	return b[0], err
+}
+
+// ReadUint16 reads a 16-bit value starting at register reg.
+func (d *I2CDevice) ReadUint16(reg uint16) (uint16, error) {
+	b := d.buf[2:4]
+	err := d.ReadRegister(reg, b)
+	return d.order().Uint16(b), err
+}
+
+// ReadUint32 reads a 32-bit value starting at register reg.
+func (d *I2CDevice) ReadUint32(reg uint16) (uint32, error) {
+	b := d.buf[2:6]
+	err := d.ReadRegister(reg, b)
+	return d.order().Uint32(b), err
+}
+
+// WriteUint8 writes a single byte register.
+func (d *I2CDevice) WriteUint8(reg uint16, value uint8) error {
+	return d.WriteRegister(reg, []byte{value})
+}
+
+// WriteUint16 writes a 16-bit value starting at register reg.
+func (d *I2CDevice) WriteUint16(reg uint16, value uint16) error {
+	var b [2]byte
+	d.order().PutUint16(b[:], value)
+	return d.WriteRegister(reg, b[:])
+}
+
+// WriteUint32 writes a 32-bit value starting at register reg.
+func (d *I2CDevice) WriteUint32(reg uint16, value uint32) error {
+	var b [4]byte
+	d.order().PutUint32(b[:], value)
+	return d.WriteRegister(reg, b[:])
+}
+
+// UpdateUint8 reads a single byte register, clears the bits in mask, sets the
+// bits in value and writes the result back.
+func (d *I2CDevice) UpdateUint8(reg uint16, mask, value uint8) error {
+	v, err := d.ReadUint8(reg)
+	if err != nil {
+		return err
+	}
+	return d.WriteUint8(reg, v&^mask|value&mask)
+}
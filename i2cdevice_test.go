@@ -0,0 +1,89 @@
+package drivers_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
+)
+
+// bus16 is a fake I2C bus for a device with 16-bit register addresses.
+type bus16 struct {
+	registers [0x300]byte
+	err       error
+}
+
+func (b *bus16) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	panic("unexpected ReadRegister")
+}
+
+func (b *bus16) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	panic("unexpected WriteRegister")
+}
+
+func (b *bus16) Tx(addr uint16, w, r []byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	reg := binary.BigEndian.Uint16(w)
+	copy(b.registers[reg:], w[2:])
+	copy(r, b.registers[reg:])
+	return nil
+}
+
+func TestI2CDevice8(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(0x40)
+	dev := drivers.NewI2CDevice(bus, 0x40)
+
+	c.Assert(dev.WriteUint16(0x10, 0x1234), qt.IsNil)
+	c.Assert(fake.Registers[0x10:0x12], qt.DeepEquals, []byte{0x12, 0x34})
+
+	dev.Order = binary.LittleEndian
+	v, err := dev.ReadUint16(0x10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, uint16(0x3412))
+
+	fake.Registers[0x20] = 0xF0
+	c.Assert(dev.UpdateUint8(0x20, 0x0C, 0x04), qt.IsNil)
+	c.Assert(fake.Registers[0x20], qt.Equals, uint8(0xF4))
+}
+
+func TestI2CDevice16(t *testing.T) {
+	c := qt.New(t)
+	bus := &bus16{}
+	dev := drivers.NewI2CDevice(bus, 0x29)
+	dev.Register16 = true
+
+	c.Assert(dev.WriteUint32(0x0212, 0xDEADBEEF), qt.IsNil)
+	c.Assert(bus.registers[0x0212:0x0216], qt.DeepEquals, []byte{0xDE, 0xAD, 0xBE, 0xEF})
+
+	v, err := dev.ReadUint8(0x0213)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, uint8(0xAD))
+
+	// longer than the internal buffer
+	c.Assert(dev.WriteRegister(0x0100, make([]byte, 32)), qt.IsNil)
+}
+
+func TestI2CError(t *testing.T) {
+	c := qt.New(t)
+	errBus := errors.New("nack")
+	bus := &bus16{err: errBus}
+	dev := drivers.NewI2CDevice(bus, 0x29)
+	dev.Register16 = true
+
+	_, err := dev.ReadUint16(0x0062)
+	c.Assert(err, qt.ErrorMatches, "i2c device 0x29: read register 0x0062: nack")
+	c.Assert(errors.Is(err, errBus), qt.IsTrue)
+
+	err = dev.WriteUint8(0x16, 0)
+	c.Assert(err, qt.ErrorMatches, "i2c device 0x29: write register 0x0016: nack")
+	var i2cErr *drivers.I2CError
+	c.Assert(errors.As(err, &i2cErr), qt.IsTrue)
+	c.Assert(i2cErr.Register, qt.Equals, uint16(0x16))
+}
@@ -0,0 +1,48 @@
+//go:build tinygo
+
+package i2crecover
+
+import (
+	"machine"
+	"time"
+)
+
+// openDrain drives a pin like an open-drain output.
+type openDrain machine.Pin
+
+func (p openDrain) Low() {
+	machine.Pin(p).Configure(machine.PinConfig{Mode: machine.PinOutput})
+	machine.Pin(p).Low()
+}
+
+func (p openDrain) High() {
+	machine.Pin(p).Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+}
+
+func (p openDrain) Get() bool {
+	return machine.Pin(p).Get()
+}
+
+// Stuck reports whether a device holds SDA low while the bus is idle. The
+// pins are left configured as inputs, so the I2C peripheral must be
+// reconfigured afterwards.
+func Stuck(scl, sda machine.Pin) bool {
+	return stuck(openDrain(scl), openDrain(sda))
+}
+
+// Recover clocks the bus until SDA is released and generates a STOP
+// condition. The pins are left configured as inputs, so the I2C peripheral
+// must be reconfigured afterwards.
+func Recover(scl, sda machine.Pin) error {
+	return recoverBus(openDrain(scl), openDrain(sda), time.Sleep)
+}
+
+// RecoverBus recovers the bus on the pins of config and reconfigures the I2C
+// peripheral with it. The SCL and SDA pins must be set in config.
+func RecoverBus(bus *machine.I2C, config machine.I2CConfig) error {
+	err := Recover(config.SCL, config.SDA)
+	if cerr := bus.Configure(config); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+// Package i2crecover frees an I2C bus that is stuck because a device holds SDA
+// low, usually after the controller was reset or a clock pulse was lost in
+// the middle of a read (long cables and noise make this a common failure).
+//
+// The recovery clocks SCL up to nine times until the device has shifted out
+// the rest of its byte and releases SDA, then generates a STOP condition, as
+// described in section 3.1.16 of the I2C specification (UM10204).
+//
+// Drivers that time out on a transfer can call Recover with the SCL and SDA
+// pins of the bus and then reconfigure the I2C peripheral:
+//
+//	if err := i2crecover.RecoverBus(machine.I2C0, config); err != nil {
+//		println("bus still stuck:", err.Error())
+//	}
+package i2crecover // import "tinygo.org/x/drivers/i2crecover"
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrClockStuck is returned when SCL is held low, which can't be
+	// recovered by clocking the bus.
+	ErrClockStuck = errors.New("i2crecover: SCL held low")
+
+	// ErrDataStuck is returned when SDA is still held low after recovery.
+	ErrDataStuck = errors.New("i2crecover: SDA held low")
+)
+
+// halfPeriod is half an SCL period of the recovery clock, 100kHz.
+const halfPeriod = 5 * time.Microsecond
+
+// stretchTimeout is how long a device may stretch the recovery clock.
+const stretchTimeout = 10 * time.Millisecond
+
+// line is an open-drain bus line: Low drives it low, High releases it to the
+// pull-up and Get reads the actual level.
+type line interface {
+	Low()
+	High()
+	Get() bool
+}
+
+// stuck reports whether SDA is held low with both lines released.
+func stuck(scl, sda line) bool {
+	scl.High()
+	sda.High()
+	return !sda.Get()
+}
+
+// recoverBus clocks the bus until SDA is released and generates a STOP.
+func recoverBus(scl, sda line, wait func(time.Duration)) error {
+	scl.High()
+	sda.High()
+	wait(halfPeriod)
+	if !waitHigh(scl, wait) {
+		return ErrClockStuck
+	}
+	for i := 0; i < 9 && !sda.Get(); i++ {
+		scl.Low()
+		wait(halfPeriod)
+		scl.High()
+		if !waitHigh(scl, wait) {
+			return ErrClockStuck
+		}
+		wait(halfPeriod)
+	}
+
+	// STOP: SDA rises while SCL is high
+	scl.Low()
+	wait(halfPeriod)
+	sda.Low()
+	wait(halfPeriod)
+	scl.High()
+	wait(halfPeriod)
+	sda.High()
+	wait(halfPeriod)
+
+	if !sda.Get() {
+		return ErrDataStuck
+	}
+	return nil
+}
+
+// waitHigh waits for a released line to go high, allowing for clock
+// stretching.
+func waitHigh(l line, wait func(time.Duration)) bool {
+	for t := time.Duration(0); t < stretchTimeout; t += halfPeriod {
+		if l.Get() {
+			return true
+		}
+		wait(halfPeriod)
+	}
+	return l.Get()
+}
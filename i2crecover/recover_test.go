@@ -0,0 +1,75 @@
+package i2crecover
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeBus simulates a device holding SDA low for a number of SCL pulses.
+type fakeBus struct {
+	sclLow, sdaLow bool // driven low by the controller
+	sclStuck       bool // SCL held low by a device
+	holdPulses     int  // rising SCL edges until the device releases SDA
+	pulses         int
+	stop           bool
+}
+
+type fakeSCL struct{ b *fakeBus }
+
+func (l fakeSCL) Low() { l.b.sclLow = true }
+
+func (l fakeSCL) High() {
+	if l.b.sclLow {
+		l.b.pulses++
+		if l.b.holdPulses > 0 {
+			l.b.holdPulses--
+		}
+	}
+	l.b.sclLow = false
+}
+
+func (l fakeSCL) Get() bool { return !l.b.sclLow && !l.b.sclStuck }
+
+type fakeSDA struct{ b *fakeBus }
+
+func (l fakeSDA) Low() { l.b.sdaLow = true }
+
+func (l fakeSDA) High() {
+	if l.b.sdaLow && !l.b.sclLow {
+		l.b.stop = true
+	}
+	l.b.sdaLow = false
+}
+
+func (l fakeSDA) Get() bool { return !l.b.sdaLow && l.b.holdPulses == 0 }
+
+func noWait(time.Duration) {}
+
+func TestRecover(t *testing.T) {
+	c := qt.New(t)
+	b := &fakeBus{holdPulses: 5}
+	c.Assert(stuck(fakeSCL{b}, fakeSDA{b}), qt.IsTrue)
+	c.Assert(recoverBus(fakeSCL{b}, fakeSDA{b}, noWait), qt.IsNil)
+	c.Assert(b.pulses, qt.Equals, 6) // five to release SDA, one in the STOP
+	c.Assert(b.stop, qt.IsTrue)
+	c.Assert(stuck(fakeSCL{b}, fakeSDA{b}), qt.IsFalse)
+}
+
+func TestRecoverIdle(t *testing.T) {
+	c := qt.New(t)
+	b := &fakeBus{}
+	c.Assert(recoverBus(fakeSCL{b}, fakeSDA{b}, noWait), qt.IsNil)
+	c.Assert(b.pulses, qt.Equals, 1)
+}
+
+func TestRecoverFails(t *testing.T) {
+	c := qt.New(t)
+	b := &fakeBus{holdPulses: 100}
+	c.Assert(recoverBus(fakeSCL{b}, fakeSDA{b}, noWait), qt.Equals, ErrDataStuck)
+	c.Assert(b.pulses, qt.Equals, 10)
+
+	b = &fakeBus{sclStuck: true}
+	c.Assert(recoverBus(fakeSCL{b}, fakeSDA{b}, noWait), qt.Equals, ErrClockStuck)
+}
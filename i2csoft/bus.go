@@ -0,0 +1,205 @@
+package i2csoft
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errSI2CAckExpected  error = &busError{"I2C error: expected ACK not NACK", drivers.ErrNack}
+	errSI2CClockStretch error = &busError{"I2C error: clock stretching timeout", drivers.ErrTimeout}
+)
+
+// busError is an error of the bus, which errors.Is matches with the kind of
+// failure it is, drivers.ErrNack or drivers.ErrTimeout.
+type busError struct {
+	msg  string
+	kind error
+}
+
+func (e *busError) Error() string {
+	return e.msg
+}
+
+func (e *busError) Unwrap() error {
+	return e.kind
+}
+
+// Defaults for I2CConfig.
+const (
+	defaultFrequency      = 100e3
+	defaultStretchTimeout = 10 * time.Millisecond
+)
+
+// line is an open-drain bus line: Low drives it low, High releases it to the
+// pull-up and Get reads the actual level.
+type line interface {
+	Low()
+	High()
+	Get() bool
+}
+
+// bus implements the I2C protocol over two open-drain lines. Between
+// transactions both lines are released, during a transaction SCL is left low
+// between bits.
+type bus struct {
+	scl, sda line
+	half     time.Duration // half an SCL period
+	stretch  time.Duration // how long a device may hold SCL low
+	wait     func(time.Duration)
+}
+
+// setFrequency sets the SCL frequency in Hz. The actual frequency is lower,
+// because of the time spent toggling the pins.
+func (b *bus) setFrequency(hz uint32) {
+	if hz == 0 {
+		hz = defaultFrequency
+	}
+	b.half = time.Second / time.Duration(2*hz)
+}
+
+// releaseSCL releases SCL and waits for it to go high, as a device may hold it
+// low to stretch the clock.
+func (b *bus) releaseSCL() error {
+	b.scl.High()
+	for t := time.Duration(0); !b.scl.Get(); t += b.half {
+		if t >= b.stretch {
+			return errSI2CClockStretch
+		}
+		b.wait(b.half)
+	}
+	return nil
+}
+
+// start generates a START condition, or a repeated START in the middle of a
+// transaction: SDA falls while SCL is high.
+func (b *bus) start() error {
+	b.sda.High()
+	b.wait(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return err
+	}
+	b.wait(b.half)
+	b.sda.Low()
+	b.wait(b.half)
+	b.scl.Low()
+	return nil
+}
+
+// stop generates a STOP condition: SDA rises while SCL is high.
+func (b *bus) stop() error {
+	b.sda.Low()
+	b.wait(b.half)
+	err := b.releaseSCL()
+	b.wait(b.half)
+	b.sda.High()
+	b.wait(b.half)
+	return err
+}
+
+// writeBit sets SDA while SCL is low and clocks it out.
+func (b *bus) writeBit(bit bool) error {
+	if bit {
+		b.sda.High()
+	} else {
+		b.sda.Low()
+	}
+	b.wait(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return err
+	}
+	b.wait(b.half)
+	b.scl.Low()
+	return nil
+}
+
+// readBit releases SDA and samples it while SCL is high.
+func (b *bus) readBit() (bool, error) {
+	b.sda.High()
+	b.wait(b.half)
+	if err := b.releaseSCL(); err != nil {
+		return false, err
+	}
+	b.wait(b.half)
+	bit := b.sda.Get()
+	b.scl.Low()
+	return bit, nil
+}
+
+// writeByte writes a byte, most significant bit first, and checks that the
+// device acknowledges it.
+func (b *bus) writeByte(data byte) error {
+	for i := 7; i >= 0; i-- {
+		if err := b.writeBit(data&(1<<i) != 0); err != nil {
+			return err
+		}
+	}
+	nack, err := b.readBit()
+	if err != nil {
+		return err
+	}
+	if nack {
+		return errSI2CAckExpected
+	}
+	return nil
+}
+
+// readByte reads a byte and acknowledges it, or not for the last byte of a
+// read.
+func (b *bus) readByte(ack bool) (byte, error) {
+	var data byte
+	for i := 0; i < 8; i++ {
+		bit, err := b.readBit()
+		if err != nil {
+			return 0, err
+		}
+		data <<= 1
+		if bit {
+			data |= 1
+		}
+	}
+	return data, b.writeBit(!ack)
+}
+
+// tx writes w and then reads r with a repeated START in between. With both
+// empty, only the address is written, which checks that a device is present.
+func (b *bus) tx(addr uint16, w, r []byte) error {
+	err := b.transfer(addr, w, r)
+	if serr := b.stop(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+func (b *bus) transfer(addr uint16, w, r []byte) error {
+	if len(w) != 0 || len(r) == 0 {
+		if err := b.start(); err != nil {
+			return err
+		}
+		if err := b.writeByte(byte(addr << 1)); err != nil {
+			return err
+		}
+		for _, data := range w {
+			if err := b.writeByte(data); err != nil {
+				return err
+			}
+		}
+	}
+	if len(r) != 0 {
+		if err := b.start(); err != nil {
+			return err
+		}
+		if err := b.writeByte(byte(addr<<1) | 1); err != nil {
+			return err
+		}
+		for i := range r {
+			data, err := b.readByte(i < len(r)-1)
+			if err != nil {
+				return err
+			}
+			r[i] = data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package i2csoft
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers"
+)
+
+// fakeDevice simulates the bus lines and a device that acknowledges its
+// address, records written bytes and transmits tx when read.
+type fakeDevice struct {
+	addr byte
+	tx   []byte
+
+	written []byte
+	starts  int
+	stops   int
+
+	sclLow, sdaLow bool // driven low by the controller
+	devSDALow      bool // driven low by the device
+	stretch        int  // number of SCL reads the device holds it low
+
+	active    bool
+	bit       int // bit of the current 9-bit frame, -1 after START
+	shift     byte
+	receiving bool
+	address   bool // the current byte is the address
+	tosend    byte
+}
+
+func (d *fakeDevice) sda() bool { return !d.sdaLow && !d.devSDALow }
+
+func (d *fakeDevice) rise() {
+	if !d.active {
+		return
+	}
+	if d.bit < 8 && d.receiving {
+		d.shift <<= 1
+		if d.sda() {
+			d.shift |= 1
+		}
+	}
+	if d.bit == 8 && !d.receiving && d.sda() {
+		// NACK from the controller, stop transmitting
+		d.active = false
+	}
+}
+
+func (d *fakeDevice) fall() {
+	if !d.active {
+		d.devSDALow = false
+		return
+	}
+	if d.bit < 8 {
+		d.bit++
+		if d.bit < 8 {
+			if !d.receiving {
+				d.devSDALow = d.tosend&(0x80>>d.bit) == 0
+			}
+			return
+		}
+		// acknowledge slot
+		d.devSDALow = false
+		if d.receiving {
+			if d.address {
+				if d.shift>>1 != d.addr {
+					d.active = false
+					return
+				}
+				d.address = false
+				d.receiving = d.shift&1 == 0
+			} else {
+				d.written = append(d.written, d.shift)
+			}
+			d.devSDALow = true
+		}
+		return
+	}
+	d.bit = 0
+	d.devSDALow = false
+	if !d.receiving {
+		d.tosend = 0xFF
+		if len(d.tx) > 0 {
+			d.tosend = d.tx[0]
+			d.tx = d.tx[1:]
+		}
+		d.devSDALow = d.tosend&0x80 == 0
+	}
+}
+
+type fakeSCL struct{ d *fakeDevice }
+
+func (l fakeSCL) Low() {
+	if !l.d.sclLow {
+		l.d.sclLow = true
+		l.d.fall()
+	}
+}
+
+func (l fakeSCL) High() {
+	if l.d.sclLow {
+		l.d.sclLow = false
+		l.d.rise()
+	}
+}
+
+func (l fakeSCL) Get() bool {
+	if l.d.stretch > 0 {
+		l.d.stretch--
+		return false
+	}
+	return !l.d.sclLow
+}
+
+type fakeSDA struct{ d *fakeDevice }
+
+func (l fakeSDA) Low() {
+	if !l.d.sdaLow && !l.d.sclLow {
+		// START
+		l.d.starts++
+		l.d.active = true
+		l.d.address = true
+		l.d.receiving = true
+		l.d.bit = -1
+		l.d.devSDALow = false
+	}
+	l.d.sdaLow = true
+}
+
+func (l fakeSDA) High() {
+	if l.d.sdaLow && !l.d.sclLow {
+		// STOP
+		l.d.stops++
+		l.d.active = false
+	}
+	l.d.sdaLow = false
+}
+
+func (l fakeSDA) Get() bool { return l.d.sda() }
+
+func newFakeBus(d *fakeDevice) *bus {
+	b := &bus{
+		scl:     fakeSCL{d},
+		sda:     fakeSDA{d},
+		stretch: defaultStretchTimeout,
+		wait:    func(time.Duration) {},
+	}
+	b.setFrequency(0)
+	return b
+}
+
+func TestTx(t *testing.T) {
+	c := qt.New(t)
+	d := &fakeDevice{addr: 0x48, tx: []byte{0x12, 0x34}}
+	b := newFakeBus(d)
+
+	r := make([]byte, 2)
+	c.Assert(b.tx(0x48, []byte{0x0B}, r), qt.IsNil)
+	c.Assert(d.written, qt.DeepEquals, []byte{0x0B})
+	c.Assert(r, qt.DeepEquals, []byte{0x12, 0x34})
+	c.Assert(d.starts, qt.Equals, 2) // START and repeated START
+	c.Assert(d.stops, qt.Equals, 1)
+
+	c.Assert(b.tx(0x48, []byte{0x03, 0x80}, nil), qt.IsNil)
+	c.Assert(d.written, qt.DeepEquals, []byte{0x0B, 0x03, 0x80})
+	c.Assert(d.stops, qt.Equals, 2)
+
+	// address only
+	c.Assert(b.tx(0x48, nil, nil), qt.IsNil)
+}
+
+func TestTxNack(t *testing.T) {
+	c := qt.New(t)
+	d := &fakeDevice{addr: 0x48}
+	b := newFakeBus(d)
+	err := b.tx(0x49, []byte{0x00}, nil)
+	c.Assert(err, qt.Equals, errSI2CAckExpected)
+	c.Assert(errors.Is(err, drivers.ErrNack), qt.IsTrue)
+	c.Assert(d.written, qt.HasLen, 0)
+	c.Assert(d.stops, qt.Equals, 1)
+}
+
+func TestClockStretching(t *testing.T) {
+	c := qt.New(t)
+	d := &fakeDevice{addr: 0x48, tx: []byte{0xA5}, stretch: 20}
+	b := newFakeBus(d)
+	r := []byte{0}
+	c.Assert(b.tx(0x48, nil, r), qt.IsNil)
+	c.Assert(r[0], qt.Equals, byte(0xA5))
+
+	d.stretch = 1 << 30
+	err := b.tx(0x48, nil, r)
+	c.Assert(err, qt.Equals, errSI2CClockStretch)
+	c.Assert(errors.Is(err, drivers.ErrTimeout), qt.IsTrue)
+}
+
+func TestSetFrequency(t *testing.T) {
+	c := qt.New(t)
+	var b bus
+	b.setFrequency(400e3)
+	c.Assert(b.half, qt.Equals, 1250*time.Nanosecond)
+	b.setFrequency(0)
+	c.Assert(b.half, qt.Equals, 5*time.Microsecond)
+}
@@ -1,7 +1,8 @@
+//go:build tinygo
+
 package i2csoft
 
 import (
-	"errors"
 	"machine"
 	"time"
 
@@ -11,11 +12,13 @@ import (
 // I2C is an I2C implementation by Software. Since it is implemented by
 // software, it can be used with microcontrollers that do not have I2C
 // function. This is not efficient but works around broken or missing drivers.
+//
+// The pins are driven like open-drain outputs, relying on the bus pull-up
+// resistors, and devices may stretch the clock.
 type I2C struct {
-	scl      machine.Pin
-	sda      machine.Pin
-	nack     bool
-	baudrate uint32
+	scl machine.Pin
+	sda machine.Pin
+	bus bus
 }
 
 // I2CConfig is used to store config info for I2C.
@@ -23,21 +26,26 @@ type I2CConfig struct {
 	Frequency uint32
 	SCL       machine.Pin
 	SDA       machine.Pin
-}
 
-var (
-	errSI2CAckExpected = errors.New("I2C error: expected ACK not NACK")
-)
+	// StretchTimeout is how long a device may hold SCL low before a
+	// transaction fails. Defaults to 10ms.
+	StretchTimeout time.Duration
+}
 
 // New returns the i2csoft driver. For the arguments, specify the pins to be
 // used as SCL and SDA. As I2C is implemented in software, any GPIO pin can be
 // specified.
 func New(sclPin, sdaPin machine.Pin) *I2C {
-	return &I2C{
-		scl:      sclPin,
-		sda:      sdaPin,
-		baudrate: 100e3,
+	i2c := &I2C{
+		scl: sclPin,
+		sda: sdaPin,
+		bus: bus{
+			stretch: defaultStretchTimeout,
+			wait:    sleep,
+		},
 	}
+	i2c.bus.setFrequency(defaultFrequency)
+	return i2c
 }
 
 // Configure is intended to setup the I2C interface.
@@ -46,6 +54,9 @@ func (i2c *I2C) Configure(config I2CConfig) error {
 	if config.Frequency != 0 {
 		i2c.SetBaudRate(config.Frequency)
 	}
+	if config.StretchTimeout != 0 {
+		i2c.bus.stretch = config.StretchTimeout
+	}
 
 	// This exists for compatibility with machine.I2CConfig. SCL and SDA must
 	// be set at the same time. Because Pin(0) is sometimes set, it is not
@@ -55,208 +66,27 @@ func (i2c *I2C) Configure(config I2CConfig) error {
 		i2c.sda = config.SDA
 	}
 
-	// enable pins
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.sda.High()
-	i2c.scl.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.scl.High()
+	// release both lines, leaving the bus idle
+	i2c.bus.scl = openDrain(i2c.scl)
+	i2c.bus.sda = openDrain(i2c.sda)
+	i2c.bus.sda.High()
+	i2c.bus.scl.High()
 
 	return nil
 }
 
-// SetBaudRate sets the communication speed for the I2C.
+// SetBaudRate sets the communication speed for the I2C. The actual speed is
+// lower, because of the time needed to toggle the pins in software.
 func (i2c *I2C) SetBaudRate(br uint32) {
-	// At this time, the value of i2c.baudrate is ignored because it is fixed
-	// at 100 kHz. SetBaudrate() is exist for compatibility with machine.I2C.
-	i2c.baudrate = br
+	i2c.bus.setFrequency(br)
 }
 
 // Tx does a single I2C transaction at the specified address.
 // It clocks out the given address, writes the bytes in w, reads back len(r)
 // bytes and stores them in r, and generates a stop condition on the bus.
+// A repeated start is used between writing and reading.
 func (i2c *I2C) Tx(addr uint16, w, r []byte) error {
-	i2c.nack = false
-	if len(w) != 0 {
-		// send start/address for write
-		i2c.sendAddress(addr, true)
-
-		// wait until transmission complete
-
-		// ACK received (0: ACK, 1: NACK)
-		if i2c.nack {
-			i2c.signalStop()
-			return errSI2CAckExpected
-		}
-
-		// write data
-		for _, b := range w {
-			i2c.writeByte(b)
-		}
-
-		i2c.signalStop()
-	}
-	if len(r) != 0 {
-		// send start/address for read
-		i2c.sendAddress(addr, false)
-
-		// wait transmission complete
-
-		// ACK received (0: ACK, 1: NACK)
-		if i2c.nack {
-			i2c.signalStop()
-			return errSI2CAckExpected
-		}
-
-		// read first byte
-		r[0] = i2c.readByte()
-		for i := 1; i < len(r); i++ {
-			// Send an ACK
-
-			i2c.signalRead()
-
-			// Read data and send the ACK
-			r[i] = i2c.readByte()
-		}
-
-		// Send NACK to end transmission
-		i2c.sendNack()
-
-		i2c.signalStop()
-	}
-
-	return nil
-}
-
-// writeByte writes a single byte to the I2C bus.
-func (i2c *I2C) writeByte(data byte) {
-	// Send data byte
-	i2c.scl.Low()
-	i2c.sda.High()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.wait()
-
-	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
-		if ((data >> (7 - i)) & 1) == 1 {
-			i2c.sda.High()
-		} else {
-			i2c.sda.Low()
-		}
-		i2c.wait()
-		i2c.wait()
-		i2c.scl.High()
-		i2c.wait()
-		i2c.wait()
-	}
-
-	i2c.scl.Low()
-	i2c.wait()
-	i2c.wait()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
-	i2c.scl.High()
-	i2c.wait()
-
-	i2c.nack = i2c.sda.Get()
-
-	i2c.wait()
-
-	// wait until transmission successful
-}
-
-// sendAddress sends the address and start signal
-func (i2c *I2C) sendAddress(address uint16, write bool) {
-	data := (address << 1)
-	if !write {
-		data |= 1 // set read flag
-	}
-
-	i2c.scl.High()
-	i2c.sda.Low()
-	i2c.wait()
-	i2c.wait()
-	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
-		if ((data >> (7 - i)) & 1) == 1 {
-			i2c.sda.High()
-		} else {
-			i2c.sda.Low()
-		}
-		i2c.wait()
-		i2c.wait()
-		i2c.scl.High()
-		i2c.wait()
-		i2c.wait()
-	}
-
-	i2c.scl.Low()
-	i2c.wait()
-	i2c.wait()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
-	i2c.scl.High()
-	i2c.wait()
-
-	i2c.nack = i2c.sda.Get()
-
-	i2c.wait()
-
-	// wait until bus ready
-}
-
-func (i2c *I2C) signalStop() {
-	i2c.scl.Low()
-	i2c.sda.Low()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.wait()
-	i2c.wait()
-	i2c.scl.High()
-	i2c.wait()
-	i2c.wait()
-	i2c.sda.High()
-	i2c.wait()
-	i2c.wait()
-}
-
-func (i2c *I2C) signalRead() {
-	i2c.wait()
-	i2c.wait()
-	i2c.scl.Low()
-	i2c.sda.Low()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.wait()
-	i2c.wait()
-	i2c.scl.High()
-	i2c.wait()
-	i2c.wait()
-}
-
-func (i2c *I2C) readByte() byte {
-	var data byte
-	for i := 0; i < 8; i++ {
-		i2c.scl.Low()
-		i2c.sda.Configure(machine.PinConfig{Mode: machine.PinInput})
-		i2c.wait()
-		i2c.wait()
-		i2c.scl.High()
-		if i2c.sda.Get() {
-			data |= 1 << (7 - i)
-		}
-		i2c.wait()
-		i2c.wait()
-	}
-	return data
-}
-
-func (i2c *I2C) sendNack() {
-	i2c.wait()
-	i2c.wait()
-	i2c.scl.Low()
-	i2c.sda.High()
-	i2c.sda.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	i2c.wait()
-	i2c.wait()
-	i2c.scl.High()
-	i2c.wait()
-	i2c.wait()
+	return i2c.bus.tx(addr, w, r)
 }
 
 // WriteRegister transmits first the register and then the data to the
@@ -282,7 +112,23 @@ func (i2c *I2C) ReadRegister(address uint8, register uint8, data []byte) error {
 	return i2c.Tx(uint16(address), []byte{register}, data)
 }
 
-// wait waits for half the time of the SCL operation interval.
-func (i2c *I2C) wait() {
-	delay.Sleep(50 * time.Microsecond) // half of a 100kHz cycle (50µs)
+// openDrain drives a pin like an open-drain output.
+type openDrain machine.Pin
+
+func (p openDrain) Low() {
+	machine.Pin(p).Configure(machine.PinConfig{Mode: machine.PinOutput})
+	machine.Pin(p).Low()
+}
+
+func (p openDrain) High() {
+	machine.Pin(p).Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+}
+
+func (p openDrain) Get() bool {
+	return machine.Pin(p).Get()
+}
+
+// sleep busy-waits, as the bit times are too short for time.Sleep.
+func sleep(d time.Duration) {
+	delay.Sleep(d)
 }
@@ -278,6 +278,12 @@ func (d *Device) Sleep(sleepEnabled bool) error {
 	return nil
 }
 
+// WakeLatency returns the time the panel needs after leaving sleep mode before
+// it may be put to sleep again.
+func (d *Device) WakeLatency() time.Duration {
+	return 120 * time.Millisecond
+}
+
 // Rotation returns the current rotation of the device.
 func (d *Device) Rotation() drivers.Rotation {
 	return d.rotation
@@ -1,6 +1,9 @@
 package ina260
 
-import "tinygo.org/x/drivers"
+import (
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
+)
 
 // Device wraps an I2C connection to an INA260 device.
 type Device struct {
@@ -23,6 +26,28 @@ type Config struct {
 	Mode byte
 }
 
+// AlertConfig holds the configuration of the ALERT pin.
+type AlertConfig struct {
+	// One of ALERT_XXX, or 0 to disable the alert
+	Function uint16
+
+	// The limit for the selected function. Only the field matching Function
+	// is used: Current for ALERT_OVER_CURRENT and ALERT_UNDER_CURRENT,
+	// Voltage for ALERT_OVER_VOLTAGE and ALERT_UNDER_VOLTAGE and Power for
+	// ALERT_OVER_POWER.
+	Current units.MicroAmpere
+	Voltage units.MicroVolt
+	Power   units.MicroWatt
+
+	// Latch keeps the ALERT pin asserted until AlertFlags is called, instead
+	// of releasing it when the condition clears.
+	Latch bool
+
+	// ActiveHigh drives the ALERT pin high when asserted. By default it is
+	// open-drain and pulled low.
+	ActiveHigh bool
+}
+
 // New creates a new INA260 connection. The I2C bus must already be
 // configured.
 //
@@ -66,32 +91,66 @@ func (d *Device) Connected() bool {
 }
 
 // Gets the measured current in µA (max resolution 1.25mA)
-func (d *Device) Current() int32 {
+func (d *Device) Current() units.MicroAmpere {
 	val := d.ReadRegister(REG_CURRENT)
 
 	if val&0x8000 == 0 {
-		return int32(val) * 1250
+		return units.MicroAmpere(val) * 1250
 	}
 
 	// Two's complement, convert to signed int
-	return -(int32(^val) + 1) * 1250
+	return -(units.MicroAmpere(^val) + 1) * 1250
 }
 
 // Gets the measured voltage in µV (max resolution 1.25mV)
-func (d *Device) Voltage() int32 {
+func (d *Device) Voltage() units.MicroVolt {
 	val := d.ReadRegister(REG_BUSVOLTAGE)
 
 	if val&0x8000 == 0 {
-		return int32(val) * 1250
+		return units.MicroVolt(val) * 1250
 	}
 
 	// Two's complement, convert to signed int
-	return -(int32(^val) + 1) * 1250
+	return -(units.MicroVolt(^val) + 1) * 1250
 }
 
 // Gets the measured power in µW (max resolution 10mW)
-func (d *Device) Power() int32 {
-	return int32(d.ReadRegister(REG_POWER)) * 10000
+func (d *Device) Power() units.MicroWatt {
+	return units.MicroWatt(d.ReadRegister(REG_POWER)) * 10000
+}
+
+// ConfigureAlert sets up the ALERT pin to signal when a measurement crosses
+// a limit, or when a conversion is ready.
+func (d *Device) ConfigureAlert(cfg AlertConfig) {
+	var limit uint16
+	switch cfg.Function {
+	case ALERT_OVER_CURRENT, ALERT_UNDER_CURRENT:
+		// Two's complement, same LSB as the current register
+		limit = uint16(int16(cfg.Current / 1250))
+	case ALERT_OVER_VOLTAGE, ALERT_UNDER_VOLTAGE:
+		limit = uint16(cfg.Voltage / 1250)
+	case ALERT_OVER_POWER:
+		limit = uint16(cfg.Power / 10000)
+	}
+
+	val := cfg.Function
+	if cfg.Latch {
+		val |= alertLatch
+	}
+	if cfg.ActiveHigh {
+		val |= alertPolarity
+	}
+
+	d.WriteRegister(REG_ALERTLIMIT, limit)
+	d.WriteRegister(REG_MASKENABLE, val)
+}
+
+// AlertFlags returns the status flags, a combination of FLAG_ALERT,
+// FLAG_CONVERSION_READY and FLAG_OVERFLOW.
+//
+// Reading the flags clears a latched alert and the conversion ready flag.
+func (d *Device) AlertFlags() uint16 {
+	return d.ReadRegister(REG_MASKENABLE) & (FLAG_ALERT | FLAG_CONVERSION_READY | FLAG_OVERFLOW)
 }
 
 // Read a register
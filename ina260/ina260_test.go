@@ -5,6 +5,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 	"tinygo.org/x/drivers/tester"
+	"tinygo.org/x/drivers/units"
 )
 
 func TestDefaultI2CAddress(t *testing.T) {
@@ -35,7 +36,7 @@ func TestVoltage(t *testing.T) {
 
 	dev := New(bus)
 	// Datasheet: 2570h = 11.98V = 11980mV = 11980000uV
-	c.Assert(dev.Voltage(), qt.Equals, int32(11980000))
+	c.Assert(dev.Voltage(), qt.Equals, units.MicroVolt(11980000))
 }
 
 func TestCurrent(t *testing.T) {
@@ -48,7 +49,7 @@ func TestCurrent(t *testing.T) {
 
 	dev := New(bus)
 	// Datasheet: 2710h = 12.5A = 12500mA = 12500000uA
-	c.Assert(dev.Current(), qt.Equals, int32(12500000))
+	c.Assert(dev.Current(), qt.Equals, units.MicroAmpere(12500000))
 }
 
 func TestPower(t *testing.T) {
@@ -61,7 +62,7 @@ func TestPower(t *testing.T) {
 
 	dev := New(bus)
 	// 3A7Fh = 149.75W = 149750mW = 149750000uW
-	c.Assert(dev.Power(), qt.Equals, int32(149750000))
+	c.Assert(dev.Power(), qt.Equals, units.MicroWatt(149750000))
 }
 
 // defaultRegisters returns the default values for all of the device's registers.
@@ -78,3 +79,31 @@ func defaultRegisters() map[uint8]uint16 {
 		REG_DIE_ID:     0x2270,
 	}
 }
+
+func TestConfigureAlert(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := tester.NewI2CDevice16(c, Address)
+	fake.Registers = defaultRegisters()
+	bus.AddDevice(fake)
+
+	dev := New(bus)
+	dev.ConfigureAlert(AlertConfig{
+		Function: ALERT_UNDER_CURRENT,
+		Current:  -2500000,
+		Latch:    true,
+	})
+	c.Assert(fake.Registers[REG_ALERTLIMIT], qt.Equals, uint16(0xF830)) // -2000
+	c.Assert(fake.Registers[REG_MASKENABLE], qt.Equals, uint16(0x4001))
+
+	dev.ConfigureAlert(AlertConfig{
+		Function:   ALERT_OVER_POWER,
+		Power:      50000000,
+		ActiveHigh: true,
+	})
+	c.Assert(fake.Registers[REG_ALERTLIMIT], qt.Equals, uint16(5000))
+	c.Assert(fake.Registers[REG_MASKENABLE], qt.Equals, uint16(0x0802))
+
+	fake.Registers[REG_MASKENABLE] = 0x0812
+	c.Assert(dev.AlertFlags(), qt.Equals, uint16(FLAG_ALERT))
+}
@@ -48,3 +48,20 @@ const (
 	MODE_CURRENT    = 0x1
 	MODE_NO_CURRENT = 0x0
 )
+
+// Mask/Enable register bits
+const (
+	ALERT_OVER_CURRENT     = 0x8000 // OCL: current above the limit
+	ALERT_UNDER_CURRENT    = 0x4000 // UCL: current below the limit
+	ALERT_OVER_VOLTAGE     = 0x2000 // BOL: bus voltage above the limit
+	ALERT_UNDER_VOLTAGE    = 0x1000 // BUL: bus voltage below the limit
+	ALERT_OVER_POWER       = 0x0800 // POL: power above the limit
+	ALERT_CONVERSION_READY = 0x0400 // CNVR: conversion complete
+
+	FLAG_ALERT            = 0x0010 // AFF: the alert function triggered
+	FLAG_CONVERSION_READY = 0x0008 // CVRF: a conversion completed
+	FLAG_OVERFLOW         = 0x0004 // OVF: the power calculation overflowed
+
+	alertPolarity = 0x0002 // APOL: ALERT pin active high
+	alertLatch    = 0x0001 // LEN: ALERT latched until the flags are read
+)
@@ -0,0 +1,216 @@
+package irremote
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+// Daikin air conditioner protocol reference
+// https://github.com/crankyoldgit/IRremoteESP8266/blob/master/src/ir_Daikin.cpp
+
+// DaikinTiming is the timing of the sections of Daikin frames. Pass it to
+// SetStateHandler with a slice of DaikinStateLength bytes to receive them.
+var DaikinTiming = StateTiming{
+	Carrier: 38000,
+	PulseDistance: irprotocol.PulseDistance{
+		HeaderMark: 3650 * time.Microsecond, HeaderSpace: 1623 * time.Microsecond,
+		BitMark:  428 * time.Microsecond,
+		OneSpace: 1280 * time.Microsecond, ZeroSpace: 428 * time.Microsecond,
+		Gap: 29 * time.Millisecond,
+	},
+}
+
+// DaikinStateLength is the number of bytes of the state sent by Daikin
+// remotes, in sections of 8, 8 and 19 bytes each ending with a checksum.
+const DaikinStateLength = 35
+
+// daikinTimerOff is the time of a disabled timer.
+const daikinTimerOff = 0x600
+
+var (
+	errDaikinFrame    = errors.New("irremote: not a Daikin frame")
+	errDaikinChecksum = errors.New("irremote: invalid Daikin checksum")
+)
+
+// DaikinMode is the operating mode of a Daikin air conditioner.
+type DaikinMode uint8
+
+// Daikin operating modes.
+const (
+	DaikinAuto DaikinMode = 0
+	DaikinDry  DaikinMode = 2
+	DaikinCool DaikinMode = 3
+	DaikinHeat DaikinMode = 4
+	DaikinFan  DaikinMode = 6
+)
+
+// DaikinFanSpeed is the fan speed of a Daikin air conditioner.
+type DaikinFanSpeed uint8
+
+// Daikin fan speeds, from DaikinFan1 (lowest) to DaikinFan5 (highest).
+const (
+	DaikinFanAuto DaikinFanSpeed = iota
+	DaikinFan1
+	DaikinFan2
+	DaikinFan3
+	DaikinFan4
+	DaikinFan5
+	DaikinFanQuiet
+)
+
+// DaikinState is the state of a Daikin air conditioner, which its remotes
+// send as a whole on every key press. Times are in minutes after midnight.
+type DaikinState struct {
+	Power       bool
+	Mode        DaikinMode
+	Temperature uint8 // °C, from 10 to 32
+	Fan         DaikinFanSpeed
+	SwingV      bool // vertical swing of the flaps
+	SwingH      bool // horizontal swing of the flaps
+	OnTimer     bool // turn on at OnTime
+	OnTime      uint16
+	OffTimer    bool // turn off at OffTime
+	OffTime     uint16
+	Clock       uint16 // current time of the remote
+}
+
+// Encode returns the bytes of the state, with their checksums.
+func (s *DaikinState) Encode() [DaikinStateLength]byte {
+	b := [DaikinStateLength]byte{
+		0x11, 0xDA, 0x27, 0x00, 0xC5, 0x00, 0x00, 0x00,
+		0x11, 0xDA, 0x27, 0x00, 0x42, 0x00, 0x00, 0x00,
+		0x11, 0xDA, 0x27, 0x00, 0x00,
+	}
+	b[13] = uint8(s.Clock)
+	b[14] = uint8(s.Clock >> 8)
+
+	b[21] = uint8(s.Mode&7)<<4 | 0x08 | b2u8(s.OffTimer)<<2 | b2u8(s.OnTimer)<<1 | b2u8(s.Power)
+	temp := s.Temperature
+	switch {
+	case temp < 10:
+		temp = 10
+	case temp > 32:
+		temp = 32
+	}
+	b[22] = temp * 2
+	fan := uint8(0xA)
+	switch {
+	case s.Fan == DaikinFanQuiet:
+		fan = 0xB
+	case s.Fan >= DaikinFan1 && s.Fan <= DaikinFan5:
+		fan = uint8(s.Fan) + 2
+	}
+	b[24] = fan << 4
+	if s.SwingV {
+		b[24] |= 0x0F
+	}
+	if s.SwingH {
+		b[25] = 0x0F
+	}
+	on, off := uint16(daikinTimerOff), uint16(daikinTimerOff)
+	if s.OnTimer {
+		on = s.OnTime & 0xFFF
+	}
+	if s.OffTimer {
+		off = s.OffTime & 0xFFF
+	}
+	b[26] = uint8(on)
+	b[27] = uint8(on>>8) | uint8(off<<4)
+	b[28] = uint8(off >> 4)
+	b[31] = 0xC0
+
+	b[7] = daikinChecksum(b[:7])
+	b[15] = daikinChecksum(b[8:15])
+	b[34] = daikinChecksum(b[16:34])
+	return b
+}
+
+// Decode decodes the bytes of a whole state, or of one of its sections as
+// received with SetStateHandler, which only sets the fields the section
+// holds. It returns an error, without changing s, if the bytes aren't a
+// Daikin state or section, or if a checksum doesn't match.
+func (s *DaikinState) Decode(data []byte) error {
+	if len(data) == DaikinStateLength {
+		for _, section := range [][]byte{data[:8], data[8:16], data[16:]} {
+			if err := daikinCheck(section); err != nil {
+				return err
+			}
+		}
+		s.Decode(data[8:16])
+		return s.Decode(data[16:])
+	}
+	if err := daikinCheck(data); err != nil {
+		return err
+	}
+	switch {
+	case len(data) == 8 && data[4] == 0x42:
+		s.Clock = uint16(data[5]) | uint16(data[6])<<8
+	case len(data) == 19:
+		s.Power = data[5]&0x01 != 0
+		s.OnTimer = data[5]&0x02 != 0
+		s.OffTimer = data[5]&0x04 != 0
+		s.Mode = DaikinMode(data[5] >> 4 & 7)
+		s.Temperature = data[6] / 2
+		switch fan := data[8] >> 4; {
+		case fan == 0xB:
+			s.Fan = DaikinFanQuiet
+		case fan >= 3 && fan <= 7:
+			s.Fan = DaikinFanSpeed(fan - 2)
+		default:
+			s.Fan = DaikinFanAuto
+		}
+		s.SwingV = data[8]&0x0F == 0x0F
+		s.SwingH = data[9]&0x0F == 0x0F
+		s.OnTime, s.OffTime = 0, 0
+		if s.OnTimer {
+			s.OnTime = uint16(data[10]) | uint16(data[11]&0x0F)<<8
+		}
+		if s.OffTimer {
+			s.OffTime = uint16(data[11]>>4) | uint16(data[12])<<4
+		}
+	}
+	return nil
+}
+
+// daikinCheck returns an error if section isn't a Daikin section with a
+// valid checksum.
+func daikinCheck(section []byte) error {
+	if len(section) != 8 && len(section) != 19 ||
+		section[0] != 0x11 || section[1] != 0xDA || section[2] != 0x27 {
+		return errDaikinFrame
+	}
+	n := len(section) - 1
+	if daikinChecksum(section[:n]) != section[n] {
+		return errDaikinChecksum
+	}
+	return nil
+}
+
+// daikinChecksum returns the Daikin checksum of the bytes of a section, the
+// sum of them.
+func daikinChecksum(b []byte) uint8 {
+	var sum uint8
+	for _, v := range b {
+		sum += v
+	}
+	return sum
+}
+
+// SendDaikin sends the state of a Daikin air conditioner: a preamble of 5
+// zero bits, followed by the 3 sections of the state.
+func (ir *SenderDevice) SendDaikin(s *DaikinState) {
+	t := &DaikinTiming
+	ir.start(t.Carrier)
+	for i := 0; i < 5; i++ {
+		ir.mark(t.BitMark)
+		ir.space(t.ZeroSpace)
+	}
+	ir.mark(t.BitMark)
+	ir.space(t.Gap)
+	b := s.Encode()
+	ir.SendState(t, b[:8])
+	ir.SendState(t, b[8:16])
+	ir.SendState(t, b[16:])
+}
@@ -0,0 +1,62 @@
+package irremote
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDaikinEncode(t *testing.T) {
+	c := qt.New(t)
+	s := DaikinState{
+		Power: true, Mode: DaikinCool, Temperature: 22, Fan: DaikinFan3,
+		SwingV: true, OffTimer: true, OffTime: 23 * 60, Clock: 18*60 + 30,
+	}
+	b := s.Encode()
+	c.Assert(b[:8], qt.DeepEquals, []byte{0x11, 0xDA, 0x27, 0x00, 0xC5, 0x00, 0x00, 0xD7})
+	c.Assert(b[8:16], qt.DeepEquals, []byte{0x11, 0xDA, 0x27, 0x00, 0x42, 0x56, 0x04, 0xAE})
+	c.Assert(b[16:], qt.DeepEquals, []byte{
+		0x11, 0xDA, 0x27, 0x00, 0x00, 0x3D, 0x2C, 0x00, 0x5F, 0x00,
+		0x00, 0x46, 0x56, 0x00, 0x00, 0xC0, 0x00, 0x00, 0x36,
+	})
+
+	var got DaikinState
+	c.Assert(got.Decode(b[:]), qt.IsNil)
+	c.Assert(got, qt.Equals, s)
+
+	// out of range temperature
+	s.Temperature = 40
+	b = s.Encode()
+	c.Assert(b[22], qt.Equals, uint8(64))
+
+	// sections are checked
+	b[30]++
+	c.Assert(got.Decode(b[:]), qt.Equals, errDaikinChecksum)
+	c.Assert(got.Decode(b[16:]), qt.Equals, errDaikinChecksum)
+	c.Assert(got.Decode(b[:8]), qt.IsNil)
+	c.Assert(got.Decode(b[:10]), qt.Equals, errDaikinFrame)
+	c.Assert(got.Temperature, qt.Equals, uint8(22))
+}
+
+func TestSendDaikin(t *testing.T) {
+	c := qt.New(t)
+	s := DaikinState{
+		Power: true, Mode: DaikinHeat, Temperature: 25, Fan: DaikinFanQuiet,
+		SwingH: true, OnTimer: true, OnTime: 7 * 60, Clock: 6 * 60,
+	}
+	ir, r := newTestSender()
+	ir.SendDaikin(&s)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	// preamble
+	c.Assert(r.pulses[:12], qt.DeepEquals, []int32{428, -428, 428, -428, 428, -428, 428, -428, 428, -428, 428, -29000})
+
+	// the sections are received one by one
+	var got DaikinState
+	dec := &StateDecoder{Timing: &DaikinTiming, State: make([]byte, DaikinStateLength)}
+	sections := receiveStates(dec, r.pulses)
+	c.Assert(sections, qt.HasLen, 3)
+	for _, section := range sections {
+		c.Assert(got.Decode(section), qt.IsNil)
+	}
+	c.Assert(got, qt.Equals, s)
+}
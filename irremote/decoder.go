@@ -0,0 +1,1864 @@
+package irremote
+
+import (
+	"errors"
+	"math/bits"
+	"time"
+
+	"tinygo.org/x/drivers/events"
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+// Data encapsulates the data received by the ReceiverDevice.
+type Data struct {
+	// Code is the raw IR data received.
+	Code uint32
+	// Address is the decoded address from the IR data received.
+	Address uint16
+	// Command is the decoded command from the IR data recieved
+	Command uint16
+	// Flags provides additional information about the IR data received. See DataFlags
+	Flags DataFlags
+	// Protocol is the IR protocol the data was received with.
+	Protocol Protocol
+	// Count is the number of identical frames the data was received in, for
+	// protocols that send every command several times, like SIRC.
+	Count uint8
+	// Vendor is the vendor ID of protocols that carry one, like Kaseikyo.
+	Vendor uint16
+}
+
+// DataFlags provides bitwise flags representing various information about recieved IR data.
+type DataFlags uint16
+
+// Valid values for DataFlags
+const (
+	// DataFlagIsRepeat set indicates that the IR data is a repeat commmand
+	DataFlagIsRepeat DataFlags = 1 << iota
+	// DataFlagToggle is the toggle bit of protocols like RC-5, which changes
+	// with every key press but not while a key is held
+	DataFlagToggle
+)
+
+// Protocol identifies the IR protocol of received data.
+type Protocol uint8
+
+// Protocols decoded by the ReceiverDevice.
+const (
+	ProtocolNEC Protocol = iota
+	ProtocolRC5
+	ProtocolRC6  // mode 0
+	ProtocolRC6A // mode 6, including MCE
+	ProtocolSIRC12
+	ProtocolSIRC15
+	ProtocolSIRC20
+	ProtocolSamsung
+	ProtocolKaseikyo // Kaseikyo of an unknown vendor
+	ProtocolPanasonic
+	ProtocolDenonK
+	ProtocolMitsubishiK
+	ProtocolSharpK
+	ProtocolJVC48
+	ProtocolJVC
+	ProtocolSharp
+	ProtocolDenon
+	ProtocolLG
+	ProtocolLG2
+	ProtocolSanyo
+	ProtocolMitsubishi
+	ProtocolPioneer
+	ProtocolRCA
+	ProtocolDish
+	ProtocolBose
+	ProtocolLegoPF
+	ProtocolMagiQuest // Code holds the wand ID and Command the magnitude
+	ProtocolRCMM12
+	ProtocolRCMM24
+	ProtocolRCMM32
+	ProtocolXMP
+	ProtocolBangOlufsen
+	ProtocolNECMSB // NEC with Code in the MSB first order of some vendors
+)
+
+// CommandHandler defines the callback function used to provide IR data received by the ReceiverDevice.
+type CommandHandler func(data Data)
+
+// DataFromEvent decodes the IR data from an events.IRCommand event posted by the ReceiverDevice.
+// The vendor ID of Kaseikyo frames of unknown vendors and the magnitude of MagiQuest frames
+// don't fit in the event and are lost.
+func DataFromEvent(e events.Event) Data {
+	data := Data{
+		Code:     e.Value,
+		Flags:    DataFlags(e.Flags & 0x0F),
+		Protocol: Protocol(e.Flags >> 8),
+		Count:    uint8(e.Flags>>4) & 0x0F,
+	}
+	data.Vendor = data.Protocol.vendor()
+	data.decode()
+	return data
+}
+
+// eventFlags returns the Flags of the event posted for data: the DataFlags
+// in the low nibble, the Count in the high nibble of the low byte and the
+// Protocol in the high byte.
+func (data *Data) eventFlags() uint16 {
+	count := data.Count
+	if count > 0x0F {
+		count = 0x0F
+	}
+	return uint16(data.Flags&0x0F) | uint16(count)<<4 | uint16(data.Protocol)<<8
+}
+
+// decode decodes Address and Command from the Code of the protocol.
+func (data *Data) decode() {
+	switch data.Protocol {
+	case ProtocolNEC:
+		data.decodeNEC()
+	case ProtocolNECMSB:
+		data.decodeNECMSB()
+	case ProtocolRC5:
+		data.decodeRC5()
+	case ProtocolRC6, ProtocolRC6A:
+		data.decodeRC6()
+	case ProtocolSIRC12, ProtocolSIRC15, ProtocolSIRC20:
+		data.decodeSIRC()
+	case ProtocolSamsung:
+		data.decodeSamsung()
+	case ProtocolKaseikyo, ProtocolPanasonic, ProtocolDenonK, ProtocolMitsubishiK, ProtocolSharpK, ProtocolJVC48:
+		data.decodeKaseikyo()
+	case ProtocolJVC:
+		data.decodeJVC()
+	case ProtocolSharp, ProtocolDenon:
+		data.decodeSharp()
+	case ProtocolLG, ProtocolLG2:
+		data.decodeLG()
+	case ProtocolSanyo:
+		data.decodeSanyo()
+	case ProtocolMitsubishi:
+		data.decodeMitsubishi()
+	case ProtocolPioneer:
+		data.decodePioneer()
+	case ProtocolRCA:
+		data.decodeRCA()
+	case ProtocolDish:
+		data.decodeDish()
+	case ProtocolBose:
+		data.decodeBose()
+	case ProtocolLegoPF:
+		data.decodeLegoPF()
+	case ProtocolRCMM12, ProtocolRCMM24, ProtocolRCMM32:
+		data.decodeRCMM()
+	case ProtocolXMP:
+		data.decodeXMP()
+	case ProtocolBangOlufsen:
+		data.decodeBangOlufsen()
+	}
+}
+
+// decoder decodes the frames of a protocol from the durations of the marks
+// (IR received) and spaces of the signal. It is called from interrupt
+// context, so it must not allocate.
+type decoder interface {
+	// reset discards a partially received frame.
+	reset()
+	// pulse handles a mark or space of duration d. It returns true when a
+	// frame has been decoded into data.
+	pulse(mark bool, d time.Duration, data *Data) bool
+}
+
+// ender is implemented by the decoders of protocols whose frames only end
+// with the gap after them, see irprotocol.PulseDistance. The gap after the
+// last frame is only measured once the next one starts, so the receiver
+// polls them with end instead.
+type ender interface {
+	// end handles the line having been idle for d after the last mark. It
+	// returns true when this ends a frame decoded into data.
+	end(d time.Duration, data *Data) bool
+}
+
+// newDecoders returns the decoders of all supported protocols.
+func newDecoders() []decoder {
+	return []decoder{
+		&necDecoder{},
+		&rc5Decoder{},
+		&rc6Decoder{},
+		&sircDecoder{},
+		&samsungDecoder{},
+		&kaseikyoDecoder{},
+		&jvcDecoder{},
+		&sharpDecoder{},
+		&lgDecoder{},
+		&sanyoDecoder{},
+		&mitsubishiDecoder{},
+		&pioneerDecoder{},
+		&rcaDecoder{},
+		&dishDecoder{},
+		&boseDecoder{},
+		&legoPFDecoder{},
+		&magiQuestDecoder{},
+		&rcmmDecoder{},
+		&xmpDecoder{},
+		&beoDecoder{},
+	}
+}
+
+// Confidence returns how unlikely it is, from 0 to 100, that data was
+// decoded from a frame of another protocol or from noise. It depends on how
+// much of the frame the protocol validates, with inverted bits, checksums or
+// repeated frames.
+func (data Data) Confidence() uint8 {
+	switch data.Protocol {
+	case ProtocolPanasonic, ProtocolDenonK, ProtocolMitsubishiK, ProtocolSharpK, ProtocolJVC48,
+		ProtocolXMP:
+		return 95
+	case ProtocolPioneer:
+		if data.Address>>8 == data.Address&0xFF && data.Command>>8 == data.Command&0xFF {
+			// the same NEC frame twice, as sent by NEC remotes held down
+			return 80
+		}
+		return 95
+	case ProtocolKaseikyo, ProtocolSanyo:
+		return 90
+	case ProtocolNEC, ProtocolNECMSB:
+		if data.Command > 0xFF {
+			// lenient frame without the inverse command
+			return 60
+		}
+		return 85
+	case ProtocolSamsung, ProtocolRCA, ProtocolSharp, ProtocolDenon, ProtocolMagiQuest:
+		return 85
+	case ProtocolBose:
+		return 80
+	case ProtocolSIRC12, ProtocolSIRC15, ProtocolSIRC20, ProtocolDish, ProtocolMitsubishi:
+		return 75
+	case ProtocolRC5, ProtocolRC6, ProtocolRC6A, ProtocolLG, ProtocolLG2, ProtocolLegoPF,
+		ProtocolBangOlufsen:
+		return 70
+	}
+	// no validation beyond the timing
+	return 50
+}
+
+// autoDetectGap is the shortest space between two frames for auto-detection.
+// Spaces within frames are shorter, except the pauses of XMP frames, which no
+// decoder completes a frame at, and those of B&O frames.
+const autoDetectGap = 10 * time.Millisecond
+
+// beoAutoDetectGap replaces autoDetectGap when B&O frames are decoded, as it
+// is longer than their spaces of up to 5 units.
+const beoAutoDetectGap = 6 * beoUnit
+
+// autoDetector keeps the most confident decoding of a frame, as several
+// decoders may accept it. For example, the second NEC frame of a Pioneer key
+// completes a Pioneer command.
+type autoDetector struct {
+	gap     time.Duration // shortest space between frames
+	best    Data
+	pending bool // best holds a decoding to report
+}
+
+// add adds a decoding of the current frame.
+func (a *autoDetector) add(data *Data) {
+	if !a.pending || data.Confidence() > a.best.Confidence() {
+		a.best = *data
+		a.pending = true
+	}
+}
+
+// flush returns the best decoding of the frame once the space d after it is
+// longer than gap, which ends it, and starts a new one. It returns false if
+// the frame hasn't ended or if no decoder accepted it.
+func (a *autoDetector) flush(d time.Duration, data *Data) bool {
+	if !a.pending || d <= a.gap {
+		return false
+	}
+	*data = a.best
+	a.pending = false
+	return true
+}
+
+// NEC protocol references
+// https://www.sbprojects.net/knowledge/ir/nec.php
+// https://techdocs.altium.com/display/FPGA/NEC+Infrared+Transmission+Protocol
+// https://simple-circuit.com/arduino-nec-remote-control-decoder/
+
+// necFrame is the frame format of NEC.
+var necFrame = irprotocol.PulseDistance{
+	HeaderMark: necHeaderMark, HeaderSpace: necHeaderSpace,
+	BitMark: necTiming.zeroMark, OneSpace: necTiming.oneSpace, ZeroSpace: necTiming.zeroSpace,
+	Bits: 32,
+}
+
+// necDecoder decodes NEC frames and repeat codes.
+type necDecoder struct {
+	frame    irprotocol.DistanceDecoder
+	header   bool   // the last pulse was a header mark, which may start a repeat code
+	code     uint32 // last received code, for repeats
+	valid    bool   // code holds a valid frame, so repeat codes are possible
+	lenient  bool   // accept frames without the inverse command
+	msbFirst bool   // report codes MSB first
+}
+
+func (n *necDecoder) reset() {
+	n.frame.Reset()
+	n.header = false
+	n.valid = false
+}
+
+func (n *necDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	n.frame.Timing = &necFrame
+	header := n.header
+	n.header = mark && match(d, necHeaderMark)
+	if header && !mark {
+		if match(d, necRepeatSpace) {
+			n.frame.Reset()
+			if !n.valid {
+				// no valid code to repeat
+				return false
+			}
+			n.decode(data)
+			data.Flags |= DataFlagIsRepeat
+			return true
+		}
+		// a new frame, or noise, ends the repeats of the previous one
+		n.valid = false
+	}
+	if !n.frame.Pulse(mark, d) {
+		return false
+	}
+	code := uint32(n.frame.Code())
+	if !n.lenient && uint8(code>>16) != ^uint8(code>>24) {
+		return false
+	}
+	n.code = code
+	n.valid = true
+	n.decode(data)
+	return true
+}
+
+// decode decodes the last received code into data.
+func (n *necDecoder) decode(data *Data) {
+	if n.msbFirst {
+		*data = Data{Code: bits.Reverse32(n.code), Protocol: ProtocolNECMSB}
+		data.decodeNECMSB()
+		return
+	}
+	*data = Data{Code: n.code, Protocol: ProtocolNEC}
+	data.decodeNEC()
+}
+
+// errNECInverse is returned by DecodeNEC for codes where the command isn't
+// followed by its inverse.
+var errNECInverse = errors.New("irremote: NEC command not followed by its inverse")
+
+// DecodeNEC decodes the Address and Command of a 32-bit NEC code, like a
+// Code from another source than the ReceiverDevice. Unless lenient, it
+// returns an error if the command isn't followed by its inverse, like the
+// ReceiverDevice unless its NECLenient is set.
+func DecodeNEC(code uint32, lenient bool) (Data, error) {
+	data := Data{Code: code, Protocol: ProtocolNEC}
+	if !lenient && uint8(code>>16) != ^uint8(code>>24) {
+		return data, errNECInverse
+	}
+	data.decodeNEC()
+	return data, nil
+}
+
+// NECBytes returns the 4 bytes of a NEC code in the order they are sent: the
+// address, the inverse address or high address byte, the command and the
+// inverse command, or whatever a remote sends instead.
+func NECBytes(code uint32) [4]byte {
+	return [4]byte{uint8(code), uint8(code >> 8), uint8(code >> 16), uint8(code >> 24)}
+}
+
+// decodeNEC decodes Command and Address from an already validated NEC Code.
+// The command of a code without the inverse command, only accepted with
+// NECLenient, is the 16-bit value of the last 2 bytes.
+func (data *Data) decodeNEC() {
+	data.Command = uint16(uint8(data.Code >> 16))
+	if uint8(data.Code>>16) != ^uint8(data.Code>>24) {
+		data.Command = uint16(data.Code >> 16)
+	}
+	addrLow := uint8(data.Code & 0xff)
+	addrHigh := uint8((data.Code & 0xff00) >> 8)
+	if addrHigh == ^addrLow {
+		// addrHigh is inverse of addrLow. This is not a valid 16-bit address in extended NEC coding
+		// since it is indistinguishable from 8-bit address with inverse validation. Use the 8-bit address
+		data.Address = uint16(addrLow)
+	} else {
+		// 16-bit extended NEC address
+		data.Address = (uint16(addrHigh) << 8) | uint16(addrLow)
+	}
+}
+
+// NECMSBFirst converts a NEC code between the LSB first order of Code, in
+// which NEC frames are sent, and the MSB first order in which some vendors
+// document their codes, like 0x20DF10EF for the power key of LG TVs.
+func NECMSBFirst(code uint32) uint32 {
+	return bits.Reverse32(code)
+}
+
+// decodeNECMSB decodes Command and Address from an already validated NEC
+// Code in MSB first order, in the bit order of the vendor documentation.
+func (data *Data) decodeNECMSB() {
+	data.Address = uint16(data.Code >> 24)
+	if uint8(data.Code>>16) != ^uint8(data.Code>>24) {
+		// 16-bit extended NEC address
+		data.Address = uint16(data.Code >> 16)
+	}
+	data.Command = uint16(data.Code>>8) & 0xFF
+	if uint8(data.Code) != ^uint8(data.Code>>8) {
+		// 16-bit command, only accepted with NECLenient
+		data.Command = uint16(data.Code)
+	}
+}
+
+// match returns whether d matches the duration want, see irprotocol.Match.
+func match(d, want time.Duration) bool {
+	return irprotocol.Match(d, want)
+}
+
+// States of the decoders of the protocols that don't fit the decoders of
+// irprotocol.
+const (
+	distanceStateIdle   = iota // waiting for the header mark
+	distanceStateHeader        // header mark received, expecting its space
+	distanceStateMark          // expecting the mark of a bit
+	distanceStateSpace         // expecting the space of a bit
+	distanceStateStop          // expecting the stop mark
+)
+
+// rc5MaxRepeat is the longest space between the frames of a held RC-5 key.
+const rc5MaxRepeat = 150 * time.Millisecond
+
+// rc5Decoder decodes RC-5 and RC-5X frames.
+type rc5Decoder struct {
+	bits irprotocol.ManchesterDecoder
+	idle time.Duration // space before the frame
+	last uint32        // previous code, to detect held keys
+}
+
+func (r *rc5Decoder) reset() {
+	r.bits.Reset()
+}
+
+func (r *rc5Decoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.bits.Half = rc5Half
+	if r.bits.Len() == 0 {
+		if !mark {
+			r.idle = d
+			return false
+		}
+		// the start bit begins with a space half, hidden in the idle line
+		r.bits.AddHalves(false, 1)
+	}
+	if !r.bits.Add(mark, d) {
+		r.reset()
+		if !mark {
+			r.idle = d
+		}
+		return false
+	}
+	if mark && r.bits.Len() == 27 {
+		// the last half of a trailing 0 is hidden in the idle line
+		r.bits.AddHalves(false, 1)
+	}
+	if r.bits.Len() < 28 {
+		return false
+	}
+	code, ok := r.bits.Decode()
+	r.reset()
+	if !ok || code>>13 != 1 {
+		// too long, not bi-phase, or invalid start bit
+		return false
+	}
+	*data = Data{Code: code, Protocol: ProtocolRC5}
+	if code == r.last && r.idle < rc5MaxRepeat {
+		data.Flags |= DataFlagIsRepeat
+	}
+	if code&(1<<11) != 0 {
+		data.Flags |= DataFlagToggle
+	}
+	data.decodeRC5()
+	r.last = code
+	return true
+}
+
+// decodeRC5 decodes Command and Address from a 14-bit RC-5 Code. The second
+// start bit is the inverted 7th command bit of RC-5X.
+func (data *Data) decodeRC5() {
+	data.Address = uint16(data.Code>>6) & 0x1F
+	data.Command = uint16(data.Code)&0x3F | uint16(^data.Code>>12&1)<<6
+}
+
+// rc6MaxRepeat is the longest space between the frames of a held RC-6 key.
+const rc6MaxRepeat = 150 * time.Millisecond
+
+// rc6Decoder decodes RC-6 mode 0 and mode 6A frames.
+type rc6Decoder struct {
+	state   uint8                        // rc6StateIdle, rc6StateLeader or rc6StateBits
+	bits    irprotocol.ManchesterDecoder // half bits of the current field
+	units   int                          // number of received units, from the start bit
+	trailer bool                         // level of the first unit of a half of the trailer bit
+	nbits   int                          // number of payload bits, 0 until known
+	mode    uint8                        // mode field
+	toggle  bool                         // trailer bit
+	idle    time.Duration                // space before the frame
+	last    Data                         // previous frame, to detect held keys
+}
+
+const (
+	rc6StateIdle   = iota // waiting for the leader mark
+	rc6StateLeader        // leader mark received, expecting its space
+	rc6StateBits          // receiving bits
+)
+
+// rc6Trailer and rc6Payload are the numbers of units before the trailer bit,
+// after the start bit and 3 mode bits, and before the payload, after the
+// trailer bit of double width.
+const (
+	rc6Trailer = 8
+	rc6Payload = rc6Trailer + 4
+)
+
+func (r *rc6Decoder) reset() {
+	r.state = rc6StateIdle
+	r.bits.Reset()
+	r.units = 0
+	r.nbits = 0
+}
+
+func (r *rc6Decoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	switch r.state {
+	case rc6StateIdle:
+		if mark && d > 5*rc6Unit && d < 7*rc6Unit {
+			r.state = rc6StateLeader
+		} else if !mark {
+			r.idle = d
+		}
+		return false
+	case rc6StateLeader:
+		if mark || d < 3*rc6Unit/2 || d > 5*rc6Unit/2 {
+			return r.fail(mark, d)
+		}
+		r.state = rc6StateBits
+		return false
+	}
+	k := int((d + rc6Unit/2) / rc6Unit)
+	if k < 1 || k > 3 {
+		return r.fail(mark, d)
+	}
+	for ; k > 0; k-- {
+		if !r.unit(mark) {
+			return r.fail(mark, d)
+		}
+	}
+	end := rc6Payload + 2*r.nbits
+	if mark && r.nbits != 0 && r.units == end-1 {
+		// the space half of a trailing 1 is hidden in the idle line
+		if !r.unit(false) {
+			return r.fail(mark, d)
+		}
+	}
+	if r.nbits == 0 || r.units < end {
+		return false
+	}
+	payload, ok := r.bits.Decode()
+	if !ok {
+		return r.fail(mark, d)
+	}
+	*data = Data{Code: payload, Protocol: ProtocolRC6A}
+	if r.mode == 0 {
+		data.Protocol = ProtocolRC6
+	}
+	if r.toggle {
+		data.Flags |= DataFlagToggle
+	}
+	data.decodeRC6()
+	if *data == r.last && r.idle < rc6MaxRepeat {
+		data.Flags |= DataFlagIsRepeat
+	}
+	r.last = *data
+	r.last.Flags &^= DataFlagIsRepeat
+	r.reset()
+	return true
+}
+
+// unit adds a unit of the frame, which is a half bit except in the trailer
+// bit, and decodes the start bit and mode, and then the trailer bit, once
+// they have been received. It returns false if the frame is invalid.
+func (r *rc6Decoder) unit(mark bool) bool {
+	r.bits.MarkFirst = true // a 1 is a mark followed by a space
+	u := r.units
+	r.units++
+	switch {
+	case r.nbits != 0 && u >= rc6Payload+2*r.nbits:
+		return false // longer than the frame
+	case u >= rc6Trailer && u < rc6Payload:
+		// the half bits of the trailer bit are 2 units long
+		if u%2 == 0 {
+			r.trailer = mark
+			return true
+		}
+		if mark != r.trailer {
+			return false
+		}
+	}
+	r.bits.AddHalves(mark, 1)
+	switch r.units {
+	case rc6Trailer:
+		header, ok := r.bits.Decode()
+		r.bits.Reset()
+		if !ok || header>>3 != 1 {
+			return false // start bit
+		}
+		r.mode = uint8(header & 7)
+		switch r.mode {
+		case 0:
+			r.nbits = 16
+		case 6:
+			// the payload length depends on the first bit of the customer code
+		default:
+			return false
+		}
+	case rc6Payload:
+		toggle, ok := r.bits.Decode()
+		r.bits.Reset()
+		if !ok {
+			return false
+		}
+		r.toggle = toggle != 0
+	case rc6Payload + 2:
+		if r.nbits != 0 {
+			break
+		}
+		first, ok := r.bits.Decode()
+		if !ok {
+			return false
+		}
+		// customer codes with the top bit set are 16 bits long, others 8 bits
+		r.nbits = 24
+		if first != 0 {
+			r.nbits = 32
+		}
+	}
+	return true
+}
+
+// fail resets the decoder after an invalid pulse.
+func (r *rc6Decoder) fail(mark bool, d time.Duration) bool {
+	r.reset()
+	if !mark {
+		r.idle = d
+	}
+	return false
+}
+
+// decodeRC6 decodes Command and Address from an RC-6 Code, which holds the
+// payload of the frame. The customer code of RC-6A frames remains in the
+// high bits of Code. MCE remotes send the toggle in the top address bit,
+// which is reported as DataFlagToggle.
+func (data *Data) decodeRC6() {
+	data.Address = uint16(data.Code>>8) & 0xFF
+	data.Command = uint16(data.Code) & 0xFF
+	if data.Protocol == ProtocolRC6A && data.Code>>16 == MCE {
+		if data.Address&0x80 != 0 {
+			data.Flags |= DataFlagToggle
+		}
+		data.Address &= 0x7F
+	}
+}
+
+func b2u8(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sircMaxGap is the longest space between the frames of a SIRC command.
+const sircMaxGap = 40 * time.Millisecond
+
+// sircFrame is the frame format of SIRC, of 12, 15 or 20 bits. Any space
+// longer than that of a bit ends a frame.
+var sircFrame = irprotocol.PulseWidth{
+	HeaderMark: 4 * sircUnit, HeaderSpace: sircUnit,
+	OneMark: sircTiming.oneMark, OneSpace: sircTiming.oneSpace,
+	ZeroMark: sircTiming.zeroMark, ZeroSpace: sircTiming.zeroSpace,
+	Lengths: []int{12, 15, 20}, Gap: 2 * sircUnit,
+}
+
+// sircDecoder decodes 12, 15 and 20-bit SIRC frames. Sony remotes send every
+// command at least three times, so one Data is reported for every three
+// identical frames.
+type sircDecoder struct {
+	frame    irprotocol.WidthDecoder
+	last     uint32 // code of the previous frame
+	lastBits int    // length of the previous frame
+	count    uint8  // number of identical frames received in a row
+}
+
+func (r *sircDecoder) reset() {
+	r.frame.Reset()
+	r.count = 0
+}
+
+func (r *sircDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &sircFrame
+	if r.frame.Pulse(mark, d) {
+		return r.decode(data)
+	}
+	if mark && r.count > 0 && r.frame.Len() == r.lastBits && uint32(r.frame.Code()) == r.last &&
+		r.frame.Idle() < sircMaxGap {
+		// A repeated frame ends as soon as it matches the previous one, as
+		// the gap after the last frame is only measured when the next key
+		// is pressed.
+		r.frame.Reset()
+		return r.decode(data)
+	}
+	return false
+}
+
+// decode handles a received frame, and returns true for every third
+// identical one.
+func (r *sircDecoder) decode(data *Data) bool {
+	code, bits := uint32(r.frame.Code()), r.frame.Len()
+	protocol := ProtocolSIRC12
+	switch bits {
+	case 15:
+		protocol = ProtocolSIRC15
+	case 20:
+		protocol = ProtocolSIRC20
+	}
+	if r.count > 0 && code == r.last && bits == r.lastBits && r.frame.Idle() < sircMaxGap {
+		r.count++
+	} else {
+		r.count = 1
+		r.last = code
+		r.lastBits = bits
+	}
+	if r.count%sircRepeats != 0 {
+		return false
+	}
+	*data = Data{Code: code, Protocol: protocol, Count: sircRepeats}
+	if r.count > sircRepeats {
+		// key held
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeSIRC()
+	return true
+}
+
+// decodeSIRC decodes Command and Address from a SIRC Code. The extended
+// bits of 20-bit frames are in the high byte of Address.
+func (data *Data) decodeSIRC() {
+	data.Command = uint16(data.Code) & 0x7F
+	switch data.Protocol {
+	case ProtocolSIRC15:
+		data.Address = uint16(data.Code>>7) & 0xFF
+	case ProtocolSIRC20:
+		data.Address = uint16(data.Code>>7)&0x1F | uint16(data.Code>>12)<<8
+	default:
+		data.Address = uint16(data.Code>>7) & 0x1F
+	}
+}
+
+// samsungFrame is the frame format of Samsung32.
+var samsungFrame = irprotocol.PulseDistance{
+	HeaderMark: samsungHeader, HeaderSpace: samsungHeader,
+	BitMark: necTiming.zeroMark, OneSpace: necTiming.oneSpace, ZeroSpace: necTiming.zeroSpace,
+	Bits: 32,
+}
+
+// samsungDecoder decodes Samsung32 frames.
+type samsungDecoder struct {
+	frame irprotocol.DistanceDecoder
+	last  uint32 // previous code, to detect held keys
+}
+
+func (r *samsungDecoder) reset() {
+	r.frame.Reset()
+}
+
+func (r *samsungDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &samsungFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	code := uint32(r.frame.Code())
+	if uint8(code>>16) != ^uint8(code>>24) {
+		return false
+	}
+	*data = Data{Code: code, Protocol: ProtocolSamsung}
+	if code == r.last && r.frame.Idle() < samsungPeriod {
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeSamsung()
+	r.last = code
+	return true
+}
+
+// decodeSamsung decodes Command and Address from a Samsung32 Code.
+func (data *Data) decodeSamsung() {
+	data.Address = uint16(data.Code)
+	data.Command = uint16(data.Code>>16) & 0xFF
+}
+
+// kaseikyoFrame is the frame format of Kaseikyo.
+var kaseikyoFrame = irprotocol.PulseDistance{
+	HeaderMark: 8 * kaseikyoUnit, HeaderSpace: 4 * kaseikyoUnit,
+	BitMark: kaseikyoUnit, OneSpace: kaseikyoTiming.oneSpace, ZeroSpace: kaseikyoTiming.zeroSpace,
+	Bits: 48,
+}
+
+// kaseikyoDecoder decodes 48-bit Kaseikyo frames.
+type kaseikyoDecoder struct {
+	frame irprotocol.DistanceDecoder
+	last  uint64 // previous frame, to detect held keys
+}
+
+func (r *kaseikyoDecoder) reset() {
+	r.frame.Reset()
+}
+
+func (r *kaseikyoDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &kaseikyoFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	frame := r.frame.Code()
+	vendor := uint16(frame)
+	code := uint32(frame >> 16)
+	if uint8(code)&0x0F != vendorParity(vendor) ||
+		uint8(code>>24) != uint8(code)^uint8(code>>8)^uint8(code>>16) {
+		return false
+	}
+	*data = Data{Code: code, Vendor: vendor, Protocol: ProtocolKaseikyo}
+	switch vendor {
+	case VendorPanasonic:
+		data.Protocol = ProtocolPanasonic
+	case VendorDenon:
+		data.Protocol = ProtocolDenonK
+	case VendorMitsubishi:
+		data.Protocol = ProtocolMitsubishiK
+	case VendorSharp:
+		data.Protocol = ProtocolSharpK
+	case VendorJVC:
+		data.Protocol = ProtocolJVC48
+	}
+	if frame == r.last && r.frame.Idle() < kaseikyoPeriod {
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeKaseikyo()
+	r.last = frame
+	return true
+}
+
+// vendor returns the Kaseikyo vendor ID of a protocol, or 0 if it has none.
+func (p Protocol) vendor() uint16 {
+	switch p {
+	case ProtocolPanasonic:
+		return VendorPanasonic
+	case ProtocolDenonK:
+		return VendorDenon
+	case ProtocolMitsubishiK:
+		return VendorMitsubishi
+	case ProtocolSharpK:
+		return VendorSharp
+	case ProtocolJVC48:
+		return VendorJVC
+	}
+	return 0
+}
+
+// decodeKaseikyo decodes Command and Address from a Kaseikyo Code, which
+// holds the 32 bits following the vendor ID: the vendor parity nibble, the
+// genre, 10 bits of data, a 2-bit ID and the parity byte.
+//
+// Panasonic frames are decoded to the device in the low and the subdevice in
+// the high byte of Address, and the function in Command. Other known vendors
+// use a 12-bit Address and an 8-bit Command. Frames of unknown vendors are
+// decoded to the genre in Address, and the data and ID in Command.
+func (data *Data) decodeKaseikyo() {
+	switch data.Protocol {
+	case ProtocolPanasonic:
+		data.Address = uint16(data.Code)
+		data.Command = uint16(data.Code>>16) & 0xFF
+	case ProtocolKaseikyo:
+		data.Address = uint16(data.Code>>4) & 0xFF
+		data.Command = uint16(data.Code>>12) & 0xFFF
+	default:
+		data.Address = uint16(data.Code>>4) & 0xFFF
+		data.Command = uint16(data.Code>>16) & 0xFF
+	}
+}
+
+// jvcMinGap is the shortest space after a JVC frame. JVC frames have the
+// timing of the first 16 bits of NEC frames, which the gap tells apart.
+const jvcMinGap = 8 * time.Millisecond
+
+// jvcFrame is the frame format of JVC.
+var jvcFrame = irprotocol.PulseDistance{
+	HeaderMark: 16 * jvcUnit, HeaderSpace: 8 * jvcUnit,
+	BitMark: jvcUnit, OneSpace: jvcTiming.oneSpace, ZeroSpace: jvcTiming.zeroSpace,
+	Bits: 16, Gap: jvcMinGap,
+}
+
+// jvcRepeatFrame is the frame format of the repeats of JVC, which have no
+// header.
+var jvcRepeatFrame = irprotocol.PulseDistance{
+	BitMark: jvcUnit, OneSpace: jvcTiming.oneSpace, ZeroSpace: jvcTiming.zeroSpace,
+	Bits: 16, Gap: jvcMinGap,
+}
+
+// jvcDecoder decodes JVC frames, and the repeats of a held key, which are
+// sent without a header.
+type jvcDecoder struct {
+	frame  irprotocol.DistanceDecoder
+	repeat irprotocol.DistanceDecoder
+	last   uint16 // previous code, for repeats
+	valid  bool   // last holds a valid code
+}
+
+func (r *jvcDecoder) reset() {
+	r.frame.Reset()
+	r.repeat.Reset()
+	r.valid = false
+}
+
+func (r *jvcDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &jvcFrame
+	r.repeat.Timing = &jvcRepeatFrame
+	// The repeat decoder also receives the bits of frames, after their
+	// header, and ends them at the same time.
+	frame, repeat := r.frame.Pulse(mark, d), r.repeat.Pulse(mark, d)
+	switch {
+	case frame:
+		return r.decode(data)
+	case repeat:
+		return r.decodeRepeat(data)
+	}
+	return false
+}
+
+func (r *jvcDecoder) end(d time.Duration, data *Data) bool {
+	frame, repeat := r.frame.End(d), r.repeat.End(d)
+	switch {
+	case frame:
+		return r.decode(data)
+	case repeat:
+		return r.decodeRepeat(data)
+	}
+	return false
+}
+
+// decode decodes a received frame into data.
+func (r *jvcDecoder) decode(data *Data) bool {
+	r.last = uint16(r.frame.Code())
+	r.valid = true
+	*data = Data{Code: uint32(r.last), Protocol: ProtocolJVC}
+	data.decodeJVC()
+	return true
+}
+
+// decodeRepeat decodes a received repeat into data, if it repeats the
+// previous frame.
+func (r *jvcDecoder) decodeRepeat(data *Data) bool {
+	if !r.valid || r.repeat.Idle() >= jvcPeriod || uint16(r.repeat.Code()) != r.last {
+		r.valid = false
+		return false
+	}
+	*data = Data{Code: uint32(r.last), Protocol: ProtocolJVC, Flags: DataFlagIsRepeat}
+	data.decodeJVC()
+	return true
+}
+
+// decodeJVC decodes Command and Address from a JVC Code.
+func (data *Data) decodeJVC() {
+	data.Address = uint16(data.Code) & 0xFF
+	data.Command = uint16(data.Code>>8) & 0xFF
+}
+
+// sharpMaxGap is the longest space between the two frames of a Sharp or
+// Denon command.
+const sharpMaxGap = 60 * time.Millisecond
+
+// sharpFrame is the frame format of Sharp, which also matches the slightly
+// different timing of Denon.
+var sharpFrame = irprotocol.PulseDistance{
+	BitMark: sharpTiming.zeroMark, OneSpace: sharpTiming.oneSpace, ZeroSpace: sharpTiming.zeroSpace,
+	Bits: 15,
+}
+
+// sharpDecoder decodes the 15-bit Sharp and Denon protocols, which only
+// differ in the expansion and check bits and slightly in timing. A command
+// is reported once its second frame, with the command, expansion and check
+// bits inverted, has confirmed the first one.
+type sharpDecoder struct {
+	frame   irprotocol.DistanceDecoder
+	first   uint16 // first frame of the command
+	pending bool   // first holds a first frame
+}
+
+func (r *sharpDecoder) reset() {
+	r.frame.Reset()
+	r.pending = false
+}
+
+func (r *sharpDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &sharpFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	return r.decode(uint16(r.frame.Code()), data)
+}
+
+// decode handles a received frame, and returns true if it confirms the
+// previous one.
+func (r *sharpDecoder) decode(frame uint16, data *Data) bool {
+	if r.pending && r.frame.Idle() < sharpMaxGap && frame == r.first^0x7FE0 {
+		r.pending = false
+		*data = Data{Code: uint32(r.first), Protocol: ProtocolDenon}
+		if r.first>>13 == 1 {
+			// expansion bit set, check bit clear
+			data.Protocol = ProtocolSharp
+		}
+		data.decodeSharp()
+		return true
+	}
+	// Sharp sends the expansion bit set and the check bit clear in the first
+	// frame, Denon both clear.
+	r.first = frame
+	r.pending = frame>>13 <= 1
+	return false
+}
+
+// decodeSharp decodes Command and Address from the Code of a Sharp or Denon
+// frame.
+func (data *Data) decodeSharp() {
+	data.Address = uint16(data.Code) & 0x1F
+	data.Command = uint16(data.Code>>5) & 0xFF
+}
+
+// lgMinGap is the shortest space after an LG frame. 28-bit LG frames have
+// the timing of the first 28 bits of NEC frames, which the gap tells apart.
+const lgMinGap = 8 * time.Millisecond
+
+// lgFrame and lg2Frame are the frame formats of LG and LG2.
+var (
+	lgFrame = irprotocol.PulseDistance{
+		HeaderMark: necHeaderMark, HeaderSpace: necHeaderSpace,
+		BitMark: necTiming.zeroMark, OneSpace: necTiming.oneSpace, ZeroSpace: necTiming.zeroSpace,
+		Bits: 28, MSBFirst: true, Gap: lgMinGap,
+	}
+	lg2Frame = irprotocol.PulseDistance{
+		HeaderMark: lg2HeaderMark, HeaderSpace: lg2HeaderSpace,
+		BitMark: necTiming.zeroMark, OneSpace: necTiming.oneSpace, ZeroSpace: necTiming.zeroSpace,
+		Bits: 28, MSBFirst: true, Gap: lgMinGap,
+	}
+)
+
+// lgDecoder decodes LG and LG2 28-bit frames, and the repeat codes of held
+// keys, which are those of NEC. 32-bit LG frames are NEC frames, decoded by
+// the NEC decoder.
+type lgDecoder struct {
+	lg, lg2 irprotocol.DistanceDecoder
+	header  bool   // the last pulse was an LG header mark, which may start a repeat code
+	repeat  bool   // the last pulses were the header of a repeat code
+	last    uint32 // previous code, for repeats
+	valid   bool   // last holds a valid code
+}
+
+func (r *lgDecoder) reset() {
+	r.lg.Reset()
+	r.lg2.Reset()
+	r.header = false
+	r.repeat = false
+	r.valid = false
+}
+
+func (r *lgDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.lg.Timing = &lgFrame
+	r.lg2.Timing = &lg2Frame
+	header, repeat := r.header, r.repeat
+	r.header = mark && match(d, necHeaderMark)
+	r.repeat = false
+	if header && !mark {
+		if match(d, necRepeatSpace) {
+			r.repeat = r.valid
+		} else {
+			// a new frame, or noise, ends the repeats of the previous one
+			r.valid = false
+		}
+	}
+	if repeat && mark && match(d, necTiming.zeroMark) {
+		// the stop mark of a repeat code
+		*data = Data{Code: r.last, Protocol: ProtocolLG, Flags: DataFlagIsRepeat}
+		data.decodeLG()
+		return true
+	}
+	switch {
+	case r.lg.Pulse(mark, d):
+		return r.decode(uint32(r.lg.Code()), ProtocolLG, data)
+	case r.lg2.Pulse(mark, d):
+		return r.decode(uint32(r.lg2.Code()), ProtocolLG2, data)
+	}
+	return false
+}
+
+func (r *lgDecoder) end(d time.Duration, data *Data) bool {
+	switch {
+	case r.lg.End(d):
+		return r.decode(uint32(r.lg.Code()), ProtocolLG, data)
+	case r.lg2.End(d):
+		return r.decode(uint32(r.lg2.Code()), ProtocolLG2, data)
+	}
+	return false
+}
+
+// decode validates the checksum of a received frame and decodes it into
+// data.
+func (r *lgDecoder) decode(code uint32, protocol Protocol, data *Data) bool {
+	if uint8(code)&0x0F != lgChecksum(uint16(code>>4)) {
+		r.valid = false
+		return false
+	}
+	*data = Data{Code: code, Protocol: protocol}
+	data.decodeLG()
+	r.last = code
+	r.valid = true
+	return true
+}
+
+// decodeLG decodes Command and Address from an LG Code.
+func (data *Data) decodeLG() {
+	data.Address = uint16(data.Code>>20) & 0xFF
+	data.Command = uint16(data.Code >> 4)
+}
+
+// sanyoFrame is the frame format of Sanyo LC7461.
+var sanyoFrame = irprotocol.PulseDistance{
+	HeaderMark: necHeaderMark, HeaderSpace: necHeaderSpace,
+	BitMark: necTiming.zeroMark, OneSpace: necTiming.oneSpace, ZeroSpace: necTiming.zeroSpace,
+	Bits: 42,
+}
+
+// sanyoDecoder decodes 42-bit Sanyo LC7461 frames.
+type sanyoDecoder struct {
+	frame irprotocol.DistanceDecoder
+	last  uint32 // previous code, to detect held keys
+}
+
+func (r *sanyoDecoder) reset() {
+	r.frame.Reset()
+}
+
+func (r *sanyoDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &sanyoFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	frame := r.frame.Code()
+	address := uint16(frame) & 0x1FFF
+	command := uint8(frame >> 26)
+	if uint16(frame>>13)&0x1FFF != ^address&0x1FFF || uint8(frame>>34) != ^command {
+		return false
+	}
+	code := uint32(address) | uint32(command)<<16
+	*data = Data{Code: code, Protocol: ProtocolSanyo}
+	if code == r.last && r.frame.Idle() < sanyoPeriod {
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeSanyo()
+	r.last = code
+	return true
+}
+
+// decodeSanyo decodes Command and Address from a Sanyo Code, which holds the
+// 13-bit address in the low and the command in the high half, without their
+// inverses.
+func (data *Data) decodeSanyo() {
+	data.Address = uint16(data.Code) & 0x1FFF
+	data.Command = uint16(data.Code>>16) & 0xFF
+}
+
+// mitsubishiMinIdle is the shortest space before a Mitsubishi frame. Frames
+// have no header, so only a space much longer than those of the bits marks
+// the start of one.
+const mitsubishiMinIdle = 20 * time.Millisecond
+
+// mitsubishiFrame is the frame format of Mitsubishi 16-bit commands.
+var mitsubishiFrame = irprotocol.PulseDistance{
+	BitMark: mitsubishiTiming.zeroMark, OneSpace: mitsubishiTiming.oneSpace, ZeroSpace: mitsubishiTiming.zeroSpace,
+	Bits: 16, MSBFirst: true,
+}
+
+// mitsubishiDecoder decodes Mitsubishi 16-bit commands. A command is reported
+// once its second, identical frame has confirmed the first one.
+type mitsubishiDecoder struct {
+	frame     irprotocol.DistanceDecoder
+	first     uint16        // first frame of the command
+	firstIdle time.Duration // space before the first frame
+	pending   bool          // first holds a first frame
+	last      uint16        // previous command, to detect held keys
+}
+
+func (r *mitsubishiDecoder) reset() {
+	r.frame.Reset()
+	r.pending = false
+}
+
+func (r *mitsubishiDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &mitsubishiFrame
+	if !r.frame.Pulse(mark, d) || r.frame.Idle() < mitsubishiMinIdle {
+		return false
+	}
+	frame := uint16(r.frame.Code())
+	if r.pending && r.frame.Idle() < mitsubishiPeriod && frame == r.first {
+		r.pending = false
+		*data = Data{Code: uint32(frame), Protocol: ProtocolMitsubishi}
+		if frame == r.last && r.firstIdle < mitsubishiPeriod {
+			data.Flags |= DataFlagIsRepeat
+		}
+		data.decodeMitsubishi()
+		r.last = frame
+		return true
+	}
+	r.first = frame
+	r.firstIdle = r.frame.Idle()
+	r.pending = true
+	return false
+}
+
+// decodeMitsubishi decodes Command and Address from a Mitsubishi Code.
+func (data *Data) decodeMitsubishi() {
+	data.Address = uint16(data.Code>>8) & 0xFF
+	data.Command = uint16(data.Code) & 0xFF
+}
+
+// pioneerDecoder decodes the Pioneer commands made of two different NEC
+// frames with 8-bit addresses, sent one period apart. Keys sending the same
+// frame twice can't be told apart from the full frame repeats of NEC remotes
+// held down, see NECRepeatFullFrame, so they are left to the NEC decoder,
+// which also reports the frames of two frame commands on their own.
+type pioneerDecoder struct {
+	nec      necDecoder
+	gap      time.Duration // last space between frames
+	first    uint32        // first frame of the command
+	firstGap time.Duration // space before the first frame
+	pending  bool          // first holds a first frame
+	last     uint32        // previous Code, to detect held keys
+}
+
+func (r *pioneerDecoder) reset() {
+	r.nec.reset()
+	r.gap = 0
+	r.pending = false
+}
+
+func (r *pioneerDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	if !mark && d > 5*time.Millisecond {
+		// longer than any space of a frame
+		r.gap = d
+	}
+	if !r.nec.pulse(mark, d, data) {
+		return false
+	}
+	frame := data.Code
+	if data.Flags&DataFlagIsRepeat != 0 || uint8(frame>>8) != ^uint8(frame) {
+		// not a Pioneer frame
+		r.pending = false
+		return false
+	}
+	if r.pending && r.gap < necPeriod && frame != r.first {
+		r.pending = false
+		code := uint32(uint8(r.first)) | uint32(uint8(r.first>>16))<<8 |
+			uint32(uint8(frame))<<16 | uint32(uint8(frame>>16))<<24
+		*data = Data{Code: code, Protocol: ProtocolPioneer}
+		if code == r.last && r.firstGap < necPeriod {
+			data.Flags |= DataFlagIsRepeat
+		}
+		data.decodePioneer()
+		r.last = code
+		return true
+	}
+	r.first = frame
+	r.firstGap = r.gap
+	r.pending = true
+	return false
+}
+
+// decodePioneer decodes Command and Address from a Pioneer Code, which holds
+// the address and command of the first frame in the low half and those of
+// the second frame in the high half, without their inverses. Address and
+// Command hold those of the first frame in the low and of the second frame
+// in the high byte.
+func (data *Data) decodePioneer() {
+	data.Address = uint16(data.Code)&0xFF | uint16(data.Code>>8)&0xFF00
+	data.Command = uint16(data.Code>>8)&0xFF | uint16(data.Code>>16)&0xFF00
+}
+
+// rcaOnePeriod and rcaZeroPeriod are the durations of the marks and spaces
+// of RCA bits together. Receivers tuned to 38kHz, the most common ones,
+// respond late to the 56kHz carrier of RCA, shortening its marks and
+// lengthening its spaces, which barely changes their sum.
+const (
+	rcaOnePeriod  = 5 * rcaUnit
+	rcaZeroPeriod = 3 * rcaUnit
+)
+
+// rcaDecoder decodes RCA frames. Unlike irprotocol.DistanceDecoder, it
+// matches the periods of bits rather than their marks and spaces, see
+// rcaOnePeriod.
+type rcaDecoder struct {
+	state uint8         // distanceState*
+	code  uint32        // received bits, MSB first
+	n     int           // number of received bits
+	mark  time.Duration // last mark
+	idle  time.Duration // space before the frame
+	last  uint32        // previous code, to detect held keys
+}
+
+func (r *rcaDecoder) reset() {
+	r.state = distanceStateIdle
+}
+
+func (r *rcaDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	switch r.state {
+	case distanceStateIdle:
+		if !mark {
+			r.idle = d
+		} else if match(d, 8*rcaUnit) {
+			r.mark = d
+			r.state = distanceStateHeader
+		}
+		return false
+	case distanceStateHeader:
+		if mark || !match(r.mark+d, 16*rcaUnit) {
+			break
+		}
+		r.code = 0
+		r.n = 0
+		r.state = distanceStateMark
+		return false
+	case distanceStateMark:
+		if !mark || d > 2*rcaUnit {
+			break
+		}
+		r.mark = d
+		r.state = distanceStateSpace
+		return false
+	case distanceStateSpace:
+		one := match(r.mark+d, rcaOnePeriod)
+		if mark || !one && !match(r.mark+d, rcaZeroPeriod) {
+			break
+		}
+		r.code = r.code<<1 | uint32(b2u8(one))
+		r.n++
+		r.state = distanceStateMark
+		if r.n == 24 {
+			r.state = distanceStateStop
+		}
+		return false
+	case distanceStateStop:
+		if !mark || d > 2*rcaUnit {
+			break
+		}
+		r.state = distanceStateIdle
+		code := r.code >> 12
+		if r.code&0xFFF != ^code&0xFFF {
+			return false
+		}
+		*data = Data{Code: code, Protocol: ProtocolRCA}
+		if code == r.last && r.idle < rcaPeriod {
+			data.Flags |= DataFlagIsRepeat
+		}
+		data.decodeRCA()
+		r.last = code
+		return true
+	}
+	// invalid pulse, which may start a new frame
+	r.reset()
+	return r.pulse(mark, d, data)
+}
+
+// decodeRCA decodes Command and Address from an RCA Code.
+func (data *Data) decodeRCA() {
+	data.Address = uint16(data.Code>>8) & 0x0F
+	data.Command = uint16(data.Code) & 0xFF
+}
+
+// dishFrame is the frame format of Dish Network. The header mark of a frame
+// is the stop mark of the previous one.
+var dishFrame = irprotocol.PulseDistance{
+	HeaderMark: dishUnit, HeaderSpace: dishGap,
+	BitMark: dishUnit, OneSpace: dishTiming.oneSpace, ZeroSpace: dishTiming.zeroSpace,
+	Bits: 16, MSBFirst: true,
+}
+
+// dishDecoder decodes Dish Network frames. Dish remotes send every code four
+// times, each frame starting with the stop mark of the previous one, so one
+// Data is reported for every four identical frames.
+type dishDecoder struct {
+	frame irprotocol.DistanceDecoder
+	last  uint16 // code of the previous frame
+	count uint8  // number of identical frames received in a row
+}
+
+func (r *dishDecoder) reset() {
+	r.frame.Reset()
+	r.count = 0
+}
+
+func (r *dishDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &dishFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	// Chained frames follow the previous one without idle time, which tells
+	// them from the first frame of a code.
+	chained := r.frame.Idle() == 0
+	ok := r.decode(uint16(r.frame.Code()), chained, data)
+	// the stop mark is the header mark of the next frame
+	r.frame.Pulse(false, 0)
+	r.frame.Pulse(mark, d)
+	return ok
+}
+
+// decode handles a received frame, and returns true for every fourth
+// identical one.
+func (r *dishDecoder) decode(code uint16, chained bool, data *Data) bool {
+	if chained && r.count > 0 && code == r.last {
+		r.count++
+	} else {
+		r.count = 1
+		r.last = code
+	}
+	if r.count%(dishRepeats+1) != 0 {
+		return false
+	}
+	*data = Data{Code: uint32(code), Protocol: ProtocolDish, Count: dishRepeats + 1}
+	if r.count > dishRepeats+1 {
+		// key held
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeDish()
+	return true
+}
+
+// decodeDish decodes Command and Address from a Dish Network Code, which
+// holds the 6-bit command in the high bits, followed by the unit code and
+// address of the receiver in the low 10 bits.
+func (data *Data) decodeDish() {
+	data.Address = uint16(data.Code) & 0x3FF
+	data.Command = uint16(data.Code>>10) & 0x3F
+}
+
+// boseFrame is the frame format of Bose Wave.
+var boseFrame = irprotocol.PulseDistance{
+	HeaderMark: 1060 * time.Microsecond, HeaderSpace: 1425 * time.Microsecond,
+	BitMark: boseTiming.zeroMark, OneSpace: boseTiming.oneSpace, ZeroSpace: boseTiming.zeroSpace,
+	Bits: 16,
+}
+
+// boseDecoder decodes Bose Wave frames.
+type boseDecoder struct {
+	frame irprotocol.DistanceDecoder
+	last  uint8 // previous command, to detect held keys
+}
+
+func (r *boseDecoder) reset() {
+	r.frame.Reset()
+}
+
+func (r *boseDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &boseFrame
+	if !r.frame.Pulse(mark, d) {
+		return false
+	}
+	frame := uint16(r.frame.Code())
+	command := uint8(frame)
+	if uint8(frame>>8) != ^command {
+		return false
+	}
+	*data = Data{Code: uint32(frame), Protocol: ProtocolBose}
+	if command == r.last && r.frame.Idle() < bosePeriod {
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeBose()
+	r.last = command
+	return true
+}
+
+// decodeBose decodes Command from a Bose Wave Code. The protocol has no
+// address.
+func (data *Data) decodeBose() {
+	data.Command = uint16(data.Code) & 0xFF
+}
+
+// magiQuestFrame is the frame format of MagiQuest.
+var magiQuestFrame = irprotocol.PulseWidth{
+	OneMark: magiQuestTiming.oneMark, OneSpace: magiQuestTiming.oneSpace,
+	ZeroMark: magiQuestTiming.zeroMark, ZeroSpace: magiQuestTiming.zeroSpace,
+	Bits: 56, MSBFirst: true,
+}
+
+// magiQuestDecoder decodes the 56-bit frames of MagiQuest wands, which are
+// pulse width coded without a header.
+type magiQuestDecoder struct {
+	frame irprotocol.WidthDecoder
+}
+
+func (r *magiQuestDecoder) reset() {
+	r.frame.Reset()
+}
+
+func (r *magiQuestDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	r.frame.Timing = &magiQuestFrame
+	return r.frame.Pulse(mark, d) && r.decode(r.frame.Code(), data)
+}
+
+// decode validates a received frame and decodes it into data.
+func (r *magiQuestDecoder) decode(frame uint64, data *Data) bool {
+	wandID := uint32(frame>>17) & 0x7FFFFFFF
+	magnitude := uint16(frame>>8) & 0x1FF
+	sum := uint8(wandID) + uint8(wandID>>8) + uint8(wandID>>16) + uint8(wandID>>24) +
+		uint8(magnitude) + uint8(magnitude>>8) + uint8(frame)
+	if frame>>48 != 0 || sum != 0 {
+		return false
+	}
+	*data = Data{Code: wandID, Command: magnitude, Protocol: ProtocolMagiQuest}
+	return true
+}
+
+// rcmmDecoder decodes 12, 24 and 32-bit RC-MM frames. The length of a frame
+// follows from its mode bits: 00 escapes to the 24-bit extended modes, and
+// 0000 01 to the 32-bit OEM mode.
+type rcmmDecoder struct {
+	state  uint8 // distanceState*
+	code   uint32
+	n      int           // number of received bits
+	length int           // length of the frame, 0 until known
+	idle   time.Duration // space before the frame
+	last   uint32        // previous code, to detect held keys
+}
+
+func (r *rcmmDecoder) reset() {
+	r.state = distanceStateIdle
+}
+
+// rcmmPair returns the 2 bits coded by the space d, or -1 if d isn't one of
+// the 4 spaces of RC-MM. These are only 6 carrier cycles apart, so d must be
+// within 3 cycles of one.
+func rcmmPair(d time.Duration) int {
+	if d < 7*rcmmCycle || d >= 31*rcmmCycle {
+		return -1
+	}
+	return int((d - 7*rcmmCycle) / (6 * rcmmCycle))
+}
+
+// rcmmBits returns the length of the frame starting with the given bits, or
+// 0 if more bits are needed to tell.
+func rcmmBits(code uint32, n int) int {
+	switch {
+	case n == 2 && code != 0:
+		return 12
+	case n == 6 && code == 1:
+		return 32
+	case n == 6:
+		return 24
+	}
+	return 0
+}
+
+func (r *rcmmDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	switch r.state {
+	case distanceStateIdle:
+		if !mark {
+			r.idle = d
+		} else if match(d, 15*rcmmCycle) {
+			r.state = distanceStateHeader
+		}
+		return false
+	case distanceStateHeader:
+		if mark || rcmmPair(d) != 0 {
+			break
+		}
+		r.code = 0
+		r.n = 0
+		r.length = 0
+		r.state = distanceStateMark
+		return false
+	case distanceStateMark:
+		if !mark || !match(d, rcmmMark) {
+			break
+		}
+		r.state = distanceStateSpace
+		return false
+	case distanceStateSpace:
+		pair := rcmmPair(d)
+		if mark || pair < 0 {
+			break
+		}
+		r.code = r.code<<2 | uint32(pair)
+		r.n += 2
+		if r.length == 0 {
+			r.length = rcmmBits(r.code, r.n)
+		}
+		r.state = distanceStateMark
+		if r.n == r.length {
+			r.state = distanceStateStop
+		}
+		return false
+	case distanceStateStop:
+		if !mark || !match(d, rcmmMark) {
+			break
+		}
+		r.state = distanceStateIdle
+		*data = Data{Code: r.code}
+		switch r.length {
+		case 12:
+			data.Protocol = ProtocolRCMM12
+		case 24:
+			data.Protocol = ProtocolRCMM24
+		default:
+			data.Protocol = ProtocolRCMM32
+		}
+		if r.code == r.last && r.idle < rcmmPeriod {
+			data.Flags |= DataFlagIsRepeat
+		}
+		data.decodeRCMM()
+		r.last = r.code
+		return true
+	}
+	// invalid pulse, which may start a new frame
+	r.reset()
+	return r.pulse(mark, d, data)
+}
+
+// decodeRCMM decodes Command and Address from an RC-MM Code. Address holds
+// the bits above the low 8 bits of 12-bit frames, and above the low 16 bits
+// of longer frames, including the mode bits.
+func (data *Data) decodeRCMM() {
+	if data.Protocol == ProtocolRCMM12 {
+		data.Address = uint16(data.Code>>8) & 0x0F
+		data.Command = uint16(data.Code) & 0xFF
+		return
+	}
+	data.Address = uint16(data.Code >> 16)
+	data.Command = uint16(data.Code)
+}
+
+// xmpDecoder decodes XMP frames, made of two halves of 8 nibbles separated
+// by a pause. Nibbles are coded by the length of the space after a mark,
+// without a header.
+type xmpDecoder struct {
+	state  uint8 // distanceState*
+	code   uint64
+	n      int           // number of received nibbles
+	second bool          // receiving the second half
+	idle   time.Duration // space before the frame
+	last   uint32        // previous code, to detect held keys
+}
+
+func (r *xmpDecoder) reset() {
+	r.state = distanceStateIdle
+}
+
+// xmpNibble returns the nibble coded by the space d, or -1 if d isn't one of
+// the 16 spaces of XMP. These are only 136µs apart, so d must be within half
+// of that of one.
+func xmpNibble(d time.Duration) int {
+	if d < xmpSpace-xmpStep/2 || d >= xmpSpace+31*xmpStep/2 {
+		return -1
+	}
+	return int((d - xmpSpace + xmpStep/2) / xmpStep)
+}
+
+func (r *xmpDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	switch r.state {
+	case distanceStateIdle:
+		if !mark {
+			r.idle = d
+			return false
+		}
+		if !match(d, xmpMark) {
+			return false
+		}
+		r.code = 0
+		r.n = 0
+		r.second = false
+		r.state = distanceStateSpace
+		return false
+	case distanceStateMark:
+		if !mark || !match(d, xmpMark) {
+			break
+		}
+		r.state = distanceStateSpace
+		if r.n == 8 && !r.second {
+			r.state = distanceStateHeader
+		} else if r.n == 16 {
+			r.state = distanceStateIdle
+			return r.frame(data)
+		}
+		return false
+	case distanceStateHeader:
+		// pause between the halves
+		if mark || !match(d, xmpHalfPause) {
+			break
+		}
+		r.second = true
+		r.state = distanceStateMark
+		return false
+	case distanceStateSpace:
+		nibble := xmpNibble(d)
+		if mark || nibble < 0 {
+			break
+		}
+		r.code = r.code<<4 | uint64(nibble)
+		r.n++
+		r.state = distanceStateMark
+		return false
+	}
+	// invalid pulse, which may start a new frame
+	r.reset()
+	return r.pulse(mark, d, data)
+}
+
+// frame validates the checksums of a received frame and decodes it into
+// data.
+func (r *xmpDecoder) frame(data *Data) bool {
+	for half := 0; half < 2; half++ {
+		var sum uint64
+		for i := 0; i < 32; i += 4 {
+			sum += r.code >> uint(32*half+i) & 0xF
+		}
+		if sum&0xF != 0xF {
+			return false
+		}
+	}
+	code := uint32(r.code>>40)<<16 | uint32(r.code>>8)&0xFFFF
+	*data = Data{Code: code, Protocol: ProtocolXMP}
+	if code == r.last && r.idle < xmpPeriod {
+		data.Flags |= DataFlagIsRepeat
+	}
+	data.decodeXMP()
+	r.last = code
+	return true
+}
+
+// decodeXMP decodes Command and Address from an XMP Code, which holds the
+// OEM code and device of the first half in the high 16 bits, and the middle
+// 16 bits of the second half in the low bits. The sub-device and checksum
+// nibbles and the unused low byte are not kept.
+func (data *Data) decodeXMP() {
+	data.Address = uint16(data.Code >> 16)
+	data.Command = uint16(data.Code) & 0xFF
+}
+
+// beoDecoder decodes Bang & Olufsen Datalink 80 frames, where bits are coded
+// by the distance between marks in units of 3.125ms. Frames last up to 200ms,
+// with spaces of up to 5 units. They need a 455kHz receiver, so the decoder
+// is only enabled by ReceiverDevice.BangOlufsen.
+type beoDecoder struct {
+	enabled bool
+	state   uint8         // distanceStateIdle, distanceStateMark or distanceStateSpace
+	mark    time.Duration // last mark
+	n       int           // number of received distances
+	code    uint16
+	prev    bool // previous bit
+}
+
+// beoSequence are the distances between the marks of a frame before and
+// after the 16 data bits.
+var beoSequence = [...]int{1, 1, 4, 2, 5}
+
+func (r *beoDecoder) reset() {
+	r.state = distanceStateIdle
+}
+
+// beoUnits returns the number of units from 1 to 5 in d, rounded, or 0 if d
+// isn't close to a whole number of them.
+func beoUnits(d time.Duration) int {
+	n := (d + beoUnit/2) / beoUnit
+	if n < 1 || n > 5 || !match(d, n*beoUnit) {
+		return 0
+	}
+	return int(n)
+}
+
+func (r *beoDecoder) pulse(mark bool, d time.Duration, data *Data) bool {
+	if !r.enabled {
+		return false
+	}
+	switch r.state {
+	case distanceStateIdle:
+		if !mark {
+			return false
+		}
+		if !match(d, beoMark) {
+			return false
+		}
+		r.n = 0
+		r.mark = d
+		r.state = distanceStateSpace
+		return false
+	case distanceStateMark:
+		if !mark || !match(d, beoMark) {
+			break
+		}
+		r.mark = d
+		r.state = distanceStateSpace
+		if r.n < 21 {
+			return false
+		}
+		// the mark ending the trailer
+		r.state = distanceStateIdle
+		*data = Data{Code: uint32(r.code), Protocol: ProtocolBangOlufsen}
+		data.decodeBangOlufsen()
+		return true
+	case distanceStateSpace:
+		if mark || !r.distance(beoUnits(r.mark+d)) {
+			break
+		}
+		r.n++
+		r.state = distanceStateMark
+		return false
+	}
+	// invalid pulse, which may start a new frame
+	r.reset()
+	return r.pulse(mark, d, data)
+}
+
+// distance handles the distance of units from the previous mark, and returns
+// false if it isn't valid at this point of the frame.
+func (r *beoDecoder) distance(units int) bool {
+	switch {
+	case r.n < 4:
+		r.code = 0
+		r.prev = true // the last distance is a 1 before the data
+		return units == beoSequence[r.n]
+	case r.n < 20:
+		var bit bool
+		switch units {
+		case 1:
+		case 2:
+			bit = true
+		case 3:
+			// the previous bit again
+			bit = r.prev
+		default:
+			return false
+		}
+		r.code = r.code<<1 | uint16(b2u8(bit))
+		r.prev = bit
+		return true
+	}
+	return units == beoSequence[4]
+}
+
+// decodeBangOlufsen decodes Command and Address from a Bang & Olufsen Code.
+func (data *Data) decodeBangOlufsen() {
+	data.Address = uint16(data.Code>>8) & 0xFF
+	data.Command = uint16(data.Code) & 0xFF
+}
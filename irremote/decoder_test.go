@@ -0,0 +1,698 @@
+package irremote
+
+import (
+	"math/bits"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/events"
+)
+
+// receive feeds the pulses recorded from a sender to dec, after an idle
+// line, and returns the decoded data.
+func receive(dec decoder, pulses []int32) []Data {
+	var received []Data
+	var data Data
+	for _, p := range append([]int32{-200000}, pulses...) {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		if dec.pulse(mark, time.Duration(p)*time.Microsecond, &data) {
+			received = append(received, data)
+		}
+	}
+	return received
+}
+
+func TestDecodeNEC(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendNEC(0x04, 0x08, 2)
+	c.Assert(receive(&necDecoder{}, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xF708FB04, Address: 0x04, Command: 0x08},
+		{Code: 0xF708FB04, Address: 0x04, Command: 0x08, Flags: DataFlagIsRepeat},
+		{Code: 0xF708FB04, Address: 0x04, Command: 0x08, Flags: DataFlagIsRepeat},
+	})
+
+	// extended address
+	ir, r = newTestSender()
+	ir.SendNEC(0x1234, 0x08, 0)
+	c.Assert(receive(&necDecoder{}, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xF7081234, Address: 0x1234, Command: 0x08},
+	})
+
+	// command not followed by its inverse
+	ir, r = newTestSender()
+	ir.SendNEC16Command(0x04, 0x0808, 1)
+	c.Assert(receive(&necDecoder{}, r.pulses), qt.HasLen, 0)
+	c.Assert(receive(&necDecoder{lenient: true}, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x0808FB04, Address: 0x04, Command: 0x0808},
+		{Code: 0x0808FB04, Address: 0x04, Command: 0x0808, Flags: DataFlagIsRepeat},
+	})
+}
+
+func TestDecodeNECCode(t *testing.T) {
+	c := qt.New(t)
+	data, err := DecodeNEC(0xF708FB04, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(data, qt.DeepEquals, Data{Code: 0xF708FB04, Address: 0x04, Command: 0x08})
+
+	_, err = DecodeNEC(0x0808FB04, false)
+	c.Assert(err, qt.Equals, errNECInverse)
+	data, err = DecodeNEC(0x0808FB04, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(data, qt.DeepEquals, Data{Code: 0x0808FB04, Address: 0x04, Command: 0x0808})
+	c.Assert(NECBytes(data.Code), qt.Equals, [4]byte{0x04, 0xFB, 0x08, 0x08})
+
+	// lenient frames survive events
+	e := events.Event{Flags: data.eventFlags(), Value: data.Code}
+	c.Assert(DataFromEvent(e), qt.DeepEquals, data)
+}
+
+func TestDecodeRC5(t *testing.T) {
+	c := qt.New(t)
+	dec := &rc5Decoder{}
+	for _, tc := range []struct {
+		address, command uint8
+		toggle           bool
+	}{
+		{0x05, 0x35, false},
+		{0x00, 0x00, true},
+		{0x1F, 0x3F, false},
+		{0x14, 0x47, true}, // RC-5X
+	} {
+		ir, r := newTestSender()
+		ir.SendRC5(tc.address, tc.command, tc.toggle)
+		got := receive(dec, r.pulses)
+		c.Assert(got, qt.HasLen, 1)
+		c.Assert(got[0].Protocol, qt.Equals, ProtocolRC5)
+		c.Assert(got[0].Address, qt.Equals, uint16(tc.address))
+		c.Assert(got[0].Command, qt.Equals, uint16(tc.command))
+		c.Assert(got[0].Flags&DataFlagToggle != 0, qt.Equals, tc.toggle)
+		c.Assert(got[0].Flags&DataFlagIsRepeat, qt.Equals, DataFlags(0))
+	}
+
+	// a held key repeats the frame with the same toggle bit
+	ir, r := newTestSender()
+	ir.SendRC5(0x05, 0x35, true)
+	ir.SendRC5(0x05, 0x35, true)
+	got := receive(dec, r.pulses)
+	c.Assert(got, qt.HasLen, 2)
+	c.Assert(got[0].Flags, qt.Equals, DataFlagToggle)
+	c.Assert(got[1].Flags, qt.Equals, DataFlagToggle|DataFlagIsRepeat)
+}
+
+func TestDataFromEvent(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendRC5(0x14, 0x47, true)
+	data := receive(&rc5Decoder{}, r.pulses)[0]
+	e := events.Event{Kind: events.IRCommand, Flags: data.eventFlags(), Value: data.Code}
+	c.Assert(DataFromEvent(e), qt.DeepEquals, data)
+}
+
+func TestDecodeRC6(t *testing.T) {
+	c := qt.New(t)
+	dec := &rc6Decoder{}
+	ir, r := newTestSender()
+	ir.SendRC6(0x04, 0x0C, true)
+	ir.SendRC6(0x04, 0x0C, true)
+	ir.SendRC6(0xFF, 0x00, false)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x040C, Address: 0x04, Command: 0x0C, Flags: DataFlagToggle, Protocol: ProtocolRC6},
+		{Code: 0x040C, Address: 0x04, Command: 0x0C, Flags: DataFlagToggle | DataFlagIsRepeat, Protocol: ProtocolRC6},
+		{Code: 0xFF00, Address: 0xFF, Command: 0x00, Protocol: ProtocolRC6},
+	})
+
+	// MCE, with the toggle in the address
+	ir, r = newTestSender()
+	ir.SendMCE(0x04, 0x0D, true)
+	ir.SendMCE(0x04, 0x0E, false)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x800F840D, Address: 0x04, Command: 0x0D, Flags: DataFlagToggle, Protocol: ProtocolRC6A},
+		{Code: 0x800F040E, Address: 0x04, Command: 0x0E, Protocol: ProtocolRC6A},
+	})
+
+	// 24-bit RC-6A with a short customer code
+	ir, r = newTestSender()
+	ir.SendRC6A(0x26, 0x01, 0x03, true)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x260103, Address: 0x01, Command: 0x03, Flags: DataFlagToggle, Protocol: ProtocolRC6A},
+	})
+
+	// a corrupted mark makes a bit of two marks
+	ir, r = newTestSender()
+	ir.SendRC6(0x55, 0xAA, false)
+	c.Assert(r.pulses[8], qt.Equals, int32(444))
+	r.pulses[8] = 888
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// RC-5 isn't mistaken for RC-6
+	ir, r = newTestSender()
+	ir.SendRC5(0x05, 0x35, false)
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeSIRC(t *testing.T) {
+	c := qt.New(t)
+	dec := &sircDecoder{}
+	ir, r := newTestSender()
+	ir.SendSIRC12(0x01, 0x15)
+	ir.SendSIRC12(0x01, 0x15) // key held
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x01<<7 | 0x15, Address: 0x01, Command: 0x15, Protocol: ProtocolSIRC12, Count: 3},
+		{Code: 0x01<<7 | 0x15, Address: 0x01, Command: 0x15, Protocol: ProtocolSIRC12, Count: 3, Flags: DataFlagIsRepeat},
+	})
+
+	ir, r = newTestSender()
+	ir.SendSIRC15(0x97, 0x1A)
+	ir.SendSIRC20(0x1A, 0x49, 0xB5)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x97<<7 | 0x1A, Address: 0x97, Command: 0x1A, Protocol: ProtocolSIRC15, Count: 3},
+		{Code: 0xB5<<12 | 0x1A<<7 | 0x49, Address: 0xB51A, Command: 0x49, Protocol: ProtocolSIRC20, Count: 3},
+	})
+
+	// a frame missing from the triple
+	ir, r = newTestSender()
+	ir.SendSIRC12(0x01, 0x15)
+	c.Assert(receive(dec, r.pulses[26:]), qt.HasLen, 0)
+
+	data := Data{Code: 0xB5<<12 | 0x1A<<7 | 0x49, Address: 0xB51A, Command: 0x49, Protocol: ProtocolSIRC20, Count: 3}
+	e := events.Event{Flags: data.eventFlags(), Value: data.Code}
+	c.Assert(DataFromEvent(e), qt.DeepEquals, data)
+}
+
+func TestDecodeSamsung(t *testing.T) {
+	c := qt.New(t)
+	dec := &samsungDecoder{}
+	ir, r := newTestSender()
+	ir.SendSamsung(0x0707, 0x02)
+	ir.SendSamsung(0x0707, 0x02)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xFD020707, Address: 0x0707, Command: 0x02, Protocol: ProtocolSamsung},
+		{Code: 0xFD020707, Address: 0x0707, Command: 0x02, Protocol: ProtocolSamsung, Flags: DataFlagIsRepeat},
+	})
+
+	// 36-bit frames have a separator after the address
+	ir, r = newTestSender()
+	ir.SendSamsung36(0x0707, 0x02)
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// NEC frames have a longer header
+	ir, r = newTestSender()
+	ir.SendNEC(0x07, 0x02, 0)
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeKaseikyo(t *testing.T) {
+	c := qt.New(t)
+	dec := &kaseikyoDecoder{}
+	ir, r := newTestSender()
+	ir.SendPanasonic(0x80, 0x00, 0x3D)
+	ir.SendPanasonic(0x80, 0x00, 0x3D)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xBD3D0080, Address: 0x0080, Command: 0x3D, Vendor: VendorPanasonic, Protocol: ProtocolPanasonic},
+		{Code: 0xBD3D0080, Address: 0x0080, Command: 0x3D, Vendor: VendorPanasonic, Protocol: ProtocolPanasonic, Flags: DataFlagIsRepeat},
+	})
+
+	for _, tc := range []struct {
+		send     func(ir *SenderDevice, address uint16, command uint8)
+		vendor   uint16
+		protocol Protocol
+	}{
+		{(*SenderDevice).SendDenonK, VendorDenon, ProtocolDenonK},
+		{(*SenderDevice).SendMitsubishiK, VendorMitsubishi, ProtocolMitsubishiK},
+		{(*SenderDevice).SendSharpK, VendorSharp, ProtocolSharpK},
+		{(*SenderDevice).SendJVC48, VendorJVC, ProtocolJVC48},
+	} {
+		ir, r := newTestSender()
+		tc.send(ir, 0xABC, 0xDE)
+		got := receive(dec, r.pulses)
+		c.Assert(got, qt.HasLen, 1)
+		c.Assert(got[0].Protocol, qt.Equals, tc.protocol)
+		c.Assert(got[0].Vendor, qt.Equals, tc.vendor)
+		c.Assert(got[0].Address, qt.Equals, uint16(0xABC))
+		c.Assert(got[0].Command, qt.Equals, uint16(0xDE))
+		e := events.Event{Flags: got[0].eventFlags(), Value: got[0].Code}
+		c.Assert(DataFromEvent(e), qt.DeepEquals, got[0])
+	}
+
+	// unknown vendor
+	ir, r = newTestSender()
+	ir.SendKaseikyo(0x1234, 0xA1, 0x2F5, 2)
+	got := receive(dec, r.pulses)
+	c.Assert(got, qt.HasLen, 1)
+	c.Assert(got[0].Protocol, qt.Equals, ProtocolKaseikyo)
+	c.Assert(got[0].Vendor, qt.Equals, uint16(0x1234))
+	c.Assert(got[0].Address, qt.Equals, uint16(0xA1))
+	c.Assert(got[0].Command, qt.Equals, uint16(2<<10|0x2F5))
+
+	// wrong vendor parity
+	ir, r = newTestSender()
+	ir.SendPanasonic(0x81, 0x00, 0x3D)
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeJVC(t *testing.T) {
+	c := qt.New(t)
+	dec := &jvcDecoder{}
+	ir, r := newTestSender()
+	ir.SendJVC(0x03, 0x17, 2)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x1703, Address: 0x03, Command: 0x17, Protocol: ProtocolJVC},
+		{Code: 0x1703, Address: 0x03, Command: 0x17, Protocol: ProtocolJVC, Flags: DataFlagIsRepeat},
+		{Code: 0x1703, Address: 0x03, Command: 0x17, Protocol: ProtocolJVC, Flags: DataFlagIsRepeat},
+	})
+
+	// repeats are only accepted right after a frame
+	ir, r = newTestSender()
+	ir.SendJVC(0x03, 0x17, 1)
+	c.Assert(receive(&jvcDecoder{}, r.pulses[36:]), qt.HasLen, 0)
+
+	// the last frame ends once the line has been idle long enough
+	dec = &jvcDecoder{}
+	c.Assert(receive(dec, r.pulses[:35]), qt.HasLen, 0)
+	var data Data
+	c.Assert(dec.end(time.Millisecond, &data), qt.IsFalse)
+	c.Assert(dec.end(jvcMinGap, &data), qt.IsTrue)
+	c.Assert(data, qt.DeepEquals, Data{Code: 0x1703, Address: 0x03, Command: 0x17, Protocol: ProtocolJVC})
+
+	// NEC frames start like JVC frames
+	ir, r = newTestSender()
+	ir.SendNEC(0x04, 0x08, 1)
+	c.Assert(receive(&jvcDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeSharp(t *testing.T) {
+	c := qt.New(t)
+	dec := &sharpDecoder{}
+	ir, r := newTestSender()
+	ir.SendSharp(0x11, 0xA5)
+	ir.SendDenon(0x02, 0xE1)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x11 | 0xA5<<5 | 1<<13, Address: 0x11, Command: 0xA5, Protocol: ProtocolSharp},
+		{Code: 0x02 | 0xE1<<5, Address: 0x02, Command: 0xE1, Protocol: ProtocolDenon},
+	})
+
+	// the first frame alone
+	ir, r = newTestSender()
+	ir.SendSharp(0x11, 0xA5)
+	c.Assert(receive(&sharpDecoder{}, r.pulses[:32]), qt.HasLen, 0)
+
+	// the second frame doesn't confirm the first
+	ir, r = newTestSender()
+	ir.SendSharp(0x11, 0xA5)
+	c.Assert(r.pulses[33], qt.Equals, int32(-1680))
+	r.pulses[33] = -680 // flip the first address bit of the second frame
+	c.Assert(receive(&sharpDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeLG(t *testing.T) {
+	c := qt.New(t)
+	dec := &lgDecoder{}
+	ir, r := newTestSender()
+	ir.SendLG(0x88, 0x00C5)
+	ir.SendLG2(0x88, 0x1234)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x88_00C5_1, Address: 0x88, Command: 0x00C5, Protocol: ProtocolLG},
+		{Code: 0x88_1234_A, Address: 0x88, Command: 0x1234, Protocol: ProtocolLG2},
+	})
+
+	// repeat code
+	p := append(r.pulses[:60:60], 9000, -2250, 560, -96190)
+	c.Assert(receive(&lgDecoder{}, p), qt.DeepEquals, []Data{
+		{Code: 0x88_00C5_1, Address: 0x88, Command: 0x00C5, Protocol: ProtocolLG},
+		{Code: 0x88_00C5_1, Address: 0x88, Command: 0x00C5, Protocol: ProtocolLG, Flags: DataFlagIsRepeat},
+	})
+
+	// corrupted checksum
+	ir, r = newTestSender()
+	ir.SendLG(0x88, 0x00C5)
+	c.Assert(r.pulses[57], qt.Equals, int32(-1690))
+	r.pulses[57] = -560
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// NEC frames, even those which start with a valid LG frame, and their
+	// repeat codes
+	ir, r = newTestSender()
+	ir.SendNEC(0x04, lgPrefixCommand(), 2)
+	c.Assert(receive(&lgDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+// lgPrefixCommand returns the command of an NEC frame with address 0x04
+// which starts with a valid LG frame.
+func lgPrefixCommand() uint8 {
+	for command := uint8(0); ; command++ {
+		code := uint64(MakeNEC16Code(0x04, uint16(command)|uint16(^command)<<8))
+		lg := uint32(bits.Reverse64(code) >> 36)
+		if uint8(lg)&0x0F == lgChecksum(uint16(lg>>4)) {
+			return command
+		}
+	}
+}
+
+func TestDecodeSanyo(t *testing.T) {
+	c := qt.New(t)
+	dec := &sanyoDecoder{}
+	ir, r := newTestSender()
+	c.Assert(ir.SendSanyo(0x1ABC, 0x5A), qt.IsNil)
+	c.Assert(ir.SendSanyo(0x1ABC, 0x5A), qt.IsNil)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x5A_1ABC, Address: 0x1ABC, Command: 0x5A, Protocol: ProtocolSanyo},
+		{Code: 0x5A_1ABC, Address: 0x1ABC, Command: 0x5A, Protocol: ProtocolSanyo, Flags: DataFlagIsRepeat},
+	})
+
+	// address not followed by its inverse
+	ir, r = newTestSender()
+	c.Assert(ir.SendSanyo(0x0000, 0x5A), qt.IsNil)
+	c.Assert(r.pulses[3], qt.Equals, int32(-560))
+	r.pulses[3] = -1690
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeMitsubishi(t *testing.T) {
+	c := qt.New(t)
+	dec := &mitsubishiDecoder{}
+	ir, r := newTestSender()
+	ir.SendMitsubishi(0xE2, 0x1F)
+	ir.SendMitsubishi(0xE2, 0x1F)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xE21F, Address: 0xE2, Command: 0x1F, Protocol: ProtocolMitsubishi},
+		{Code: 0xE21F, Address: 0xE2, Command: 0x1F, Protocol: ProtocolMitsubishi, Flags: DataFlagIsRepeat},
+	})
+
+	// the first frame alone
+	ir, r = newTestSender()
+	ir.SendMitsubishi(0xE2, 0x1F)
+	c.Assert(receive(&mitsubishiDecoder{}, r.pulses[:34]), qt.HasLen, 0)
+
+	// the second frame doesn't confirm the first
+	ir, r = newTestSender()
+	ir.SendMitsubishi(0xE2, 0x1F)
+	c.Assert(r.pulses[35], qt.Equals, int32(-2100))
+	r.pulses[35] = -900
+	c.Assert(receive(&mitsubishiDecoder{}, r.pulses), qt.HasLen, 0)
+
+	// frames start after a long space, not in the middle of another frame
+	ir, r = newTestSender()
+	ir.SendMitsubishi(0xE2, 0x1F)
+	c.Assert(receive(&mitsubishiDecoder{}, append([]int32{300, -900}, r.pulses...)), qt.HasLen, 0)
+}
+
+func TestDecodePioneer(t *testing.T) {
+	c := qt.New(t)
+	dec := &pioneerDecoder{}
+	nec := func(address, command uint8) uint32 {
+		return MakeNEC16Code(uint16(address), uint16(command)|uint16(^command)<<8)
+	}
+	ir, r := newTestSender()
+	ir.SendPioneer(nec(0xA5, 0x58), nec(0xAF, 0x24))
+	ir.SendPioneer(nec(0xA5, 0x58), nec(0xAF, 0x24))
+	want := Data{Code: 0x24AF_58A5, Address: 0xAFA5, Command: 0x2458, Protocol: ProtocolPioneer}
+	repeat := want
+	repeat.Flags = DataFlagIsRepeat
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{want, repeat})
+
+	// the first frame alone
+	ir, r = newTestSender()
+	ir.SendPioneer(nec(0xA5, 0x58), nec(0xAF, 0x24))
+	c.Assert(receive(&pioneerDecoder{}, r.pulses[:67]), qt.HasLen, 0)
+
+	// full frame repeats of a NEC remote
+	ir, r = newTestSender()
+	ir.NECRepeat = NECRepeatFullFrame
+	ir.SendNEC(0xA5, 0x58, 3)
+	c.Assert(receive(&pioneerDecoder{}, r.pulses), qt.HasLen, 0)
+
+	// frames too far apart
+	ir, r = newTestSender()
+	ir.SendNEC(0xA5, 0x58, 0)
+	ir.space(necPeriod)
+	ir.SendNEC(0xAF, 0x24, 0)
+	c.Assert(receive(&pioneerDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeRCA(t *testing.T) {
+	c := qt.New(t)
+	dec := &rcaDecoder{}
+	ir, r := newTestSender()
+	ir.SendRCA(0x0F, 0x2E)
+	ir.SendRCA(0x0F, 0x2E)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xF2E, Address: 0x0F, Command: 0x2E, Protocol: ProtocolRCA},
+		{Code: 0xF2E, Address: 0x0F, Command: 0x2E, Protocol: ProtocolRCA, Flags: DataFlagIsRepeat},
+	})
+
+	// marks shortened and spaces lengthened by a 38kHz receiver
+	ir, r = newTestSender()
+	ir.SendRCA(0x05, 0xA1)
+	for i := range r.pulses {
+		r.pulses[i] -= 250
+	}
+	c.Assert(receive(&rcaDecoder{}, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x5A1, Address: 0x05, Command: 0xA1, Protocol: ProtocolRCA},
+	})
+
+	// command not followed by its inverse
+	ir, r = newTestSender()
+	ir.SendRCA(0x05, 0xA1)
+	c.Assert(r.pulses[49], qt.Equals, int32(-1000))
+	r.pulses[49] = -2000
+	c.Assert(receive(&rcaDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeDish(t *testing.T) {
+	c := qt.New(t)
+	dec := &dishDecoder{}
+	ir, r := newTestSender()
+	ir.SendDish(0x9C00)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x9C00, Address: 0x000, Command: 0x27, Protocol: ProtocolDish, Count: 4},
+	})
+
+	// held key
+	ir, r = newTestSender()
+	ir.SendDish(0x9C00)
+	held := append(r.pulses[:len(r.pulses)-1:len(r.pulses)-1], r.pulses[1:]...)
+	c.Assert(receive(&dishDecoder{}, held), qt.DeepEquals, []Data{
+		{Code: 0x9C00, Address: 0x000, Command: 0x27, Protocol: ProtocolDish, Count: 4},
+		{Code: 0x9C00, Address: 0x000, Command: 0x27, Protocol: ProtocolDish, Count: 4, Flags: DataFlagIsRepeat},
+	})
+
+	// a corrupted frame breaks the sequence
+	ir, r = newTestSender()
+	ir.SendDish(0x9C00)
+	c.Assert(r.pulses[3], qt.Equals, int32(-1700))
+	r.pulses[3] = -2800
+	c.Assert(receive(&dishDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeBose(t *testing.T) {
+	c := qt.New(t)
+	dec := &boseDecoder{}
+	ir, r := newTestSender()
+	ir.SendBose(0x4C)
+	ir.SendBose(0x4C)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0xB34C, Command: 0x4C, Protocol: ProtocolBose},
+		{Code: 0xB34C, Command: 0x4C, Protocol: ProtocolBose, Flags: DataFlagIsRepeat},
+	})
+
+	// command not followed by its inverse
+	ir, r = newTestSender()
+	ir.SendBose(0x4C)
+	c.Assert(r.pulses[33], qt.Equals, int32(-1468))
+	r.pulses[33] = -468
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// NEC frames have a longer header
+	ir, r = newTestSender()
+	ir.SendNEC(0x04, 0x4C, 0)
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeMagiQuest(t *testing.T) {
+	c := qt.New(t)
+	dec := &magiQuestDecoder{}
+	ir, r := newTestSender()
+	ir.SendMagiQuest(0x12345678, 0x0101)
+	ir.SendMagiQuest(0x7FFFFFFF, 0x01FF)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x12345678, Command: 0x101, Protocol: ProtocolMagiQuest},
+		{Code: 0x7FFFFFFF, Command: 0x1FF, Protocol: ProtocolMagiQuest},
+	})
+
+	// corrupted checksum
+	ir, r = newTestSender()
+	ir.SendMagiQuest(0x12345678, 0x0101)
+	c.Assert(r.pulses[110], qt.Equals, int32(288))
+	r.pulses[110] = 576
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeRCMM(t *testing.T) {
+	c := qt.New(t)
+	dec := &rcmmDecoder{}
+	ir, r := newTestSender()
+	c.Assert(ir.SendRCMM(0x9A5, 12), qt.IsNil)
+	c.Assert(ir.SendRCMM(0x9A5, 12), qt.IsNil)
+	c.Assert(ir.SendRCMM(0x25A5A5, 24), qt.IsNil)
+	c.Assert(ir.SendRCMM(0x0475A5A5, 32), qt.IsNil)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x9A5, Address: 0x9, Command: 0xA5, Protocol: ProtocolRCMM12},
+		{Code: 0x9A5, Address: 0x9, Command: 0xA5, Protocol: ProtocolRCMM12, Flags: DataFlagIsRepeat},
+		{Code: 0x25A5A5, Address: 0x25, Command: 0xA5A5, Protocol: ProtocolRCMM24},
+		{Code: 0x0475A5A5, Address: 0x0475, Command: 0xA5A5, Protocol: ProtocolRCMM32},
+	})
+
+	// a space too long for any pair
+	ir, r = newTestSender()
+	c.Assert(ir.SendRCMM(0x9A5, 12), qt.IsNil)
+	c.Assert(r.pulses[3], qt.Equals, int32(-611))
+	r.pulses[3] = -900
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// spaces are decoded to the nearest pair
+	c.Assert(rcmmPair(444*time.Microsecond+80*time.Microsecond), qt.Equals, 1)
+	c.Assert(rcmmPair(444*time.Microsecond+90*time.Microsecond), qt.Equals, 2)
+}
+
+func TestDecodeXMP(t *testing.T) {
+	c := qt.New(t)
+	dec := &xmpDecoder{}
+	ir, r := newTestSender()
+	ir.SendXMP(0x1A44_0000_1E44_5600)
+	ir.SendXMP(0x1A44_0000_1E44_5600)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x4400_4456, Address: 0x4400, Command: 0x56, Protocol: ProtocolXMP},
+		{Code: 0x4400_4456, Address: 0x4400, Command: 0x56, Protocol: ProtocolXMP, Flags: DataFlagIsRepeat},
+	})
+
+	// wrong checksum in the second half
+	ir, r = newTestSender()
+	ir.SendXMP(0x1A44_0000_1E44_5600)
+	c.Assert(r.pulses[27], qt.Equals, int32(-760-5*136))
+	r.pulses[27] = -760 - 6*136
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// spaces are decoded to the nearest nibble
+	c.Assert(xmpNibble(760*time.Microsecond-60*time.Microsecond), qt.Equals, 0)
+	c.Assert(xmpNibble(760*time.Microsecond-70*time.Microsecond), qt.Equals, -1)
+	c.Assert(xmpNibble(760*time.Microsecond+15*136*time.Microsecond+60*time.Microsecond), qt.Equals, 15)
+	c.Assert(xmpNibble(760*time.Microsecond+15*136*time.Microsecond+70*time.Microsecond), qt.Equals, -1)
+}
+
+func TestDecodeBangOlufsen(t *testing.T) {
+	c := qt.New(t)
+	dec := &beoDecoder{enabled: true}
+	ir, r := newTestSender()
+	ir.SendBangOlufsen(0x00, 0x0C)
+	ir.SendBangOlufsen(0xA5, 0xF0)
+	c.Assert(receive(dec, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x000C, Command: 0x0C, Protocol: ProtocolBangOlufsen},
+		{Code: 0xA5F0, Address: 0xA5, Command: 0xF0, Protocol: ProtocolBangOlufsen},
+	})
+
+	// wrong start sequence
+	ir, r = newTestSender()
+	ir.SendBangOlufsen(0x00, 0x0C)
+	c.Assert(r.pulses[5], qt.Equals, int32(-4*3125+200))
+	r.pulses[5] = -3*3125 + 200
+	c.Assert(receive(dec, r.pulses), qt.HasLen, 0)
+
+	// disabled
+	ir, r = newTestSender()
+	ir.SendBangOlufsen(0x00, 0x0C)
+	c.Assert(receive(&beoDecoder{}, r.pulses), qt.HasLen, 0)
+}
+
+func TestDecodeNECMSB(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(NECMSBFirst(0x20DF10EF), qt.Equals, uint32(0xF708FB04))
+	c.Assert(NECMSBFirst(0xF708FB04), qt.Equals, uint32(0x20DF10EF))
+
+	ir, r := newTestSender()
+	ir.SendNECMSB(0x20DF10EF, 1)
+	ir.SendNEC(0x1234, 0x08, 0)
+	c.Assert(receive(&necDecoder{msbFirst: true}, r.pulses), qt.DeepEquals, []Data{
+		{Code: 0x20DF10EF, Address: 0x20, Command: 0x10, Protocol: ProtocolNECMSB},
+		{Code: 0x20DF10EF, Address: 0x20, Command: 0x10, Protocol: ProtocolNECMSB, Flags: DataFlagIsRepeat},
+		{Code: 0x2C4810EF, Address: 0x2C48, Command: 0x10, Protocol: ProtocolNECMSB},
+	})
+
+	data := Data{Code: 0x20DF10EF, Address: 0x20, Command: 0x10, Protocol: ProtocolNECMSB}
+	e := events.Event{Flags: data.eventFlags(), Value: data.Code}
+	c.Assert(DataFromEvent(e), qt.DeepEquals, data)
+}
+
+// receiveAll feeds pulses to all decoders like the ReceiverDevice, and
+// returns the decoded data, only the most confident of every frame with
+// autoDetect.
+func receiveAll(pulses []int32, autoDetect bool) []Data {
+	decoders := newDecoders()
+	detector := autoDetector{gap: autoDetectGap}
+	var received []Data
+	var data Data
+	for _, p := range append(append([]int32{-200000}, pulses...), -200000) {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		d := time.Duration(p) * time.Microsecond
+		for _, dec := range decoders {
+			if !dec.pulse(mark, d, &data) {
+				continue
+			}
+			if autoDetect {
+				detector.add(&data)
+			} else {
+				received = append(received, data)
+			}
+		}
+		// after the decoders, which report frames ended by the gap
+		if autoDetect && !mark && detector.flush(d, &data) {
+			received = append(received, data)
+		}
+	}
+	return received
+}
+
+func TestAutoDetect(t *testing.T) {
+	c := qt.New(t)
+	command := lgPrefixCommand()
+	ir, r := newTestSender()
+	ir.SendNEC(0x04, command, 0)
+	ir.SendLG(0x88, 0x00C5)
+	nec := Data{Code: uint32(^command)<<24 | uint32(command)<<16 | 0xFB04, Address: 0x04, Command: uint16(command)}
+	lg := Data{Code: 0x88_00C5_1, Address: 0x88, Command: 0x00C5, Protocol: ProtocolLG}
+
+	// LG frames are prefixes of NEC frames, told apart by the gap after them
+	var protocols []Protocol
+	for _, data := range receiveAll(r.pulses, false) {
+		protocols = append(protocols, data.Protocol)
+	}
+	c.Assert(protocols, qt.DeepEquals, []Protocol{ProtocolNEC, ProtocolLG})
+
+	c.Assert(receiveAll(r.pulses, true), qt.DeepEquals, []Data{nec, lg})
+	c.Assert(nec.Confidence() > lg.Confidence(), qt.IsTrue)
+
+	// full frame repeats of a NEC remote aren't Pioneer commands
+	ir, r = newTestSender()
+	ir.NECRepeat = NECRepeatFullFrame
+	ir.SendNEC(0x04, 0x08, 3)
+	nec = Data{Code: 0xF708FB04, Address: 0x04, Command: 0x08}
+	c.Assert(receiveAll(r.pulses, true), qt.DeepEquals, []Data{nec, nec, nec, nec})
+	pioneer := Data{Code: 0x0804_0804, Protocol: ProtocolPioneer}
+	pioneer.decodePioneer()
+	c.Assert(pioneer.Confidence() < nec.Confidence(), qt.IsTrue)
+
+	// the spaces within B&O frames don't end a frame
+	detector := autoDetector{gap: beoAutoDetectGap}
+	detector.add(&nec)
+	var data Data
+	c.Assert(detector.flush(5*beoUnit, &data), qt.IsFalse)
+	c.Assert(detector.flush(20*time.Millisecond, &data), qt.IsTrue)
+	c.Assert(data, qt.Equals, nec)
+}
@@ -0,0 +1,223 @@
+package irprotocol
+
+import "time"
+
+// PulseDistance describes the frames of a pulse distance protocol: a header
+// mark and space, bits coded as a mark followed by a short space for a 0 or
+// a long space for a 1, and a stop mark.
+//
+// A frame ends with its stop mark, unless Gap is set. Frames that may be the
+// start of the longer frames of another protocol, whose next bit mark would
+// be taken as the stop mark, only end with a space of at least Gap after the
+// stop mark.
+type PulseDistance struct {
+	HeaderMark  time.Duration // 0 if frames have no header
+	HeaderSpace time.Duration
+	BitMark     time.Duration // mark of every bit and of the stop bit
+	OneSpace    time.Duration
+	ZeroSpace   time.Duration
+	Bits        int           // number of bits, up to 64
+	MSBFirst    bool          // bits are sent MSB first, otherwise LSB first
+	Gap         time.Duration // shortest space after a frame, 0 to end frames at their stop mark
+}
+
+// States of DistanceDecoder.
+const (
+	distanceIdle   = iota // waiting for the header mark
+	distanceHeader        // header mark received, expecting its space
+	distanceMark          // expecting the mark of a bit
+	distanceSpace         // expecting the space of a bit
+	distanceStop          // expecting the stop mark
+	distanceGap           // expecting the space after the frame
+)
+
+// DistanceDecoder decodes the frames of a pulse distance protocol described
+// by Timing from the durations of the marks and spaces of a signal.
+//
+// If Bytes is set, frames of any number of whole bytes up to len(Bytes) are
+// received into it instead of Code, longer ones are dropped, and Bits is
+// ignored. The bits of each byte are in the order given by MSBFirst. As the
+// stop mark can't be told from the mark of a next bit, these frames end with
+// a space of at least Gap, which must be set, within the tolerance of Match
+// as protocols of long frames often send exactly that space between them.
+type DistanceDecoder struct {
+	Timing *PulseDistance
+	Bytes  []byte
+
+	state uint8
+	code  uint64        // received bits
+	n     int           // number of received bits
+	idle  time.Duration // space before the frame
+	space time.Duration // last space between frames
+}
+
+// Reset discards a partially received frame.
+func (r *DistanceDecoder) Reset() {
+	r.state = distanceIdle
+}
+
+// Code returns the bits of the last received frame.
+func (r *DistanceDecoder) Code() uint64 {
+	return r.code
+}
+
+// Len returns the number of bits of the last received frame.
+func (r *DistanceDecoder) Len() int {
+	return r.n
+}
+
+// Idle returns the space before the last received frame, which tells a
+// repeated frame of a held key from a new key press.
+func (r *DistanceDecoder) Idle() time.Duration {
+	return r.idle
+}
+
+// Pulse handles a mark or space of duration d. It returns true when the stop
+// mark of a frame, or the gap after it, has been received.
+func (r *DistanceDecoder) Pulse(mark bool, d time.Duration) bool {
+	t := r.Timing
+	switch r.state {
+	case distanceIdle:
+		if !mark {
+			r.space = d
+			return false
+		}
+		r.code = 0
+		r.n = 0
+		r.idle = r.space
+		switch {
+		case t.HeaderMark == 0 && Match(d, t.BitMark):
+			// the frame starts with the mark of the first bit
+			r.state = distanceSpace
+		case t.HeaderMark != 0 && Match(d, t.HeaderMark):
+			r.state = distanceHeader
+		}
+		return false
+	case distanceHeader:
+		if mark || !Match(d, t.HeaderSpace) {
+			break
+		}
+		r.state = distanceMark
+		return false
+	case distanceMark:
+		if !mark || !Match(d, t.BitMark) {
+			break
+		}
+		r.state = distanceSpace
+		return false
+	case distanceSpace:
+		if mark {
+			break
+		}
+		one, zero := Match(d, t.OneSpace), Match(d, t.ZeroSpace)
+		if one && zero {
+			// taken as the closest one
+			one = abs(d-t.OneSpace) < abs(d-t.ZeroSpace)
+		} else if !one && !zero {
+			if !r.ends(d) {
+				break
+			}
+			r.state = distanceIdle
+			r.space = d
+			return true
+		}
+		if r.Bytes != nil {
+			if !r.store(one) {
+				break
+			}
+		} else {
+			var bit uint64
+			if one {
+				bit = 1
+			}
+			if t.MSBFirst {
+				r.code = r.code<<1 | bit
+			} else {
+				r.code |= bit << uint(r.n)
+			}
+		}
+		r.n++
+		r.state = distanceMark
+		if r.Bytes == nil && r.n == t.Bits {
+			r.state = distanceStop
+		}
+		return false
+	case distanceStop:
+		if !mark || !Match(d, t.BitMark) {
+			break
+		}
+		if t.Gap != 0 {
+			r.state = distanceGap
+			return false
+		}
+		r.state = distanceIdle
+		return true
+	case distanceGap:
+		if mark || d < t.Gap {
+			break
+		}
+		r.state = distanceIdle
+		r.space = d
+		return true
+	}
+	// invalid pulse, which may start a new frame
+	r.Reset()
+	if !mark {
+		r.space = d
+		return false
+	}
+	return r.Pulse(mark, d)
+}
+
+// End handles the line having been idle for d after the last mark. The gap
+// after a frame is only measured once the next one starts, so the last frame
+// of a protocol with a Gap ends here instead. Like Pulse, it returns true
+// when this ends a frame. Once the space ends, Pulse takes it as the space
+// before the next frame.
+func (r *DistanceDecoder) End(d time.Duration) bool {
+	if r.state == distanceSpace && r.ends(d) {
+		return r.Pulse(false, d)
+	}
+	if r.state != distanceGap || d < r.Timing.Gap {
+		return false
+	}
+	r.state = distanceIdle
+	r.space = d
+	return true
+}
+
+// ends returns whether the space d after the mark of the last bit received
+// into Bytes is the gap after the frame, which needs whole bytes.
+func (r *DistanceDecoder) ends(d time.Duration) bool {
+	if r.Bytes == nil || r.n == 0 || r.n%8 != 0 {
+		return false
+	}
+	gap := r.Timing.Gap
+	return d >= gap || Match(d, gap)
+}
+
+// store adds a bit to Bytes, and returns false if they are full.
+func (r *DistanceDecoder) store(one bool) bool {
+	i, j := r.n/8, uint(r.n%8)
+	if i == len(r.Bytes) {
+		return false
+	}
+	if j == 0 {
+		r.Bytes[i] = 0
+	}
+	if r.Timing.MSBFirst {
+		j = 7 - j
+	}
+	if one {
+		r.Bytes[i] |= 1 << j
+	}
+	return true
+}
+
+// Match returns whether d matches the duration want, with a tolerance for
+// the distortion of IR receivers, which lengthen or shorten marks by up to
+// 100µs or so.
+func Match(d, want time.Duration) bool {
+	tolerance := want/4 + 100*time.Microsecond
+	return d > want-tolerance && d < want+tolerance
+}
@@ -0,0 +1,166 @@
+package irprotocol
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// distancePulses returns the marks (positive) and spaces (negative) of a
+// frame in µs.
+func distancePulses(t *PulseDistance, code uint64) []int32 {
+	us := func(d time.Duration) int32 { return int32(d / time.Microsecond) }
+	var p []int32
+	if t.HeaderMark != 0 {
+		p = append(p, us(t.HeaderMark), -us(t.HeaderSpace))
+	}
+	for i := 0; i < t.Bits; i++ {
+		bit := code >> uint(i) & 1
+		if t.MSBFirst {
+			bit = code >> uint(t.Bits-1-i) & 1
+		}
+		space := t.ZeroSpace
+		if bit != 0 {
+			space = t.OneSpace
+		}
+		p = append(p, us(t.BitMark), -us(space))
+	}
+	return append(p, us(t.BitMark), -20000)
+}
+
+// decodeDistance feeds pulses to a DistanceDecoder and returns the codes of
+// the received frames.
+func decodeDistance(t *PulseDistance, pulses []int32) []uint64 {
+	r := DistanceDecoder{Timing: t}
+	var codes []uint64
+	for _, p := range append([]int32{-100000}, pulses...) {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		if r.Pulse(mark, time.Duration(p)*time.Microsecond) {
+			codes = append(codes, r.Code())
+		}
+	}
+	return codes
+}
+
+func TestDistanceDecoder(t *testing.T) {
+	c := qt.New(t)
+	nec := PulseDistance{
+		HeaderMark: 9000 * time.Microsecond, HeaderSpace: 4500 * time.Microsecond,
+		BitMark: 560 * time.Microsecond, OneSpace: 1690 * time.Microsecond, ZeroSpace: 560 * time.Microsecond,
+		Bits: 32,
+	}
+	p := append(distancePulses(&nec, 0xF708FB04), distancePulses(&nec, 0xE31CFF00)...)
+	c.Assert(decodeDistance(&nec, p), qt.DeepEquals, []uint64{0xF708FB04, 0xE31CFF00})
+
+	// an invalid space restarts at the next header
+	p = distancePulses(&nec, 0xF708FB04)
+	p[9] = -1100
+	c.Assert(decodeDistance(&nec, append(p, distancePulses(&nec, 0xE31CFF00)...)), qt.DeepEquals, []uint64{0xE31CFF00})
+
+	// headerless, MSB first
+	sharp := PulseDistance{
+		BitMark: 320 * time.Microsecond, OneSpace: 1680 * time.Microsecond, ZeroSpace: 680 * time.Microsecond,
+		Bits: 15, MSBFirst: true,
+	}
+	c.Assert(decodeDistance(&sharp, distancePulses(&sharp, 0x5123)), qt.DeepEquals, []uint64{0x5123})
+}
+
+func TestMatch(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(Match(560*time.Microsecond, 560*time.Microsecond), qt.IsTrue)
+	// within a quarter plus 100µs
+	c.Assert(Match(799*time.Microsecond, 560*time.Microsecond), qt.IsTrue)
+	c.Assert(Match(321*time.Microsecond, 560*time.Microsecond), qt.IsTrue)
+	c.Assert(Match(800*time.Microsecond, 560*time.Microsecond), qt.IsFalse)
+	c.Assert(Match(320*time.Microsecond, 560*time.Microsecond), qt.IsFalse)
+}
+
+func TestDistanceDecoderTolerance(t *testing.T) {
+	c := qt.New(t)
+	// LEGO Power Functions, whose spaces match both bits from 315µs to 428µs
+	pf := PulseDistance{
+		HeaderMark: 158 * time.Microsecond, HeaderSpace: 1026 * time.Microsecond,
+		BitMark: 158 * time.Microsecond, OneSpace: 553 * time.Microsecond, ZeroSpace: 263 * time.Microsecond,
+		Bits: 16, MSBFirst: true,
+	}
+	p := distancePulses(&pf, 0x1234)
+	for i := 3; i < len(p)-2; i += 2 {
+		// spaces stretched by the receiver
+		p[i] -= 60
+	}
+	c.Assert(decodeDistance(&pf, p), qt.DeepEquals, []uint64{0x1234})
+}
+
+func TestDistanceDecoderGap(t *testing.T) {
+	c := qt.New(t)
+	nec := PulseDistance{
+		HeaderMark: 9000 * time.Microsecond, HeaderSpace: 4500 * time.Microsecond,
+		BitMark: 560 * time.Microsecond, OneSpace: 1690 * time.Microsecond, ZeroSpace: 560 * time.Microsecond,
+		Bits: 32,
+	}
+	lg := nec
+	lg.Bits = 28
+	lg.MSBFirst = true
+	lg.Gap = 5 * time.Millisecond
+	c.Assert(decodeDistance(&lg, distancePulses(&lg, 0x88_00C5_1)), qt.DeepEquals, []uint64{0x88_00C5_1})
+	// the 29th bit of a longer frame isn't a stop mark
+	c.Assert(decodeDistance(&lg, distancePulses(&nec, 0xF708FB04)), qt.HasLen, 0)
+
+	// the last frame ends while the line is idle
+	p := distancePulses(&lg, 0x88_00C5_1)
+	r := DistanceDecoder{Timing: &lg}
+	for _, p := range p[:len(p)-1] {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		c.Assert(r.Pulse(mark, time.Duration(p)*time.Microsecond), qt.IsFalse)
+	}
+	c.Assert(r.End(time.Millisecond), qt.IsFalse)
+	c.Assert(r.End(lg.Gap), qt.IsTrue)
+	c.Assert(r.Code(), qt.Equals, uint64(0x88_00C5_1))
+	c.Assert(r.End(time.Second), qt.IsFalse)
+	c.Assert(r.Pulse(false, 20*time.Millisecond), qt.IsFalse)
+}
+
+func TestDistanceDecoderBytes(t *testing.T) {
+	c := qt.New(t)
+	timing := PulseDistance{
+		HeaderMark: 3500 * time.Microsecond, HeaderSpace: 1700 * time.Microsecond,
+		BitMark: 430 * time.Microsecond, OneSpace: 1300 * time.Microsecond, ZeroSpace: 430 * time.Microsecond,
+		Bits: 24, Gap: 20 * time.Millisecond,
+	}
+	r := DistanceDecoder{Timing: &timing, Bytes: make([]byte, 3)}
+	pulse := func(p []int32) (n []int) {
+		for _, p := range p {
+			mark := p > 0
+			if p < 0 {
+				p = -p
+			}
+			if r.Pulse(mark, time.Duration(p)*time.Microsecond) {
+				n = append(n, r.Len())
+			}
+		}
+		return n
+	}
+	// frames of 3 and 2 bytes
+	c.Assert(pulse(distancePulses(&timing, 0xC5DA11)), qt.DeepEquals, []int{24})
+	c.Assert(r.Bytes, qt.DeepEquals, []byte{0x11, 0xDA, 0xC5})
+	timing.Bits = 16
+	p := distancePulses(&timing, 0x27DA)
+	c.Assert(pulse(p[:len(p)-1]), qt.HasLen, 0)
+	c.Assert(r.End(timing.OneSpace), qt.IsFalse)
+	c.Assert(r.End(19900*time.Microsecond), qt.IsTrue) // slightly short gap
+	c.Assert(r.Len(), qt.Equals, 16)
+	c.Assert(r.Bytes[:2], qt.DeepEquals, []byte{0xDA, 0x27})
+
+	// frames longer than Bytes are dropped, and so are partial bytes
+	timing.Bits = 32
+	c.Assert(pulse(distancePulses(&timing, 0x01020304)), qt.HasLen, 0)
+	timing.Bits = 12
+	c.Assert(pulse(distancePulses(&timing, 0x123)), qt.HasLen, 0)
+}
@@ -0,0 +1,95 @@
+// Package irprotocol provides the building blocks of IR protocols, shared by
+// the irremote package and available for custom protocols.
+package irprotocol // import "tinygo.org/x/drivers/irremote/irprotocol"
+
+import "time"
+
+// EncodeManchester encodes the nbits low bits of data MSB first as
+// Manchester (bi-phase) symbols of two half bits of length half, calling
+// send for every half bit. With markFirst, a 1 is a mark followed by a
+// space, like RC-6, otherwise a space followed by a mark, like RC-5.
+func EncodeManchester(data uint64, nbits int, half time.Duration, markFirst bool, send func(mark bool, d time.Duration)) {
+	for i := nbits - 1; i >= 0; i-- {
+		first := data>>uint(i)&1 != 0 == markFirst
+		send(first, half)
+		send(!first, half)
+	}
+}
+
+// ManchesterDecoder decodes Manchester (bi-phase) coded bits from the
+// durations of the marks and spaces of a signal, each lasting one or two
+// half bits.
+//
+// The line is idle between frames, so a frame starting with a space half
+// bit or ending with a space half bit, like the start bit and a trailing 0
+// of RC-5, has it merged with the idle line. Add it with AddHalves.
+type ManchesterDecoder struct {
+	Half      time.Duration // length of a half bit
+	MarkFirst bool          // a 1 is a mark followed by a space, see EncodeManchester
+
+	halves uint64 // received half bits, 1 for a mark, latest in bit 0
+	n      int    // number of received half bits
+}
+
+// Reset discards the received half bits.
+func (m *ManchesterDecoder) Reset() {
+	m.n = 0
+}
+
+// Len returns the number of received half bits.
+func (m *ManchesterDecoder) Len() int {
+	return m.n
+}
+
+// AddHalves adds k half bits of the given level.
+func (m *ManchesterDecoder) AddHalves(mark bool, k int) {
+	for ; k > 0; k-- {
+		m.halves <<= 1
+		if mark {
+			m.halves |= 1
+		}
+		m.n++
+	}
+}
+
+// Add adds a mark or space of duration d. It returns false, without adding
+// anything, if d isn't 1 or 2 half bits long.
+func (m *ManchesterDecoder) Add(mark bool, d time.Duration) bool {
+	k := HalfBits(d, m.Half)
+	if k == 0 {
+		return false
+	}
+	m.AddHalves(mark, k)
+	return true
+}
+
+// Decode returns the bits of the received half bits, the first one in the
+// MSB, up to 32 of them. It returns false if there is an odd number of half
+// bits, or if two halves of a bit have the same level.
+func (m *ManchesterDecoder) Decode() (data uint32, ok bool) {
+	if m.n%2 != 0 || m.n > 64 {
+		return 0, false
+	}
+	for i := m.n/2 - 1; i >= 0; i-- {
+		h := m.halves >> uint(2*i) & 3
+		if h != 1 && h != 2 {
+			return 0, false
+		}
+		bit := uint32(h & 1)
+		if m.MarkFirst {
+			bit ^= 1
+		}
+		data = data<<1 | bit
+	}
+	return data, true
+}
+
+// HalfBits returns the number of half bits of length half in d, rounded,
+// or 0 if d is not 1 or 2 half bits long.
+func HalfBits(d, half time.Duration) int {
+	n := int((d + half/2) / half)
+	if n > 2 {
+		return 0
+	}
+	return n
+}
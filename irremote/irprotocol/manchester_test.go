@@ -0,0 +1,57 @@
+package irprotocol
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestManchester(t *testing.T) {
+	c := qt.New(t)
+	const half = 500 * time.Microsecond
+	for _, markFirst := range []bool{false, true} {
+		// encode, merging half bits of the same level like the IR signal
+		var pulses []time.Duration
+		var marks []bool
+		EncodeManchester(0b1100_1011, 8, half, markFirst, func(mark bool, d time.Duration) {
+			if n := len(pulses); n > 0 && marks[n-1] == mark {
+				pulses[n-1] += d
+				return
+			}
+			pulses = append(pulses, d)
+			marks = append(marks, mark)
+		})
+		c.Assert(marks[0], qt.Equals, markFirst)
+
+		m := ManchesterDecoder{Half: half, MarkFirst: markFirst}
+		for i, d := range pulses {
+			// receivers lengthen marks
+			if marks[i] {
+				d += 100 * time.Microsecond
+			} else {
+				d -= 100 * time.Microsecond
+			}
+			c.Assert(m.Add(marks[i], d), qt.IsTrue)
+		}
+		c.Assert(m.Len(), qt.Equals, 16)
+		data, ok := m.Decode()
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(data, qt.Equals, uint32(0b1100_1011))
+	}
+
+	m := ManchesterDecoder{Half: 500 * time.Microsecond}
+	c.Assert(m.Add(true, 1600*time.Microsecond), qt.IsFalse)
+	c.Assert(m.Len(), qt.Equals, 0)
+
+	// two halves of a bit at the same level
+	m.AddHalves(true, 2)
+	_, ok := m.Decode()
+	c.Assert(ok, qt.IsFalse)
+
+	// odd number of halves
+	m.Reset()
+	m.AddHalves(true, 1)
+	_, ok = m.Decode()
+	c.Assert(ok, qt.IsFalse)
+}
@@ -0,0 +1,194 @@
+package irprotocol
+
+import "time"
+
+// PulseWidth describes the frames of a pulse width protocol: a header mark
+// and space, and bits coded as a long mark for a 1 or a short mark for a 0,
+// each followed by a space. The space after the last bit merges with the
+// space after the frame, so frames end with the mark of their last bit.
+//
+// Protocols with frames of several lengths set Lengths instead of Bits. As
+// the length of a frame is only known once no other bit follows, their
+// frames end with a space of at least Gap after the mark of their last bit.
+type PulseWidth struct {
+	HeaderMark  time.Duration // 0 if frames have no header
+	HeaderSpace time.Duration
+	OneMark     time.Duration
+	OneSpace    time.Duration
+	ZeroMark    time.Duration
+	ZeroSpace   time.Duration
+	Bits        int           // number of bits, up to 64
+	MSBFirst    bool          // bits are sent MSB first, otherwise LSB first
+	Lengths     []int         // numbers of bits of frames of variable length, instead of Bits
+	Gap         time.Duration // shortest space after frames of variable length
+}
+
+// whole returns whether n bits make a whole frame of variable length.
+func (t *PulseWidth) whole(n int) bool {
+	for _, bits := range t.Lengths {
+		if n == bits {
+			return true
+		}
+	}
+	return false
+}
+
+// States of WidthDecoder.
+const (
+	widthIdle   = iota // waiting for the header mark
+	widthHeader        // header mark received, expecting its space
+	widthMark          // expecting the mark of a bit
+	widthSpace         // expecting the space of a bit
+)
+
+// WidthDecoder decodes the frames of a pulse width protocol described by
+// Timing from the durations of the marks and spaces of a signal.
+type WidthDecoder struct {
+	Timing *PulseWidth
+
+	state uint8
+	code  uint64        // received bits
+	n     int           // number of received bits
+	bit   bool          // value of the bit being received
+	idle  time.Duration // space before the frame
+	space time.Duration // last space between frames
+}
+
+// Reset discards a partially received frame.
+func (r *WidthDecoder) Reset() {
+	r.state = widthIdle
+}
+
+// Code returns the bits of the last received frame, or those received so far
+// of the current one.
+func (r *WidthDecoder) Code() uint64 {
+	return r.code
+}
+
+// Len returns the number of bits of the last received frame, or of those
+// received so far of the current one.
+func (r *WidthDecoder) Len() int {
+	return r.n
+}
+
+// Idle returns the space before the last received frame, or before the
+// current one, which tells a repeated frame of a held key from a new key
+// press.
+func (r *WidthDecoder) Idle() time.Duration {
+	return r.idle
+}
+
+// Pulse handles a mark or space of duration d. It returns true when the mark
+// of the last bit of a frame, or the gap after a frame of variable length,
+// has been received.
+func (r *WidthDecoder) Pulse(mark bool, d time.Duration) bool {
+	t := r.Timing
+	switch r.state {
+	case widthIdle:
+		if !mark {
+			r.space = d
+			return false
+		}
+		r.code = 0
+		r.n = 0
+		r.idle = r.space
+		if t.HeaderMark != 0 {
+			if Match(d, t.HeaderMark) {
+				r.state = widthHeader
+			}
+			return false
+		}
+		// the frame starts with the mark of the first bit
+		r.state = widthMark
+		if !r.mark(d) {
+			r.state = widthIdle
+			return false
+		}
+		return r.state == widthIdle
+	case widthHeader:
+		if mark || !Match(d, t.HeaderSpace) {
+			break
+		}
+		r.state = widthMark
+		return false
+	case widthMark:
+		if !mark || !r.mark(d) {
+			break
+		}
+		return r.state == widthIdle
+	case widthSpace:
+		if mark {
+			break
+		}
+		want := t.ZeroSpace
+		if r.bit {
+			want = t.OneSpace
+		}
+		if Match(d, want) {
+			r.state = widthMark
+			return false
+		}
+		if t.Lengths == nil || d < t.Gap || !t.whole(r.n) {
+			break
+		}
+		r.state = widthIdle
+		r.space = d
+		return true
+	}
+	// invalid pulse, which may start a new frame
+	r.Reset()
+	if !mark {
+		r.space = d
+		return false
+	}
+	return r.Pulse(mark, d)
+}
+
+// End handles the line having been idle for d after the last mark. The gap
+// after a frame of variable length is only measured once the next one
+// starts, so the last one ends here instead. Like Pulse, it returns true
+// when this ends a frame.
+func (r *WidthDecoder) End(d time.Duration) bool {
+	if r.state != widthSpace || r.Timing.Lengths == nil || d < r.Timing.Gap {
+		return false
+	}
+	return r.Pulse(false, d)
+}
+
+// mark adds the bit coded by the mark d, and returns false if d matches
+// neither bit. A mark matching both is taken as the closest one.
+func (r *WidthDecoder) mark(d time.Duration) bool {
+	t := r.Timing
+	one, zero := Match(d, t.OneMark), Match(d, t.ZeroMark)
+	if one && zero {
+		one = abs(d-t.OneMark) < abs(d-t.ZeroMark)
+	} else if !one && !zero {
+		return false
+	}
+	if r.n == 64 {
+		return false
+	}
+	var bit uint64
+	if one {
+		bit = 1
+	}
+	if t.MSBFirst {
+		r.code = r.code<<1 | bit
+	} else {
+		r.code |= bit << uint(r.n)
+	}
+	r.n++
+	r.bit = one
+	r.state = widthSpace
+	if t.Lengths == nil && r.n == t.Bits {
+		r.state = widthIdle
+	}
+	return true
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,111 @@
+package irprotocol
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// widthPulses returns the marks (positive) and spaces (negative) of a frame
+// in µs, with marks lengthened by stretch µs.
+func widthPulses(t *PulseWidth, code uint64, stretch int32) []int32 {
+	us := func(d time.Duration) int32 { return int32(d / time.Microsecond) }
+	var p []int32
+	if t.HeaderMark != 0 {
+		p = append(p, us(t.HeaderMark)+stretch, -us(t.HeaderSpace)+stretch)
+	}
+	for i := 0; i < t.Bits; i++ {
+		bit := code >> uint(i) & 1
+		if t.MSBFirst {
+			bit = code >> uint(t.Bits-1-i) & 1
+		}
+		if bit != 0 {
+			p = append(p, us(t.OneMark)+stretch, -us(t.OneSpace)+stretch)
+		} else {
+			p = append(p, us(t.ZeroMark)+stretch, -us(t.ZeroSpace)+stretch)
+		}
+	}
+	p[len(p)-1] = -40000
+	return p
+}
+
+// decodeWidth feeds pulses to a WidthDecoder and returns the codes of the
+// received frames.
+func decodeWidth(t *PulseWidth, pulses []int32) []uint64 {
+	r := WidthDecoder{Timing: t}
+	var codes []uint64
+	for _, p := range append([]int32{-100000}, pulses...) {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		if r.Pulse(mark, time.Duration(p)*time.Microsecond) {
+			codes = append(codes, r.Code())
+		}
+	}
+	return codes
+}
+
+func TestWidthDecoder(t *testing.T) {
+	c := qt.New(t)
+	sirc := PulseWidth{
+		HeaderMark: 2400 * time.Microsecond, HeaderSpace: 600 * time.Microsecond,
+		OneMark: 1200 * time.Microsecond, OneSpace: 600 * time.Microsecond,
+		ZeroMark: 600 * time.Microsecond, ZeroSpace: 600 * time.Microsecond,
+		Bits: 12,
+	}
+	p := append(widthPulses(&sirc, 0x095, 0), widthPulses(&sirc, 0xA95, 100)...)
+	c.Assert(decodeWidth(&sirc, p), qt.DeepEquals, []uint64{0x095, 0xA95})
+
+	// a mark matching neither bit restarts at the next header
+	p = widthPulses(&sirc, 0x095, 0)
+	p[6] = 1800
+	c.Assert(decodeWidth(&sirc, append(p, widthPulses(&sirc, 0xA95, 0)...)), qt.DeepEquals, []uint64{0xA95})
+
+	// headerless, MSB first, with marks close enough to match both bits
+	magiQuest := PulseWidth{
+		OneMark: 576 * time.Microsecond, OneSpace: 576 * time.Microsecond,
+		ZeroMark: 288 * time.Microsecond, ZeroSpace: 864 * time.Microsecond,
+		Bits: 16, MSBFirst: true,
+	}
+	c.Assert(decodeWidth(&magiQuest, widthPulses(&magiQuest, 0x00A5, 100)), qt.DeepEquals, []uint64{0x00A5})
+	c.Assert(decodeWidth(&magiQuest, widthPulses(&magiQuest, 0x00A5, -100)), qt.DeepEquals, []uint64{0x00A5})
+}
+
+func TestWidthDecoderLengths(t *testing.T) {
+	c := qt.New(t)
+	sirc := PulseWidth{
+		HeaderMark: 2400 * time.Microsecond, HeaderSpace: 600 * time.Microsecond,
+		OneMark: 1200 * time.Microsecond, OneSpace: 600 * time.Microsecond,
+		ZeroMark: 600 * time.Microsecond, ZeroSpace: 600 * time.Microsecond,
+		Lengths: []int{12, 15, 20}, Gap: 1200 * time.Microsecond,
+	}
+	frame := func(code uint64, bits int) []int32 {
+		t := sirc
+		t.Bits = bits
+		return widthPulses(&t, code, 0)
+	}
+	p := append(frame(0x095, 12), frame(0x7A95, 15)...)
+	p = append(p, frame(0x12345, 20)...)
+	c.Assert(decodeWidth(&sirc, p), qt.DeepEquals, []uint64{0x095, 0x7A95, 0x12345})
+
+	// frames of other lengths
+	c.Assert(decodeWidth(&sirc, append(frame(0x095, 13), frame(0x095, 21)...)), qt.HasLen, 0)
+
+	// the last frame ends while the line is idle
+	p = frame(0x7A95, 15)
+	r := WidthDecoder{Timing: &sirc}
+	for _, p := range p[:len(p)-1] {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		c.Assert(r.Pulse(mark, time.Duration(p)*time.Microsecond), qt.IsFalse)
+	}
+	c.Assert(r.End(time.Millisecond), qt.IsFalse)
+	c.Assert(r.End(sirc.Gap), qt.IsTrue)
+	c.Assert(r.Code(), qt.Equals, uint64(0x7A95))
+	c.Assert(r.Len(), qt.Equals, 15)
+	c.Assert(r.End(time.Second), qt.IsFalse)
+}
@@ -0,0 +1,106 @@
+package irremote
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// pfMessages decodes the messages of a LEGO Power Functions transmission
+// and checks the timing between them.
+func pfMessages(c *qt.C, r *recorder, channel int32) []uint16 {
+	var msgs []uint16
+	p := r.pulses
+	for i := 0; i < 5; i++ {
+		c.Assert(p[:2], qt.DeepEquals, []int32{158, -1026})
+		msg := uint16(pulseBits(c, p[2:], 16, false, false, 400))
+		var length int32
+		for _, d := range p[:35] {
+			if d < 0 {
+				d = -d
+			}
+			length += d
+		}
+		msgs = append(msgs, msg)
+		c.Assert(p[34], qt.Equals, int32(158))
+		if i == 4 {
+			c.Assert(p[35:], qt.DeepEquals, []int32{-1026})
+			break
+		}
+		// start to start
+		period := int32(5 * 16000)
+		if i >= 2 {
+			period = (6 + 2*(channel+1)) * 16000
+		}
+		c.Assert(length-p[35], qt.Equals, period)
+		p = p[36:]
+	}
+	return msgs
+}
+
+func TestSendPF(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendPFComboDirect(0, PFDirectForward, PFDirectBackward)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	msgs := pfMessages(c, r, 0)
+	// toggle 0, channel 0, mode 1, B backward, A forward, LRC
+	c.Assert(msgs, qt.DeepEquals, []uint16{0x0197, 0x0197, 0x0197, 0x0197, 0x0197})
+
+	// the toggle bit changes with every message of a channel
+	r.pulses = nil
+	ir.SendPFComboDirect(0, PFDirectFloat, PFDirectFloat)
+	c.Assert(pfMessages(c, r, 0)[0], qt.Equals, uint16(0x8106))
+
+	r.pulses = nil
+	ir.SendPFSingleOutput(2, PFOutputB, PFSpeed(-1))
+	// single output mode, output B, one step backward
+	c.Assert(pfMessages(c, r, 2)[0], qt.Equals, uint16(0x25F7))
+
+	r.pulses = nil
+	ir.SendPFComboPWM(3, PFSpeed(7), PFBrake)
+	// escape bit set, B brake, A full forward
+	c.Assert(pfMessages(c, r, 3)[0], qt.Equals, uint16(0x7877))
+}
+
+func TestPFSpeed(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(PFSpeed(0), qt.Equals, PFFloat)
+	c.Assert(PFSpeed(1), qt.Equals, PFStep(1))
+	c.Assert(PFSpeed(9), qt.Equals, PFStep(7))
+	c.Assert(PFSpeed(-1), qt.Equals, PFStep(15))
+	c.Assert(PFSpeed(-7), qt.Equals, PFStep(9))
+}
+
+func TestDecodeLegoPF(t *testing.T) {
+	c := qt.New(t)
+	dec := &legoPFDecoder{}
+	ir, r := newTestSender()
+	ir.SendPFSingleOutput(1, PFOutputB, PFSpeed(-2))
+	got := receive(dec, r.pulses)
+	c.Assert(got, qt.HasLen, 5)
+	c.Assert(got[0], qt.DeepEquals, Data{
+		Code: 0x15E5, Address: 1, Command: 0x05E, Protocol: ProtocolLegoPF,
+	})
+	for _, data := range got[1:] {
+		c.Assert(data.Flags, qt.Equals, DataFlagIsRepeat)
+		c.Assert(data.Code, qt.Equals, got[0].Code)
+	}
+
+	// the next message toggles
+	ir, r = newTestSender()
+	ir.SendPFSingleOutput(1, PFOutputB, PFSpeed(-2))
+	ir.SendPFComboPWM(1, PFSpeed(3), PFBrake)
+	got = receive(&legoPFDecoder{}, r.pulses)
+	c.Assert(got, qt.HasLen, 10)
+	c.Assert(got[5], qt.DeepEquals, Data{
+		Code: 0xD839, Address: 1, Command: 0x183, Flags: DataFlagToggle, Protocol: ProtocolLegoPF,
+	})
+
+	// corrupted LRC
+	ir, r = newTestSender()
+	ir.SendPFSingleOutput(1, PFOutputB, PFSpeed(-2))
+	c.Assert(r.pulses[33], qt.Equals, int32(-553))
+	r.pulses[33] = -263
+	c.Assert(receive(&legoPFDecoder{}, r.pulses[:35]), qt.HasLen, 0)
+}
@@ -1,65 +1,65 @@
+//go:build tinygo
+
 package irremote // import "tinygo.org/x/drivers/irremote"
 
 import (
 	"machine"
+	"runtime/interrupt"
 	"time"
-)
-
-// NEC protocol references
-// https://www.sbprojects.net/knowledge/ir/nec.php
-// https://techdocs.altium.com/display/FPGA/NEC+Infrared+Transmission+Protocol
-// https://simple-circuit.com/arduino-nec-remote-control-decoder/
-
-// Data encapsulates the data received by the ReceiverDevice.
-type Data struct {
-	// Code is the raw IR data received.
-	Code uint32
-	// Address is the decoded address from the IR data received.
-	Address uint16
-	// Command is the decoded command from the IR data recieved
-	Command uint16
-	// Flags provides additional information about the IR data received. See DataFlags
-	Flags DataFlags
-}
-
-// DataFlags provides bitwise flags representing various information about recieved IR data.
-type DataFlags uint16
-
-// Valid values for DataFlags
-const (
-	// DataFlagIsRepeat set indicates that the IR data is a repeat commmand
-	DataFlagIsRepeat DataFlags = 1 << iota
-)
-
-// CommandHandler defines the callback function used to provide IR data received by the ReceiverDevice.
-type CommandHandler func(data Data)
-
-// nec_ir_state represents the various internal states used to decode the NEC IR protocol commands
-type nec_ir_state uint8
 
-// Valid values for nec_ir_state
-const (
-	lead_pulse_start nec_ir_state = iota // Start receiving IR data, beginning of 9ms lead pulse
-	lead_space_start                     // End of 9ms lead pulse, start of 4.5ms space
-	lead_space_end                       // End of 4.5ms space, start of 562µs pulse
-	bit_read_start                       // End of 562µs pulse, start of 562µs or 1687µs space
-	bit_read_end                         // End of 562µs or 1687µs space
-	trail_pulse_end                      // End of 562µs trailing pulse
+	"tinygo.org/x/drivers/events"
 )
 
 // ReceiverDevice is the device for receiving IR commands
 type ReceiverDevice struct {
+	// BangOlufsen enables decoding Bang & Olufsen Datalink 80 frames. B&O
+	// equipment uses a 455kHz carrier, which the usual 38kHz receivers don't
+	// pass, so this requires a 455kHz receiver like the TSOP7000. The frames
+	// last up to 200ms with spaces of up to 15.6ms between marks, longer than
+	// those after which the decoders of other protocols give up on a frame,
+	// so this also widens the space after which AutoDetect takes a frame as
+	// ended from 10ms to 18.75ms. Set it before SetCommandHandler or
+	// SetDispatcher.
+	BangOlufsen bool
+
+	// NECLenient accepts NEC frames where the fourth byte isn't the inverse
+	// of the command, as sent by some cheap remotes. Their Command is the
+	// 16-bit value of the last 2 bytes, use NECBytes to split the Code.
+	// Set it before SetCommandHandler or SetDispatcher.
+	NECLenient bool
+
+	// NECMSBFirst reports NEC frames as ProtocolNECMSB, with their Code,
+	// Address and Command in MSB first order, to match the documentation of
+	// vendors like LG. Set it before SetCommandHandler or SetDispatcher.
+	NECMSBFirst bool
+
+	// AutoDetect reports only the most confident decoding of every frame,
+	// see Data.Confidence, instead of those of all decoders that accept it,
+	// as a decoder may mistake a frame of another protocol for one of its
+	// own. A frame is reported once the next one starts, or from Poll once
+	// the line has been idle long enough, so call Poll regularly from the
+	// main loop. Set it before SetCommandHandler or SetDispatcher.
+	AutoDetect bool
+
 	pin      machine.Pin    // IR input pin.
 	ch       CommandHandler // client callback function
-	necState nec_ir_state   // internal state machine
+	decoders []decoder      // decoders of the supported protocols
 	data     Data           // decoded data for client
-	lastTime time.Time      // used to track states
-	bitIndex int            // tracks which bit (0-31) of necCode is being read
+	lastTime time.Time      // used to measure pulses
+	detector autoDetector   // best decoding of the frame with AutoDetect
+	polled   Data           // decoded data reported by Poll
+	ended    []Data         // frames ended by Poll, at most one per decoder
+	sh       StateHandler   // client callback function of state frames
+	states   StateDecoder   // decoder of state frames for sh
+
+	dispatcher *events.Dispatcher // optional, receives IRCommand events
+	source     uint8              // Source of posted events
 }
 
 // NewReceiver returns a new IR receiver device
 func NewReceiver(pin machine.Pin) ReceiverDevice {
-	return ReceiverDevice{pin: pin}
+	decoders := newDecoders()
+	return ReceiverDevice{pin: pin, decoders: decoders, ended: make([]Data, len(decoders))}
 }
 
 // Configure configures the input pin for the IR receiver device
@@ -68,11 +68,49 @@ func (ir *ReceiverDevice) Configure() {
 	ir.pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
 }
 
-// SetCommandHandler is used to start or stop receiving IR commands via a callback function (pass nil to stop)
+// SetCommandHandler is used to start or stop receiving IR commands via a callback function (pass nil to stop).
+// The callback is invoked from interrupt context, use SetDispatcher to handle commands in the main loop instead.
 func (ir *ReceiverDevice) SetCommandHandler(ch CommandHandler) {
 	ir.ch = ch
-	ir.resetStateMachine()
-	if ch != nil {
+	ir.listen()
+}
+
+// SetDispatcher is used to start or stop posting received IR commands as events.IRCommand events (pass nil to stop).
+// The event Value holds Data.Code and Flags holds Data.Flags and Data.Protocol, use DataFromEvent to decode them.
+func (ir *ReceiverDevice) SetDispatcher(d *events.Dispatcher, source uint8) {
+	ir.dispatcher = d
+	ir.source = source
+	ir.listen()
+}
+
+// SetStateHandler is used to start or stop receiving the long state frames of an air conditioner protocol with the
+// timing t via a callback function (pass nil to stop), see SendState. Frames of up to len(state) bytes are received
+// into state. A frame is reported once the next one starts, or from Poll once the line has been idle long enough, so
+// call it regularly from the main loop. Commands of the other protocols are still handled by SetCommandHandler.
+func (ir *ReceiverDevice) SetStateHandler(t *StateTiming, state []byte, sh StateHandler) {
+	ir.states = StateDecoder{Timing: t, State: state}
+	ir.sh = sh
+	ir.listen()
+}
+
+// Internal helper function to start or stop monitoring the IR output pin
+func (ir *ReceiverDevice) listen() {
+	for _, d := range ir.decoders {
+		switch d := d.(type) {
+		case *beoDecoder:
+			d.enabled = ir.BangOlufsen
+		case *necDecoder:
+			d.lenient = ir.NECLenient
+			d.msbFirst = ir.NECMSBFirst
+		}
+		d.reset()
+	}
+	ir.detector = autoDetector{gap: autoDetectGap}
+	if ir.BangOlufsen {
+		ir.detector.gap = beoAutoDetectGap
+	}
+	ir.states.Reset()
+	if ir.ch != nil || ir.dispatcher != nil || ir.sh != nil {
 		// Start monitoring IR output pin for changes
 		ir.pin.SetInterrupt(machine.PinFalling|machine.PinRising, ir.pinChange)
 	} else {
@@ -81,11 +119,57 @@ func (ir *ReceiverDevice) SetCommandHandler(ch CommandHandler) {
 	}
 }
 
-// Internal helper function to reset state machine on protocol failure
-func (ir *ReceiverDevice) resetStateMachine() {
-	ir.data = Data{}
-	ir.bitIndex = 0
-	ir.necState = lead_pulse_start
+// Poll reports the last frame received with AutoDetect, or the last state
+// frame, once the line has been idle long enough to tell that the frame has
+// ended. Likewise, frames of protocols like JVC, which only end with the gap
+// after them, are reported from Poll when no other frame follows. The
+// CommandHandler or StateHandler is then called from Poll instead of from
+// interrupt context.
+func (ir *ReceiverDevice) Poll() {
+	mask := interrupt.Disable()
+	idle := time.Since(ir.lastTime)
+	var ended int
+	for _, d := range ir.decoders {
+		e, ok := d.(ender)
+		if !ok || !e.end(idle, &ir.ended[ended]) {
+			continue
+		}
+		if ir.AutoDetect {
+			ir.detector.add(&ir.ended[ended])
+		} else {
+			ended++
+		}
+	}
+	ok := ir.detector.flush(idle, &ir.polled)
+	var n int
+	if ir.sh != nil {
+		n = ir.states.End(idle)
+	}
+	interrupt.Restore(mask)
+	for i := range ir.ended[:ended] {
+		ir.notify(&ir.ended[i])
+	}
+	if ok {
+		ir.notify(&ir.polled)
+	}
+	if n > 0 {
+		ir.sh(ir.states.State[:n])
+	}
+}
+
+// Internal helper function to hand decoded data to the client
+func (ir *ReceiverDevice) notify(data *Data) {
+	if ir.ch != nil {
+		ir.ch(*data)
+	}
+	if ir.dispatcher != nil {
+		ir.dispatcher.Post(events.Event{
+			Kind:   events.IRCommand,
+			Source: ir.source,
+			Flags:  data.eventFlags(),
+			Value:  data.Code,
+		})
+	}
 }
 
 // Internal pin rising/falling edge interrupt handler
@@ -98,128 +182,26 @@ func (ir *ReceiverDevice) pinChange(pin machine.Pin) {
 	now := time.Now()
 	duration := now.Sub(ir.lastTime)
 	ir.lastTime = now
-	switch ir.necState {
-	case lead_pulse_start:
-		if !ir.pin.Get() {
-			// IR is 'on' (pin is pulled high and sent low when IR is received)
-			ir.necState = lead_space_start // move to next state
-		}
-	case lead_space_start:
-		if duration > time.Microsecond*9500 || duration < time.Microsecond*8500 {
-			// Invalid interval for 9ms lead pulse. Reset
-			ir.resetStateMachine()
-		} else {
-			// 9ms lead pulse detected, move to next state
-			ir.necState = lead_space_end
-		}
-	case lead_space_end:
-		if duration > time.Microsecond*5000 || duration < time.Microsecond*1750 {
-			// Invalid interval for 4.5ms lead space OR 2.25ms repeat space. Reset
-			ir.resetStateMachine()
-		} else {
-			// 4.5ms lead space OR 2.25ms repeat space detected
-			if duration > time.Microsecond*3000 {
-				// 4.5ms lead space detected, new code incoming, move to next state
-				ir.resetStateMachine()
-				ir.necState = bit_read_start
-			} else {
-				// 2.25ms repeat space detected.
-				if ir.data.Code != 0 {
-					// Valid repeat code. Invoke client callback with repeat flag set
-					ir.data.Flags |= DataFlagIsRepeat
-					if ir.ch != nil {
-						ir.ch(ir.data)
-					}
-					ir.necState = lead_pulse_start
-				} else {
-					// ir.data is not in a valid state for a repeat. Reset
-					ir.resetStateMachine()
-				}
-			}
+	// The IR receiver sends logic LOW when receiving IR, so the pin is HIGH after a mark
+	mark := ir.pin.Get()
+	if ir.sh != nil {
+		if n := ir.states.Pulse(mark, duration); n > 0 {
+			ir.sh(ir.states.State[:n])
 		}
-	case bit_read_start:
-		if duration > time.Microsecond*700 || duration < time.Microsecond*400 {
-			// Invalid interval for 562.5µs pulse. Reset
-			ir.resetStateMachine()
-		} else {
-			// 562.5µs pulse detected, move to next state
-			ir.necState = bit_read_end
-		}
-	case bit_read_end:
-		if duration > time.Microsecond*1800 || duration < time.Microsecond*400 {
-			// Invalid interval for 562.5µs space OR 1687.5µs space. Reset
-			ir.resetStateMachine()
-		} else {
-			// 562.5µs OR 1687.5µs space detected
-			mask := uint32(1 << ir.bitIndex)
-			if duration > time.Microsecond*1000 {
-				// 1687.5µs space detected (logic 1) - Set bit
-				ir.data.Code |= mask
-			} else {
-				// 562.5µs space detected (logic 0) - Clear bit
-				ir.data.Code &^= mask
-			}
-
-			ir.bitIndex++
-			if ir.bitIndex > 31 {
-				// We've read all bits for this code, move to next state
-				ir.necState = trail_pulse_end
-			} else {
-				// Read next bit
-				ir.necState = bit_read_start
-			}
+	}
+	for _, d := range ir.decoders {
+		if !d.pulse(mark, duration, &ir.data) {
+			continue
 		}
-	case trail_pulse_end:
-		if duration > time.Microsecond*700 || duration < time.Microsecond*400 {
-			// Invalid interval for trailing 562.5µs pulse. Reset
-			ir.resetStateMachine()
+		if ir.AutoDetect {
+			ir.detector.add(&ir.data)
 		} else {
-			// 562.5µs trailing pulse detected. Decode & validate data
-			err := ir.decode()
-			if err == irDecodeErrorNone {
-				// Valid data, invoke client callback
-				if ir.ch != nil {
-					ir.ch(ir.data)
-				}
-				// around we go again. Note: we don't resetStateMachine() since repeat codes are now possible
-				ir.necState = lead_pulse_start
-			} else {
-				ir.resetStateMachine()
-			}
+			ir.notify(&ir.data)
 		}
 	}
-}
-
-// Error type for NEC format decoding
-type irDecodeError int
-
-// Valid values for irDecodeError
-const (
-	irDecodeErrorNone             irDecodeError = iota // no error occurred
-	irDecodeErrorInverseCheckFail                      // validation of inverse cmd does not match cmd
-)
-
-func (ir *ReceiverDevice) decode() irDecodeError {
-	// Decode cmd and inverse cmd and perform validation check
-	cmd := uint8((ir.data.Code & 0x00ff0000) >> 16)
-	invCmd := uint8((ir.data.Code & 0xff000000) >> 24)
-	if cmd != ^invCmd {
-		// Validation failure. cmd and inverse cmd do not match
-		return irDecodeErrorInverseCheckFail
-	}
-	// cmd validation pass, decode address
-	ir.data.Command = uint16(cmd)
-	addrLow := uint8(ir.data.Code & 0xff)
-	addrHigh := uint8((ir.data.Code & 0xff00) >> 8)
-	if addrHigh == ^addrLow {
-		// addrHigh is inverse of addrLow. This is not a valid 16-bit address in extended NEC coding
-		// since it is indistinguishable from 8-bit address with inverse validation. Use the 8-bit address
-		ir.data.Address = uint16(addrLow)
-	} else {
-		// 16-bit extended NEC address
-		ir.data.Address = (uint16(addrHigh) << 8) | uint16(addrLow)
+	// after the decoders, which report frames ended by this gap
+	if ir.AutoDetect && !mark && ir.detector.flush(duration, &ir.data) {
+		// a new frame starts after the previous one
+		ir.notify(&ir.data)
 	}
-	// Clear repeat flag
-	ir.data.Flags &^= DataFlagIsRepeat
-	return irDecodeErrorNone
 }
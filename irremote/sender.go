@@ -0,0 +1,1007 @@
+package irremote // import "tinygo.org/x/drivers/irremote"
+
+import (
+	"errors"
+	"math/bits"
+	"time"
+
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+var (
+	errInvalidAddress = errors.New("irremote: address out of range")
+	errInvalidLength  = errors.New("irremote: unsupported number of bits")
+)
+
+// transmitter emits a modulated IR signal. The SenderDevice drives an IR LED
+// with it, tests record the signal instead.
+//
+// Consecutive marks or spaces simply extend each other, so encoders don't
+// need to merge half bits of the same level.
+type transmitter interface {
+	configure() error
+	// start prepares a transmission with the given carrier frequency in Hz.
+	start(carrier uint32)
+	// mark turns the carrier on for d.
+	mark(d time.Duration)
+	// space turns the carrier off for d.
+	space(d time.Duration)
+}
+
+// SenderDevice is the device for sending IR commands.
+//
+// The Send methods block until the frame, including the gap that the
+// protocol requires after it, has been sent.
+type SenderDevice struct {
+	// NECRepeat selects how NEC frames are repeated for a held key.
+	NECRepeat NECRepeatStyle
+
+	tx       transmitter
+	elapsed  time.Duration // since the start of the current frame
+	pfToggle uint8         // LEGO Power Functions toggle bit of each channel
+}
+
+// Configure configures the output for the IR sender device.
+func (ir *SenderDevice) Configure() error {
+	return ir.tx.configure()
+}
+
+// start starts a frame with the given carrier frequency in Hz.
+func (ir *SenderDevice) start(carrier uint32) {
+	ir.tx.start(carrier)
+	ir.elapsed = 0
+}
+
+// startFrame starts a frame that follows the previous one without changing
+// the carrier.
+func (ir *SenderDevice) startFrame() {
+	ir.elapsed = 0
+}
+
+func (ir *SenderDevice) mark(d time.Duration) {
+	ir.tx.mark(d)
+	ir.elapsed += d
+}
+
+func (ir *SenderDevice) space(d time.Duration) {
+	ir.tx.space(d)
+	ir.elapsed += d
+}
+
+// gap ends a frame with a space lasting until period after its start, or
+// with a space of min if the frame was longer than that.
+func (ir *SenderDevice) gap(period, min time.Duration) {
+	if d := period - ir.elapsed; d > min {
+		ir.space(d)
+	} else {
+		ir.space(min)
+	}
+}
+
+// bitTiming holds the mark and space durations of the bits of a pulse
+// distance or pulse width protocol.
+type bitTiming struct {
+	oneMark, oneSpace   time.Duration
+	zeroMark, zeroSpace time.Duration
+}
+
+// sendBits sends the nbits low bits of data, MSB first unless lsbFirst.
+func (ir *SenderDevice) sendBits(data uint64, nbits int, lsbFirst bool, t *bitTiming) {
+	for i := 0; i < nbits; i++ {
+		var bit bool
+		if lsbFirst {
+			bit = data>>uint(i)&1 != 0
+		} else {
+			bit = data>>uint(nbits-1-i)&1 != 0
+		}
+		if bit {
+			ir.mark(t.oneMark)
+			ir.space(t.oneSpace)
+		} else {
+			ir.mark(t.zeroMark)
+			ir.space(t.zeroSpace)
+		}
+	}
+}
+
+// manchester sends the nbits low bits of data MSB first as bi-phase symbols
+// of two half bits of length half. With markFirst, a 1 is sent as mark then
+// space (RC-6), otherwise as space then mark (RC-5).
+func (ir *SenderDevice) manchester(data uint32, nbits int, half time.Duration, markFirst bool) {
+	irprotocol.EncodeManchester(uint64(data), nbits, half, markFirst, ir.pulse)
+}
+
+// biphase sends a single bi-phase bit.
+func (ir *SenderDevice) biphase(bit bool, half time.Duration, markFirst bool) {
+	ir.manchester(uint32(b2u8(bit)), 1, half, markFirst)
+}
+
+// pulse sends a mark or a space.
+func (ir *SenderDevice) pulse(mark bool, d time.Duration) {
+	if mark {
+		ir.mark(d)
+	} else {
+		ir.space(d)
+	}
+}
+
+// RC-5 protocol reference
+// https://www.sbprojects.net/knowledge/ir/rc5.php
+
+const (
+	rc5Carrier = 36000
+	rc5Half    = 889 * time.Microsecond
+	rc5Period  = 113778 * time.Microsecond // frame repetition period
+)
+
+// SendRC5 sends a Philips RC-5 frame with a 5-bit address and a 7-bit
+// command. Commands above 63 use the extended RC-5X format, where the second
+// start bit carries the inverted command bit 6.
+//
+// The toggle bit must be flipped on every new key press and kept while a key
+// is held, so that the receiver can tell a new press from a repeat. A held key
+// is sent by calling SendRC5 repeatedly with the same toggle.
+func (ir *SenderDevice) SendRC5(address, command uint8, toggle bool) {
+	frame := uint32(1) << 13 // first start bit
+	if command&0x40 == 0 {
+		frame |= 1 << 12 // second start bit, or inverted command bit 6
+	}
+	if toggle {
+		frame |= 1 << 11
+	}
+	frame |= uint32(address&0x1F) << 6
+	frame |= uint32(command & 0x3F)
+
+	ir.start(rc5Carrier)
+	ir.manchester(frame, 14, rc5Half, false)
+	ir.gap(rc5Period, 0)
+}
+
+// RC-6 protocol reference
+// https://www.sbprojects.net/knowledge/ir/rc6.php
+
+const (
+	rc6Carrier = 36000
+	rc6Unit    = 444 * time.Microsecond
+	rc6Period  = 107 * time.Millisecond // frame repetition period
+)
+
+// SendRC6 sends a Philips RC-6 mode 0 frame with an 8-bit address and
+// command.
+//
+// The toggle bit must be flipped on every new key press and kept while a key
+// is held. A held key is sent by calling SendRC6 repeatedly with the same
+// toggle.
+func (ir *SenderDevice) SendRC6(address, command uint8, toggle bool) {
+	ir.rc6(0, toggle, uint32(address)<<8|uint32(command), 16)
+}
+
+// rc6 sends an RC-6 frame: the leader, a start bit, 3 mode bits, the double
+// width toggle (trailer) bit and nbits of payload, MSB first.
+func (ir *SenderDevice) rc6(mode uint8, toggle bool, payload uint32, nbits int) {
+	ir.start(rc6Carrier)
+	ir.mark(6 * rc6Unit)
+	ir.space(2 * rc6Unit)
+	ir.biphase(true, rc6Unit, true)
+	ir.manchester(uint32(mode), 3, rc6Unit, true)
+	ir.biphase(toggle, 2*rc6Unit, true)
+	ir.manchester(payload, nbits, rc6Unit, true)
+	ir.gap(rc6Period, 6*rc6Unit)
+}
+
+// SendRC6A sends an RC-6A (mode 6) frame with a customer (OEM) code, an 8-bit
+// address and an 8-bit command. OEM codes with the top bit set, like 0x800F
+// for Microsoft, are sent as 16 bits, others as 8 bits with the top bit clear.
+// The toggle is sent in the trailer bit.
+func (ir *SenderDevice) SendRC6A(oem uint16, address, command uint8, toggle bool) {
+	payload := uint32(address)<<8 | uint32(command)
+	if oem&0x8000 != 0 {
+		ir.rc6(6, toggle, uint32(oem)<<16|payload, 32)
+	} else {
+		ir.rc6(6, toggle, uint32(oem&0x7F)<<16|payload, 24)
+	}
+}
+
+// MCE is the RC-6A customer code of Windows Media Center and Xbox remotes.
+const MCE = 0x800F
+
+// SendMCE sends a Windows Media Center (or Xbox 360) command: an RC-6A
+// frame with the MCE customer code and a 7-bit address, usually 0x04 for
+// Media Center remotes and 0x74 for Xbox ones.
+//
+// MCE remotes leave the trailer bit clear and send the toggle in the top bit
+// of the address byte instead.
+func (ir *SenderDevice) SendMCE(address, command uint8, toggle bool) {
+	address &= 0x7F
+	if toggle {
+		address |= 0x80
+	}
+	ir.SendRC6A(MCE, address, command, false)
+}
+
+// Sony SIRC protocol reference
+// https://www.sbprojects.net/knowledge/ir/sirc.php
+
+const (
+	sircCarrier = 40000
+	sircUnit    = 600 * time.Microsecond
+	sircPeriod  = 45 * time.Millisecond // frame repetition period
+	sircRepeats = 3                     // Sony devices need every command 3 times
+)
+
+var sircTiming = bitTiming{
+	oneMark: 2 * sircUnit, oneSpace: sircUnit,
+	zeroMark: sircUnit, zeroSpace: sircUnit,
+}
+
+// SendSIRC12 sends a Sony SIRC 12-bit command with a 5-bit address and a
+// 7-bit command. Like all SIRC commands, the frame is sent three times, as
+// Sony devices ignore commands received fewer times.
+func (ir *SenderDevice) SendSIRC12(address, command uint8) {
+	ir.sirc(uint32(address&0x1F)<<7|uint32(command&0x7F), 12)
+}
+
+// SendSIRC15 sends a Sony SIRC 15-bit command with an 8-bit address and a
+// 7-bit command.
+func (ir *SenderDevice) SendSIRC15(address, command uint8) {
+	ir.sirc(uint32(address)<<7|uint32(command&0x7F), 15)
+}
+
+// SendSIRC20 sends a Sony SIRC 20-bit command with a 5-bit address, a 7-bit
+// command and 8 extended bits.
+func (ir *SenderDevice) SendSIRC20(address, command, extended uint8) {
+	ir.sirc(uint32(extended)<<12|uint32(address&0x1F)<<7|uint32(command&0x7F), 20)
+}
+
+// sirc sends the SIRC frames of nbits, LSB first, starting with the command.
+func (ir *SenderDevice) sirc(data uint32, nbits int) {
+	ir.start(sircCarrier)
+	for i := 0; i < sircRepeats; i++ {
+		ir.startFrame()
+		ir.mark(4 * sircUnit)
+		ir.space(sircUnit)
+		ir.sendBits(uint64(data), nbits, true, &sircTiming)
+		ir.gap(sircPeriod, 0)
+	}
+}
+
+// NEC protocol reference
+// https://www.sbprojects.net/knowledge/ir/nec.php
+
+const (
+	necCarrier     = 38000
+	necHeaderMark  = 9000 * time.Microsecond
+	necHeaderSpace = 4500 * time.Microsecond
+	necRepeatSpace = 2250 * time.Microsecond // header space of repeat codes
+	necPeriod      = 108 * time.Millisecond
+)
+
+// NECRepeatStyle is the way NEC frames are repeated while a key is held.
+type NECRepeatStyle uint8
+
+const (
+	// NECRepeatDitto sends the short repeat code of the original NEC
+	// protocol.
+	NECRepeatDitto NECRepeatStyle = iota
+	// NECRepeatFullFrame repeats the full frame, as expected by NEC2
+	// devices.
+	NECRepeatFullFrame
+)
+
+// necTiming is the 562.5µs pulse distance bit timing of NEC and the protocols
+// derived from it.
+var necTiming = bitTiming{
+	oneMark: 560 * time.Microsecond, oneSpace: 1690 * time.Microsecond,
+	zeroMark: 560 * time.Microsecond, zeroSpace: 560 * time.Microsecond,
+}
+
+// SendNEC sends an NEC frame with an 8-bit command, followed by repeats
+// repeat codes for a held key. An address above 0xFF is sent as an extended
+// 16-bit address, otherwise the 8-bit address is followed by its inverse.
+func (ir *SenderDevice) SendNEC(address uint16, command uint8, repeats int) {
+	ir.nec(necAddress(address)|uint32(command)<<16|uint32(^command)<<24, repeats)
+}
+
+// SendNECMSB sends a 32-bit NEC code given in the MSB first order of some
+// vendors' documentation, followed by repeats repeat codes. See NECMSBFirst.
+func (ir *SenderDevice) SendNECMSB(code uint32, repeats int) {
+	ir.nec(bits.Reverse32(code), repeats)
+}
+
+// SendNEC16Command sends an NEC frame with a full 16-bit command in place of
+// the command and its inverse, as used by Onkyo, followed by repeats repeat
+// codes. The address is sent like with SendNEC.
+func (ir *SenderDevice) SendNEC16Command(address, command uint16, repeats int) {
+	ir.nec(MakeNEC16Code(address, command), repeats)
+}
+
+// MakeNEC16Code returns the 32-bit NEC code of an address and a 16-bit
+// command, as sent by SendNEC16Command.
+func MakeNEC16Code(address, command uint16) uint32 {
+	return necAddress(address) | uint32(command)<<16
+}
+
+// necAddress returns the address bits of an NEC code: an address above 0xFF
+// is an extended 16-bit address, otherwise the 8-bit address is followed by
+// its inverse.
+func necAddress(address uint16) uint32 {
+	if address <= 0xFF {
+		address |= ^address << 8
+	}
+	return uint32(address)
+}
+
+// nec sends the 32-bit NEC code followed by repeats repeat codes.
+func (ir *SenderDevice) nec(code uint32, repeats int) {
+	ir.start(necCarrier)
+	ir.necFrame(code)
+	for i := 0; i < repeats; i++ {
+		if ir.NECRepeat == NECRepeatFullFrame {
+			ir.necFrame(code)
+			continue
+		}
+		ir.startFrame()
+		ir.mark(necHeaderMark)
+		ir.space(necRepeatSpace)
+		ir.mark(necTiming.zeroMark)
+		ir.gap(necPeriod, 0)
+	}
+}
+
+// necFrame sends the 32-bit NEC code, LSB first, and the gap after it.
+func (ir *SenderDevice) necFrame(code uint32) {
+	ir.startFrame()
+	ir.mark(necHeaderMark)
+	ir.space(necHeaderSpace)
+	ir.sendBits(uint64(code), 32, true, &necTiming)
+	ir.mark(necTiming.zeroMark) // stop bit
+	ir.gap(necPeriod, 0)
+}
+
+// Apple remote protocol reference
+// https://en.wikipedia.org/wiki/Apple_Remote#Technical_details
+
+// AppleAddress is the NEC address of the Apple remotes.
+const AppleAddress = 0x87EE
+
+// MakeAppleCode returns the 32-bit NEC code of an Apple remote command.
+// Instead of the inverse of the command, the code carries the pairing ID of
+// the remote, which paired devices compare to their own.
+func MakeAppleCode(command, pairingID uint8) uint32 {
+	return AppleAddress | uint32(command)<<16 | uint32(pairingID)<<24
+}
+
+// SendApple sends an Apple remote command with the given pairing ID,
+// followed by repeats repeat codes. Sending another pairing ID emulates
+// another remote.
+func (ir *SenderDevice) SendApple(command, pairingID uint8, repeats int) {
+	ir.nec(MakeAppleCode(command, pairingID), repeats)
+}
+
+// Pioneer protocol reference
+// http://www.adrian-kingston.com/IRFormatPioneer.htm
+
+// SendPioneer sends two 32-bit NEC codes at the 40kHz carrier of Pioneer.
+// Most keys send the same code twice, which is received as NEC, some send a
+// shift code first.
+func (ir *SenderDevice) SendPioneer(code1, code2 uint32) {
+	ir.start(40000)
+	ir.necFrame(code1)
+	ir.necFrame(code2)
+}
+
+// Samsung protocol reference
+// https://www.mikrocontroller.net/articles/IRMP_-_english#SAMSUNG
+
+const (
+	samsungCarrier = 38000
+	samsungHeader  = 4500 * time.Microsecond
+	samsungPeriod  = 108 * time.Millisecond
+)
+
+// SendSamsung sends a Samsung32 frame with a 16-bit address and an 8-bit
+// command, which is followed by its inverse. Samsung TVs usually repeat the
+// low address byte in the high byte, like 0x0707.
+func (ir *SenderDevice) SendSamsung(address uint16, command uint8) {
+	ir.start(samsungCarrier)
+	ir.mark(samsungHeader)
+	ir.space(samsungHeader)
+	data := uint64(address) | uint64(command)<<16 | uint64(^command)<<24
+	ir.sendBits(data, 32, true, &necTiming)
+	ir.mark(necTiming.zeroMark) // stop bit
+	ir.gap(samsungPeriod, 0)
+}
+
+// SendSamsung36 sends a 36-bit Samsung frame, used by Blu-ray players and
+// soundbars: a 16-bit address, a separator and 20 bits of command.
+func (ir *SenderDevice) SendSamsung36(address uint16, command uint32) {
+	ir.start(samsungCarrier)
+	ir.mark(samsungHeader)
+	ir.space(samsungHeader)
+	ir.sendBits(uint64(address), 16, true, &necTiming)
+	ir.mark(necTiming.zeroMark)
+	ir.space(samsungHeader)
+	ir.sendBits(uint64(command), 20, true, &necTiming)
+	ir.mark(necTiming.zeroMark) // stop bit
+	ir.gap(samsungPeriod, 0)
+}
+
+// Kaseikyo (Japanese manufacturers' association) protocol reference
+// https://www.mikrocontroller.net/articles/IRMP_-_english#KASEIKYO
+
+const (
+	kaseikyoCarrier = 37000
+	kaseikyoUnit    = 432 * time.Microsecond
+	kaseikyoPeriod  = 130 * time.Millisecond
+
+	// Kaseikyo vendor IDs.
+	VendorPanasonic  = 0x2002
+	VendorDenon      = 0x3254
+	VendorMitsubishi = 0xCB23
+	VendorSharp      = 0x5AAA
+	VendorJVC        = 0x0103
+)
+
+var kaseikyoTiming = bitTiming{
+	oneMark: kaseikyoUnit, oneSpace: 3 * kaseikyoUnit,
+	zeroMark: kaseikyoUnit, zeroSpace: kaseikyoUnit,
+}
+
+// SendKaseikyo sends a 48-bit Kaseikyo frame: the 16-bit vendor ID, its
+// 4-bit parity, the 8-bit genre (genre 1 in the low nibble, genre 2 in the
+// high one), 10 bits of data, a 2-bit ID and the parity byte. Both parities
+// are computed.
+func (ir *SenderDevice) SendKaseikyo(vendorID uint16, genre uint8, data uint16, id uint8) {
+	ir.kaseikyo(vendorID,
+		vendorParity(vendorID)|genre<<4,
+		genre>>4|uint8(data<<4),
+		uint8(data>>4)&0x3F|id<<6)
+}
+
+// vendorParity returns the XOR of the nibbles of a Kaseikyo vendor ID.
+func vendorParity(vendorID uint16) uint8 {
+	v := vendorID ^ vendorID>>8
+	return uint8(v^v>>4) & 0x0F
+}
+
+// SendDenonK sends a Denon Kaseikyo frame of a 12-bit address and an 8-bit
+// command.
+func (ir *SenderDevice) SendDenonK(address uint16, command uint8) {
+	ir.kaseikyoVendor(VendorDenon, address, command)
+}
+
+// SendMitsubishiK sends a Mitsubishi Kaseikyo frame of a 12-bit address and
+// an 8-bit command.
+func (ir *SenderDevice) SendMitsubishiK(address uint16, command uint8) {
+	ir.kaseikyoVendor(VendorMitsubishi, address, command)
+}
+
+// SendSharpK sends a Sharp Kaseikyo frame of a 12-bit address and an 8-bit
+// command.
+func (ir *SenderDevice) SendSharpK(address uint16, command uint8) {
+	ir.kaseikyoVendor(VendorSharp, address, command)
+}
+
+// SendJVC48 sends a JVC Kaseikyo frame of a 12-bit address and an 8-bit
+// command.
+func (ir *SenderDevice) SendJVC48(address uint16, command uint8) {
+	ir.kaseikyoVendor(VendorJVC, address, command)
+}
+
+// kaseikyoVendor sends a Kaseikyo frame in the layout shared by most
+// vendors: a 12-bit address in place of the genre and the low data bits,
+// followed by an 8-bit command.
+func (ir *SenderDevice) kaseikyoVendor(vendorID, address uint16, command uint8) {
+	ir.SendKaseikyo(vendorID, uint8(address), address>>8&0x0F|uint16(command)<<4&0x3F0, command>>6)
+}
+
+// SendPanasonic sends a Panasonic command, a Kaseikyo frame with the
+// Panasonic vendor ID made of device, subdevice and function bytes.
+func (ir *SenderDevice) SendPanasonic(device, subdevice, function uint8) {
+	ir.kaseikyo(VendorPanasonic, device, subdevice, function)
+}
+
+// kaseikyo sends a Kaseikyo frame of the vendor ID, three data bytes and
+// their parity, LSB first.
+func (ir *SenderDevice) kaseikyo(vendorID uint16, b2, b3, b4 uint8) {
+	ir.start(kaseikyoCarrier)
+	ir.mark(8 * kaseikyoUnit)
+	ir.space(4 * kaseikyoUnit)
+	data := uint64(vendorID) | uint64(b2)<<16 | uint64(b3)<<24 | uint64(b4)<<32 | uint64(b2^b3^b4)<<40
+	ir.sendBits(data, 48, true, &kaseikyoTiming)
+	ir.mark(kaseikyoUnit) // stop bit
+	ir.gap(kaseikyoPeriod, 0)
+}
+
+// JVC protocol reference
+// https://www.sbprojects.net/knowledge/ir/jvc.php
+
+const (
+	jvcCarrier = 38000
+	jvcUnit    = 526 * time.Microsecond
+	jvcPeriod  = 55 * time.Millisecond
+)
+
+var jvcTiming = bitTiming{
+	oneMark: jvcUnit, oneSpace: 3 * jvcUnit,
+	zeroMark: jvcUnit, zeroSpace: jvcUnit,
+}
+
+// SendJVC sends a JVC frame with an 8-bit address and an 8-bit command,
+// followed by repeats frames for a held key. Only the first frame has a
+// header, the repeats consist of the data alone.
+func (ir *SenderDevice) SendJVC(address, command uint8, repeats int) {
+	ir.start(jvcCarrier)
+	data := uint64(address) | uint64(command)<<8
+	for i := 0; i <= repeats; i++ {
+		ir.startFrame()
+		if i == 0 {
+			ir.mark(16 * jvcUnit)
+			ir.space(8 * jvcUnit)
+		}
+		ir.sendBits(data, 16, true, &jvcTiming)
+		ir.mark(jvcUnit) // stop bit
+		ir.gap(jvcPeriod, 0)
+	}
+}
+
+// Sharp protocol reference
+// https://www.sbprojects.net/knowledge/ir/sharp.php
+
+const (
+	sharpCarrier = 38000
+	sharpGap     = 40 * time.Millisecond
+)
+
+var sharpTiming = bitTiming{
+	oneMark: 320 * time.Microsecond, oneSpace: 1680 * time.Microsecond,
+	zeroMark: 320 * time.Microsecond, zeroSpace: 680 * time.Microsecond,
+}
+
+// SendSharp sends a Sharp command with a 5-bit address and an 8-bit
+// command. As required by the protocol, the frame is followed 40ms later by
+// a frame with the command, expansion and check bits inverted.
+func (ir *SenderDevice) SendSharp(address, command uint8) {
+	// expansion bit 1, check bit 0
+	ir.doubleFrame(uint16(address&0x1F)|uint16(command)<<5|1<<13, &sharpTiming, sharpGap)
+}
+
+// doubleFrame sends the 15 bits of data, LSB first, followed after gap by
+// the same frame with all but the 5 address bits inverted, as used by the
+// Sharp and Denon protocols.
+func (ir *SenderDevice) doubleFrame(data uint16, t *bitTiming, gap time.Duration) {
+	ir.start(sharpCarrier)
+	for i := 0; i < 2; i++ {
+		ir.sendBits(uint64(data), 15, true, t)
+		ir.mark(t.zeroMark) // stop bit
+		ir.space(gap)
+		data ^= 0x7FE0
+	}
+}
+
+// Denon protocol reference
+// https://www.mikrocontroller.net/articles/IRMP_-_english#DENON
+
+const denonGap = 45 * time.Millisecond
+
+var denonTiming = bitTiming{
+	oneMark: 260 * time.Microsecond, oneSpace: 1820 * time.Microsecond,
+	zeroMark: 260 * time.Microsecond, zeroSpace: 780 * time.Microsecond,
+}
+
+// SendDenon sends a Denon 15-bit command with a 5-bit address and an 8-bit
+// command, followed by its confirmation frame with the command bits
+// inverted.
+func (ir *SenderDevice) SendDenon(address, command uint8) {
+	ir.doubleFrame(uint16(address&0x1F)|uint16(command)<<5, &denonTiming, denonGap)
+}
+
+// LG protocol reference
+// https://github.com/Arduino-IRremote/Arduino-IRremote/blob/master/src/ir_LG.hpp
+
+const (
+	lgCarrier      = 38000
+	lg2HeaderMark  = 3200 * time.Microsecond
+	lg2HeaderSpace = 9900 * time.Microsecond
+	lgPeriod       = 110 * time.Millisecond
+)
+
+// SendLG sends an LG 28-bit frame with an 8-bit address and a 16-bit
+// command, followed by the 4-bit checksum of the command.
+func (ir *SenderDevice) SendLG(address uint8, command uint16) {
+	ir.lg(necHeaderMark, necHeaderSpace, address, command)
+}
+
+// SendLG2 sends an LG2 frame, used by some newer LG appliances. It only
+// differs from SendLG in its header: a 3.2ms mark followed by a 9.9ms space,
+// instead of 9ms and 4.5ms.
+func (ir *SenderDevice) SendLG2(address uint8, command uint16) {
+	ir.lg(lg2HeaderMark, lg2HeaderSpace, address, command)
+}
+
+// lgChecksum returns the LG checksum of a command, the sum of its nibbles.
+func lgChecksum(command uint16) uint8 {
+	var sum uint16
+	for c := command; c != 0; c >>= 4 {
+		sum += c & 0x0F
+	}
+	return uint8(sum & 0x0F)
+}
+
+// lg sends an LG frame with the given header, MSB first.
+func (ir *SenderDevice) lg(headerMark, headerSpace time.Duration, address uint8, command uint16) {
+	ir.start(lgCarrier)
+	ir.mark(headerMark)
+	ir.space(headerSpace)
+	data := uint64(address)<<20 | uint64(command)<<4 | uint64(lgChecksum(command))
+	ir.sendBits(data, 28, false, &necTiming)
+	ir.mark(necTiming.zeroMark) // stop bit
+	ir.gap(lgPeriod, 0)
+}
+
+// Sanyo LC7461 protocol reference
+// https://www.sbprojects.net/knowledge/ir/lc7461.php
+
+const (
+	sanyoCarrier = 38000
+	sanyoPeriod  = 108 * time.Millisecond
+)
+
+// SendSanyo sends a Sanyo LC7461 42-bit frame with a 13-bit address and an
+// 8-bit command, each followed by its inverse. It returns an error without
+// sending anything if the address doesn't fit in 13 bits.
+func (ir *SenderDevice) SendSanyo(address uint16, command uint8) error {
+	if address > 0x1FFF {
+		return errInvalidAddress
+	}
+	ir.start(sanyoCarrier)
+	ir.mark(9000 * time.Microsecond)
+	ir.space(4500 * time.Microsecond)
+	data := uint64(address) | uint64(^address&0x1FFF)<<13 |
+		uint64(command)<<26 | uint64(^command)<<34
+	ir.sendBits(data, 42, true, &necTiming)
+	ir.mark(necTiming.zeroMark) // stop bit
+	ir.gap(sanyoPeriod, 0)
+	return nil
+}
+
+// Mitsubishi 16-bit protocol reference
+// https://github.com/crankyoldgit/IRremoteESP8266/blob/master/src/ir_Mitsubishi.cpp
+
+const (
+	mitsubishiCarrier = 33000
+	mitsubishiPeriod  = 53560 * time.Microsecond
+	mitsubishiMinGap  = 28 * time.Millisecond
+)
+
+var mitsubishiTiming = bitTiming{
+	oneMark: 300 * time.Microsecond, oneSpace: 2100 * time.Microsecond,
+	zeroMark: 300 * time.Microsecond, zeroSpace: 900 * time.Microsecond,
+}
+
+// SendMitsubishi sends a Mitsubishi 16-bit command with an 8-bit address and
+// an 8-bit command. The frame has no header and is sent twice, as the
+// receivers require.
+func (ir *SenderDevice) SendMitsubishi(address, command uint8) {
+	ir.start(mitsubishiCarrier)
+	for i := 0; i < 2; i++ {
+		ir.startFrame()
+		ir.sendBits(uint64(address)<<8|uint64(command), 16, false, &mitsubishiTiming)
+		ir.mark(mitsubishiTiming.zeroMark) // stop bit
+		ir.gap(mitsubishiPeriod, mitsubishiMinGap)
+	}
+}
+
+// Dish Network protocol reference
+// https://github.com/crankyoldgit/IRremoteESP8266/blob/master/src/ir_Dish.cpp
+
+const (
+	dishCarrier = 57600
+	dishUnit    = 400 * time.Microsecond
+	dishGap     = 6100 * time.Microsecond
+	dishRepeats = 3
+)
+
+var dishTiming = bitTiming{
+	oneMark: dishUnit, oneSpace: 1700 * time.Microsecond,
+	zeroMark: dishUnit, zeroSpace: 2800 * time.Microsecond,
+}
+
+// SendDish sends a 16-bit Dish Network code at the 57.6kHz carrier of the
+// protocol. The header is sent once, followed by the code four times, as
+// Dish receivers expect.
+func (ir *SenderDevice) SendDish(code uint16) {
+	ir.start(dishCarrier)
+	ir.mark(dishUnit)
+	ir.space(dishGap)
+	for i := 0; i <= dishRepeats; i++ {
+		ir.sendBits(uint64(code), 16, false, &dishTiming)
+		ir.mark(dishUnit) // stop bit
+		ir.space(dishGap)
+	}
+}
+
+// Bose Wave protocol reference
+// https://github.com/Arduino-IRremote/Arduino-IRremote/blob/master/src/ir_BoseWave.hpp
+
+const (
+	boseCarrier = 38000
+	bosePeriod  = 75 * time.Millisecond
+)
+
+var boseTiming = bitTiming{
+	oneMark: 534 * time.Microsecond, oneSpace: 1468 * time.Microsecond,
+	zeroMark: 534 * time.Microsecond, zeroSpace: 468 * time.Microsecond,
+}
+
+// SendBose sends a Bose Wave command, followed by its inverse.
+func (ir *SenderDevice) SendBose(command uint8) {
+	ir.start(boseCarrier)
+	ir.mark(1060 * time.Microsecond)
+	ir.space(1425 * time.Microsecond)
+	ir.sendBits(uint64(command)|uint64(^command)<<8, 16, true, &boseTiming)
+	ir.mark(boseTiming.zeroMark) // stop bit
+	ir.gap(bosePeriod, 0)
+}
+
+// Bang & Olufsen Datalink 80 IR protocol reference
+// https://github.com/Arduino-IRremote/Arduino-IRremote/blob/master/src/ir_BangOlufsen.hpp
+
+const (
+	beoCarrier = 455000
+	beoMark    = 200 * time.Microsecond
+	beoUnit    = 3125 * time.Microsecond // from one mark to the next for a 0
+)
+
+// SendBangOlufsen sends a Bang & Olufsen command with an 8-bit address and
+// an 8-bit command on the 455kHz carrier of B&O equipment. Bits are coded as
+// the distance between marks: 1, 2 or 3 units for a 0, a 1 and a bit equal
+// to the previous one, 4 units for the start bit and 5 for the trailer.
+func (ir *SenderDevice) SendBangOlufsen(address, command uint8) {
+	ir.start(beoCarrier)
+	ir.beoPulse(1)
+	ir.beoPulse(1)
+	ir.beoPulse(4)
+	ir.beoPulse(2) // a 1 before the data
+	prev := true
+	data := uint16(address)<<8 | uint16(command)
+	for i := 15; i >= 0; i-- {
+		bit := data>>uint(i)&1 != 0
+		switch {
+		case bit == prev:
+			ir.beoPulse(3)
+		case bit:
+			ir.beoPulse(2)
+		default:
+			ir.beoPulse(1)
+		}
+		prev = bit
+	}
+	ir.beoPulse(5)
+	ir.mark(beoMark)
+	ir.space(beoUnit)
+}
+
+// beoPulse sends a mark and the space lasting until n units after it.
+func (ir *SenderDevice) beoPulse(n time.Duration) {
+	ir.mark(beoMark)
+	ir.space(n*beoUnit - beoMark)
+}
+
+// RCA protocol reference
+// https://www.sbprojects.net/knowledge/ir/rca.php
+
+const (
+	rcaCarrier = 56000
+	rcaUnit    = 500 * time.Microsecond
+	rcaPeriod  = 64 * time.Millisecond
+	rcaMinGap  = 8 * time.Millisecond
+)
+
+var rcaTiming = bitTiming{
+	oneMark: rcaUnit, oneSpace: 4 * rcaUnit,
+	zeroMark: rcaUnit, zeroSpace: 2 * rcaUnit,
+}
+
+// SendRCA sends an RCA frame with a 4-bit address and an 8-bit command,
+// followed by their inverses, on the 56kHz carrier of RCA equipment.
+func (ir *SenderDevice) SendRCA(address, command uint8) {
+	data := uint32(address&0x0F)<<8 | uint32(command)
+	ir.start(rcaCarrier)
+	ir.mark(8 * rcaUnit)
+	ir.space(8 * rcaUnit)
+	ir.sendBits(uint64(data)<<12|uint64(^data&0xFFF), 24, false, &rcaTiming)
+	ir.mark(rcaUnit) // stop bit
+	ir.gap(rcaPeriod, rcaMinGap)
+}
+
+// Whynter protocol reference
+// https://github.com/crankyoldgit/IRremoteESP8266/blob/master/src/ir_Whynter.cpp
+
+const (
+	whynterCarrier = 38000
+	whynterMark    = 750 * time.Microsecond
+	whynterPeriod  = 108 * time.Millisecond
+)
+
+var whynterTiming = bitTiming{
+	oneMark: whynterMark, oneSpace: 2150 * time.Microsecond,
+	zeroMark: whynterMark, zeroSpace: 750 * time.Microsecond,
+}
+
+// SendWhynter sends a 32-bit Whynter code, used by Whynter air conditioners
+// and fans. The header is preceded by a single short mark.
+func (ir *SenderDevice) SendWhynter(code uint32) {
+	ir.start(whynterCarrier)
+	ir.mark(whynterMark)
+	ir.space(whynterTiming.zeroSpace)
+	ir.mark(2850 * time.Microsecond)
+	ir.space(2850 * time.Microsecond)
+	ir.sendBits(uint64(code), 32, false, &whynterTiming)
+	ir.mark(whynterMark) // stop bit
+	ir.gap(whynterPeriod, 0)
+}
+
+// MagiQuest protocol reference
+// https://github.com/Arduino-IRremote/Arduino-IRremote/blob/master/src/ir_MagiQuest.hpp
+
+const (
+	magiQuestCarrier = 36000
+	magiQuestUnit    = 288 * time.Microsecond
+	magiQuestGap     = 100 * time.Millisecond
+)
+
+var magiQuestTiming = bitTiming{
+	oneMark: 2 * magiQuestUnit, oneSpace: 2 * magiQuestUnit,
+	zeroMark: magiQuestUnit, zeroSpace: 3 * magiQuestUnit,
+}
+
+// SendMagiQuest sends the 56-bit frame of a MagiQuest wand: 8 zero bits,
+// the 31-bit wand ID, the 9-bit magnitude of the swing and a checksum.
+func (ir *SenderDevice) SendMagiQuest(wandID uint32, magnitude uint16) {
+	wandID &= 0x7FFFFFFF
+	magnitude &= 0x1FF
+	sum := uint8(wandID) + uint8(wandID>>8) + uint8(wandID>>16) + uint8(wandID>>24) +
+		uint8(magnitude) + uint8(magnitude>>8)
+	data := uint64(wandID)<<17 | uint64(magnitude)<<8 | uint64(-sum)
+	ir.start(magiQuestCarrier)
+	ir.sendBits(data, 56, false, &magiQuestTiming)
+	ir.space(magiQuestGap)
+}
+
+// Nokia NRC17 protocol reference
+// https://www.sbprojects.net/knowledge/ir/nrc17.php
+
+const (
+	nrc17Carrier   = 38000
+	nrc17Half      = 500 * time.Microsecond
+	nrc17Period    = 100 * time.Millisecond
+	nrc17First     = 40 * time.Millisecond // from the start message to the first command
+	nrc17StartStop = 0xFFFE                // command 0xFE, address 0xF, subcode 0xF
+)
+
+// SendNRC17 sends a Nokia NRC17 command with a 4-bit address and a 4-bit
+// subcode, repeated repeats times for a held key. As required by the
+// protocol, the command is preceded by a start message and followed by a
+// stop message.
+func (ir *SenderDevice) SendNRC17(address, subcode, command uint8, repeats int) {
+	ir.start(nrc17Carrier)
+	ir.nrc17(nrc17StartStop, nrc17First)
+	data := uint16(command) | uint16(address&0x0F)<<8 | uint16(subcode&0x0F)<<12
+	for i := 0; i <= repeats; i++ {
+		ir.nrc17(data, nrc17Period)
+	}
+	ir.nrc17(nrc17StartStop, nrc17Period)
+}
+
+// nrc17 sends an NRC17 message of 16 bits of data, LSB first, lasting
+// period.
+func (ir *SenderDevice) nrc17(data uint16, period time.Duration) {
+	ir.startFrame()
+	ir.mark(nrc17Half) // pre-pulse
+	ir.space(5 * nrc17Half)
+	// start bit, followed by the data
+	ir.manchester(1<<16|uint32(bits.Reverse16(data)), 17, nrc17Half, true)
+	ir.gap(period, 0)
+}
+
+// RC-MM protocol reference
+// https://www.sbprojects.net/knowledge/ir/rcmm.php
+
+const (
+	rcmmCarrier = 36000
+	rcmmCycle   = time.Second / rcmmCarrier
+	rcmmMark    = 6 * rcmmCycle
+	rcmmPeriod  = 27778 * time.Microsecond
+)
+
+// SendRCMM sends an RC-MM frame of nbits of data, MSB first. The 12, 24 and
+// 32-bit variants of the protocol are supported, any other length returns
+// an error. Every 2 bits are sent as a single space of 4 possible lengths.
+func (ir *SenderDevice) SendRCMM(data uint32, nbits int) error {
+	switch nbits {
+	case 12, 24, 32:
+	default:
+		return errInvalidLength
+	}
+	ir.start(rcmmCarrier)
+	ir.mark(15 * rcmmCycle)
+	ir.space(10 * rcmmCycle)
+	for i := nbits - 2; i >= 0; i -= 2 {
+		ir.mark(rcmmMark)
+		ir.space(time.Duration(10+6*(data>>uint(i)&3)) * rcmmCycle)
+	}
+	ir.mark(rcmmMark) // stop bit
+	ir.gap(rcmmPeriod, 0)
+	return nil
+}
+
+// XMP protocol reference
+// http://www.hifi-remote.com/wiki/index.php/XMP
+
+const (
+	xmpCarrier   = 38000
+	xmpMark      = 210 * time.Microsecond
+	xmpSpace     = 760 * time.Microsecond // space of a 0 nibble
+	xmpStep      = 136 * time.Microsecond // space added per nibble value
+	xmpHalfPause = 13800 * time.Microsecond
+	xmpPeriod    = 80 * time.Millisecond
+)
+
+// SendXMP sends a 64-bit XMP code, made of two halves of 8 nibbles each,
+// MSB first. The second nibble of each half is the checksum, which is
+// computed so that the nibbles of the half add up to 0xF.
+func (ir *SenderDevice) SendXMP(code uint64) {
+	ir.start(xmpCarrier)
+	for half := 1; half >= 0; half-- {
+		data := uint32(code >> uint(32*half))
+		var sum uint32
+		for i := 0; i < 32; i += 4 {
+			sum += data >> uint(i) & 0xF
+		}
+		sum -= data >> 24 & 0xF
+		data = data&^0x0F000000 | (0xF-sum)&0xF<<24
+		for i := 28; i >= 0; i -= 4 {
+			ir.mark(xmpMark)
+			ir.space(xmpSpace + time.Duration(data>>uint(i)&0xF)*xmpStep)
+		}
+		ir.mark(xmpMark)
+		if half == 1 {
+			ir.space(xmpHalfPause)
+		}
+	}
+	ir.gap(xmpPeriod, 0)
+}
+
+// StateTiming describes the frames of the pulse distance protocols of air
+// conditioners, which send the whole state of the unit, often tens of bytes
+// long, instead of a key code: a header mark and space, the bits of every
+// byte coded as a mark followed by a short space for a 0 or a long space for
+// a 1, a stop mark and a gap. Bits is ignored, as frames hold any number of
+// bytes, and Gap is the space sent after the stop mark, which ends frames
+// when received, see irprotocol.DistanceDecoder.
+type StateTiming struct {
+	Carrier uint32 // carrier frequency in Hz
+	irprotocol.PulseDistance
+}
+
+// SendState sends a frame of any number of bytes of state with the timing
+// t, for the air conditioner protocols that don't fit in the 32 or 64 bits
+// of the other Send methods. Protocols made of several frames send each of
+// them with SendState.
+func (ir *SenderDevice) SendState(t *StateTiming, state []byte) {
+	bt := bitTiming{
+		oneMark: t.BitMark, oneSpace: t.OneSpace,
+		zeroMark: t.BitMark, zeroSpace: t.ZeroSpace,
+	}
+	ir.start(t.Carrier)
+	if t.HeaderMark != 0 {
+		ir.mark(t.HeaderMark)
+		ir.space(t.HeaderSpace)
+	}
+	for _, b := range state {
+		ir.sendBits(uint64(b), 8, !t.MSBFirst, &bt)
+	}
+	ir.mark(t.BitMark) // stop bit
+	ir.space(t.Gap)
+}
@@ -0,0 +1,92 @@
+//go:build tinygo
+
+package irremote
+
+import (
+	"machine"
+	"time"
+)
+
+// PWM is the interface necessary for generating the IR carrier.
+type PWM interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (channel uint8, err error)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+// pwmTransmitter modulates an IR LED with a PWM channel.
+type pwmTransmitter struct {
+	pwm      PWM
+	pin      machine.Pin
+	ch       uint8
+	carrier  uint32
+	duty     uint32
+	deadline time.Time
+}
+
+// NewSender returns a new IR sender device, driving an IR LED on pin with
+// the carrier generated by pwm. The pin must be an output of pwm.
+func NewSender(pwm PWM, pin machine.Pin) SenderDevice {
+	return SenderDevice{tx: &pwmTransmitter{pwm: pwm, pin: pin}}
+}
+
+func (t *pwmTransmitter) configure() error {
+	if err := t.pwm.Configure(machine.PWMConfig{Period: uint64(time.Second) / 38000}); err != nil {
+		return err
+	}
+	ch, err := t.pwm.Channel(t.pin)
+	if err != nil {
+		return err
+	}
+	t.ch = ch
+	t.carrier = 38000
+	t.setDuty()
+	t.pwm.Set(t.ch, 0)
+	return nil
+}
+
+func (t *pwmTransmitter) start(carrier uint32) {
+	if carrier != t.carrier {
+		// Round to the nearest period, carriers like the 57.6kHz of Dish
+		// don't have a whole number of nanoseconds.
+		period := (uint64(time.Second) + uint64(carrier)/2) / uint64(carrier)
+		t.pwm.Configure(machine.PWMConfig{Period: period})
+		t.carrier = carrier
+		t.setDuty()
+	}
+	t.deadline = time.Now()
+}
+
+// setDuty sets the duty cycle of marks for the configured carrier.
+func (t *pwmTransmitter) setDuty() {
+	// a third of the period on is the usual IR LED duty cycle
+	t.duty = t.pwm.Top() / 3
+	if t.duty == 0 {
+		// At high carriers like the 455kHz of B&O, slow PWMs only have a
+		// few steps per period.
+		t.duty = 1
+	}
+}
+
+func (t *pwmTransmitter) mark(d time.Duration) {
+	t.pwm.Set(t.ch, t.duty)
+	t.wait(d)
+}
+
+func (t *pwmTransmitter) space(d time.Duration) {
+	t.pwm.Set(t.ch, 0)
+	t.wait(d)
+}
+
+// wait waits until d after the previous deadline, so that the time spent
+// between pulses doesn't add up.
+func (t *pwmTransmitter) wait(d time.Duration) {
+	t.deadline = t.deadline.Add(d)
+	if remaining := time.Until(t.deadline); remaining > 2*time.Millisecond {
+		// sleep through long gaps, spin for accuracy at the end
+		time.Sleep(remaining - time.Millisecond)
+	}
+	for time.Now().Before(t.deadline) {
+	}
+}
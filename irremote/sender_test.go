@@ -0,0 +1,616 @@
+package irremote
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+// recorder is a transmitter that records the signal as durations in µs,
+// positive for marks and negative for spaces. Spaces before the first mark
+// are dropped, consecutive marks or spaces are merged.
+type recorder struct {
+	carrier uint32
+	pulses  []int32
+}
+
+func (r *recorder) configure() error { return nil }
+
+func (r *recorder) start(carrier uint32) {
+	r.carrier = carrier
+}
+
+func (r *recorder) mark(d time.Duration) {
+	r.add(int32(d / time.Microsecond))
+}
+
+func (r *recorder) space(d time.Duration) {
+	if len(r.pulses) > 0 {
+		r.add(-int32(d / time.Microsecond))
+	}
+}
+
+func (r *recorder) add(us int32) {
+	if n := len(r.pulses); n > 0 && (r.pulses[n-1] > 0) == (us > 0) {
+		r.pulses[n-1] += us
+		return
+	}
+	r.pulses = append(r.pulses, us)
+}
+
+func newTestSender() (*SenderDevice, *recorder) {
+	r := &recorder{}
+	return &SenderDevice{tx: r}, r
+}
+
+// total returns the duration of the recorded signal in µs.
+func (r *recorder) total() int32 {
+	var t int32
+	for _, p := range r.pulses {
+		if p < 0 {
+			p = -p
+		}
+		t += p
+	}
+	return t
+}
+
+// biphase decodes n bi-phase bits of the recorded signal, with a half bit of
+// length half. RC-5 style (space then mark for a 1) unless markFirst.
+func (r *recorder) biphase(c *qt.C, n int, half int32, markFirst bool) uint32 {
+	// expand the signal to half bits
+	var halves []bool
+	for _, p := range r.pulses {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		for n := (p + half/2) / half; n > 0; n-- {
+			halves = append(halves, mark)
+		}
+	}
+	var bits uint32
+	for i := 0; i < 2*n; i += 2 {
+		c.Assert(halves[i] != halves[i+1], qt.IsTrue, qt.Commentf("half bits %d", i))
+		bits <<= 1
+		if halves[i] == markFirst {
+			bits |= 1
+		}
+	}
+	return bits
+}
+
+func TestSendRC5(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendRC5(0x05, 0x35, true)
+	c.Assert(r.carrier, qt.Equals, uint32(36000))
+
+	// the leading space of the first start bit is idle time
+	r.pulses = append([]int32{-889}, r.pulses...)
+	c.Assert(r.biphase(c, 14, 889, false), qt.Equals, uint32(0b11_1_00101_110101))
+	// the frame repeats every 113.778ms
+	c.Assert(r.total(), qt.Equals, int32(113778))
+
+	// extended command, the second start bit is the inverted bit 6
+	ir, r = newTestSender()
+	ir.SendRC5(0x00, 0x45, false)
+	c.Assert(r.pulses[:3], qt.DeepEquals, []int32{1778, -889, 889})
+}
+
+// levels returns the recorded signal as one character per unit of time, M
+// for a mark and _ for a space, up to n units.
+func (r *recorder) levels(unit int32, n int) string {
+	var b []byte
+	for _, p := range r.pulses {
+		ch := byte('M')
+		if p < 0 {
+			ch, p = '_', -p
+		}
+		for k := (p + unit/2) / unit; k > 0 && len(b) < n; k-- {
+			b = append(b, ch)
+		}
+	}
+	return string(b)
+}
+
+// rc6Bits returns the RC-6 bi-phase levels of the n low bits of v.
+func rc6Bits(v uint32, n int) string {
+	var s string
+	for i := n - 1; i >= 0; i-- {
+		if v>>uint(i)&1 != 0 {
+			s += "M_"
+		} else {
+			s += "_M"
+		}
+	}
+	return s
+}
+
+func TestSendRC6(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendRC6(0x04, 0x0C, true)
+	c.Assert(r.carrier, qt.Equals, uint32(36000))
+
+	// leader, start bit, mode 0, double width toggle, address, command
+	want := "MMMMMM__" + "M_" + rc6Bits(0, 3) + "MM__" + rc6Bits(0x04, 8) + rc6Bits(0x0C, 8)
+	c.Assert(r.levels(444, len(want)), qt.Equals, want)
+	c.Assert(r.total(), qt.Equals, int32(107000))
+}
+
+func TestSendRC6A(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendMCE(0x04, 0x0D, true)
+	want := "MMMMMM__" + "M_" + rc6Bits(6, 3) + "__MM" + rc6Bits(0x800F840D, 32)
+	c.Assert(r.levels(444, len(want)), qt.Equals, want)
+	c.Assert(r.total(), qt.Equals, int32(107000))
+
+	// short customer code
+	ir, r = newTestSender()
+	ir.SendRC6A(0x26, 0x01, 0x02, true)
+	want = "MMMMMM__" + "M_" + rc6Bits(6, 3) + "MM__" + rc6Bits(0x260102, 24)
+	c.Assert(r.levels(444, len(want)), qt.Equals, want)
+}
+
+// pulseBits decodes the n bits of a pulse width or distance signal from
+// pulses, which must start with the first bit, LSB first if lsbFirst. A bit
+// is a 1 if its mark (pulse width) or space (pulse distance) is longer than
+// threshold.
+func pulseBits(c *qt.C, pulses []int32, n int, lsbFirst, width bool, threshold int32) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		c.Assert(2*i+1 < len(pulses), qt.IsTrue, qt.Commentf("bit %d missing", i))
+		d := pulses[2*i]
+		if !width {
+			d = -pulses[2*i+1]
+		}
+		var bit uint64
+		if d > threshold {
+			bit = 1
+		}
+		if lsbFirst {
+			v |= bit << uint(i)
+		} else {
+			v = v<<1 | bit
+		}
+	}
+	return v
+}
+
+func TestSendSIRC12(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendSIRC12(0x01, 0x15) // TV, power
+	c.Assert(r.carrier, qt.Equals, uint32(40000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{2400, -600})
+	c.Assert(pulseBits(c, r.pulses[2:], 12, true, true, 900), qt.Equals, uint64(0x01<<7|0x15))
+	// sent three times, every 45ms
+	c.Assert(r.total(), qt.Equals, int32(3*45000))
+	c.Assert(r.pulses[2+24:2+24+2], qt.DeepEquals, r.pulses[:2])
+}
+
+func TestSendSIRC15And20(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendSIRC15(0x97, 0x1A)
+	c.Assert(pulseBits(c, r.pulses[2:], 15, true, true, 900), qt.Equals, uint64(0x97<<7|0x1A))
+	c.Assert(r.total(), qt.Equals, int32(3*45000))
+
+	ir, r = newTestSender()
+	ir.SendSIRC20(0x1A, 0x7B, 0xE5)
+	c.Assert(pulseBits(c, r.pulses[2:], 20, true, true, 900), qt.Equals, uint64(0xE5<<12|0x1A<<7|0x7B))
+	c.Assert(r.total(), qt.Equals, int32(3*45000))
+}
+
+func TestSendSamsung(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendSamsung(0x0707, 0x02) // power
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{4500, -4500})
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1000), qt.Equals, uint64(0xFD020707))
+	c.Assert(r.pulses[2+64], qt.Equals, int32(560))
+	c.Assert(r.total(), qt.Equals, int32(108000))
+}
+
+func TestSendSamsung36(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendSamsung36(0x0400, 0xE13EF)
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{4500, -4500})
+	c.Assert(pulseBits(c, r.pulses[2:], 16, true, false, 1000), qt.Equals, uint64(0x0400))
+	// separator
+	c.Assert(r.pulses[2+32:2+34], qt.DeepEquals, []int32{560, -4500})
+	c.Assert(pulseBits(c, r.pulses[2+34:], 20, true, false, 1000), qt.Equals, uint64(0xE13EF))
+	c.Assert(r.total(), qt.Equals, int32(108000))
+}
+
+func TestSendKaseikyo(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendPanasonic(0x80, 0x00, 0x3D) // TV power
+	c.Assert(r.carrier, qt.Equals, uint32(37000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{3456, -1728})
+	c.Assert(pulseBits(c, r.pulses[2:], 48, true, false, 864), qt.Equals, uint64(0xBD_3D_00_80_2002))
+	c.Assert(r.total(), qt.Equals, int32(130000))
+
+	ir, r = newTestSender()
+	// vendor parity of 0x3254 is 3^2^5^4 = 0
+	ir.SendKaseikyo(0x3254, 0xA1, 0x2F5, 2)
+	frame := pulseBits(c, r.pulses[2:], 48, true, false, 864)
+	c.Assert(frame&0xFFFF, qt.Equals, uint64(0x3254))
+	c.Assert(frame>>16&0xF, qt.Equals, uint64(0))     // vendor parity
+	c.Assert(frame>>20&0xFF, qt.Equals, uint64(0xA1)) // genre
+	c.Assert(frame>>28&0x3FF, qt.Equals, uint64(0x2F5))
+	c.Assert(frame>>38&0x3, qt.Equals, uint64(2))
+	b := frame >> 16
+	c.Assert(frame>>40, qt.Equals, (b^b>>8^b>>16)&0xFF)
+}
+
+func TestSendKaseikyoVendors(t *testing.T) {
+	c := qt.New(t)
+	for _, tc := range []struct {
+		send   func(ir *SenderDevice, address uint16, command uint8)
+		vendor uint64
+		parity uint64
+	}{
+		{(*SenderDevice).SendDenonK, 0x3254, 0x0},
+		{(*SenderDevice).SendMitsubishiK, 0xCB23, 0xB ^ 0xC ^ 0x2 ^ 0x3},
+		{(*SenderDevice).SendSharpK, 0x5AAA, 0x5 ^ 0xA ^ 0xA ^ 0xA},
+		{(*SenderDevice).SendJVC48, 0x0103, 0x1 ^ 0x3},
+	} {
+		ir, r := newTestSender()
+		tc.send(ir, 0xABC, 0xDE)
+		frame := pulseBits(c, r.pulses[2:], 48, true, false, 864)
+		c.Assert(frame&0xFFFF, qt.Equals, tc.vendor)
+		c.Assert(frame>>16&0xF, qt.Equals, tc.parity)
+		c.Assert(frame>>20&0xFFF, qt.Equals, uint64(0xABC))
+		c.Assert(frame>>32&0xFF, qt.Equals, uint64(0xDE))
+		b := frame >> 16
+		c.Assert(frame>>40, qt.Equals, (b^b>>8^b>>16)&0xFF)
+	}
+}
+
+func TestSendJVC(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendJVC(0x03, 0x17, 2)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{8416, -4208})
+	c.Assert(pulseBits(c, r.pulses[2:], 16, true, false, 1052), qt.Equals, uint64(0x1703))
+	// header-less repeats, one every 55ms
+	p := r.pulses[2+32:]
+	for i := 0; i < 2; i++ {
+		c.Assert(p[0], qt.Equals, int32(526))
+		c.Assert(p[1] < -10000, qt.IsTrue)
+		p = p[2:]
+		c.Assert(pulseBits(c, p, 16, true, false, 1052), qt.Equals, uint64(0x1703))
+		p = p[32:]
+	}
+	c.Assert(p, qt.DeepEquals, []int32{526, p[1]})
+	c.Assert(r.total(), qt.Equals, int32(3*55000))
+}
+
+func TestSendSharp(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendSharp(0x11, 0xA5)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(pulseBits(c, r.pulses, 15, true, false, 1180), qt.Equals, uint64(0x11|0xA5<<5|1<<13))
+	c.Assert(r.pulses[30:32], qt.DeepEquals, []int32{320, -40000})
+	c.Assert(pulseBits(c, r.pulses[32:], 15, true, false, 1180), qt.Equals, uint64(0x11|0x5A<<5|1<<14))
+	c.Assert(r.pulses[62], qt.Equals, int32(320))
+}
+
+func TestSendDenon(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendDenon(0x02, 0xE1)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(pulseBits(c, r.pulses, 15, true, false, 1300), qt.Equals, uint64(0x02|0xE1<<5))
+	c.Assert(r.pulses[30:32], qt.DeepEquals, []int32{260, -45000})
+	c.Assert(pulseBits(c, r.pulses[32:], 15, true, false, 1300), qt.Equals, uint64(0x02|0x1E<<5|3<<13))
+}
+
+func TestSendLG(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendLG(0x88, 0x00C5) // power
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{9000, -4500})
+	// checksum 0x0 + 0x0 + 0xC + 0x5 = 0x11
+	c.Assert(pulseBits(c, r.pulses[2:], 28, false, false, 1120), qt.Equals, uint64(0x88_00C5_1))
+	c.Assert(r.total(), qt.Equals, int32(110000))
+}
+
+func TestSendLG2(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendLG2(0x88, 0x1234)
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{3200, -9900})
+	c.Assert(pulseBits(c, r.pulses[2:], 28, false, false, 1120), qt.Equals, uint64(0x88_1234_A))
+}
+
+func TestSendSanyo(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	c.Assert(ir.SendSanyo(0x2000, 0x01), qt.Equals, errInvalidAddress)
+	c.Assert(r.pulses, qt.HasLen, 0)
+
+	c.Assert(ir.SendSanyo(0x1234, 0x56), qt.IsNil)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{9000, -4500})
+	frame := pulseBits(c, r.pulses[2:], 42, true, false, 1120)
+	c.Assert(frame&0x1FFF, qt.Equals, uint64(0x1234))
+	c.Assert(frame>>13&0x1FFF, qt.Equals, uint64(0x1FFF^0x1234))
+	c.Assert(frame>>26, qt.Equals, uint64(0xA956))
+	c.Assert(r.total(), qt.Equals, int32(108000))
+}
+
+func TestSendMitsubishi(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendMitsubishi(0xE2, 0x02)
+	c.Assert(r.carrier, qt.Equals, uint32(33000))
+	c.Assert(pulseBits(c, r.pulses, 16, false, false, 1500), qt.Equals, uint64(0xE202))
+	c.Assert(r.pulses[33] <= -28000, qt.IsTrue)
+	c.Assert(pulseBits(c, r.pulses[34:], 16, false, false, 1500), qt.Equals, uint64(0xE202))
+	c.Assert(r.total(), qt.Equals, int32(2*53560))
+}
+
+func TestSendNEC(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendNEC(0x04, 0x08, 2)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{9000, -4500})
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1120), qt.Equals, uint64(0xF708FB04))
+	// repeat codes, one every 108ms
+	c.Assert(r.pulses[68:], qt.DeepEquals, []int32{
+		9000, -2250, 560, -96190,
+		9000, -2250, 560, -96190,
+	})
+	c.Assert(r.total(), qt.Equals, int32(3*108000))
+
+	ir, r = newTestSender()
+	ir.SendNEC(0x1234, 0x08, 0)
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1120), qt.Equals, uint64(0xF7081234))
+}
+
+func TestSendPioneer(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendPioneer(0xE11EAA55, 0xF50AAA55)
+	c.Assert(r.carrier, qt.Equals, uint32(40000))
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1120), qt.Equals, uint64(0xE11EAA55))
+	c.Assert(pulseBits(c, r.pulses[70:], 32, true, false, 1120), qt.Equals, uint64(0xF50AAA55))
+	c.Assert(r.total(), qt.Equals, int32(2*108000))
+}
+
+func TestSendApple(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(MakeAppleCode(0x02, 0x5A), qt.Equals, uint32(0x5A0287EE))
+	ir, r := newTestSender()
+	ir.SendApple(0x02, 0x5A, 1)
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1120), qt.Equals, uint64(0x5A0287EE))
+	c.Assert(r.pulses[68:71], qt.DeepEquals, []int32{9000, -2250, 560})
+}
+
+func TestSendNEC16Command(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(MakeNEC16Code(0xD2, 0x1234), qt.Equals, uint32(0x12342DD2))
+	c.Assert(MakeNEC16Code(0x6DD2, 0x1234), qt.Equals, uint32(0x12346DD2))
+	ir, r := newTestSender()
+	ir.SendNEC16Command(0x6DD2, 0x0102, 0)
+	c.Assert(pulseBits(c, r.pulses[2:], 32, true, false, 1120), qt.Equals, uint64(0x01026DD2))
+}
+
+func TestSendNECFullFrameRepeat(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.NECRepeat = NECRepeatFullFrame
+	ir.SendNEC(0x04, 0x08, 2)
+	for i := 0; i < 3; i++ {
+		p := r.pulses[68*i:]
+		c.Assert(p[:2], qt.DeepEquals, []int32{9000, -4500})
+		c.Assert(pulseBits(c, p[2:], 32, true, false, 1120), qt.Equals, uint64(0xF708FB04))
+	}
+	c.Assert(r.pulses, qt.HasLen, 3*68)
+	c.Assert(r.total(), qt.Equals, int32(3*108000))
+}
+
+func TestSendDish(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendDish(0x9C00)
+	c.Assert(r.carrier, qt.Equals, uint32(57600))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{400, -6100})
+	for i := 0; i < 4; i++ {
+		p := r.pulses[2+34*i:]
+		// a 1 is the shorter space
+		c.Assert(pulseBits(c, p, 16, false, false, 2250), qt.Equals, uint64(^uint16(0x9C00)))
+		c.Assert(p[32:34], qt.DeepEquals, []int32{400, -6100})
+	}
+}
+
+func TestSendBose(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendBose(0x4C)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{1060, -1425})
+	c.Assert(pulseBits(c, r.pulses[2:], 16, true, false, 968), qt.Equals, uint64(0xB34C))
+	c.Assert(r.total(), qt.Equals, int32(75000))
+}
+
+func TestSendBangOlufsen(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendBangOlufsen(0x00, 0x0C)
+	c.Assert(r.carrier, qt.Equals, uint32(455000))
+	// distances between marks in units
+	var units []int32
+	for i := 0; i < len(r.pulses); i += 2 {
+		c.Assert(r.pulses[i], qt.Equals, int32(200))
+		if i+1 < len(r.pulses) {
+			units = append(units, (r.pulses[i]-r.pulses[i+1])/3125)
+		}
+	}
+	c.Assert(units, qt.DeepEquals, []int32{
+		1, 1, 4, 2, // header
+		1, 3, 3, 3, 3, 3, 3, 3, // address 0x00
+		3, 3, 3, 3, 2, 3, 1, 3, // command 0x0C
+		5, 1, // trailer
+	})
+}
+
+func TestSendRCA(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendRCA(0x0F, 0x54)
+	c.Assert(r.carrier, qt.Equals, uint32(56000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{4000, -4000})
+	c.Assert(pulseBits(c, r.pulses[2:], 24, false, false, 1500), qt.Equals, uint64(0xF540AB))
+	// 56.5ms long, so the minimum gap applies
+	c.Assert(r.pulses[len(r.pulses)-1], qt.Equals, int32(-8000))
+}
+
+func TestSendWhynter(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendWhynter(0x87654321)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:4], qt.DeepEquals, []int32{750, -750, 2850, -2850})
+	c.Assert(pulseBits(c, r.pulses[4:], 32, false, false, 1450), qt.Equals, uint64(0x87654321))
+	c.Assert(r.total(), qt.Equals, int32(108000))
+}
+
+func TestSendMagiQuest(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendMagiQuest(0x12345678, 0x0101)
+	c.Assert(r.carrier, qt.Equals, uint32(36000))
+	frame := pulseBits(c, r.pulses, 56, false, true, 432)
+	c.Assert(frame>>48, qt.Equals, uint64(0))
+	c.Assert(frame>>17&0x7FFFFFFF, qt.Equals, uint64(0x12345678))
+	c.Assert(frame>>8&0x1FF, qt.Equals, uint64(0x101))
+	// all bytes including the checksum add up to 0
+	c.Assert(uint8(0x12+0x34+0x56+0x78+0x01+0x01+frame), qt.Equals, uint8(0))
+}
+
+func TestSendNRC17(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendNRC17(0x3, 0x0, 0x25, 1)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	msg := func(v uint32) string {
+		// pre-pulse and start bit
+		return "M_____" + rc6Bits(1<<16|v, 17)
+	}
+	// start message, then 40ms later the command
+	levels := r.levels(500, 80+200+200+200)
+	c.Assert(levels[:40], qt.Equals, msg(0b0111_1111_1111_1111))
+	c.Assert(levels[40:80], qt.Matches, "_*")
+	// command 0x25, address 3 and subcode 0, LSB first
+	command := msg(0b1010_0100_1100_0000)
+	c.Assert(levels[80:120], qt.Equals, command)
+	c.Assert(levels[280:320], qt.Equals, command)
+	c.Assert(levels[480:520], qt.Equals, msg(0b0111_1111_1111_1111))
+	c.Assert(r.total(), qt.Equals, int32(40000+3*100000))
+}
+
+func TestSendRCMM(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	c.Assert(ir.SendRCMM(0x123, 16), qt.Equals, errInvalidLength)
+	c.Assert(r.pulses, qt.HasLen, 0)
+
+	for _, nbits := range []int{12, 24, 32} {
+		ir, r := newTestSender()
+		data := uint32(0xE4E4E4E4) >> uint(32-nbits)
+		c.Assert(ir.SendRCMM(data, nbits), qt.IsNil)
+		c.Assert(r.carrier, qt.Equals, uint32(36000))
+		c.Assert(r.pulses[:2], qt.DeepEquals, []int32{416, -277})
+		var got uint32
+		for i := 0; i < nbits/2; i++ {
+			c.Assert(r.pulses[2+2*i], qt.Equals, int32(166))
+			got = got<<2 | uint32((-r.pulses[3+2*i]-277+83)/167)
+		}
+		c.Assert(got, qt.Equals, data)
+		// the recorder truncates each pulse to whole µs
+		c.Assert(27778-r.total() < int32(len(r.pulses)), qt.IsTrue)
+	}
+}
+
+func TestSendXMP(t *testing.T) {
+	c := qt.New(t)
+	ir, r := newTestSender()
+	ir.SendXMP(0x1A44_0000_1E44_5600)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	nibbles := func(p []int32) (v uint32) {
+		for i := 0; i < 8; i++ {
+			c.Assert(p[2*i], qt.Equals, int32(210))
+			v = v<<4 | uint32((-p[2*i+1]-760+68)/136)
+		}
+		c.Assert(p[16], qt.Equals, int32(210))
+		return v
+	}
+	// checksums: 1+4+4 = 9 -> 6, 1+4+4+5+6 = 20 -> 0xB
+	c.Assert(nibbles(r.pulses), qt.Equals, uint32(0x16440000))
+	c.Assert(r.pulses[17], qt.Equals, int32(-13800))
+	c.Assert(nibbles(r.pulses[18:]), qt.Equals, uint32(0x1B445600))
+	c.Assert(r.total(), qt.Equals, int32(80000))
+}
+
+func TestSendState(t *testing.T) {
+	c := qt.New(t)
+	timing := StateTiming{
+		Carrier: 38000,
+		PulseDistance: irprotocol.PulseDistance{
+			HeaderMark: 3500 * time.Microsecond, HeaderSpace: 1700 * time.Microsecond,
+			BitMark:  430 * time.Microsecond,
+			OneSpace: 1300 * time.Microsecond, ZeroSpace: 430 * time.Microsecond,
+			Gap: 30 * time.Millisecond,
+		},
+	}
+	// longer than any integer code
+	state := []byte{0x11, 0xDA, 0x27, 0x00, 0xC5, 0x00, 0x00, 0xD7, 0x01, 0x80}
+	bits := func(p []int32, msbFirst bool) []byte {
+		got := make([]byte, len(state))
+		for i := range got {
+			for j := 0; j < 8; j++ {
+				c.Assert(p[2*(8*i+j)], qt.Equals, int32(430))
+				bit := byte(0)
+				if p[2*(8*i+j)+1] == -1300 {
+					bit = 1
+				}
+				if msbFirst {
+					got[i] |= bit << uint(7-j)
+				} else {
+					got[i] |= bit << uint(j)
+				}
+			}
+		}
+		return got
+	}
+
+	ir, r := newTestSender()
+	ir.SendState(&timing, state)
+	c.Assert(r.carrier, qt.Equals, uint32(38000))
+	c.Assert(r.pulses[:2], qt.DeepEquals, []int32{3500, -1700})
+	c.Assert(bits(r.pulses[2:], false), qt.DeepEquals, state)
+	c.Assert(r.pulses[2+16*len(state):], qt.DeepEquals, []int32{430, -30000})
+
+	// without header, MSB first
+	timing.HeaderMark = 0
+	timing.MSBFirst = true
+	ir, r = newTestSender()
+	ir.SendState(&timing, state)
+	c.Assert(bits(r.pulses, true), qt.DeepEquals, state)
+	c.Assert(r.pulses, qt.HasLen, 16*len(state)+2)
+}
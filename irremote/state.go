@@ -0,0 +1,52 @@
+package irremote // import "tinygo.org/x/drivers/irremote"
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+// StateHandler defines the callback function used to provide the state
+// frames received by the ReceiverDevice, see SetStateHandler. The state is
+// only valid until the next frame starts.
+type StateHandler func(state []byte)
+
+// StateDecoder decodes the long frames of the air conditioner protocols
+// described by Timing, see SendState, into State. Frames of any number of
+// bytes up to len(State) are received, longer ones are dropped. A frame ends
+// with the gap after its stop mark, see End.
+type StateDecoder struct {
+	Timing *StateTiming
+	State  []byte
+
+	frame irprotocol.DistanceDecoder
+}
+
+// Reset discards a partially received frame.
+func (r *StateDecoder) Reset() {
+	r.frame.Reset()
+}
+
+// Pulse handles a mark or space of duration d. It returns the number of
+// bytes of the frame received into State once it has ended, or 0.
+func (r *StateDecoder) Pulse(mark bool, d time.Duration) int {
+	r.frame.Timing = &r.Timing.PulseDistance
+	r.frame.Bytes = r.State
+	if !r.frame.Pulse(mark, d) {
+		return 0
+	}
+	return r.frame.Len() / 8
+}
+
+// End handles the line having been idle for d after the last mark, as the
+// gap after the last frame is only known once the next one starts. Like
+// Pulse, it returns the number of bytes of a frame ended by this gap, or 0
+// if d may still be the space of a bit.
+func (r *StateDecoder) End(d time.Duration) int {
+	r.frame.Timing = &r.Timing.PulseDistance
+	r.frame.Bytes = r.State
+	if !r.frame.End(d) {
+		return 0
+	}
+	return r.frame.Len() / 8
+}
@@ -0,0 +1,75 @@
+package irremote
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/irremote/irprotocol"
+)
+
+var testStateTiming = StateTiming{
+	Carrier: 38000,
+	PulseDistance: irprotocol.PulseDistance{
+		HeaderMark: 3500 * time.Microsecond, HeaderSpace: 1700 * time.Microsecond,
+		BitMark:  430 * time.Microsecond,
+		OneSpace: 1300 * time.Microsecond, ZeroSpace: 430 * time.Microsecond,
+		Gap: 30 * time.Millisecond,
+	},
+}
+
+// receiveStates feeds the recorded pulses to r, and returns the received
+// frames, including one ended by the line staying idle.
+func receiveStates(r *StateDecoder, pulses []int32) [][]byte {
+	var frames [][]byte
+	add := func(n int) {
+		if n > 0 {
+			frames = append(frames, append([]byte(nil), r.State[:n]...))
+		}
+	}
+	for _, p := range pulses {
+		mark := p > 0
+		if p < 0 {
+			p = -p
+		}
+		add(r.Pulse(mark, time.Duration(p)*time.Microsecond))
+	}
+	add(r.End(time.Second))
+	return frames
+}
+
+func TestStateDecoder(t *testing.T) {
+	c := qt.New(t)
+	long := []byte{0x11, 0xDA, 0x27, 0x00, 0x42, 0x00, 0x00, 0x54, 0x01, 0x80, 0x9C, 0xF0}
+	short := []byte{0x11, 0xDA, 0x27, 0x00, 0x02}
+	ir, rec := newTestSender()
+	ir.SendState(&testStateTiming, short)
+	ir.SendState(&testStateTiming, long)
+	ir.SendState(&testStateTiming, short)
+
+	// frames up to the length of State
+	r := &StateDecoder{Timing: &testStateTiming, State: make([]byte, len(long))}
+	c.Assert(receiveStates(r, rec.pulses), qt.DeepEquals, [][]byte{short, long, short})
+
+	// frames too long for State are dropped
+	r = &StateDecoder{Timing: &testStateTiming, State: make([]byte, len(short))}
+	c.Assert(receiveStates(r, rec.pulses), qt.DeepEquals, [][]byte{short, short})
+
+	// the spaces of bits don't end a frame
+	r.Reset()
+	r.Pulse(true, testStateTiming.HeaderMark)
+	r.Pulse(false, testStateTiming.HeaderSpace)
+	r.Pulse(true, testStateTiming.BitMark)
+	c.Assert(r.End(testStateTiming.OneSpace), qt.Equals, 0)
+	c.Assert(r.End(time.Second), qt.Equals, 0) // not a whole byte
+
+	// MSB first, without header
+	timing := testStateTiming
+	timing.HeaderMark = 0
+	timing.MSBFirst = true
+	ir, rec = newTestSender()
+	ir.SendState(&timing, long)
+	r = &StateDecoder{Timing: &timing, State: make([]byte, 32)}
+	c.Assert(receiveStates(r, rec.pulses), qt.DeepEquals, [][]byte{long})
+}
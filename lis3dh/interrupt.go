@@ -0,0 +1,77 @@
+package lis3dh
+
+import "tinygo.org/x/drivers/events"
+
+// CTRL_REG3 INT1 sources and CTRL_REG5 latch bit.
+const (
+	ctrl3IA1   = 1 << 6
+	ctrl3ZYXDA = 1 << 4
+	ctrl5LIR1  = 1 << 3
+)
+
+// INT1_CFG high event enables for all axes, combined with OR.
+const int1CfgHighEvents = 0x2A
+
+// EnableDataReadyInterrupt raises INT1 when a new sample is available. Read
+// the sample to clear it.
+func (d *Device) EnableDataReadyInterrupt() {
+	d.bus.WriteRegister(uint8(d.Address), REG_CTRL3, []byte{ctrl3ZYXDA})
+	d.intKind = events.DataReady
+}
+
+// EnableMotionInterrupt raises INT1 when the acceleration on any axis exceeds
+// threshold (in µg) for at least duration samples. The interrupt is latched
+// until ReadInterruptSource is called.
+func (d *Device) EnableMotionInterrupt(threshold int32, duration uint8) {
+	// threshold resolution depends on the range, in µg per LSB
+	var step int32
+	switch d.r {
+	case RANGE_16_G:
+		step = 186000
+	case RANGE_8_G:
+		step = 62000
+	case RANGE_4_G:
+		step = 32000
+	default:
+		step = 16000
+	}
+	ths := threshold / step
+	if ths > 0x7F {
+		ths = 0x7F
+	}
+	d.bus.WriteRegister(uint8(d.Address), REG_INT1THS, []byte{uint8(ths)})
+	d.bus.WriteRegister(uint8(d.Address), REG_INT1DUR, []byte{duration & 0x7F})
+	d.bus.WriteRegister(uint8(d.Address), REG_INT1CFG, []byte{int1CfgHighEvents})
+	d.updateRegister(REG_CTRL5, ctrl5LIR1, ctrl5LIR1)
+	d.bus.WriteRegister(uint8(d.Address), REG_CTRL3, []byte{ctrl3IA1})
+	d.intKind = events.Motion
+}
+
+// DisableInterrupts disables all INT1 sources.
+func (d *Device) DisableInterrupts() {
+	d.bus.WriteRegister(uint8(d.Address), REG_CTRL3, []byte{0})
+	d.bus.WriteRegister(uint8(d.Address), REG_INT1CFG, []byte{0})
+	d.intKind = events.KindNone
+}
+
+// ReadInterruptSource reads INT1_SRC, which reports the axes that caused a
+// motion interrupt and releases a latched interrupt.
+func (d *Device) ReadInterruptSource() uint8 {
+	data := []byte{0}
+	d.bus.ReadRegister(uint8(d.Address), REG_INT1SRC, data)
+	return data[0]
+}
+
+// InterruptEvent returns the event to post when INT1 rises, for use with
+// events.Dispatcher.Attach. Its kind depends on the enabled interrupt.
+func (d *Device) InterruptEvent(source uint8) events.Event {
+	return events.Event{Kind: d.intKind, Source: source}
+}
+
+// updateRegister changes the bits in mask of a register to value.
+func (d *Device) updateRegister(reg uint8, mask, value uint8) {
+	data := []byte{0}
+	d.bus.ReadRegister(uint8(d.Address), reg, data)
+	data[0] = data[0]&^mask | value&mask
+	d.bus.WriteRegister(uint8(d.Address), reg, data)
+}
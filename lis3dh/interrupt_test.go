@@ -0,0 +1,29 @@
+package lis3dh
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/events"
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestMotionInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(Address0)
+	dev := New(bus)
+	dev.r = RANGE_4_G
+
+	dev.EnableMotionInterrupt(500000, 2)
+	c.Assert(fake.Registers[REG_INT1THS], qt.Equals, uint8(15))
+	c.Assert(fake.Registers[REG_INT1DUR], qt.Equals, uint8(2))
+	c.Assert(fake.Registers[REG_INT1CFG], qt.Equals, uint8(0x2A))
+	c.Assert(fake.Registers[REG_CTRL5]&ctrl5LIR1, qt.Not(qt.Equals), uint8(0))
+	c.Assert(fake.Registers[REG_CTRL3], qt.Equals, uint8(ctrl3IA1))
+	c.Assert(dev.InterruptEvent(3), qt.Equals, events.Event{Kind: events.Motion, Source: 3})
+
+	dev.EnableDataReadyInterrupt()
+	c.Assert(fake.Registers[REG_CTRL3], qt.Equals, uint8(ctrl3ZYXDA))
+	c.Assert(dev.InterruptEvent(0).Kind, qt.Equals, events.DataReady)
+}
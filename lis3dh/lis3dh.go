@@ -3,13 +3,17 @@
 // Datasheet: https://www.st.com/resource/en/datasheet/lis3dh.pdf
 package lis3dh // import "tinygo.org/x/drivers/lis3dh"
 
-import "tinygo.org/x/drivers"
+import (
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/events"
+)
 
 // Device wraps an I2C connection to a LIS3DH device.
 type Device struct {
 	bus     drivers.I2C
 	Address uint16
 	r       Range
+	intKind events.Kind
 }
 
 // New creates a new LIS3DH connection. The I2C bus must already be configured.
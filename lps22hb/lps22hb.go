@@ -23,7 +23,9 @@ func New(bus drivers.I2C) Device {
 
 // ReadPressure returns the pressure in milli pascals (mPa).
 func (d *Device) ReadPressure() (pressure int32, err error) {
-	d.waitForOneShot()
+	if err := d.waitForOneShot(); err != nil {
+		return 0, err
+	}
 
 	// read data
 	data := []byte{0, 0, 0}
@@ -45,7 +47,9 @@ func (d *Device) Connected() bool {
 
 // ReadTemperature returns the temperature in celsius milli degrees (°C/1000).
 func (d *Device) ReadTemperature() (temperature int32, err error) {
-	d.waitForOneShot()
+	if err := d.waitForOneShot(); err != nil {
+		return 0, err
+	}
 
 	// read data
 	data := []byte{0, 0}
@@ -59,16 +63,14 @@ func (d *Device) ReadTemperature() (temperature int32, err error) {
 // private functions
 
 // wait and trigger one shot in block update
-func (d *Device) waitForOneShot() {
+func (d *Device) waitForOneShot() error {
 	// trigger one shot
 	d.bus.WriteRegister(d.Address, LPS22HB_CTRL2_REG, []byte{0x01})
 
 	// wait until one shot is cleared
 	data := []byte{1}
-	for {
-		d.bus.ReadRegister(d.Address, LPS22HB_CTRL2_REG, data)
-		if data[0]&0x01 == 0 {
-			break
-		}
-	}
+	return drivers.DefaultRetryPolicy.Until(func() (bool, error) {
+		err := d.bus.ReadRegister(d.Address, LPS22HB_CTRL2_REG, data)
+		return data[0]&0x01 == 0, err
+	})
 }
@@ -0,0 +1,124 @@
+package mcp23017
+
+// IOCON register bits. BANK and SEQOP are left at zero as the driver relies
+// on the interleaved register layout and sequential addressing.
+const (
+	ioconMirror = 1 << 6 // INTA and INTB are internally connected.
+	ioconODR    = 1 << 2 // INT pins are open-drain outputs.
+	ioconINTPOL = 1 << 1 // INT pins are active-high.
+)
+
+// InterruptMode represents the condition that causes a pin to
+// raise an interrupt.
+type InterruptMode uint8
+
+const (
+	// InterruptDisabled disables the interrupt for the pin.
+	InterruptDisabled = InterruptMode(iota)
+	// InterruptOnChange raises an interrupt whenever the pin value changes.
+	InterruptOnChange
+	// InterruptWhenLow raises an interrupt while the pin is low.
+	InterruptWhenLow
+	// InterruptWhenHigh raises an interrupt while the pin is high.
+	InterruptWhenHigh
+)
+
+// InterruptConfig holds the configuration of the INTA and INTB pins.
+type InterruptConfig struct {
+	// Mirror connects INTA and INTB, so that either pin signals
+	// interrupts from both ports.
+	Mirror bool
+	// OpenDrain configures the INT pins as open-drain outputs,
+	// which allows several devices to share one interrupt line.
+	// It overrides ActiveHigh.
+	OpenDrain bool
+	// ActiveHigh makes the INT pins active-high rather than
+	// active-low.
+	ActiveHigh bool
+}
+
+// SetInterruptConfig configures the behavior of the INTA and INTB pins.
+func (d *Device) SetInterruptConfig(cfg InterruptConfig) error {
+	var iocon uint8
+	if cfg.Mirror {
+		iocon |= ioconMirror
+	}
+	if cfg.OpenDrain {
+		iocon |= ioconODR
+	}
+	if cfg.ActiveHigh {
+		iocon |= ioconINTPOL
+	}
+	return d.bus.WriteRegister(d.addr, uint8(rIOCON), []byte{iocon})
+}
+
+// SetInterrupts configures the interrupt-on-change behavior of all pins.
+// Pins set in enabled raise an interrupt. Of those, pins set in compare
+// raise an interrupt while their value differs from the corresponding
+// bit in defval; the others raise an interrupt whenever their value
+// changes.
+func (d *Device) SetInterrupts(enabled, compare, defval Pins) error {
+	// Disable interrupts first so that changing the comparison
+	// doesn't raise spurious interrupts.
+	if err := d.writeRegisterAB(rGPINTEN, 0); err != nil {
+		return err
+	}
+	if err := d.writeRegisterAB(rDEFVAL, defval); err != nil {
+		return err
+	}
+	if err := d.writeRegisterAB(rINTCON, compare); err != nil {
+		return err
+	}
+	return d.writeRegisterAB(rGPINTEN, enabled)
+}
+
+// GetInterrupts returns the interrupt configuration of all pins
+// as set by SetInterrupts.
+func (d *Device) GetInterrupts() (enabled, compare, defval Pins, err error) {
+	if enabled, err = d.readRegisterAB(rGPINTEN); err != nil {
+		return
+	}
+	if compare, err = d.readRegisterAB(rINTCON); err != nil {
+		return
+	}
+	defval, err = d.readRegisterAB(rDEFVAL)
+	return
+}
+
+// ReadInterrupt returns the pins that caused the pending interrupt and
+// the values of all pins captured when it occurred. Reading the
+// captured values clears the interrupt.
+func (d *Device) ReadInterrupt() (flags, captured Pins, err error) {
+	// INTFA, INTFB, INTCAPA and INTCAPB are consecutive, so read
+	// them in a single operation.
+	var buf [4]byte
+	if err := d.bus.ReadRegister(d.addr, uint8(rINTF), buf[:]); err != nil {
+		return 0, 0, err
+	}
+	flags = Pins(buf[0]) | Pins(buf[1])<<8
+	captured = Pins(buf[2]) | Pins(buf[3])<<8
+	return flags, captured, nil
+}
+
+// SetInterrupt configures the interrupt behavior of the pin.
+func (p Pin) SetInterrupt(mode InterruptMode) error {
+	enabled, compare, defval, err := p.dev.GetInterrupts()
+	if err != nil {
+		return err
+	}
+	enabled &^= p.mask
+	compare &^= p.mask
+	defval &^= p.mask
+	switch mode {
+	case InterruptOnChange:
+		enabled |= p.mask
+	case InterruptWhenLow:
+		enabled |= p.mask
+		compare |= p.mask
+		defval |= p.mask
+	case InterruptWhenHigh:
+		enabled |= p.mask
+		compare |= p.mask
+	}
+	return p.dev.SetInterrupts(enabled, compare, defval)
+}
@@ -0,0 +1,64 @@
+package mcp23017
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestSetInterruptConfig(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := newDevice(bus, 0x20)
+	dev, err := NewI2C(bus, 0x20)
+	c.Assert(err, qt.IsNil)
+	err = dev.SetInterruptConfig(InterruptConfig{Mirror: true, ActiveHigh: true})
+	c.Assert(err, qt.IsNil)
+	c.Assert(fdev.Registers[rIOCON], qt.Equals, uint8(0b0100_0010))
+}
+
+func TestPinSetInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := newDevice(bus, 0x20)
+	dev, err := NewI2C(bus, 0x20)
+	c.Assert(err, qt.IsNil)
+
+	err = dev.Pin(1).SetInterrupt(InterruptOnChange)
+	c.Assert(err, qt.IsNil)
+	err = dev.Pin(2).SetInterrupt(InterruptWhenLow)
+	c.Assert(err, qt.IsNil)
+	err = dev.Pin(9).SetInterrupt(InterruptWhenHigh)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fdev.Registers[rGPINTEN], qt.Equals, uint8(0b110))
+	c.Assert(fdev.Registers[rGPINTEN|portB], qt.Equals, uint8(0b10))
+	c.Assert(fdev.Registers[rINTCON], qt.Equals, uint8(0b100))
+	c.Assert(fdev.Registers[rINTCON|portB], qt.Equals, uint8(0b10))
+	c.Assert(fdev.Registers[rDEFVAL], qt.Equals, uint8(0b100))
+	c.Assert(fdev.Registers[rDEFVAL|portB], qt.Equals, uint8(0))
+
+	err = dev.Pin(2).SetInterrupt(InterruptDisabled)
+	c.Assert(err, qt.IsNil)
+	enabled, compare, defval, err := dev.GetInterrupts()
+	c.Assert(err, qt.IsNil)
+	c.Assert(enabled, qt.Equals, Pins(0b10_00000010))
+	c.Assert(compare, qt.Equals, Pins(0b10_00000000))
+	c.Assert(defval, qt.Equals, Pins(0))
+}
+
+func TestReadInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := newDevice(bus, 0x20)
+	fdev.Registers[rINTF] = 0b1000
+	fdev.Registers[rINTCAP] = 0b1010
+	fdev.Registers[rINTCAP|portB] = 0b1
+	dev, err := NewI2C(bus, 0x20)
+	c.Assert(err, qt.IsNil)
+	flags, captured, err := dev.ReadInterrupt()
+	c.Assert(err, qt.IsNil)
+	c.Assert(flags, qt.Equals, Pins(0b1000))
+	c.Assert(captured, qt.Equals, Pins(0b1_00001010))
+}
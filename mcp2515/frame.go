@@ -0,0 +1,211 @@
+package mcp2515
+
+import (
+	"errors"
+	"machine"
+)
+
+var errInvalidFilter = errors.New("invalid filter or mask number")
+
+// rxQueueSize is the number of received frames that can be queued by Poll
+// before the oldest ones are dropped.
+const rxQueueSize = 16
+
+// Frame is a CAN frame with its data stored inline, so that frames can be
+// queued without allocating.
+type Frame struct {
+	ID   uint32
+	Dlc  uint8
+	Data [canMaxCharInMessage]byte
+	Ext  bool // 29-bit extended identifier
+	Rtr  bool // remote transmission request
+}
+
+// rxQueue is a ring buffer of received frames.
+type rxQueue struct {
+	frames  [rxQueueSize]Frame
+	head    uint8
+	count   uint8
+	dropped uint32
+}
+
+func (q *rxQueue) push(f *Frame) {
+	if q.count == rxQueueSize {
+		// full: drop the oldest frame
+		q.head = (q.head + 1) % rxQueueSize
+		q.count--
+		q.dropped++
+	}
+	q.frames[(q.head+q.count)%rxQueueSize] = *f
+	q.count++
+}
+
+func (q *rxQueue) pop(f *Frame) bool {
+	if q.count == 0 {
+		return false
+	}
+	*f = q.frames[q.head]
+	q.head = (q.head + 1) % rxQueueSize
+	q.count--
+	return true
+}
+
+// SendFrame transmits a standard or extended, data or remote frame.
+func (d *Device) SendFrame(f *Frame) error {
+	var ext, rtr uint8
+	if f.Ext {
+		ext = 1
+	}
+	if f.Rtr {
+		rtr = 1
+	}
+	dlc := f.Dlc
+	if dlc > canMaxCharInMessage {
+		dlc = canMaxCharInMessage
+	}
+	data := f.Data[:dlc]
+	if f.Rtr {
+		// remote frames carry a length but no data
+		data = nil
+	}
+	return d.tx(f.ID, ext, rtr, dlc, data)
+}
+
+// SetMask sets one of the two acceptance masks. Mask 0 applies to filters 0
+// and 1 (receive buffer 0), mask 1 applies to filters 2 to 5 (receive buffer
+// 1). A bit set in the mask means the corresponding identifier bit must match
+// the filter. Masks are all zero after Begin, accepting every frame.
+func (d *Device) SetMask(n uint8, mask uint32, ext bool) error {
+	var addr byte
+	switch n {
+	case 0:
+		addr = mcpRXM0SIDH
+	case 1:
+		addr = mcpRXM1SIDH
+	default:
+		return errInvalidFilter
+	}
+	return d.writeIDConfig(addr, mask, ext)
+}
+
+// SetFilter sets one of the six acceptance filters. Filters 0 and 1 belong to
+// receive buffer 0, filters 2 to 5 to receive buffer 1. Set ext to match
+// extended rather than standard frames.
+func (d *Device) SetFilter(n uint8, id uint32, ext bool) error {
+	var addr byte
+	switch n {
+	case 0:
+		addr = mcpRXF0SIDH
+	case 1:
+		addr = mcpRXF1SIDH
+	case 2:
+		addr = mcpRXF2SIDH
+	case 3:
+		addr = mcpRXF3SIDH
+	case 4:
+		addr = mcpRXF4SIDH
+	case 5:
+		addr = mcpRXF5SIDH
+	default:
+		return errInvalidFilter
+	}
+	return d.writeIDConfig(addr, id, ext)
+}
+
+// writeIDConfig writes a filter or mask identifier. These registers can only
+// be changed in configuration mode, so the controller is switched there and
+// back.
+func (d *Device) writeIDConfig(addr byte, id uint32, ext bool) error {
+	if err := d.setCANCTRLMode(modeConfig); err != nil {
+		return err
+	}
+	var b [4]byte
+	if ext {
+		id &= 0x1FFFFFFF
+		b[0] = byte(id >> 21)
+		b[1] = byte((id>>18)&0x07)<<5 | mcpTxbExideM | byte((id>>16)&0x03)
+		b[2] = byte(id >> 8)
+		b[3] = byte(id)
+	} else {
+		id &= 0x7FF
+		b[0] = byte(id >> 3)
+		b[1] = byte((id & 0x07) << 5)
+	}
+	for i, v := range b {
+		if err := d.setRegister(addr+byte(i), v); err != nil {
+			return err
+		}
+	}
+	return d.setCANCTRLMode(d.mcpMode)
+}
+
+// ConfigureInterrupt connects the INT output of the MCP2515. Once a frame
+// arrives the INT pin goes low until all receive buffers have been read,
+// after which Poll moves the frames into the receive queue.
+//
+// The frames are not read from within the interrupt handler since the SPI
+// bus may be in use by the main program at that time.
+func (d *Device) ConfigureInterrupt(pin machine.Pin) error {
+	d.intPin = pin
+	pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	return pin.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+		d.rxPending = true
+	})
+}
+
+// Poll drains the receive buffers of the MCP2515 into the receive queue. It
+// only talks to the device if an interrupt is pending, or on every call if
+// ConfigureInterrupt has not been used.
+func (d *Device) Poll() error {
+	if d.intPin != machine.NoPin && !d.rxPending && d.intPin.Get() {
+		return nil
+	}
+	d.rxPending = false
+	for {
+		status, err := d.readStatus()
+		if err != nil {
+			return err
+		}
+		var cmd uint8
+		switch {
+		case status&mcpStatRx0if != 0:
+			cmd = mcpReadRx0
+		case status&mcpStatRx1if != 0:
+			cmd = mcpReadRx1
+		default:
+			return nil
+		}
+		// READ RX BUFFER clears the interrupt flag once CS is released.
+		if err := d.readRxBuffer(cmd); err != nil {
+			return err
+		}
+		var f Frame
+		f.ID = d.msg.ID
+		f.Dlc = d.msg.Dlc
+		if f.Dlc > canMaxCharInMessage {
+			// DLC values 9 to 15 also mean 8 bytes
+			f.Dlc = canMaxCharInMessage
+		}
+		f.Ext = d.msg.Ext
+		f.Rtr = d.msg.Rtr
+		copy(f.Data[:], d.msg.Data)
+		d.rxQueue.push(&f)
+	}
+}
+
+// Available returns the number of frames in the receive queue.
+func (d *Device) Available() int {
+	return int(d.rxQueue.count)
+}
+
+// ReadFrame removes the oldest frame from the receive queue and stores it in
+// f. It returns false if the queue is empty.
+func (d *Device) ReadFrame(f *Frame) bool {
+	return d.rxQueue.pop(f)
+}
+
+// Dropped returns the number of frames discarded because the receive queue
+// was full.
+func (d *Device) Dropped() uint32 {
+	return d.rxQueue.dropped
+}
@@ -20,6 +20,10 @@ type Device struct {
 	cs      machine.Pin
 	msg     *CANMsg
 	mcpMode byte
+
+	intPin    machine.Pin
+	rxPending bool
+	rxQueue   rxQueue
 }
 
 // CANMsg stores CAN message fields.
@@ -43,8 +47,9 @@ func New(b drivers.SPI, csPin machine.Pin) *Device {
 			tx:  make([]byte, 0, bufferSize),
 			rx:  make([]byte, 0, bufferSize),
 		},
-		cs:  csPin,
-		msg: &CANMsg{},
+		cs:     csPin,
+		msg:    &CANMsg{},
+		intPin: machine.NoPin,
 	}
 
 	return d
@@ -92,7 +97,10 @@ func (d *Device) Rx() (*CANMsg, error) {
 
 // Tx transmits CAN Message.
 func (d *Device) Tx(canid uint32, dlc uint8, data []byte) error {
-	// TODO: add ext, rtrBit, waitSent
+	return d.tx(canid, 0, 0, dlc, data)
+}
+
+func (d *Device) tx(canid uint32, ext, rtrBit, dlc uint8, data []byte) error {
 	timeoutCount := 0
 
 	var bufNum, res uint8
@@ -111,7 +119,7 @@ func (d *Device) Tx(canid uint32, dlc uint8, data []byte) error {
 	if timeoutCount == timeoutvalue {
 		return fmt.Errorf("Tx: Tx timeout")
 	}
-	err = d.writeCANMsg(bufNum, canid, 0, 0, dlc, data)
+	err = d.writeCANMsg(bufNum, canid, ext, rtrBit, dlc, data)
 	if err != nil {
 		return err
 	}
@@ -235,54 +243,63 @@ func (d *Device) getMode() (byte, error) {
 	return r & modeMask, nil
 }
 
+// bitTiming holds the CNF1, CNF2 and CNF3 register values for a bit rate at
+// a given oscillator frequency.
+type bitTiming struct {
+	clock, speed     byte
+	cfg1, cfg2, cfg3 byte
+}
+
+// bitTimings lists the supported bit rate presets.
+var bitTimings = [...]bitTiming{
+	{Clock16MHz, CAN5kBps, mcp16mHz5kBpsCfg1, mcp16mHz5kBpsCfg2, mcp16mHz5kBpsCfg3},
+	{Clock16MHz, CAN10kBps, mcp16mHz10kBpsCfg1, mcp16mHz10kBpsCfg2, mcp16mHz10kBpsCfg3},
+	{Clock16MHz, CAN20kBps, mcp16mHz20kBpsCfg1, mcp16mHz20kBpsCfg2, mcp16mHz20kBpsCfg3},
+	{Clock16MHz, CAN25kBps, mcp16mHz25kBpsCfg1, mcp16mHz25kBpsCfg2, mcp16mHz25kBpsCfg3},
+	{Clock16MHz, CAN31k25Bps, mcp16mHz31k25BpsCfg1, mcp16mHz31k25BpsCfg2, mcp16mHz31k25BpsCfg3},
+	{Clock16MHz, CAN33kBps, mcp16mHz33kBpsCfg1, mcp16mHz33kBpsCfg2, mcp16mHz33kBpsCfg3},
+	{Clock16MHz, CAN40kBps, mcp16mHz40kBpsCfg1, mcp16mHz40kBpsCfg2, mcp16mHz40kBpsCfg3},
+	{Clock16MHz, CAN47kBps, mcp16mHz47kBpsCfg1, mcp16mHz47kBpsCfg2, mcp16mHz47kBpsCfg3},
+	{Clock16MHz, CAN50kBps, mcp16mHz50kBpsCfg1, mcp16mHz50kBpsCfg2, mcp16mHz50kBpsCfg3},
+	{Clock16MHz, CAN80kBps, mcp16mHz80kBpsCfg1, mcp16mHz80kBpsCfg2, mcp16mHz80kBpsCfg3},
+	{Clock16MHz, CAN83k3Bps, mcp16mHz83k3BpsCfg1, mcp16mHz83k3BpsCfg2, mcp16mHz83k3BpsCfg3},
+	{Clock16MHz, CAN95kBps, mcp16mHz95kBpsCfg1, mcp16mHz95kBpsCfg2, mcp16mHz95kBpsCfg3},
+	{Clock16MHz, CAN100kBps, mcp16mHz100kBpsCfg1, mcp16mHz100kBpsCfg2, mcp16mHz100kBpsCfg3},
+	{Clock16MHz, CAN125kBps, mcp16mHz125kBpsCfg1, mcp16mHz125kBpsCfg2, mcp16mHz125kBpsCfg3},
+	{Clock16MHz, CAN200kBps, mcp16mHz200kBpsCfg1, mcp16mHz200kBpsCfg2, mcp16mHz200kBpsCfg3},
+	{Clock16MHz, CAN250kBps, mcp16mHz250kBpsCfg1, mcp16mHz250kBpsCfg2, mcp16mHz250kBpsCfg3},
+	{Clock16MHz, CAN500kBps, mcp16mHz500kBpsCfg1, mcp16mHz500kBpsCfg2, mcp16mHz500kBpsCfg3},
+	{Clock16MHz, CAN666kBps, mcp16mHz666kBpsCfg1, mcp16mHz666kBpsCfg2, mcp16mHz666kBpsCfg3},
+	{Clock16MHz, CAN1000kBps, mcp16mHz1000kBpsCfg1, mcp16mHz1000kBpsCfg2, mcp16mHz1000kBpsCfg3},
+	{Clock8MHz, CAN5kBps, mcp8mHz5kBpsCfg1, mcp8mHz5kBpsCfg2, mcp8mHz5kBpsCfg3},
+	{Clock8MHz, CAN10kBps, mcp8mHz10kBpsCfg1, mcp8mHz10kBpsCfg2, mcp8mHz10kBpsCfg3},
+	{Clock8MHz, CAN20kBps, mcp8mHz20kBpsCfg1, mcp8mHz20kBpsCfg2, mcp8mHz20kBpsCfg3},
+	{Clock8MHz, CAN31k25Bps, mcp8mHz31k25BpsCfg1, mcp8mHz31k25BpsCfg2, mcp8mHz31k25BpsCfg3},
+	{Clock8MHz, CAN40kBps, mcp8mHz40kBpsCfg1, mcp8mHz40kBpsCfg2, mcp8mHz40kBpsCfg3},
+	{Clock8MHz, CAN50kBps, mcp8mHz50kBpsCfg1, mcp8mHz50kBpsCfg2, mcp8mHz50kBpsCfg3},
+	{Clock8MHz, CAN80kBps, mcp8mHz80kBpsCfg1, mcp8mHz80kBpsCfg2, mcp8mHz80kBpsCfg3},
+	{Clock8MHz, CAN100kBps, mcp8mHz100kBpsCfg1, mcp8mHz100kBpsCfg2, mcp8mHz100kBpsCfg3},
+	{Clock8MHz, CAN125kBps, mcp8mHz125kBpsCfg1, mcp8mHz125kBpsCfg2, mcp8mHz125kBpsCfg3},
+	{Clock8MHz, CAN200kBps, mcp8mHz200kBpsCfg1, mcp8mHz200kBpsCfg2, mcp8mHz200kBpsCfg3},
+	{Clock8MHz, CAN250kBps, mcp8mHz250kBpsCfg1, mcp8mHz250kBpsCfg2, mcp8mHz250kBpsCfg3},
+	{Clock8MHz, CAN500kBps, mcp8mHz500kBpsCfg1, mcp8mHz500kBpsCfg2, mcp8mHz500kBpsCfg3},
+	{Clock8MHz, CAN1000kBps, mcp8mHz1000kBpsCfg1, mcp8mHz1000kBpsCfg2, mcp8mHz1000kBpsCfg3},
+}
+
 func (d *Device) configRate(speed, clock byte) error {
-	// TODO: add another baudrate
-	var cfg1, cfg2, cfg3 byte
-	set := true
-	switch clock {
-	case Clock16MHz:
-		switch speed {
-		case CAN500kBps:
-			cfg1 = mcp16mHz500kBpsCfg1
-			cfg2 = mcp16mHz500kBpsCfg2
-			cfg3 = mcp16mHz500kBpsCfg3
-		case CAN1000kBps:
-			cfg1 = mcp16mHz1000kBpsCfg1
-			cfg2 = mcp16mHz1000kBpsCfg2
-			cfg3 = mcp16mHz1000kBpsCfg3
-		default:
-			set = false
+	for _, t := range bitTimings {
+		if t.clock != clock || t.speed != speed {
+			continue
 		}
-	case Clock8MHz:
-		switch speed {
-		case CAN500kBps:
-			cfg1 = mcp8mHz500kBpsCfg1
-			cfg2 = mcp8mHz500kBpsCfg2
-			cfg3 = mcp8mHz500kBpsCfg3
-		case CAN1000kBps:
-			cfg1 = mcp8mHz1000kBpsCfg1
-			cfg2 = mcp8mHz1000kBpsCfg2
-			cfg3 = mcp8mHz1000kBpsCfg3
-		default:
-			set = false
+		if err := d.setRegister(mcpCNF1, t.cfg1); err != nil {
+			return err
 		}
-	default:
-		set = false
-	}
-	if !set {
-		return errors.New("invalid parameter")
-	}
-	if err := d.setRegister(mcpCNF1, cfg1); err != nil {
-		return err
-	}
-	if err := d.setRegister(mcpCNF2, cfg2); err != nil {
-		return err
-	}
-	if err := d.setRegister(mcpCNF3, cfg3); err != nil {
-		return err
+		if err := d.setRegister(mcpCNF2, t.cfg2); err != nil {
+			return err
+		}
+		return d.setRegister(mcpCNF3, t.cfg3)
 	}
-
-	return nil
+	return errors.New("invalid parameter")
 }
 
 func (d *Device) initCANBuffers() error {
@@ -437,39 +454,20 @@ func (d *Device) writeCANMsg(bufNum uint8, canid uint32, ext, rtrBit, dlc uint8,
 }
 
 func (s *SPI) setTxBufData(canid uint32, ext, rtrBit, dlc uint8, data []byte) error {
-	canid = canid & 0x0FFFF
+	var id [4]byte
 	if ext == 1 {
-		// TODO: add Extended ID
-		err := s.setTxData(0)
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(0)
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(0)
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(0)
-		if err != nil {
-			return err
-		}
+		canid &= 0x1FFFFFFF
+		id[0] = byte(canid >> 21)
+		id[1] = byte((canid>>18)&0x07)<<5 | mcpTxbExideM | byte((canid>>16)&0x03)
+		id[2] = byte(canid >> 8)
+		id[3] = byte(canid)
 	} else {
-		err := s.setTxData(byte(canid >> 3))
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(byte((canid & 0x07) << 5))
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(0)
-		if err != nil {
-			return err
-		}
-		err = s.setTxData(0)
+		canid &= 0x7FF
+		id[0] = byte(canid >> 3)
+		id[1] = byte((canid & 0x07) << 5)
+	}
+	for _, b := range id {
+		err := s.setTxData(b)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,574 @@
+// Package mcp251xfd implements a driver for the MCP2517FD and MCP2518FD
+// CAN FD controllers.
+//
+// Datasheet MCP2517FD: https://ww1.microchip.com/downloads/en/DeviceDoc/MCP2517FD-External-CAN-FD-Controller-with-SPI-Interface-20005688B.pdf
+// Datasheet MCP2518FD: https://ww1.microchip.com/downloads/en/DeviceDoc/MCP2518FD-External-CAN-FD-Controller-with-SPI-Interface-20006027B.pdf
+//
+// The message RAM is split into a transmit event FIFO (TEF), the transmit
+// queue (TXQ) and a single receive FIFO, all of which are sized by Config.
+package mcp251xfd // import "tinygo.org/x/drivers/mcp251xfd"
+
+import (
+	"encoding/binary"
+	"errors"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errNotDetected     = errors.New("mcp251xfd: device not detected")
+	errModeTimeout     = errors.New("mcp251xfd: timeout changing operation mode")
+	errInvalidBitRate  = errors.New("mcp251xfd: bit rate not possible with this clock")
+	errRAMOverflow     = errors.New("mcp251xfd: FIFOs do not fit in message RAM")
+	errInvalidPayload  = errors.New("mcp251xfd: invalid payload size")
+	errFrameTooLong    = errors.New("mcp251xfd: frame longer than payload size")
+	errTxFull          = errors.New("mcp251xfd: transmit queue full")
+	errInvalidFilter   = errors.New("mcp251xfd: invalid filter number")
+	errInvalidFIFOSize = errors.New("mcp251xfd: FIFO size must be 1 to 32")
+)
+
+// maxPayload is the largest CAN FD payload.
+const maxPayload = 64
+
+// Config holds the configuration of the controller.
+type Config struct {
+	// Oscillator is the frequency of the crystal or clock input in Hz.
+	// Defaults to 40 MHz.
+	Oscillator uint32
+
+	// PLL multiplies a 4 MHz oscillator by 10.
+	PLL bool
+
+	// BitRate is the nominal (arbitration phase) bit rate. Defaults to
+	// 500 kbit/s.
+	BitRate uint32
+
+	// DataBitRate is the bit rate of the data phase of CAN FD frames sent
+	// with BRS. Set to 0 to run as a CAN 2.0 controller.
+	DataBitRate uint32
+
+	// PayloadSize is the largest payload of transmitted and received frames:
+	// 8, 12, 16, 20, 24, 32, 48 or 64 bytes. Defaults to 64 bytes, or 8 bytes
+	// when DataBitRate is 0.
+	PayloadSize uint8
+
+	// TxQueueSize, RxFIFOSize and TxEventSize are the depths of the
+	// transmit queue, receive FIFO and transmit event FIFO (1-32). A zero
+	// TxEventSize disables the transmit event FIFO.
+	TxQueueSize uint8
+	RxFIFOSize  uint8
+	TxEventSize uint8
+
+	// ListenOnly receives frames without ever driving the bus, not even to
+	// acknowledge frames.
+	ListenOnly bool
+}
+
+// Frame is a classic CAN or CAN FD frame.
+type Frame struct {
+	ID  uint32
+	Ext bool // 29-bit extended identifier
+	RTR bool // remote transmission request, classic CAN only
+	FD  bool // CAN FD frame
+	BRS bool // switch to the data bit rate for the payload
+	ESI bool // error state of the transmitter, receive only
+
+	// Seq is assigned by Tx and reported back in the matching TxEvent.
+	Seq uint32
+
+	Length uint8
+	Data   [maxPayload]byte
+}
+
+// TxEvent reports a frame that has been transmitted successfully, if the
+// transmit event FIFO is enabled.
+type TxEvent struct {
+	ID  uint32
+	Ext bool
+	Seq uint32
+}
+
+// Device wraps an SPI connection to an MCP2517FD or MCP2518FD.
+type Device struct {
+	spi         drivers.SPI
+	cs          machine.Pin
+	mode        uint8
+	payloadSize uint8
+	seq         uint32
+	tx          [2 + 8 + maxPayload]byte
+	rx          [2 + 8 + maxPayload]byte
+}
+
+// New returns a new MCP251xFD driver. Pass in a fully configured SPI bus,
+// running at no more than 0.85 times the system clock divided by 2.
+func New(b drivers.SPI, csPin machine.Pin) *Device {
+	return &Device{
+		spi: b,
+		cs:  csPin,
+	}
+}
+
+// Configure resets the controller, sets up the bit timing and message RAM
+// and switches to normal mode.
+func (d *Device) Configure(cfg Config) error {
+	d.cs.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	d.cs.High()
+
+	if cfg.Oscillator == 0 {
+		cfg.Oscillator = 40e6
+	}
+	if cfg.BitRate == 0 {
+		cfg.BitRate = 500e3
+	}
+	if cfg.PayloadSize == 0 {
+		cfg.PayloadSize = maxPayload
+		if cfg.DataBitRate == 0 {
+			cfg.PayloadSize = 8
+		}
+	}
+	if cfg.TxQueueSize == 0 {
+		cfg.TxQueueSize = 8
+	}
+	if cfg.RxFIFOSize == 0 {
+		cfg.RxFIFOSize = 16
+	}
+	if cfg.TxQueueSize > 32 || cfg.RxFIFOSize > 32 || cfg.TxEventSize > 32 {
+		return errInvalidFIFOSize
+	}
+	plsize, ok := payloadSizeCode(cfg.PayloadSize)
+	if !ok {
+		return errInvalidPayload
+	}
+	objSize := 8 + int(cfg.PayloadSize)
+	if 8*int(cfg.TxEventSize)+objSize*int(cfg.TxQueueSize)+objSize*int(cfg.RxFIFOSize) > ramSize {
+		return errRAMOverflow
+	}
+
+	if err := d.Reset(); err != nil {
+		return err
+	}
+
+	// Oscillator and PLL.
+	clock := cfg.Oscillator
+	osc, err := d.readRegister(regOSC)
+	if err != nil {
+		return err
+	}
+	osc &^= oscPLLEN
+	ready := uint32(oscOSCRDY)
+	if cfg.PLL {
+		clock *= 10
+		osc |= oscPLLEN
+		ready |= oscPLLRDY
+	}
+	if err := d.writeRegister(regOSC, osc); err != nil {
+		return err
+	}
+	if err := d.waitRegister(regOSC, ready, ready); err != nil {
+		return errNotDetected
+	}
+
+	// Bit timing.
+	brp, tseg1, tseg2, ok := bitTiming(clock, cfg.BitRate, 256, 128)
+	if !ok {
+		return errInvalidBitRate
+	}
+	nbt := (brp-1)<<24 | (tseg1-1)<<16 | (tseg2-1)<<8 | (tseg2 - 1)
+	if err := d.writeRegister(regNBTCFG, nbt); err != nil {
+		return err
+	}
+	if cfg.DataBitRate != 0 {
+		brp, tseg1, tseg2, ok := bitTiming(clock, cfg.DataBitRate, 32, 16)
+		if !ok {
+			return errInvalidBitRate
+		}
+		dbt := (brp-1)<<24 | (tseg1-1)<<16 | (tseg2-1)<<8 | (tseg2 - 1)
+		if err := d.writeRegister(regDBTCFG, dbt); err != nil {
+			return err
+		}
+		// Transmitter delay compensation, offset to the data phase sample
+		// point in system clocks.
+		tdco := brp * tseg1
+		if tdco > 63 {
+			tdco = 63
+		}
+		if err := d.writeRegister(regTDC, tdcAuto|tdco<<8); err != nil {
+			return err
+		}
+	}
+
+	// Enable the TXQ and optionally the TEF.
+	con, err := d.readRegister(regCON)
+	if err != nil {
+		return err
+	}
+	con |= conTXQEN | conISOCRCEN
+	con &^= conSTEF
+	if cfg.TxEventSize != 0 {
+		con |= conSTEF
+		tef := uint32(cfg.TxEventSize-1) << fifoFSIZE
+		if err := d.writeRegister(regTEFCON, tef); err != nil {
+			return err
+		}
+	}
+	if err := d.writeRegister(regCON, con); err != nil {
+		return err
+	}
+
+	// Transmit queue with unlimited retransmissions, and the receive FIFO.
+	txq := plsize<<fifoPLSIZE | uint32(cfg.TxQueueSize-1)<<fifoFSIZE | 3<<fifoTXAT
+	if err := d.writeRegister(regTXQCON, txq); err != nil {
+		return err
+	}
+	rxf := plsize<<fifoPLSIZE | uint32(cfg.RxFIFOSize-1)<<fifoFSIZE | fifoTFNRFNIE
+	if err := d.writeRegister(fifoReg(regFIFOCON, rxFIFO), rxf); err != nil {
+		return err
+	}
+	d.payloadSize = cfg.PayloadSize
+
+	// Accept every frame into the receive FIFO until filters are set.
+	if err := d.writeRegister(regFLTOBJ, 0); err != nil {
+		return err
+	}
+	if err := d.writeRegister(regMASK, 0); err != nil {
+		return err
+	}
+	if err := d.writeByte(regFLTCON, fltEN|rxFIFO); err != nil {
+		return err
+	}
+
+	// Drive INT on received frames and transmit events.
+	if err := d.writeRegister(regINT, intRXIE|intTEFIE); err != nil {
+		return err
+	}
+
+	switch {
+	case cfg.ListenOnly:
+		d.mode = modeListenOnly
+	case cfg.DataBitRate == 0:
+		d.mode = modeNormalCAN20
+	default:
+		d.mode = modeNormalFD
+	}
+	return d.setMode(d.mode)
+}
+
+// Reset resets the controller into configuration mode.
+func (d *Device) Reset() error {
+	d.cs.Low()
+	err := d.spi.Tx([]byte{cmdReset << 4, 0}, nil)
+	d.cs.High()
+	time.Sleep(time.Millisecond)
+	return err
+}
+
+// Sleep puts the controller into its low power sleep mode, or leaves it and
+// returns to the configured operation mode. It implements
+// drivers.PowerManaged.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		return d.setMode(modeSleep)
+	}
+	// Any SPI access wakes the oscillator, wait for it before switching mode.
+	if err := d.waitRegister(regOSC, oscOSCRDY, oscOSCRDY); err != nil {
+		return err
+	}
+	return d.setMode(d.mode)
+}
+
+// Tx queues a frame for transmission and assigns it a sequence number.
+func (d *Device) Tx(f *Frame) error {
+	if f.Length > d.payloadSize {
+		return errFrameTooLong
+	}
+	sta, err := d.readRegister(regTXQSTA)
+	if err != nil {
+		return err
+	}
+	if sta&fifoStaNotFullEmpty == 0 {
+		return errTxFull
+	}
+	ua, err := d.readRegister(regTXQUA)
+	if err != nil {
+		return err
+	}
+
+	d.seq++
+	f.Seq = d.seq
+	var obj [8 + maxPayload]byte
+	flags := uint32(lengthToDLC(f.Length)) | (f.Seq&0x7FFFFF)<<objSEQ
+	if f.Ext {
+		flags |= objIDE
+	}
+	if f.FD {
+		flags |= objFDF
+		if f.BRS {
+			flags |= objBRS
+		}
+	} else if f.RTR {
+		flags |= objRTR
+	}
+	binary.LittleEndian.PutUint32(obj[0:], encodeID(f.ID, f.Ext))
+	binary.LittleEndian.PutUint32(obj[4:], flags)
+	n := copy(obj[8:], f.Data[:dlcToLength(lengthToDLC(f.Length))])
+	if err := d.writeBytes(ramStart+uint16(ua), obj[:8+(n+3)&^3]); err != nil {
+		return err
+	}
+	// Advance the queue and request transmission.
+	return d.writeByte(regTXQCON+1, (fifoUINC|fifoTXREQ)>>8)
+}
+
+// Rx reads the next frame from the receive FIFO into f. It returns false if
+// the FIFO is empty.
+func (d *Device) Rx(f *Frame) (bool, error) {
+	sta, err := d.readRegister(fifoReg(regFIFOSTA, rxFIFO))
+	if err != nil {
+		return false, err
+	}
+	if sta&fifoStaNotFullEmpty == 0 {
+		return false, nil
+	}
+	ua, err := d.readRegister(fifoReg(regFIFOUA, rxFIFO))
+	if err != nil {
+		return false, err
+	}
+	var obj [8 + maxPayload]byte
+	if err := d.readBytes(ramStart+uint16(ua), obj[:8+d.payloadSize]); err != nil {
+		return false, err
+	}
+	flags := binary.LittleEndian.Uint32(obj[4:])
+	f.Ext = flags&objIDE != 0
+	f.RTR = flags&objRTR != 0
+	f.FD = flags&objFDF != 0
+	f.BRS = flags&objBRS != 0
+	f.ESI = flags&objESI != 0
+	f.ID = decodeID(binary.LittleEndian.Uint32(obj[0:]), f.Ext)
+	f.Seq = 0
+	f.Length = dlcToLength(uint8(flags & 0x0F))
+	if f.Length > d.payloadSize {
+		f.Length = d.payloadSize
+	}
+	copy(f.Data[:], obj[8:8+f.Length])
+	return true, d.writeByte(fifoReg(regFIFOCON, rxFIFO)+1, fifoUINC>>8)
+}
+
+// ReadTxEvent reads the next event from the transmit event FIFO into e. It
+// returns false if there are no events.
+func (d *Device) ReadTxEvent(e *TxEvent) (bool, error) {
+	sta, err := d.readRegister(regTEFSTA)
+	if err != nil {
+		return false, err
+	}
+	if sta&fifoStaNotFullEmpty == 0 {
+		return false, nil
+	}
+	ua, err := d.readRegister(regTEFUA)
+	if err != nil {
+		return false, err
+	}
+	var obj [8]byte
+	if err := d.readBytes(ramStart+uint16(ua), obj[:]); err != nil {
+		return false, err
+	}
+	flags := binary.LittleEndian.Uint32(obj[4:])
+	e.Ext = flags&objIDE != 0
+	e.ID = decodeID(binary.LittleEndian.Uint32(obj[0:]), e.Ext)
+	e.Seq = flags >> objSEQ
+	return true, d.writeByte(regTEFCON+1, fifoUINC>>8)
+}
+
+// SetFilter configures one of the 32 acceptance filters to route frames to
+// the receive FIFO. A frame is accepted if the identifier bits selected by
+// mask match id, and it is of the type (standard or extended) given by ext.
+// Filter 0 accepts all frames after Configure.
+func (d *Device) SetFilter(n uint8, id, mask uint32, ext bool) error {
+	if n >= 32 {
+		return errInvalidFilter
+	}
+	// A filter can only be changed while it is disabled.
+	if err := d.writeByte(regFLTCON+uint16(n), 0); err != nil {
+		return err
+	}
+	obj := encodeID(id, ext)
+	if ext {
+		obj |= fltEXIDE
+	}
+	if err := d.writeRegister(regFLTOBJ+8*uint16(n), obj); err != nil {
+		return err
+	}
+	if err := d.writeRegister(regMASK+8*uint16(n), encodeID(mask, ext)|fltMIDE); err != nil {
+		return err
+	}
+	return d.writeByte(regFLTCON+uint16(n), fltEN|rxFIFO)
+}
+
+// DisableFilter stops a filter from accepting frames.
+func (d *Device) DisableFilter(n uint8) error {
+	if n >= 32 {
+		return errInvalidFilter
+	}
+	return d.writeByte(regFLTCON+uint16(n), 0)
+}
+
+// ErrorCounters returns the transmit and receive error counters.
+func (d *Device) ErrorCounters() (tec, rec uint8, err error) {
+	trec, err := d.readRegister(regTREC)
+	return uint8(trec >> 8), uint8(trec), err
+}
+
+func (d *Device) setMode(mode uint8) error {
+	if err := d.writeByte(regCON+3, mode); err != nil {
+		return err
+	}
+	return d.waitRegister(regCON, conOPMASK<<conOPMOD, uint32(mode)<<conOPMOD)
+}
+
+// waitRegister polls a register until the bits in mask equal value.
+func (d *Device) waitRegister(addr uint16, mask, value uint32) error {
+	start := time.Now()
+	for {
+		v, err := d.readRegister(addr)
+		if err != nil {
+			return err
+		}
+		if v&mask == value {
+			return nil
+		}
+		if time.Since(start) > 10*time.Millisecond {
+			return errModeTimeout
+		}
+	}
+}
+
+func (d *Device) readRegister(addr uint16) (uint32, error) {
+	var buf [4]byte
+	err := d.readBytes(addr, buf[:])
+	return binary.LittleEndian.Uint32(buf[:]), err
+}
+
+func (d *Device) writeRegister(addr uint16, value uint32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], value)
+	return d.writeBytes(addr, buf[:])
+}
+
+func (d *Device) writeByte(addr uint16, value uint8) error {
+	return d.writeBytes(addr, []byte{value})
+}
+
+func (d *Device) readBytes(addr uint16, buf []byte) error {
+	n := 2 + len(buf)
+	binary.BigEndian.PutUint16(d.tx[0:], cmdRead<<12|addr&0xFFF)
+	for i := 2; i < n; i++ {
+		d.tx[i] = 0
+	}
+	d.cs.Low()
+	err := d.spi.Tx(d.tx[:n], d.rx[:n])
+	d.cs.High()
+	copy(buf, d.rx[2:n])
+	return err
+}
+
+func (d *Device) writeBytes(addr uint16, data []byte) error {
+	binary.BigEndian.PutUint16(d.tx[0:], cmdWrite<<12|addr&0xFFF)
+	n := 2 + copy(d.tx[2:], data)
+	d.cs.Low()
+	err := d.spi.Tx(d.tx[:n], nil)
+	d.cs.High()
+	return err
+}
+
+// fifoReg returns the address of a FIFO control, status or user address
+// register for FIFO n (1-31).
+func fifoReg(reg uint16, n uint8) uint16 {
+	return reg + 12*uint16(n-1)
+}
+
+// encodeID converts an identifier to the SID/EID layout used by message
+// objects and filters.
+func encodeID(id uint32, ext bool) uint32 {
+	if ext {
+		return (id>>18)&0x7FF | (id&0x3FFFF)<<11
+	}
+	return id & 0x7FF
+}
+
+func decodeID(v uint32, ext bool) uint32 {
+	if ext {
+		return (v&0x7FF)<<18 | (v>>11)&0x3FFFF
+	}
+	return v & 0x7FF
+}
+
+// payloadSizeCode returns the PLSIZE field value for a payload size.
+func payloadSizeCode(size uint8) (uint32, bool) {
+	switch size {
+	case 8, 12, 16, 20, 24:
+		return uint32(size-8) / 4, true
+	case 32:
+		return 5, true
+	case 48:
+		return 6, true
+	case 64:
+		return 7, true
+	}
+	return 0, false
+}
+
+// lengthToDLC returns the smallest DLC that holds length bytes.
+func lengthToDLC(length uint8) uint8 {
+	switch {
+	case length <= 8:
+		return length
+	case length <= 24:
+		return 9 + (length-9)/4
+	case length <= 32:
+		return 13
+	case length <= 48:
+		return 14
+	}
+	return 15
+}
+
+// dlcToLength returns the number of data bytes for a DLC.
+func dlcToLength(dlc uint8) uint8 {
+	switch {
+	case dlc <= 8:
+		return dlc
+	case dlc <= 12:
+		return 12 + (dlc-9)*4
+	case dlc == 13:
+		return 32
+	case dlc == 14:
+		return 48
+	}
+	return 64
+}
+
+// bitTiming finds a prescaler and time segments (in time quanta, including
+// the one quantum sync segment) for the given bit rate with a sample point
+// at 80%, preferring the smallest prescaler.
+func bitTiming(clock, rate, maxTSEG1, maxTSEG2 uint32) (brp, tseg1, tseg2 uint32, ok bool) {
+	for brp = 1; brp <= 256; brp++ {
+		if clock%(brp*rate) != 0 {
+			continue
+		}
+		ntq := clock / (brp * rate)
+		if ntq < 5 || ntq > 1+maxTSEG1+maxTSEG2 {
+			continue
+		}
+		tseg1 = ntq*8/10 - 1
+		tseg2 = ntq - 1 - tseg1
+		if tseg2 > maxTSEG2 {
+			tseg2 = maxTSEG2
+			tseg1 = ntq - 1 - tseg2
+		}
+		if tseg1 > maxTSEG1 {
+			continue
+		}
+		return brp, tseg1, tseg2, true
+	}
+	return 0, 0, 0, false
+}
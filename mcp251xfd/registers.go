@@ -0,0 +1,134 @@
+package mcp251xfd
+
+// SPI instructions, in the upper 4 bits of the 16-bit command word.
+const (
+	cmdReset = 0x0
+	cmdWrite = 0x2
+	cmdRead  = 0x3
+)
+
+// CAN FD controller registers.
+const (
+	regCON     = 0x000
+	regNBTCFG  = 0x004
+	regDBTCFG  = 0x008
+	regTDC     = 0x00C
+	regTBC     = 0x010
+	regTSCON   = 0x014
+	regVEC     = 0x018
+	regINT     = 0x01C
+	regRXIF    = 0x020
+	regTXIF    = 0x024
+	regRXOVIF  = 0x028
+	regTXATIF  = 0x02C
+	regTXREQ   = 0x030
+	regTREC    = 0x034
+	regBDIAG0  = 0x038
+	regBDIAG1  = 0x03C
+	regTEFCON  = 0x040
+	regTEFSTA  = 0x044
+	regTEFUA   = 0x048
+	regTXQCON  = 0x050
+	regTXQSTA  = 0x054
+	regTXQUA   = 0x058
+	regFIFOCON = 0x05C // FIFO 1, each following FIFO is 12 bytes further
+	regFIFOSTA = 0x060
+	regFIFOUA  = 0x064
+	regFLTCON  = 0x1D0 // one byte per filter
+	regFLTOBJ  = 0x1F0 // 8 bytes per filter, followed by the mask
+	regMASK    = 0x1F4
+)
+
+// Device registers.
+const (
+	regOSC    = 0xE00
+	regIOCON  = 0xE04
+	regCRC    = 0xE08
+	regECCCON = 0xE0C
+	regECCSTA = 0xE10
+	regDEVID  = 0xE14
+)
+
+// Message RAM.
+const (
+	ramStart = 0x400
+	ramSize  = 2048
+)
+
+// C1CON bits.
+const (
+	conISOCRCEN = 1 << 5
+	conSTEF     = 1 << 19
+	conTXQEN    = 1 << 20
+	conOPMOD    = 21
+	conREQOP    = 24
+	conOPMASK   = 0x7
+)
+
+// Operation modes, as used in the REQOP and OPMOD fields of C1CON.
+const (
+	modeNormalFD         = 0
+	modeSleep            = 1
+	modeInternalLoopback = 2
+	modeListenOnly       = 3
+	modeConfiguration    = 4
+	modeExternalLoopback = 5
+	modeNormalCAN20      = 6
+)
+
+// C1TDC bits.
+const (
+	tdcAuto = 2 << 16
+)
+
+// C1INT bits.
+const (
+	intTXIF  = 1 << 0
+	intRXIF  = 1 << 1
+	intTEFIF = 1 << 4
+	intTXIE  = 1 << 16
+	intRXIE  = 1 << 17
+	intTEFIE = 1 << 20
+)
+
+// FIFO, TXQ and TEF control bits.
+const (
+	fifoTFNRFNIE = 1 << 0 // TX FIFO not full / RX FIFO not empty interrupt
+	fifoTXEN     = 1 << 7
+	fifoUINC     = 1 << 8
+	fifoTXREQ    = 1 << 9
+	fifoFRESET   = 1 << 10
+	fifoTXAT     = 21 // retransmission attempts
+	fifoFSIZE    = 24
+	fifoPLSIZE   = 29
+
+	// TFNRFNIF/TXQNIF/TEFNEIF: FIFO not full (TX) or not empty (RX, TEF)
+	fifoStaNotFullEmpty = 1 << 0
+)
+
+// Filter control bits.
+const (
+	fltEN    = 1 << 7
+	fltEXIDE = 1 << 30
+	fltMIDE  = 1 << 30
+)
+
+// OSC bits.
+const (
+	oscPLLEN  = 1 << 0
+	oscPLLRDY = 1 << 8
+	oscOSCRDY = 1 << 10
+)
+
+// Message object bits (second word).
+const (
+	objIDE = 1 << 4
+	objRTR = 1 << 5
+	objBRS = 1 << 6
+	objFDF = 1 << 7
+	objESI = 1 << 8
+	objSEQ = 9
+)
+
+// rxFIFO is the FIFO used for received frames.
+const rxFIFO = 1
@@ -0,0 +1,214 @@
+// Package modbus implements a Modbus RTU master, for talking to industrial
+// sensors, variable frequency drives and energy meters over RS-485.
+//
+// Application protocol: https://modbus.org/docs/Modbus_Application_Protocol_V1_1b3.pdf
+// Serial line: https://modbus.org/docs/Modbus_over_serial_line_V1_02.pdf
+package modbus // import "tinygo.org/x/drivers/modbus"
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var (
+	errCRC                = errors.New("modbus: CRC mismatch")
+	errTimeout            = errors.New("modbus: response timeout")
+	errIncompleteFrame    = errors.New("modbus: incomplete response frame")
+	errUnexpectedResponse = errors.New("modbus: unexpected response")
+	errInvalidQuantity    = errors.New("modbus: invalid quantity")
+)
+
+// Function codes.
+const (
+	funcReadCoils              = 0x01
+	funcReadDiscreteInputs     = 0x02
+	funcReadHoldingRegisters   = 0x03
+	funcReadInputRegisters     = 0x04
+	funcWriteSingleCoil        = 0x05
+	funcWriteSingleRegister    = 0x06
+	funcWriteMultipleCoils     = 0x0F
+	funcWriteMultipleRegisters = 0x10
+
+	funcException = 0x80
+)
+
+// Limits on the number of items in a single request, so that the request and
+// response fit in the 256 byte RTU frame.
+const (
+	maxReadBits       = 2000
+	maxReadRegisters  = 125
+	maxWriteBits      = 1968
+	maxWriteRegisters = 123
+
+	maxFrameSize = 256
+)
+
+// BroadcastAddress sends a write request to all slaves. Slaves never respond
+// to broadcasts.
+const BroadcastAddress = 0
+
+// Exception is an exception code returned by a slave that could not process
+// a request.
+type Exception uint8
+
+// Exception codes.
+const (
+	ExceptionIllegalFunction        Exception = 0x01
+	ExceptionIllegalDataAddress     Exception = 0x02
+	ExceptionIllegalDataValue       Exception = 0x03
+	ExceptionServerDeviceFailure    Exception = 0x04
+	ExceptionAcknowledge            Exception = 0x05
+	ExceptionServerDeviceBusy       Exception = 0x06
+	ExceptionMemoryParityError      Exception = 0x08
+	ExceptionGatewayPathUnavailable Exception = 0x0A
+	ExceptionGatewayTargetFailed    Exception = 0x0B
+)
+
+// Error implements the error interface.
+func (e Exception) Error() string {
+	switch e {
+	case ExceptionIllegalFunction:
+		return "modbus: illegal function"
+	case ExceptionIllegalDataAddress:
+		return "modbus: illegal data address"
+	case ExceptionIllegalDataValue:
+		return "modbus: illegal data value"
+	case ExceptionServerDeviceFailure:
+		return "modbus: server device failure"
+	case ExceptionAcknowledge:
+		return "modbus: acknowledge, request still in progress"
+	case ExceptionServerDeviceBusy:
+		return "modbus: server device busy"
+	case ExceptionMemoryParityError:
+		return "modbus: memory parity error"
+	case ExceptionGatewayPathUnavailable:
+		return "modbus: gateway path unavailable"
+	case ExceptionGatewayTargetFailed:
+		return "modbus: gateway target device failed to respond"
+	default:
+		return "modbus: unknown exception"
+	}
+}
+
+// Config holds the serial line settings.
+type Config struct {
+	// BaudRate of the UART, used to derive the inter-frame delays. The UART
+	// itself must be configured separately. Defaults to 9600.
+	BaudRate uint32
+
+	// Timeout is how long to wait for a response. Defaults to 1 second.
+	Timeout time.Duration
+
+	// TurnaroundDelay is how long to wait after a broadcast before the next
+	// request, to give the slaves time to process it. Defaults to 100ms.
+	TurnaroundDelay time.Duration
+}
+
+// frameTiming returns the transmission time of a single 11-bit character and
+// the minimum silent interval between frames (3.5 characters). Above 19200
+// baud the spec fixes the silent interval to 1.75ms.
+func frameTiming(baud uint32) (char, frame time.Duration) {
+	char = time.Duration(11 * uint64(time.Second) / uint64(baud))
+	if baud > 19200 {
+		return char, 1750 * time.Microsecond
+	}
+	return char, char * 7 / 2
+}
+
+// crc16 computes the Modbus CRC (polynomial 0xA001, initial value 0xFFFF).
+// It is sent low byte first.
+func crc16(b []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range b {
+		crc ^= uint16(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// appendCRC appends the CRC of b to b.
+func appendCRC(b []byte) []byte {
+	crc := crc16(b)
+	return append(b, byte(crc), byte(crc>>8))
+}
+
+// encodeRequest builds a request consisting of two 16-bit fields, which is
+// the layout of all read requests and of the single write requests.
+func encodeRequest(buf []byte, slave, fn uint8, addr, value uint16) []byte {
+	b := append(buf[:0], slave, fn, byte(addr>>8), byte(addr), byte(value>>8), byte(value))
+	return appendCRC(b)
+}
+
+// encodeWriteMultiple builds a write multiple coils or registers request.
+func encodeWriteMultiple(buf []byte, slave, fn uint8, addr, quantity uint16, data []byte) []byte {
+	b := append(buf[:0], slave, fn, byte(addr>>8), byte(addr), byte(quantity>>8), byte(quantity), byte(len(data)))
+	b = append(b, data...)
+	return appendCRC(b)
+}
+
+// checkResponse validates a complete response frame for a request to the
+// given slave and function.
+func checkResponse(resp []byte, slave, fn uint8) error {
+	if len(resp) < 5 {
+		return errIncompleteFrame
+	}
+	n := len(resp) - 2
+	if crc16(resp[:n]) != binary.LittleEndian.Uint16(resp[n:]) {
+		return errCRC
+	}
+	if resp[0] != slave {
+		return errUnexpectedResponse
+	}
+	if resp[1] == fn|funcException {
+		return Exception(resp[2])
+	}
+	if resp[1] != fn {
+		return errUnexpectedResponse
+	}
+	return nil
+}
+
+// responseLength returns the total length of a response frame from the
+// first three bytes received, or 0 if more bytes are needed.
+func responseLength(resp []byte) int {
+	if len(resp) < 3 {
+		return 0
+	}
+	switch resp[1] {
+	case funcReadCoils, funcReadDiscreteInputs, funcReadHoldingRegisters, funcReadInputRegisters:
+		return 3 + int(resp[2]) + 2
+	case funcWriteSingleCoil, funcWriteSingleRegister, funcWriteMultipleCoils, funcWriteMultipleRegisters:
+		return 8
+	default:
+		// exceptions and unknown functions
+		return 5
+	}
+}
+
+// packBits packs bools into bytes, least significant bit first.
+func packBits(buf []byte, values []bool) []byte {
+	b := buf[:(len(values)+7)/8]
+	for i := range b {
+		b[i] = 0
+	}
+	for i, v := range values {
+		if v {
+			b[i/8] |= 1 << (i % 8)
+		}
+	}
+	return b
+}
+
+// unpackBits unpacks bytes into bools, least significant bit first.
+func unpackBits(dst []bool, b []byte) {
+	for i := range dst {
+		dst[i] = b[i/8]&(1<<(i%8)) != 0
+	}
+}
@@ -0,0 +1,58 @@
+package modbus
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestEncodeRequest(t *testing.T) {
+	c := qt.New(t)
+	var buf [maxFrameSize]byte
+	// read 10 holding registers from slave 1
+	c.Assert(encodeRequest(buf[:], 1, funcReadHoldingRegisters, 0, 10), qt.DeepEquals,
+		[]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A, 0xC5, 0xCD})
+}
+
+func TestEncodeWriteMultiple(t *testing.T) {
+	c := qt.New(t)
+	var buf [maxFrameSize]byte
+	var data [2]byte
+	// write coils 20-29 of slave 17, example from the application protocol spec
+	values := []bool{true, false, true, true, false, false, true, true, true, false}
+	req := encodeWriteMultiple(buf[:], 17, funcWriteMultipleCoils, 19, 10, packBits(data[:], values))
+	c.Assert(req[:9], qt.DeepEquals, []byte{0x11, 0x0F, 0x00, 0x13, 0x00, 0x0A, 0x02, 0xCD, 0x01})
+	c.Assert(crc16(req), qt.Equals, uint16(0))
+}
+
+func TestCheckResponse(t *testing.T) {
+	c := qt.New(t)
+	resp := appendCRC([]byte{0x01, 0x03, 0x04, 0x00, 0x2A, 0x01, 0x00})
+	c.Assert(responseLength(resp), qt.Equals, len(resp))
+	c.Assert(checkResponse(resp, 1, funcReadHoldingRegisters), qt.IsNil)
+	c.Assert(checkResponse(resp, 2, funcReadHoldingRegisters), qt.Equals, errUnexpectedResponse)
+
+	resp[3] ^= 0xFF
+	c.Assert(checkResponse(resp, 1, funcReadHoldingRegisters), qt.Equals, errCRC)
+
+	exc := appendCRC([]byte{0x01, 0x83, 0x02})
+	c.Assert(responseLength(exc), qt.Equals, 5)
+	c.Assert(checkResponse(exc, 1, funcReadHoldingRegisters), qt.Equals, ExceptionIllegalDataAddress)
+}
+
+func TestUnpackBits(t *testing.T) {
+	c := qt.New(t)
+	dst := make([]bool, 10)
+	unpackBits(dst, []byte{0xCD, 0x01})
+	c.Assert(dst, qt.DeepEquals, []bool{true, false, true, true, false, false, true, true, true, false})
+}
+
+func TestFrameTiming(t *testing.T) {
+	c := qt.New(t)
+	char, frame := frameTiming(9600)
+	c.Assert(char, qt.Equals, 1145833*time.Nanosecond)
+	c.Assert(frame, qt.Equals, char*7/2)
+	_, frame = frameTiming(115200)
+	c.Assert(frame, qt.Equals, 1750*time.Microsecond)
+}
@@ -0,0 +1,241 @@
+//go:build tinygo
+
+package modbus
+
+import (
+	"encoding/binary"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// Device is a Modbus RTU master on an RS-485 bus.
+type Device struct {
+	uart drivers.UART
+	de   machine.Pin
+
+	charTime   time.Duration
+	frameDelay time.Duration
+	timeout    time.Duration
+	turnaround time.Duration
+
+	// idle is the earliest time the next request may be sent.
+	idle time.Time
+
+	buf [maxFrameSize]byte
+}
+
+// New returns a new Modbus RTU master. The UART must already be configured
+// with the bus settings (usually 8E1 or 8N1). dePin drives the DE and /RE
+// pins of the RS-485 transceiver, high while transmitting. Pass
+// machine.NoPin for transceivers with automatic direction control.
+func New(uart drivers.UART, dePin machine.Pin) *Device {
+	return &Device{
+		uart: uart,
+		de:   dePin,
+	}
+}
+
+// Configure sets up the direction pin and the frame timing.
+func (d *Device) Configure(cfg Config) {
+	if cfg.BaudRate == 0 {
+		cfg.BaudRate = 9600
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.TurnaroundDelay == 0 {
+		cfg.TurnaroundDelay = 100 * time.Millisecond
+	}
+	d.charTime, d.frameDelay = frameTiming(cfg.BaudRate)
+	d.timeout = cfg.Timeout
+	d.turnaround = cfg.TurnaroundDelay
+
+	if d.de != machine.NoPin {
+		d.de.Configure(machine.PinConfig{Mode: machine.PinOutput})
+		d.de.Low()
+	}
+}
+
+// ReadCoils reads len(dst) coils starting at addr.
+func (d *Device) ReadCoils(slave uint8, addr uint16, dst []bool) error {
+	return d.readBits(slave, funcReadCoils, addr, dst)
+}
+
+// ReadDiscreteInputs reads len(dst) discrete inputs starting at addr.
+func (d *Device) ReadDiscreteInputs(slave uint8, addr uint16, dst []bool) error {
+	return d.readBits(slave, funcReadDiscreteInputs, addr, dst)
+}
+
+// ReadHoldingRegisters reads len(dst) holding registers starting at addr.
+func (d *Device) ReadHoldingRegisters(slave uint8, addr uint16, dst []uint16) error {
+	return d.readRegisters(slave, funcReadHoldingRegisters, addr, dst)
+}
+
+// ReadInputRegisters reads len(dst) input registers starting at addr.
+func (d *Device) ReadInputRegisters(slave uint8, addr uint16, dst []uint16) error {
+	return d.readRegisters(slave, funcReadInputRegisters, addr, dst)
+}
+
+// WriteSingleCoil turns a single coil on or off.
+func (d *Device) WriteSingleCoil(slave uint8, addr uint16, value bool) error {
+	v := uint16(0x0000)
+	if value {
+		v = 0xFF00
+	}
+	_, err := d.transaction(encodeRequest(d.buf[:], slave, funcWriteSingleCoil, addr, v))
+	return err
+}
+
+// WriteSingleRegister writes a single holding register.
+func (d *Device) WriteSingleRegister(slave uint8, addr uint16, value uint16) error {
+	_, err := d.transaction(encodeRequest(d.buf[:], slave, funcWriteSingleRegister, addr, value))
+	return err
+}
+
+// WriteMultipleCoils writes len(values) coils starting at addr.
+func (d *Device) WriteMultipleCoils(slave uint8, addr uint16, values []bool) error {
+	if len(values) == 0 || len(values) > maxWriteBits {
+		return errInvalidQuantity
+	}
+	var data [(maxWriteBits + 7) / 8]byte
+	req := encodeWriteMultiple(d.buf[:], slave, funcWriteMultipleCoils, addr, uint16(len(values)), packBits(data[:], values))
+	_, err := d.transaction(req)
+	return err
+}
+
+// WriteMultipleRegisters writes len(values) holding registers starting at
+// addr.
+func (d *Device) WriteMultipleRegisters(slave uint8, addr uint16, values []uint16) error {
+	if len(values) == 0 || len(values) > maxWriteRegisters {
+		return errInvalidQuantity
+	}
+	var data [2 * maxWriteRegisters]byte
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[2*i:], v)
+	}
+	req := encodeWriteMultiple(d.buf[:], slave, funcWriteMultipleRegisters, addr, uint16(len(values)), data[:2*len(values)])
+	_, err := d.transaction(req)
+	return err
+}
+
+func (d *Device) readBits(slave, fn uint8, addr uint16, dst []bool) error {
+	if len(dst) == 0 || len(dst) > maxReadBits {
+		return errInvalidQuantity
+	}
+	resp, err := d.transaction(encodeRequest(d.buf[:], slave, fn, addr, uint16(len(dst))))
+	if err != nil {
+		return err
+	}
+	if int(resp[2]) != (len(dst)+7)/8 {
+		return errUnexpectedResponse
+	}
+	unpackBits(dst, resp[3:])
+	return nil
+}
+
+func (d *Device) readRegisters(slave, fn uint8, addr uint16, dst []uint16) error {
+	if len(dst) == 0 || len(dst) > maxReadRegisters {
+		return errInvalidQuantity
+	}
+	resp, err := d.transaction(encodeRequest(d.buf[:], slave, fn, addr, uint16(len(dst))))
+	if err != nil {
+		return err
+	}
+	if int(resp[2]) != 2*len(dst) {
+		return errUnexpectedResponse
+	}
+	for i := range dst {
+		dst[i] = binary.BigEndian.Uint16(resp[3+2*i:])
+	}
+	return nil
+}
+
+// transaction sends a request and waits for the response. The request must
+// be in d.buf, which is overwritten by the response.
+func (d *Device) transaction(req []byte) ([]byte, error) {
+	slave, fn := req[0], req[1]
+
+	// Respect the silent interval since the last frame, and drop any stray
+	// bytes received in the meantime.
+	for time.Now().Before(d.idle) {
+	}
+	d.discard()
+
+	d.send(req)
+	if slave == BroadcastAddress {
+		d.idle = time.Now().Add(d.turnaround)
+		return nil, nil
+	}
+
+	resp, err := d.receive()
+	d.idle = time.Now().Add(d.frameDelay)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp, slave, fn); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// send writes a frame with the transceiver in transmit mode, and releases the
+// bus once the last character has left the UART.
+func (d *Device) send(frame []byte) {
+	if d.de != machine.NoPin {
+		d.de.High()
+	}
+	start := time.Now()
+	d.uart.Write(frame)
+	// The UART may still be shifting out buffered characters when Write
+	// returns.
+	done := start.Add(time.Duration(len(frame)) * d.charTime)
+	if earliest := time.Now().Add(d.charTime); done.Before(earliest) {
+		done = earliest
+	}
+	for time.Now().Before(done) {
+	}
+	if d.de != machine.NoPin {
+		d.de.Low()
+	}
+}
+
+// receive reads a response frame into d.buf. The frame ends once its length
+// is known from the header and all bytes arrived, or after a silent interval.
+func (d *Device) receive() ([]byte, error) {
+	n := 0
+	start := time.Now()
+	last := start
+	for {
+		if d.uart.Buffered() > 0 {
+			if n == len(d.buf) {
+				return nil, errUnexpectedResponse
+			}
+			if _, err := d.uart.Read(d.buf[n : n+1]); err != nil {
+				return nil, err
+			}
+			n++
+			last = time.Now()
+			if length := responseLength(d.buf[:n]); length != 0 && n >= length {
+				return d.buf[:n], nil
+			}
+			continue
+		}
+		now := time.Now()
+		if n == 0 {
+			if now.Sub(start) > d.timeout {
+				return nil, errTimeout
+			}
+		} else if now.Sub(last) > d.frameDelay {
+			return nil, errIncompleteFrame
+		}
+	}
+}
+
+func (d *Device) discard() {
+	var b [1]byte
+	for d.uart.Buffered() > 0 {
+		d.uart.Read(b[:])
+	}
+}
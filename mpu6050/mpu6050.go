@@ -6,7 +6,11 @@
 // https://www.invensense.com/wp-content/uploads/2015/02/MPU-6000-Register-Map1.pdf
 package mpu6050 // import "tinygo.org/x/drivers/mpu6050"
 
-import "tinygo.org/x/drivers"
+import (
+	"time"
+
+	"tinygo.org/x/drivers"
+)
 
 // Device wraps an I2C connection to a MPU6050 device.
 type Device struct {
@@ -84,6 +88,26 @@ func (d Device) SetClockSource(source uint8) error {
 	return d.bus.WriteRegister(uint8(d.Address), PWR_MGMT_1, []uint8{source})
 }
 
+// Sleep puts the device in its low power sleep mode, or wakes it up. It
+// implements drivers.PowerManaged.
+func (d Device) Sleep(sleepEnabled bool) error {
+	data := []byte{0}
+	if err := d.bus.ReadRegister(uint8(d.Address), PWR_MGMT_1, data); err != nil {
+		return err
+	}
+	if sleepEnabled {
+		data[0] |= 1 << 6
+	} else {
+		data[0] &^= 1 << 6
+	}
+	return d.bus.WriteRegister(uint8(d.Address), PWR_MGMT_1, data)
+}
+
+// WakeLatency returns the gyroscope start-up time after leaving sleep mode.
+func (d Device) WakeLatency() time.Duration {
+	return 30 * time.Millisecond
+}
+
 // SetFullScaleGyroRange allows the user to configure the scale range for the gyroscope.
 func (d Device) SetFullScaleGyroRange(rng uint8) error {
 	return d.bus.WriteRegister(uint8(d.Address), GYRO_CONFIG, []uint8{rng})
@@ -1,4 +1,5 @@
 // Package pcf8563 implements a driver for the PCF8563 CMOS Real-Time Clock (RTC)
+// and the compatible BM8563 found on M5Stack and many ESP32 boards.
 //
 // Datasheet: https://www.nxp.com/docs/en/data-sheet/PCF8563.pdf
 //
@@ -57,10 +58,10 @@ func (d *Device) ReadTime() (time.Time, error) {
 	}
 
 	seconds := bcdToDec(buf[2] & 0x7F)
-	minute := bcdToDec(buf[3] % 0x7F)
+	minute := bcdToDec(buf[3] & 0x7F)
 	hour := bcdToDec(buf[4] & 0x3F)
 	day := bcdToDec(buf[5] & 0x3F)
-	month := time.Month(bcdToDec(buf[7] & 0x0F))
+	month := time.Month(bcdToDec(buf[7] & 0x1F))
 	year := int(bcdToDec(buf[8])) + 2000
 
 	t := time.Date(year, month, day, hour, minute, seconds, 0, time.UTC)
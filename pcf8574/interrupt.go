@@ -0,0 +1,21 @@
+//go:build tinygo
+
+package pcf8574
+
+import "machine"
+
+// ConfigureInterrupt sets up intPin, connected to the INT output, to flag
+// input changes for Poll. INT is open-drain, so the pin is pulled up.
+func (d *Device) ConfigureInterrupt(intPin machine.Pin) error {
+	intPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	err := intPin.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+		d.intPending = true
+	})
+	if err != nil {
+		return err
+	}
+	d.hasInt = true
+	// Pick up changes that happened before the interrupt was enabled.
+	d.intPending = true
+	return nil
+}
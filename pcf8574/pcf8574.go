@@ -0,0 +1,200 @@
+// Package pcf8574 implements a driver for the PCF8574 and PCF8574A 8-bit
+// I2C I/O expanders.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/pcf8574.pdf
+//
+// The pins are quasi-bidirectional: a pin written high is weakly pulled up
+// and can be used as an input, a pin written low sinks current. The INT
+// output goes low whenever an input changes and is cleared by reading the
+// port, which Poll uses to only access the bus when something changed.
+package pcf8574 // import "tinygo.org/x/drivers/pcf8574"
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// Default addresses with A0-A2 tied low.
+const (
+	Address  = 0x20 // PCF8574
+	AddressA = 0x38 // PCF8574A
+)
+
+// Edge selects the transitions that invoke a pin callback.
+type Edge uint8
+
+const (
+	EdgeRising  Edge = 1 << iota // the pin went from low to high
+	EdgeFalling                  // the pin went from high to low
+	EdgeBoth    = EdgeRising | EdgeFalling
+)
+
+// Callback is called by Poll when a debounced input changes.
+type Callback func(pin uint8, value bool)
+
+type handler struct {
+	edge Edge
+	cb   Callback
+}
+
+// Device wraps an I2C connection to a PCF8574 device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+
+	// out holds the values last written to the port.
+	out uint8
+
+	handlers [8]handler
+	db       debouncer
+
+	// hasInt is set when the INT pin is connected, in which case Poll
+	// only reads the port after intPending was set by the interrupt.
+	hasInt     bool
+	intPending bool
+
+	buf [1]byte
+}
+
+// New creates a new PCF8574 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+		out:     0xFF,
+	}
+}
+
+// Configure sets all pins high, so that they can be used as inputs, and
+// reads their initial state.
+func (d *Device) Configure() error {
+	if err := d.SetPins(0xFF, 0xFF); err != nil {
+		return err
+	}
+	pins, err := d.GetPins()
+	if err != nil {
+		return err
+	}
+	d.db.reset(pins)
+	return nil
+}
+
+// GetPins reads the current level of all pins.
+func (d *Device) GetPins() (uint8, error) {
+	err := d.bus.Tx(d.Address, nil, d.buf[:])
+	return d.buf[0], err
+}
+
+// SetPins sets the pins for which mask is high to their respective values in
+// pins. Set a pin high to use it as an input.
+func (d *Device) SetPins(pins, mask uint8) error {
+	d.out = d.out&^mask | pins&mask
+	d.buf[0] = d.out
+	return d.bus.Tx(d.Address, d.buf[:], nil)
+}
+
+// Get returns the level of a single pin (0-7).
+func (d *Device) Get(pin uint8) (bool, error) {
+	pins, err := d.GetPins()
+	return pins&(1<<pin) != 0, err
+}
+
+// Set drives a single pin (0-7) low, or releases it high.
+func (d *Device) Set(pin uint8, value bool) error {
+	var v uint8
+	if value {
+		v = 0xFF
+	}
+	return d.SetPins(v, 1<<pin)
+}
+
+// SetCallback registers a function that Poll calls when the debounced value
+// of an input pin (0-7) changes in the given direction. A nil callback
+// removes the handler.
+func (d *Device) SetCallback(pin uint8, edge Edge, cb Callback) {
+	d.handlers[pin&7] = handler{edge: edge, cb: cb}
+}
+
+// SetDebounce sets how long an input must be stable before Poll reports a
+// change. Zero reports every change immediately.
+func (d *Device) SetDebounce(delay time.Duration) {
+	d.db.delay = delay
+}
+
+// Inputs returns the debounced value of all pins as of the last Poll.
+func (d *Device) Inputs() uint8 {
+	return d.db.stable
+}
+
+// Poll reads the inputs and invokes the callbacks of pins whose debounced
+// value changed. With the INT pin connected, the port is only read after an
+// interrupt or while a change is being debounced. Call it regularly from the
+// main loop, not from an interrupt handler.
+func (d *Device) Poll() error {
+	if d.hasInt && !d.intPending && d.db.pending == 0 {
+		return nil
+	}
+	d.intPending = false
+	pins, err := d.GetPins()
+	if err != nil {
+		return err
+	}
+	changed := d.db.update(pins, time.Now())
+	for pin := uint8(0); changed != 0; pin++ {
+		mask := uint8(1) << pin
+		if changed&mask == 0 {
+			continue
+		}
+		changed &^= mask
+		value := d.db.stable&mask != 0
+		h := d.handlers[pin]
+		if h.cb == nil {
+			continue
+		}
+		if (value && h.edge&EdgeRising != 0) || (!value && h.edge&EdgeFalling != 0) {
+			h.cb(pin, value)
+		}
+	}
+	return nil
+}
+
+// debouncer reports a pin as changed once its raw value has been stable for
+// delay.
+type debouncer struct {
+	delay   time.Duration
+	stable  uint8 // debounced values
+	last    uint8 // last raw values
+	pending uint8 // pins whose raw value differs from stable
+	since   [8]time.Time
+}
+
+func (db *debouncer) reset(pins uint8) {
+	db.stable = pins
+	db.last = pins
+	db.pending = 0
+}
+
+// update takes the raw pin values at time now and returns the pins whose
+// debounced value changed.
+func (db *debouncer) update(pins uint8, now time.Time) (changed uint8) {
+	diff := pins ^ db.last
+	for i := range db.since {
+		if diff&(1<<i) != 0 {
+			db.since[i] = now
+		}
+	}
+	db.last = pins
+	pending := pins ^ db.stable
+	for i := range db.since {
+		if pending&(1<<i) != 0 && now.Sub(db.since[i]) >= db.delay {
+			changed |= 1 << i
+		}
+	}
+	db.stable ^= changed
+	db.pending = pending &^ changed
+	return changed
+}
@@ -0,0 +1,104 @@
+package pcf8574
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeBus is a PCF8574 with input levels in pins and output latches in out.
+type fakeBus struct {
+	pins uint8
+	out  uint8
+}
+
+func (b *fakeBus) ReadRegister(addr uint8, r uint8, buf []byte) error  { return nil }
+func (b *fakeBus) WriteRegister(addr uint8, r uint8, buf []byte) error { return nil }
+
+func (b *fakeBus) Tx(addr uint16, w, r []byte) error {
+	if len(w) > 0 {
+		b.out = w[0]
+	}
+	if len(r) > 0 {
+		// Pins written low read low.
+		r[0] = b.pins & b.out
+	}
+	return nil
+}
+
+func TestSetPins(t *testing.T) {
+	c := qt.New(t)
+	bus := &fakeBus{pins: 0xFF}
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+	c.Assert(bus.out, qt.Equals, uint8(0xFF))
+	c.Assert(d.Set(3, false), qt.IsNil)
+	c.Assert(d.SetPins(0x00, 0x03), qt.IsNil)
+	c.Assert(bus.out, qt.Equals, uint8(0b11110100))
+	v, err := d.Get(3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.IsFalse)
+}
+
+func TestPollCallbacks(t *testing.T) {
+	c := qt.New(t)
+	bus := &fakeBus{pins: 0xFF}
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+
+	var events []string
+	d.SetCallback(0, EdgeFalling, func(pin uint8, value bool) {
+		events = append(events, "0 falling")
+	})
+	d.SetCallback(1, EdgeBoth, func(pin uint8, value bool) {
+		if value {
+			events = append(events, "1 rising")
+		} else {
+			events = append(events, "1 falling")
+		}
+	})
+
+	bus.pins = 0b11111100
+	c.Assert(d.Poll(), qt.IsNil)
+	bus.pins = 0b11111111
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(events, qt.DeepEquals, []string{"0 falling", "1 falling", "1 rising"})
+	c.Assert(d.Inputs(), qt.Equals, uint8(0xFF))
+}
+
+func TestPollInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := &fakeBus{pins: 0xFF}
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+	d.hasInt = true
+
+	// Without an interrupt the port isn't read.
+	bus.pins = 0xFE
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(d.Inputs(), qt.Equals, uint8(0xFF))
+
+	d.intPending = true
+	c.Assert(d.Poll(), qt.IsNil)
+	c.Assert(d.Inputs(), qt.Equals, uint8(0xFE))
+}
+
+func TestDebounce(t *testing.T) {
+	c := qt.New(t)
+	var db debouncer
+	db.delay = 10 * time.Millisecond
+	db.reset(0xFF)
+	t0 := time.Now()
+
+	// Bouncing pin 0 is only reported once stable for the delay.
+	c.Assert(db.update(0xFE, t0), qt.Equals, uint8(0))
+	c.Assert(db.update(0xFF, t0.Add(2*time.Millisecond)), qt.Equals, uint8(0))
+	c.Assert(db.pending, qt.Equals, uint8(0))
+	c.Assert(db.update(0xFE, t0.Add(4*time.Millisecond)), qt.Equals, uint8(0))
+	c.Assert(db.pending, qt.Equals, uint8(1))
+	c.Assert(db.update(0xFE, t0.Add(13*time.Millisecond)), qt.Equals, uint8(0))
+	c.Assert(db.update(0xFE, t0.Add(14*time.Millisecond)), qt.Equals, uint8(1))
+	c.Assert(db.stable, qt.Equals, uint8(0xFE))
+	c.Assert(db.pending, qt.Equals, uint8(0))
+}
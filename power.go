@@ -0,0 +1,48 @@
+package drivers
+
+import "time"
+
+// PowerManaged is implemented by devices with a low power sleep mode, like
+// displays, most sensors and radios. Sleep(true) puts the device into its
+// lowest power mode that keeps its configuration, Sleep(false) wakes it up
+// again in the mode it was in before.
+type PowerManaged interface {
+	Sleep(sleepEnabled bool) error
+}
+
+// WakeLatency is optionally implemented by a PowerManaged device that needs
+// time after waking up before it is fully operational, for example until the
+// first measurement is available.
+type WakeLatency interface {
+	WakeLatency() time.Duration
+}
+
+// SleepAll puts all devices to sleep. It continues on errors and returns the
+// first one.
+func SleepAll(devices ...PowerManaged) error {
+	var err error
+	for _, d := range devices {
+		if e := d.Sleep(true); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// WakeAll wakes up all devices and then waits for the longest wake latency
+// among them, so that all devices are operational when it returns. It
+// continues on errors and returns the first one.
+func WakeAll(devices ...PowerManaged) error {
+	var err error
+	var latency time.Duration
+	for _, d := range devices {
+		if e := d.Sleep(false); e != nil && err == nil {
+			err = e
+		}
+		if l, ok := d.(WakeLatency); ok && l.WakeLatency() > latency {
+			latency = l.WakeLatency()
+		}
+	}
+	time.Sleep(latency)
+	return err
+}
@@ -0,0 +1,44 @@
+package drivers_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+type sleeper struct {
+	sleeping bool
+	err      error
+}
+
+func (s *sleeper) Sleep(sleepEnabled bool) error {
+	s.sleeping = sleepEnabled
+	return s.err
+}
+
+type slowSleeper struct {
+	sleeper
+}
+
+func (s *slowSleeper) WakeLatency() time.Duration {
+	return 20 * time.Millisecond
+}
+
+func TestSleepAll(t *testing.T) {
+	c := qt.New(t)
+	a := &sleeper{err: errors.New("nack")}
+	b := &slowSleeper{}
+
+	c.Assert(drivers.SleepAll(a, b), qt.ErrorMatches, "nack")
+	c.Assert(a.sleeping, qt.IsTrue)
+	c.Assert(b.sleeping, qt.IsTrue)
+
+	start := time.Now()
+	c.Assert(drivers.WakeAll(a, b), qt.ErrorMatches, "nack")
+	c.Assert(time.Since(start) >= 20*time.Millisecond, qt.IsTrue)
+	c.Assert(a.sleeping, qt.IsFalse)
+	c.Assert(b.sleeping, qt.IsFalse)
+}
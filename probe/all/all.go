@@ -0,0 +1,90 @@
+// Package all registers the drivers of all the devices that probe can
+// identify. Import it for its side effect:
+//
+//	import _ "tinygo.org/x/drivers/probe/all"
+//
+// Every registered driver is linked into the program, so a program that only
+// looks for some devices may register their drivers with probe.Register
+// instead.
+package all // import "tinygo.org/x/drivers/probe/all"
+
+import (
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/bmp180"
+	"tinygo.org/x/drivers/bmp280"
+	"tinygo.org/x/drivers/lis2mdl"
+	"tinygo.org/x/drivers/lis3dh"
+	"tinygo.org/x/drivers/mpu6050"
+	"tinygo.org/x/drivers/probe"
+)
+
+func init() {
+	probe.Register(probe.Driver{
+		Name:       "BME280",
+		Addresses:  []uint16{0x76, 0x77},
+		IDRegister: bme280.WHO_AM_I,
+		ID:         bme280.CHIP_ID,
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := bme280.New(bus)
+			d.Address = address
+			return &d
+		},
+	})
+	probe.Register(probe.Driver{
+		Name:       "BMP180",
+		Addresses:  []uint16{bmp180.Address},
+		IDRegister: bmp180.WHO_AM_I,
+		ID:         bmp180.CHIP_ID,
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := bmp180.New(bus)
+			d.Address = address
+			return &d
+		},
+	})
+	probe.Register(probe.Driver{
+		Name:       "BMP280",
+		Addresses:  []uint16{0x76, 0x77},
+		IDRegister: bmp280.REG_ID,
+		ID:         bmp280.CHIP_ID,
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := bmp280.New(bus)
+			d.Address = address
+			return &d
+		},
+	})
+	probe.Register(probe.Driver{
+		Name:       "LIS2MDL",
+		Addresses:  []uint16{lis2mdl.ADDRESS},
+		IDRegister: lis2mdl.WHO_AM_I,
+		ID:         0x40,
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := lis2mdl.New(bus)
+			d.Address = uint8(address)
+			return &d
+		},
+	})
+	probe.Register(probe.Driver{
+		Name:       "LIS3DH",
+		Addresses:  []uint16{lis3dh.Address0, lis3dh.Address1},
+		IDRegister: lis3dh.WHO_AM_I,
+		ID:         0x33,
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := lis3dh.New(bus)
+			d.Address = address
+			return &d
+		},
+	})
+	probe.Register(probe.Driver{
+		Name:       "MPU6050",
+		Addresses:  []uint16{0x68, 0x69},
+		IDRegister: mpu6050.WHO_AM_I,
+		ID:         0x68,
+		IDMask:     0x7E, // bits 0 and 7 are reserved
+		New: func(bus drivers.I2C, address uint16) interface{} {
+			d := mpu6050.New(bus)
+			d.Address = address
+			return &d
+		},
+	})
+}
@@ -0,0 +1,150 @@
+// Package probe detects which devices are connected to an I2C bus.
+//
+// Drivers are added to a registry with Register, describing the addresses
+// their device may use and the identification register to read there. Detect
+// then checks each registered address on a bus and returns a driver instance
+// for every device that identifies itself, so a board with several sensors
+// can configure itself without hardcoding which are fitted:
+//
+//	import (
+//		"tinygo.org/x/drivers/probe"
+//		_ "tinygo.org/x/drivers/probe/all"
+//	)
+//
+//	for _, found := range probe.Detect(machine.I2C0) {
+//		switch dev := found.Device.(type) {
+//		case *bme280.Device:
+//			dev.Configure()
+//		...
+//		}
+//	}
+//
+// Importing probe/all registers all the drivers probe knows about. No driver
+// is registered otherwise, so drivers that aren't probed for don't add to the
+// program size.
+package probe // import "tinygo.org/x/drivers/probe"
+
+import (
+	"tinygo.org/x/drivers"
+)
+
+// First and last valid 7-bit addresses, the others are reserved.
+const (
+	firstAddress = 0x08
+	lastAddress  = 0x77
+)
+
+// Driver describes how to recognise a device and create a driver for it.
+type Driver struct {
+	// Name of the device, for example "BME280".
+	Name string
+
+	// Addresses the device may be configured to use.
+	Addresses []uint16
+
+	// IDRegister is read at each address, and the device is identified when
+	// the value read, masked with IDMask, equals ID. An IDMask of 0 compares
+	// all bits.
+	IDRegister uint8
+	ID         uint8
+	IDMask     uint8
+
+	// New returns a driver instance for the device at the given address,
+	// usually a pointer to the Device type of the driver package. The device
+	// is not configured.
+	New func(bus drivers.I2C, address uint16) interface{}
+}
+
+// Found is a device returned by Detect.
+type Found struct {
+	Name    string
+	Address uint16
+	Device  interface{}
+}
+
+var registry []Driver
+
+// Register adds a driver to the registry, usually from an init function.
+func Register(d Driver) {
+	registry = append(registry, d)
+}
+
+// Drivers returns the registered drivers.
+func Drivers() []Driver {
+	return registry
+}
+
+// Scan returns the addresses at which a device acknowledges a single byte
+// read.
+func Scan(bus drivers.I2C) []uint16 {
+	var found []uint16
+	buf := []byte{0}
+	for addr := uint16(firstAddress); addr <= lastAddress; addr++ {
+		if bus.Tx(addr, nil, buf) == nil {
+			found = append(found, addr)
+		}
+	}
+	return found
+}
+
+// Identify returns the registered driver whose identification register
+// matches the device at the given address.
+func Identify(bus drivers.I2C, address uint16) (Driver, bool) {
+	for _, d := range registry {
+		if hasAddress(d, address) && matches(bus, d, address) {
+			return d, true
+		}
+	}
+	return Driver{}, false
+}
+
+// Detect checks every address used by a registered driver and returns a new
+// driver instance for each device that identifies itself. At most one device
+// is returned per address, registered drivers are tried in order.
+func Detect(bus drivers.I2C) []Found {
+	var found []Found
+	for _, d := range registry {
+		for _, addr := range d.Addresses {
+			if claimed(found, addr) || !matches(bus, d, addr) {
+				continue
+			}
+			found = append(found, Found{
+				Name:    d.Name,
+				Address: addr,
+				Device:  d.New(bus, addr),
+			})
+		}
+	}
+	return found
+}
+
+// matches reads the identification register of d at the given address.
+func matches(bus drivers.I2C, d Driver, address uint16) bool {
+	data := []byte{0}
+	if bus.Tx(address, []byte{d.IDRegister}, data) != nil {
+		return false
+	}
+	mask := d.IDMask
+	if mask == 0 {
+		mask = 0xFF
+	}
+	return data[0]&mask == d.ID&mask
+}
+
+func hasAddress(d Driver, address uint16) bool {
+	for _, addr := range d.Addresses {
+		if addr == address {
+			return true
+		}
+	}
+	return false
+}
+
+func claimed(found []Found, address uint16) bool {
+	for _, f := range found {
+		if f.Address == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package probe_test
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/bme280"
+	"tinygo.org/x/drivers/bmp280"
+	"tinygo.org/x/drivers/lis3dh"
+	"tinygo.org/x/drivers/probe"
+	_ "tinygo.org/x/drivers/probe/all"
+	"tinygo.org/x/drivers/tester"
+)
+
+var errNACK = errors.New("nack")
+
+// bus is an I2C bus on which addresses without a device do not acknowledge.
+type bus struct {
+	*tester.I2CBus
+	addrs map[uint16]bool
+}
+
+func newBus(c *qt.C) *bus {
+	return &bus{I2CBus: tester.NewI2CBus(c), addrs: map[uint16]bool{}}
+}
+
+func (b *bus) NewDevice(addr uint8) *tester.I2CDevice8 {
+	b.addrs[uint16(addr)] = true
+	return b.I2CBus.NewDevice(addr)
+}
+
+func (b *bus) Tx(addr uint16, w, r []byte) error {
+	if !b.addrs[addr] {
+		return errNACK
+	}
+	if len(w) == 0 {
+		return nil
+	}
+	return b.I2CBus.Tx(addr, w, r)
+}
+
+func TestScan(t *testing.T) {
+	c := qt.New(t)
+	bus := newBus(c)
+	bus.NewDevice(0x19)
+	bus.NewDevice(0x76)
+	c.Assert(probe.Scan(bus), qt.DeepEquals, []uint16{0x19, 0x76})
+}
+
+func TestDetect(t *testing.T) {
+	c := qt.New(t)
+	bus := newBus(c)
+	bus.NewDevice(0x19).Registers[lis3dh.WHO_AM_I] = 0x33
+	bus.NewDevice(0x76).Registers[bme280.WHO_AM_I] = bme280.CHIP_ID
+	bus.NewDevice(0x77).Registers[bmp280.REG_ID] = bmp280.CHIP_ID
+	bus.NewDevice(0x18).Registers[lis3dh.WHO_AM_I] = 0x99 // something else
+
+	found := probe.Detect(bus)
+	c.Assert(found, qt.HasLen, 3)
+	byName := map[string]probe.Found{}
+	for _, f := range found {
+		byName[f.Name] = f
+	}
+
+	accel, ok := byName["LIS3DH"].Device.(*lis3dh.Device)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(accel.Address, qt.Equals, uint16(0x19))
+	c.Assert(accel.Connected(), qt.IsTrue)
+
+	bme, ok := byName["BME280"].Device.(*bme280.Device)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(bme.Address, qt.Equals, uint16(0x76))
+
+	bmp, ok := byName["BMP280"].Device.(*bmp280.Device)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(bmp.Address, qt.Equals, uint16(0x77))
+}
+
+func TestIdentify(t *testing.T) {
+	c := qt.New(t)
+	bus := newBus(c)
+	bus.NewDevice(0x77).Registers[bme280.WHO_AM_I] = bme280.CHIP_ID
+
+	d, ok := probe.Identify(bus, 0x77)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(d.Name, qt.Equals, "BME280")
+
+	_, ok = probe.Identify(bus, 0x76)
+	c.Assert(ok, qt.IsFalse)
+}
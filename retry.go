@@ -0,0 +1,203 @@
+package drivers
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTimeout is matched by errors.Is for errors caused by a bus or
+	// device timeout, including a RetryPolicy running out of time.
+	ErrTimeout = errors.New("timeout")
+
+	// ErrNack is matched by errors.Is for errors caused by a device not
+	// acknowledging a transfer. Bus implementations return errors that wrap
+	// it, or ErrTimeout, to tell these failures apart.
+	ErrNack = errors.New("device did not acknowledge")
+)
+
+// RetryPolicy retries bus operations that fail, with an increasing delay
+// between attempts. It does not interrupt an operation that blocks: a wedged
+// peripheral is detected by the bus implementation's own timeout, or by a
+// device that never becomes ready in Until.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts of an operation, at least
+	// one.
+	Attempts int
+
+	// Backoff is the delay before the first retry. It doubles with each
+	// retry, up to MaxBackoff if set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Timeout limits the total time spent on an operation including the
+	// retries, or waiting in Until. Zero means no limit.
+	Timeout time.Duration
+}
+
+// DefaultRetryPolicy tries operations three times within 100ms.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    time.Millisecond,
+	MaxBackoff: 10 * time.Millisecond,
+	Timeout:    100 * time.Millisecond,
+}
+
+// RetryError is returned by a RetryPolicy when an operation did not succeed.
+type RetryError struct {
+	// Attempts is the number of times the operation was run.
+	Attempts int
+
+	// Err is the error of the last attempt, or nil if the policy timed
+	// out waiting in Until.
+	Err error
+
+	timedOut bool
+}
+
+// Error implements the error interface.
+func (e *RetryError) Error() string {
+	if e.Err == nil {
+		return "timeout after " + itoa(e.Attempts) + " attempts"
+	}
+	return "failed after " + itoa(e.Attempts) + " attempts: " + e.Err.Error()
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the policy ran out of time (ErrTimeout). The error of
+// the last attempt, which may wrap ErrTimeout or ErrNack, is matched through
+// Unwrap.
+func (e *RetryError) Is(target error) bool {
+	return target == ErrTimeout && e.timedOut
+}
+
+// Do runs op until it succeeds, the attempts are used up or the timeout
+// expires.
+func (p RetryPolicy) Do(op func() error) error {
+	start := time.Now()
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.Attempts {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+		if p.Timeout != 0 && time.Since(start)+backoff > p.Timeout {
+			return &RetryError{Attempts: attempt, Err: err, timedOut: true}
+		}
+		time.Sleep(backoff)
+		backoff = p.next(backoff)
+	}
+}
+
+// Until polls ready until it returns true, for example to wait for a
+// conversion to complete. Errors returned by ready are retried like in Do,
+// while a device that never becomes ready fails with ErrTimeout once the
+// timeout expires. Without a timeout, it waits as long as it takes.
+func (p RetryPolicy) Until(ready func() (bool, error)) error {
+	start := time.Now()
+	backoff := p.Backoff
+	failed := 0
+	for attempt := 1; ; attempt++ {
+		ok, err := ready()
+		if err == nil && ok {
+			return nil
+		}
+		if err != nil {
+			failed++
+			if failed >= p.Attempts {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+		}
+		if p.Timeout != 0 && time.Since(start)+backoff > p.Timeout {
+			return &RetryError{Attempts: attempt, Err: err, timedOut: true}
+		}
+		time.Sleep(backoff)
+		backoff = p.next(backoff)
+	}
+}
+
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if p.MaxBackoff != 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// RetryI2C wraps an I2C bus and retries failed transactions according to its
+// policy. Only use it with transactions that can safely be repeated, reading
+// some devices' registers has side effects.
+type RetryI2C struct {
+	Bus    I2C
+	Policy RetryPolicy
+}
+
+// NewRetryI2C returns bus wrapped with the given retry policy.
+func NewRetryI2C(bus I2C, policy RetryPolicy) *RetryI2C {
+	return &RetryI2C{Bus: bus, Policy: policy}
+}
+
+// ReadRegister implements I2C.
+func (b *RetryI2C) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Policy.Do(func() error {
+		return b.Bus.ReadRegister(addr, r, buf)
+	})
+}
+
+// WriteRegister implements I2C.
+func (b *RetryI2C) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Policy.Do(func() error {
+		return b.Bus.WriteRegister(addr, r, buf)
+	})
+}
+
+// Tx implements I2C.
+func (b *RetryI2C) Tx(addr uint16, w, r []byte) error {
+	return b.Policy.Do(func() error {
+		return b.Bus.Tx(addr, w, r)
+	})
+}
+
+// RetrySPI wraps an SPI bus and retries failed transfers according to its
+// policy. SPI has no acknowledge, so this only helps with buses that report
+// errors such as DMA timeouts.
+type RetrySPI struct {
+	Bus    SPI
+	Policy RetryPolicy
+}
+
+// NewRetrySPI returns bus wrapped with the given retry policy.
+func NewRetrySPI(bus SPI, policy RetryPolicy) *RetrySPI {
+	return &RetrySPI{Bus: bus, Policy: policy}
+}
+
+// Tx implements SPI.
+func (b *RetrySPI) Tx(w, r []byte) error {
+	return b.Policy.Do(func() error {
+		return b.Bus.Tx(w, r)
+	})
+}
+
+// Transfer implements SPI.
+func (b *RetrySPI) Transfer(w byte) (r byte, err error) {
+	err = b.Policy.Do(func() error {
+		r, err = b.Bus.Transfer(w)
+		return err
+	})
+	return r, err
+}
+
+// itoa formats a small non-negative number, avoiding strconv.
+func itoa(n int) string {
+	if n < 10 {
+		return string(rune('0' + n))
+	}
+	return itoa(n/10) + string(rune('0'+n%10))
+}
@@ -0,0 +1,111 @@
+package drivers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// nackError is the error of a bus when a device doesn't acknowledge.
+type nackError struct{}
+
+func (nackError) Error() string { return "I2C error: expected ACK not NACK" }
+func (nackError) Unwrap() error { return ErrNack }
+
+var errNackTest error = nackError{}
+
+// flakyI2C fails the first n transactions.
+type flakyI2C struct {
+	fail  int
+	calls int
+}
+
+func (b *flakyI2C) Tx(addr uint16, w, r []byte) error {
+	b.calls++
+	if b.calls <= b.fail {
+		return errNackTest
+	}
+	return nil
+}
+
+func (b *flakyI2C) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Tx(uint16(addr), []byte{r}, buf)
+}
+
+func (b *flakyI2C) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	return b.Tx(uint16(addr), append([]byte{r}, buf...), nil)
+}
+
+func TestRetryI2C(t *testing.T) {
+	c := qt.New(t)
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Microsecond}
+
+	flaky := &flakyI2C{fail: 2}
+	bus := NewRetryI2C(flaky, policy)
+	c.Assert(bus.ReadRegister(0x10, 0, nil), qt.IsNil)
+	c.Assert(flaky.calls, qt.Equals, 3)
+
+	flaky = &flakyI2C{fail: 3}
+	bus = NewRetryI2C(flaky, policy)
+	err := bus.Tx(0x10, []byte{0}, nil)
+	c.Assert(err, qt.ErrorMatches, "failed after 3 attempts: I2C error: expected ACK not NACK")
+	c.Assert(errors.Is(err, ErrNack), qt.IsTrue)
+	c.Assert(errors.Is(err, ErrTimeout), qt.IsFalse)
+	c.Assert(errors.Is(err, errNackTest), qt.IsTrue)
+}
+
+func TestRetryTimeout(t *testing.T) {
+	c := qt.New(t)
+	policy := RetryPolicy{Attempts: 100, Backoff: time.Millisecond, Timeout: 5 * time.Millisecond}
+	flaky := &flakyI2C{fail: 100}
+	err := NewRetryI2C(flaky, policy).WriteRegister(0x10, 0, []byte{1})
+	c.Assert(errors.Is(err, ErrTimeout), qt.IsTrue)
+	c.Assert(errors.Is(err, ErrNack), qt.IsTrue)
+	c.Assert(flaky.calls < 10, qt.IsTrue)
+}
+
+func TestRetryUntil(t *testing.T) {
+	c := qt.New(t)
+	policy := RetryPolicy{Attempts: 2, Backoff: time.Microsecond, Timeout: 10 * time.Millisecond}
+
+	n := 0
+	err := policy.Until(func() (bool, error) {
+		n++
+		return n == 5, nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 5)
+
+	// never ready
+	err = policy.Until(func() (bool, error) { return false, nil })
+	c.Assert(err, qt.ErrorMatches, "timeout after [0-9]+ attempts")
+	c.Assert(errors.Is(err, ErrTimeout), qt.IsTrue)
+
+	// bus errors are retried up to Attempts
+	n = 0
+	err = policy.Until(func() (bool, error) {
+		n++
+		return false, errNackTest
+	})
+	c.Assert(errors.Is(err, ErrNack), qt.IsTrue)
+	c.Assert(n, qt.Equals, 2)
+
+	// without a timeout
+	policy.Timeout = 0
+	policy.MaxBackoff = time.Millisecond
+	n = 0
+	err = policy.Until(func() (bool, error) {
+		n++
+		return n == 20, nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 20)
+
+	// errors only match ErrNack by wrapping it
+	err = policy.Until(func() (bool, error) {
+		return false, errors.New("nack")
+	})
+	c.Assert(errors.Is(err, ErrNack), qt.IsFalse)
+}
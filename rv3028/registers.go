@@ -0,0 +1,103 @@
+package rv3028
+
+// The I2C address which this device listens to.
+const Address = 0x52
+
+// Registers
+const (
+	REG_SECONDS       = 0x00
+	REG_MINUTES       = 0x01
+	REG_HOURS         = 0x02
+	REG_WEEKDAY       = 0x03
+	REG_DATE          = 0x04
+	REG_MONTH         = 0x05
+	REG_YEAR          = 0x06
+	REG_ALARM_MINUTES = 0x07
+	REG_ALARM_HOURS   = 0x08
+	REG_ALARM_DAY     = 0x09
+	REG_TIMER_VALUE0  = 0x0A
+	REG_TIMER_VALUE1  = 0x0B
+	REG_TIMER_STATUS0 = 0x0C
+	REG_TIMER_STATUS1 = 0x0D
+	REG_STATUS        = 0x0E
+	REG_CONTROL1      = 0x0F
+	REG_CONTROL2      = 0x10
+	REG_GP_BITS       = 0x11
+	REG_INT_MASK      = 0x12
+	REG_EVENT_CONTROL = 0x13
+	REG_UNIX_TIME0    = 0x1B
+	REG_USER_RAM1     = 0x1F
+	REG_USER_RAM2     = 0x20
+	REG_EEPROM_ADDR   = 0x25
+	REG_EEPROM_DATA   = 0x26
+	REG_EEPROM_CMD    = 0x27
+	REG_ID            = 0x28
+
+	// RAM mirrors of the configuration EEPROM.
+	REG_EEPROM_CLKOUT = 0x35
+	REG_EEPROM_OFFSET = 0x36
+	REG_EEPROM_BACKUP = 0x37
+)
+
+// Status register bits
+const (
+	STATUS_EEBUSY = 0x80
+	STATUS_CLKF   = 0x40
+	STATUS_BSF    = 0x20
+	STATUS_UF     = 0x10
+	STATUS_TF     = 0x08
+	STATUS_AF     = 0x04
+	STATUS_EVF    = 0x02
+	STATUS_PORF   = 0x01
+)
+
+// Control 1 register bits
+const (
+	CONTROL1_TRPT = 0x80
+	CONTROL1_WADA = 0x20
+	CONTROL1_USEL = 0x10
+	CONTROL1_EERD = 0x08
+	CONTROL1_TE   = 0x04
+	CONTROL1_TD   = 0x03
+)
+
+// Control 2 register bits
+const (
+	CONTROL2_TSE   = 0x80
+	CONTROL2_CLKIE = 0x40
+	CONTROL2_UIE   = 0x20
+	CONTROL2_TIE   = 0x10
+	CONTROL2_AIE   = 0x08
+	CONTROL2_EIE   = 0x04
+	CONTROL2_12_24 = 0x02
+	CONTROL2_RESET = 0x01
+)
+
+// Backup register bits
+const (
+	BACKUP_BSIE = 0x40
+	BACKUP_TCE  = 0x20
+	BACKUP_FEDE = 0x10
+	BACKUP_BSM  = 0x0C
+	BACKUP_TCR  = 0x03
+)
+
+// Clock output register bits
+const (
+	CLKOUT_CLKOE = 0x80
+	CLKOUT_CLKSY = 0x40
+	CLKOUT_PORIE = 0x08
+	CLKOUT_FD    = 0x07
+)
+
+// Alarm register bit, set to ignore the field when matching.
+const ALARM_DISABLE = 0x80
+
+// EEPROM commands
+const (
+	eepromCmdFirst   = 0x00
+	eepromCmdUpdate  = 0x11 // write all configuration RAM to EEPROM
+	eepromCmdRefresh = 0x12 // read all configuration EEPROM to RAM
+	eepromCmdWrite   = 0x21 // write one byte to EEPROM
+	eepromCmdRead    = 0x22 // read one byte from EEPROM
+)
@@ -0,0 +1,423 @@
+// Package rv3028 implements a driver for the RV-3028-C7 extreme low power
+// real-time clock.
+//
+// Datasheet: https://www.microcrystal.com/fileadmin/Media/Products/RTC/App.Manual/RV-3028-C7_App-Manual.pdf
+package rv3028 // import "tinygo.org/x/drivers/rv3028"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errEEPROMBusy       = errors.New("rv3028: EEPROM busy")
+	errInvalidAlarmMode = errors.New("rv3028: invalid alarm mode")
+	errInvalidTimer     = errors.New("rv3028: timer period out of range")
+)
+
+// AlarmMode selects which fields of the alarm must match the current time
+// for the alarm to trigger.
+type AlarmMode uint8
+
+const (
+	// AlarmMatchMinutes triggers every hour when the minutes match.
+	AlarmMatchMinutes AlarmMode = iota
+	// AlarmMatchHours triggers every day when the hours and minutes match.
+	AlarmMatchHours
+	// AlarmMatchDate triggers when the date, hours and minutes match.
+	AlarmMatchDate
+	// AlarmMatchWeekday triggers when the weekday, hours and minutes match.
+	AlarmMatchWeekday
+)
+
+// BackupMode selects how the device switches to the backup supply.
+type BackupMode uint8
+
+const (
+	// BackupDisabled never switches to the backup supply.
+	BackupDisabled BackupMode = 0
+	// BackupDirect switches when VBACKUP is higher than VDD.
+	BackupDirect BackupMode = 1
+	// BackupLevel switches when VDD drops below 2V and VBACKUP is higher.
+	BackupLevel BackupMode = 3
+)
+
+// TrickleCharge selects the series resistor used to charge a backup
+// capacitor or rechargeable battery from VDD.
+type TrickleCharge uint8
+
+const (
+	TrickleChargeOff TrickleCharge = iota
+	TrickleCharge3k
+	TrickleCharge5k
+	TrickleCharge9k
+	TrickleCharge15k
+)
+
+// ClockOutput is the frequency of the CLKOUT pin.
+type ClockOutput uint8
+
+const (
+	ClockOutput32768Hz ClockOutput = iota
+	ClockOutput8192Hz
+	ClockOutput1024Hz
+	ClockOutput64Hz
+	ClockOutput32Hz
+	ClockOutput1Hz
+	ClockOutputTimer // pulses when the countdown timer expires
+	ClockOutputOff   // held low
+)
+
+// Device wraps an I2C connection to a RV-3028-C7 device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+}
+
+// New creates a new RV-3028 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+	}
+}
+
+// Configure selects 24 hour mode and clears the power-on reset flag.
+func (d *Device) Configure() error {
+	if err := d.updateRegister(REG_CONTROL2, CONTROL2_12_24, 0); err != nil {
+		return err
+	}
+	return d.updateRegister(REG_STATUS, STATUS_PORF, 0)
+}
+
+// LostPower returns whether the device was reset by a power loss (including
+// the backup supply) since Configure, in which case the time is invalid.
+func (d *Device) LostPower() (bool, error) {
+	status, err := d.readRegister(REG_STATUS)
+	return status&STATUS_PORF != 0, err
+}
+
+// SetTime sets the date and time. The UNIX time counter is independent of
+// the calendar and can be set with SetUnixTime.
+func (d *Device) SetTime(t time.Time) error {
+	data := []byte{
+		decToBcd(t.Second()),
+		decToBcd(t.Minute()),
+		decToBcd(t.Hour()),
+		uint8(t.Weekday()),
+		decToBcd(t.Day()),
+		decToBcd(int(t.Month())),
+		decToBcd(t.Year() - 2000),
+	}
+	return d.bus.WriteRegister(uint8(d.Address), REG_SECONDS, data)
+}
+
+// ReadTime returns the date and time.
+func (d *Device) ReadTime() (time.Time, error) {
+	var data [7]byte
+	err := d.bus.ReadRegister(uint8(d.Address), REG_SECONDS, data[:])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(
+		bcdToDec(data[6])+2000,
+		time.Month(bcdToDec(data[5]&0x1F)),
+		bcdToDec(data[4]&0x3F),
+		bcdToDec(data[2]&0x3F),
+		bcdToDec(data[1]&0x7F),
+		bcdToDec(data[0]&0x7F),
+		0, time.UTC), nil
+}
+
+// SetUnixTime sets the 32-bit UNIX time counter, which counts seconds
+// independently of the calendar registers.
+func (d *Device) SetUnixTime(seconds uint32) error {
+	data := []byte{uint8(seconds), uint8(seconds >> 8), uint8(seconds >> 16), uint8(seconds >> 24)}
+	return d.bus.WriteRegister(uint8(d.Address), REG_UNIX_TIME0, data)
+}
+
+// ReadUnixTime returns the UNIX time counter.
+func (d *Device) ReadUnixTime() (uint32, error) {
+	// The counter may increment between byte reads, so read until two
+	// consecutive values agree.
+	var prev uint32
+	for i := 0; ; i++ {
+		var data [4]byte
+		err := d.bus.ReadRegister(uint8(d.Address), REG_UNIX_TIME0, data[:])
+		if err != nil {
+			return 0, err
+		}
+		v := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+		if i > 0 && v == prev {
+			return v, nil
+		}
+		prev = v
+	}
+}
+
+// SetAlarm sets the alarm to the minute, hour and day of t, matched
+// according to mode, and clears the alarm flag.
+func (d *Device) SetAlarm(t time.Time, mode AlarmMode) error {
+	data := []byte{
+		decToBcd(t.Minute()),
+		decToBcd(t.Hour()),
+		decToBcd(t.Day()),
+	}
+	wada := uint8(CONTROL1_WADA)
+	switch mode {
+	case AlarmMatchMinutes:
+		data[1] |= ALARM_DISABLE
+		data[2] |= ALARM_DISABLE
+	case AlarmMatchHours:
+		data[2] |= ALARM_DISABLE
+	case AlarmMatchDate:
+	case AlarmMatchWeekday:
+		data[2] = uint8(t.Weekday())
+		wada = 0
+	default:
+		return errInvalidAlarmMode
+	}
+	if err := d.updateRegister(REG_CONTROL1, CONTROL1_WADA, wada); err != nil {
+		return err
+	}
+	if err := d.bus.WriteRegister(uint8(d.Address), REG_ALARM_MINUTES, data); err != nil {
+		return err
+	}
+	return d.ClearAlarm()
+}
+
+// ClearAlarm clears the alarm flag, which releases the INT pin.
+func (d *Device) ClearAlarm() error {
+	return d.updateRegister(REG_STATUS, STATUS_AF, 0)
+}
+
+// EnableAlarmInterrupt enables the alarm interrupt. When triggered, the INT
+// pin goes low.
+func (d *Device) EnableAlarmInterrupt() error {
+	return d.updateRegister(REG_CONTROL2, 0, CONTROL2_AIE)
+}
+
+// DisableAlarmInterrupt disables the alarm interrupt.
+func (d *Device) DisableAlarmInterrupt() error {
+	return d.updateRegister(REG_CONTROL2, CONTROL2_AIE, 0)
+}
+
+// AlarmTriggered returns whether or not the alarm has been triggered.
+func (d *Device) AlarmTriggered() bool {
+	status, err := d.readRegister(REG_STATUS)
+	return err == nil && status&STATUS_AF != 0
+}
+
+// timerClocks are the periods of the clocks of the countdown timer, indexed
+// by the TD field of CONTROL1.
+var timerClocks = [...]time.Duration{time.Second / 4096, time.Second / 64, time.Second, time.Minute}
+
+// SetTimer starts the periodic countdown timer. The period is rounded to the
+// resolution of the fastest clock that can count it: 244µs below 1s, 15.6ms
+// below 64s, 1s up to 4095s and 1 minute up to 4095 minutes. If repeat is
+// false the timer stops after it expires once.
+func (d *Device) SetTimer(period time.Duration, repeat bool) error {
+	var td uint8
+	var value time.Duration
+	for ; int(td) < len(timerClocks); td++ {
+		// the timer value is 12 bits
+		tick := timerClocks[td]
+		if value = (period + tick/2) / tick; value <= 0xFFF {
+			break
+		}
+	}
+	if value < 1 || value > 0xFFF {
+		return errInvalidTimer
+	}
+	// The timer must be stopped while its value is changed.
+	if err := d.updateRegister(REG_CONTROL1, CONTROL1_TE, 0); err != nil {
+		return err
+	}
+	data := []byte{uint8(value), uint8(value >> 8)}
+	if err := d.bus.WriteRegister(uint8(d.Address), REG_TIMER_VALUE0, data); err != nil {
+		return err
+	}
+	if err := d.ClearTimer(); err != nil {
+		return err
+	}
+	set := td | CONTROL1_TE
+	if repeat {
+		set |= CONTROL1_TRPT
+	}
+	return d.updateRegister(REG_CONTROL1, CONTROL1_TD|CONTROL1_TE|CONTROL1_TRPT, set)
+}
+
+// StopTimer stops the countdown timer.
+func (d *Device) StopTimer() error {
+	return d.updateRegister(REG_CONTROL1, CONTROL1_TE, 0)
+}
+
+// ClearTimer clears the timer flag, which releases the INT pin.
+func (d *Device) ClearTimer() error {
+	return d.updateRegister(REG_STATUS, STATUS_TF, 0)
+}
+
+// EnableTimerInterrupt enables the timer interrupt. When triggered, the INT
+// pin goes low.
+func (d *Device) EnableTimerInterrupt() error {
+	return d.updateRegister(REG_CONTROL2, 0, CONTROL2_TIE)
+}
+
+// DisableTimerInterrupt disables the timer interrupt.
+func (d *Device) DisableTimerInterrupt() error {
+	return d.updateRegister(REG_CONTROL2, CONTROL2_TIE, 0)
+}
+
+// TimerTriggered returns whether or not the timer has expired.
+func (d *Device) TimerTriggered() bool {
+	status, err := d.readRegister(REG_STATUS)
+	return err == nil && status&STATUS_TF != 0
+}
+
+// SetBackupSwitchover configures switching to the backup supply and trickle
+// charging of the backup capacitor or battery. The setting is lost on power
+// loss unless saved with SaveConfig.
+func (d *Device) SetBackupSwitchover(mode BackupMode, charge TrickleCharge) error {
+	// Fast edge detection must be enabled for the switchover to work
+	// reliably.
+	set := uint8(mode)<<2 | BACKUP_FEDE
+	if charge != TrickleChargeOff {
+		set |= BACKUP_TCE | uint8(charge-1)
+	}
+	return d.updateConfig(REG_EEPROM_BACKUP, BACKUP_BSM|BACKUP_FEDE|BACKUP_TCE|BACKUP_TCR, set)
+}
+
+// EnableBackupInterrupt enables an interrupt when the device switches to the
+// backup supply.
+func (d *Device) EnableBackupInterrupt(enable bool) error {
+	if enable {
+		return d.updateConfig(REG_EEPROM_BACKUP, 0, BACKUP_BSIE)
+	}
+	return d.updateConfig(REG_EEPROM_BACKUP, BACKUP_BSIE, 0)
+}
+
+// SetClockOutput sets the frequency of the CLKOUT pin. The setting is lost
+// on power loss unless saved with SaveConfig.
+func (d *Device) SetClockOutput(freq ClockOutput) error {
+	set := uint8(freq) & CLKOUT_FD
+	if freq != ClockOutputOff {
+		set |= CLKOUT_CLKOE
+	}
+	return d.updateConfig(REG_EEPROM_CLKOUT, CLKOUT_CLKOE|CLKOUT_FD, set)
+}
+
+// SaveConfig writes the configuration set by SetBackupSwitchover and
+// SetClockOutput to EEPROM, where it survives a total power loss. The EEPROM
+// endures a limited number of writes, so only save when it changed.
+func (d *Device) SaveConfig() error {
+	return d.eepromCommand(eepromCmdUpdate)
+}
+
+// ReadEEPROM reads a byte of the user EEPROM (address 0x00-0x2A).
+func (d *Device) ReadEEPROM(addr uint8) (uint8, error) {
+	if err := d.bus.WriteRegister(uint8(d.Address), REG_EEPROM_ADDR, []byte{addr}); err != nil {
+		return 0, err
+	}
+	if err := d.eepromCommand(eepromCmdRead); err != nil {
+		return 0, err
+	}
+	return d.readRegister(REG_EEPROM_DATA)
+}
+
+// WriteEEPROM writes a byte of the user EEPROM (address 0x00-0x2A).
+func (d *Device) WriteEEPROM(addr, value uint8) error {
+	if err := d.bus.WriteRegister(uint8(d.Address), REG_EEPROM_ADDR, []byte{addr, value}); err != nil {
+		return err
+	}
+	return d.eepromCommand(eepromCmdWrite)
+}
+
+// updateConfig changes a configuration register RAM mirror. Automatic
+// refresh from EEPROM is disabled first, otherwise the change would be
+// overwritten within 24 hours.
+func (d *Device) updateConfig(reg, clear, set uint8) error {
+	if err := d.updateRegister(REG_CONTROL1, 0, CONTROL1_EERD); err != nil {
+		return err
+	}
+	return d.updateRegister(reg, clear, set)
+}
+
+// eepromCommand runs an EEPROM command with automatic refresh disabled, and
+// waits for it to complete. Automatic refresh is re-enabled after an update,
+// as the EEPROM then matches the configuration registers.
+func (d *Device) eepromCommand(cmd uint8) error {
+	ctrl, err := d.readRegister(REG_CONTROL1)
+	if err != nil {
+		return err
+	}
+	err = d.bus.WriteRegister(uint8(d.Address), REG_CONTROL1, []byte{ctrl | CONTROL1_EERD})
+	if err != nil {
+		return err
+	}
+	if err := d.waitEEPROM(); err != nil {
+		return err
+	}
+	// The command register takes 0x00 followed by the command, written
+	// separately.
+	err = d.bus.WriteRegister(uint8(d.Address), REG_EEPROM_CMD, []byte{eepromCmdFirst})
+	if err != nil {
+		return err
+	}
+	err = d.bus.WriteRegister(uint8(d.Address), REG_EEPROM_CMD, []byte{cmd})
+	if err != nil {
+		return err
+	}
+	if err := d.waitEEPROM(); err != nil {
+		return err
+	}
+	if cmd == eepromCmdUpdate {
+		ctrl &^= CONTROL1_EERD
+	}
+	return d.bus.WriteRegister(uint8(d.Address), REG_CONTROL1, []byte{ctrl})
+}
+
+// waitEEPROM waits for an EEPROM operation to finish, which takes up to
+// about 63ms for an update of all configuration bytes.
+func (d *Device) waitEEPROM() error {
+	for i := 0; i < 20; i++ {
+		status, err := d.readRegister(REG_STATUS)
+		if err != nil {
+			return err
+		}
+		if status&STATUS_EEBUSY == 0 {
+			return nil
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return errEEPROMBusy
+}
+
+func (d *Device) readRegister(reg uint8) (uint8, error) {
+	data := []byte{0}
+	err := d.bus.ReadRegister(uint8(d.Address), reg, data)
+	return data[0], err
+}
+
+// updateRegister clears and then sets bits of a register.
+func (d *Device) updateRegister(reg, clear, set uint8) error {
+	v, err := d.readRegister(reg)
+	if err != nil {
+		return err
+	}
+	return d.bus.WriteRegister(uint8(d.Address), reg, []byte{v&^clear | set})
+}
+
+// decToBcd converts int to BCD
+func decToBcd(dec int) uint8 {
+	return uint8(dec + 6*(dec/10))
+}
+
+// bcdToDec converts BCD to int
+func bcdToDec(bcd uint8) int {
+	return int(bcd - 6*(bcd>>4))
+}
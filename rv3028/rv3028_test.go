@@ -0,0 +1,98 @@
+package rv3028
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestTime(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	d := New(bus)
+
+	at := time.Date(2024, 3, 10, 7, 30, 15, 0, time.UTC)
+	c.Assert(d.SetTime(at), qt.IsNil)
+	c.Assert(fdev.Registers[REG_SECONDS:REG_YEAR+1], qt.DeepEquals, []uint8{0x15, 0x30, 0x07, 0x00, 0x10, 0x03, 0x24})
+	got, err := d.ReadTime()
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, at)
+
+	c.Assert(d.SetUnixTime(0x12345678), qt.IsNil)
+	c.Assert(fdev.Registers[REG_UNIX_TIME0:REG_UNIX_TIME0+4], qt.DeepEquals, []uint8{0x78, 0x56, 0x34, 0x12})
+	unix, err := d.ReadUnixTime()
+	c.Assert(err, qt.IsNil)
+	c.Assert(unix, qt.Equals, uint32(0x12345678))
+}
+
+func TestAlarm(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	fdev.Registers[REG_STATUS] = STATUS_AF | STATUS_PORF
+	d := New(bus)
+
+	at := time.Date(2024, 3, 12, 22, 45, 0, 0, time.UTC) // Tuesday
+	c.Assert(d.SetAlarm(at, AlarmMatchHours), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARM_MINUTES:REG_ALARM_DAY+1], qt.DeepEquals, []uint8{0x45, 0x22, 0x92})
+	c.Assert(fdev.Registers[REG_CONTROL1]&CONTROL1_WADA, qt.Equals, uint8(CONTROL1_WADA))
+	c.Assert(fdev.Registers[REG_STATUS], qt.Equals, uint8(STATUS_PORF))
+
+	c.Assert(d.SetAlarm(at, AlarmMatchWeekday), qt.IsNil)
+	c.Assert(fdev.Registers[REG_ALARM_DAY], qt.Equals, uint8(2))
+	c.Assert(fdev.Registers[REG_CONTROL1]&CONTROL1_WADA, qt.Equals, uint8(0))
+
+	c.Assert(d.EnableAlarmInterrupt(), qt.IsNil)
+	c.Assert(fdev.Registers[REG_CONTROL2], qt.Equals, uint8(CONTROL2_AIE))
+	c.Assert(d.AlarmTriggered(), qt.IsFalse)
+	fdev.Registers[REG_STATUS] |= STATUS_AF
+	c.Assert(d.AlarmTriggered(), qt.IsTrue)
+}
+
+func TestTimer(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	d := New(bus)
+
+	c.Assert(d.SetTimer(10*time.Second, true), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMER_VALUE0:REG_TIMER_VALUE1+1], qt.DeepEquals, []uint8{0x80, 0x02})
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_TRPT|CONTROL1_TE|1))
+
+	c.Assert(d.SetTimer(90*time.Minute, false), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMER_VALUE0:REG_TIMER_VALUE1+1], qt.DeepEquals, []uint8{90, 0})
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_TE|3))
+
+	// the fastest clocks only count up to 4095 ticks
+	c.Assert(d.SetTimer(time.Second, false), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMER_VALUE0:REG_TIMER_VALUE1+1], qt.DeepEquals, []uint8{0x40, 0x00})
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_TE|1))
+	c.Assert(d.SetTimer(64*time.Second, false), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMER_VALUE0:REG_TIMER_VALUE1+1], qt.DeepEquals, []uint8{64, 0})
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_TE|2))
+	c.Assert(d.SetTimer(time.Second-time.Millisecond, false), qt.IsNil)
+	c.Assert(fdev.Registers[REG_TIMER_VALUE0:REG_TIMER_VALUE1+1], qt.DeepEquals, []uint8{0xFC, 0x0F})
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_TE|0))
+
+	c.Assert(d.SetTimer(0, false), qt.Equals, errInvalidTimer)
+	c.Assert(d.SetTimer(4096*time.Minute, false), qt.Equals, errInvalidTimer)
+}
+
+func TestBackupSwitchover(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fdev := bus.NewDevice(Address)
+	d := New(bus)
+
+	c.Assert(d.SetBackupSwitchover(BackupLevel, TrickleCharge5k), qt.IsNil)
+	c.Assert(fdev.Registers[REG_EEPROM_BACKUP], qt.Equals, uint8(0x0C|BACKUP_FEDE|BACKUP_TCE|1))
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(CONTROL1_EERD))
+
+	c.Assert(d.SaveConfig(), qt.IsNil)
+	c.Assert(fdev.Registers[REG_EEPROM_CMD], qt.Equals, uint8(eepromCmdUpdate))
+	c.Assert(fdev.Registers[REG_CONTROL1], qt.Equals, uint8(0))
+}
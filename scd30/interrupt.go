@@ -0,0 +1,22 @@
+//go:build tinygo
+
+package scd30
+
+import "machine"
+
+// ConfigureInterrupt sets up rdyPin, connected to the RDY output, which goes
+// high when a new measurement is available. DataReady then only asks the
+// sensor after the pin signalled new data.
+func (d *Device) ConfigureInterrupt(rdyPin machine.Pin) error {
+	rdyPin.Configure(machine.PinConfig{Mode: machine.PinInput})
+	err := rdyPin.SetInterrupt(machine.PinRising, func(machine.Pin) {
+		d.readyPending = true
+	})
+	if err != nil {
+		return err
+	}
+	d.hasInt = true
+	// Pick up a measurement that was ready before the interrupt was enabled.
+	d.readyPending = rdyPin.Get()
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package scd30 provides a driver for the Sensirion SCD30 CO2, temperature
+// and humidity sensor.
+//
+// Datasheet: https://sensirion.com/media/documents/4EAF6AF8/61652C3C/Sensirion_CO2_Sensors_SCD30_Datasheet.pdf
+// Interface description: https://sensirion.com/media/documents/D7CEEF4A/6165372F/Sensirion_CO2_Sensors_SCD30_Interface_Description.pdf
+//
+// Every 16-bit word sent to or read from the sensor is followed by a CRC-8.
+package scd30 // import "tinygo.org/x/drivers/scd30"
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
+)
+
+var (
+	errCRC        = errors.New("scd30: CRC mismatch")
+	errOutOfRange = errors.New("scd30: value out of range")
+)
+
+// readDelay is the time the sensor needs between a read command and the data.
+const readDelay = 3 * time.Millisecond
+
+// Device wraps an I2C connection to a SCD30 device.
+type Device struct {
+	bus     drivers.I2C
+	tx      [5]byte
+	rx      [18]byte
+	Address uint16
+
+	// hasInt is set when the RDY pin is connected, in which case DataReady
+	// only asks the sensor after readyPending was set by the interrupt.
+	hasInt       bool
+	readyPending bool
+
+	// used to cache the most recent readings
+	co2         float32
+	temperature float32
+	humidity    float32
+}
+
+// New returns SCD30 device for the provided I2C bus using default address of 0x61.
+// The bus must run at 100kHz or less and support clock stretching.
+func New(i2c drivers.I2C) *Device {
+	return &Device{
+		bus:     i2c,
+		Address: Address,
+	}
+}
+
+// Configure resets the sensor. It keeps the measurement interval,
+// calibration and offsets, which are stored in non-volatile memory.
+func (d *Device) Configure() error {
+	if err := d.sendCommand(CmdSoftReset); err != nil {
+		return err
+	}
+	time.Sleep(30 * time.Millisecond)
+	return nil
+}
+
+// Connected returns whether the sensor responds with a valid firmware version.
+func (d *Device) Connected() bool {
+	_, err := d.FirmwareVersion()
+	return err == nil
+}
+
+// FirmwareVersion returns the firmware version, major in the high byte.
+func (d *Device) FirmwareVersion() (uint16, error) {
+	return d.readWord(CmdFirmwareVersion)
+}
+
+// StartContinuousMeasurement starts measuring at the measurement interval.
+// The pressure in mbar (700 to 1400) compensates the CO2 reading, 0 disables
+// the compensation. It can be called again to update the pressure.
+func (d *Device) StartContinuousMeasurement(pressure uint16) error {
+	if pressure != 0 && (pressure < 700 || pressure > 1400) {
+		return errOutOfRange
+	}
+	return d.sendCommandWithValue(CmdStartContinuousMeasurement, pressure)
+}
+
+// StopContinuousMeasurement stops measuring.
+func (d *Device) StopContinuousMeasurement() error {
+	return d.sendCommand(CmdStopContinuousMeasurement)
+}
+
+// SetMeasurementInterval sets the time between measurements, from 2s to 1800s.
+func (d *Device) SetMeasurementInterval(interval time.Duration) error {
+	s := interval / time.Second
+	if s < 2 || s > 1800 {
+		return errOutOfRange
+	}
+	return d.sendCommandWithValue(CmdMeasurementInterval, uint16(s))
+}
+
+// MeasurementInterval returns the time between measurements.
+func (d *Device) MeasurementInterval() (time.Duration, error) {
+	s, err := d.readWord(CmdMeasurementInterval)
+	return time.Duration(s) * time.Second, err
+}
+
+// DataReady checks the sensor to see if new data is available. With the RDY
+// pin connected by ConfigureInterrupt, the sensor is only asked after the
+// pin signalled new data.
+func (d *Device) DataReady() (bool, error) {
+	if d.hasInt && !d.readyPending {
+		return false, nil
+	}
+	v, err := d.readWord(CmdDataReady)
+	if err != nil {
+		return false, err
+	}
+	return v == 1, nil
+}
+
+// ReadData reads the data from the sensor and caches it. It should only be
+// called when DataReady returns true.
+func (d *Device) ReadData() error {
+	d.readyPending = false
+	if err := d.sendCommandWithResult(CmdReadMeasurement, d.rx[:18]); err != nil {
+		return err
+	}
+	d.co2 = d.float(0)
+	d.temperature = d.float(6)
+	d.humidity = d.float(12)
+	return nil
+}
+
+// float decodes a float32 from the two words with CRC at rx[i:i+6].
+func (d *Device) float(i int) float32 {
+	b := d.rx[i:]
+	return math.Float32frombits(uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[3])<<8 | uint32(b[4]))
+}
+
+// ReadCO2 returns the CO2 concentration in PPM (parts per million), reading
+// new data if available.
+func (d *Device) ReadCO2() (co2 int32, err error) {
+	err = d.update()
+	return int32(d.co2 + 0.5), err
+}
+
+// ReadTemperature returns the temperature, reading new data if available.
+func (d *Device) ReadTemperature() (units.MilliCelsius, error) {
+	err := d.update()
+	return units.MilliCelsius(d.temperature * 1000), err
+}
+
+// ReadHumidity returns the relative humidity in hundredths of a percent,
+// reading new data if available.
+func (d *Device) ReadHumidity() (humidity int32, err error) {
+	err = d.update()
+	return int32(d.humidity * 100), err
+}
+
+func (d *Device) update() error {
+	ok, err := d.DataReady()
+	if err != nil {
+		return err
+	}
+	if ok {
+		return d.ReadData()
+	}
+	return nil
+}
+
+// SetAutomaticSelfCalibration enables or disables the automatic
+// self-calibration, which needs the sensor to see fresh air (400ppm) for at
+// least one hour a day during 7 days of continuous measurement.
+func (d *Device) SetAutomaticSelfCalibration(enable bool) error {
+	var v uint16
+	if enable {
+		v = 1
+	}
+	return d.sendCommandWithValue(CmdAutomaticSelfCalibration, v)
+}
+
+// ForcedRecalibration calibrates the sensor to a known CO2 concentration in
+// PPM (400 to 2000). The sensor must have been measuring continuously for at
+// least 2 minutes in a stable environment at that concentration.
+func (d *Device) ForcedRecalibration(co2 uint16) error {
+	if co2 < 400 || co2 > 2000 {
+		return errOutOfRange
+	}
+	return d.sendCommandWithValue(CmdForcedRecalibration, co2)
+}
+
+// SetTemperatureOffset sets how much the sensor heats itself up, which is
+// subtracted from the temperature reading.
+func (d *Device) SetTemperatureOffset(offset units.MilliCelsius) error {
+	if offset < 0 {
+		return errOutOfRange
+	}
+	return d.sendCommandWithValue(CmdTemperatureOffset, uint16(offset/10))
+}
+
+// SetAltitude sets the altitude in meters above sea level, which compensates
+// the CO2 reading when no pressure is given to StartContinuousMeasurement.
+func (d *Device) SetAltitude(meters uint16) error {
+	return d.sendCommandWithValue(CmdAltitude, meters)
+}
+
+func (d *Device) sendCommand(command uint16) error {
+	binary.BigEndian.PutUint16(d.tx[0:], command)
+	return d.bus.Tx(d.Address, d.tx[0:2], nil)
+}
+
+func (d *Device) sendCommandWithValue(command, value uint16) error {
+	binary.BigEndian.PutUint16(d.tx[0:], command)
+	binary.BigEndian.PutUint16(d.tx[2:], value)
+	d.tx[4] = crc8(d.tx[2:4])
+	return d.bus.Tx(d.Address, d.tx[0:5], nil)
+}
+
+func (d *Device) sendCommandWithResult(command uint16, result []byte) error {
+	binary.BigEndian.PutUint16(d.tx[0:], command)
+	if err := d.bus.Tx(d.Address, d.tx[0:2], nil); err != nil {
+		return err
+	}
+	time.Sleep(readDelay)
+	if err := d.bus.Tx(d.Address, nil, result); err != nil {
+		return err
+	}
+	for i := 0; i+2 < len(result); i += 3 {
+		if crc8(result[i:i+2]) != result[i+2] {
+			return errCRC
+		}
+	}
+	return nil
+}
+
+func (d *Device) readWord(command uint16) (uint16, error) {
+	if err := d.sendCommandWithResult(command, d.rx[:3]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(d.rx[:2]), nil
+}
+
+func crc8(buf []byte) uint8 {
+	var crc uint8 = 0xff
+	for _, b := range buf {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = (crc << 1) ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
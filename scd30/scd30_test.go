@@ -0,0 +1,117 @@
+package scd30
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+	"tinygo.org/x/drivers/units"
+)
+
+type T = tester.Transaction
+
+func TestCRC(t *testing.T) {
+	c := qt.New(t)
+	// example from the interface description
+	c.Assert(crc8([]byte{0xBE, 0xEF}), qt.Equals, uint8(0x92))
+}
+
+// words encodes values as big-endian words followed by their CRC.
+func words(values ...uint16) []byte {
+	var b []byte
+	for _, v := range values {
+		w := []byte{byte(v >> 8), byte(v)}
+		b = append(b, w[0], w[1], crc8(w))
+	}
+	return b
+}
+
+func floats(values ...float32) []byte {
+	var w []uint16
+	for _, f := range values {
+		v := math.Float32bits(f)
+		w = append(w, uint16(v>>16), uint16(v))
+	}
+	return words(w...)
+}
+
+func TestMeasurement(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: append([]byte{0x00, 0x10}, words(1013)...)},
+		T{W: append([]byte{0x46, 0x00}, words(5)...)},
+		T{W: []byte{0x02, 0x02}},
+		T{R: words(1)},
+		T{W: []byte{0x03, 0x00}},
+		T{R: floats(439.09, 27.2, 48.8)},
+		T{W: []byte{0x02, 0x02}},
+		T{R: words(0)},
+		T{W: []byte{0x02, 0x02}},
+		T{R: words(0)},
+	)
+
+	d := New(bus)
+	c.Assert(d.StartContinuousMeasurement(1013), qt.IsNil)
+	c.Assert(d.SetMeasurementInterval(5*time.Second), qt.IsNil)
+	co2, err := d.ReadCO2()
+	c.Assert(err, qt.IsNil)
+	c.Assert(co2, qt.Equals, int32(439))
+	temp, err := d.ReadTemperature()
+	c.Assert(err, qt.IsNil)
+	c.Assert(temp, qt.Equals, units.MilliCelsius(27200))
+	hum, err := d.ReadHumidity()
+	c.Assert(err, qt.IsNil)
+	c.Assert(hum, qt.Equals, int32(4880))
+	dev.AssertDone()
+}
+
+func TestCRCError(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{0xD1, 0x00}},
+		T{R: []byte{0x03, 0x42, 0x00}},
+	)
+
+	d := New(bus)
+	c.Assert(d.Connected(), qt.IsFalse)
+	dev.AssertDone()
+}
+
+func TestInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{0x02, 0x02}},
+		T{R: words(1)},
+	)
+
+	d := New(bus)
+	d.hasInt = true
+	ok, err := d.DataReady()
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsFalse) // the sensor is not asked
+	d.readyPending = true
+	ok, err = d.DataReady()
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	dev.AssertDone()
+}
+
+func TestRange(t *testing.T) {
+	c := qt.New(t)
+	d := New(tester.NewI2CBus(c))
+	c.Assert(d.StartContinuousMeasurement(500), qt.Equals, errOutOfRange)
+	c.Assert(d.SetMeasurementInterval(time.Second), qt.Equals, errOutOfRange)
+	c.Assert(d.ForcedRecalibration(300), qt.Equals, errOutOfRange)
+}
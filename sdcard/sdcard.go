@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"machine"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 const (
@@ -321,6 +323,15 @@ func (d Device) readRegister(cmd uint8, dst []byte) error {
 	return nil
 }
 
+// tx transfers a data block, using DMA if the SPI bus implements
+// drivers.AsyncSPI so that other goroutines can run in the meantime.
+func (d Device) tx(w, r []byte) error {
+	if err := drivers.StartTx(d.bus, w, r); err != nil {
+		return err
+	}
+	return drivers.WaitTx(d.bus)
+}
+
 // ReadData reads 512 bytes from sdcard into dst.
 func (d Device) ReadData(block uint32, dst []byte) error {
 	if len(dst) < 512 {
@@ -338,7 +349,7 @@ func (d Device) ReadData(block uint32, dst []byte) error {
 		return fmt.Errorf("waitStartBlock()")
 	}
 
-	err := d.bus.Tx(dummy[:512], dst)
+	err := d.tx(dummy[:512], dst)
 	if err != nil {
 		return err
 	}
@@ -375,11 +386,8 @@ func (d Device) WriteMulti(buf []byte) error {
 	// send Data Token for CMD25
 	d.bus.Transfer(byte(0xFC))
 
-	for i := 0; i < 512; i++ {
-		_, err := d.bus.Transfer(buf[i])
-		if err != nil {
-			return err
-		}
+	if err := d.tx(buf[:512], nil); err != nil {
+		return err
 	}
 
 	// send dummy CRC (2 byte)
@@ -440,7 +448,7 @@ func (d Device) WriteData(block uint32, src []byte) error {
 	token := byte(0xFE)
 	d.bus.Transfer(token)
 
-	err := d.bus.Tx(src[:512], nil)
+	err := d.tx(src[:512], nil)
 	if err != nil {
 		return err
 	}
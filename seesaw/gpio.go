@@ -0,0 +1,100 @@
+package seesaw
+
+import "time"
+
+// PinMode is the mode of a GPIO pin.
+type PinMode uint8
+
+const (
+	PinInput PinMode = iota
+	PinInputPullup
+	PinInputPulldown
+	PinOutput
+)
+
+// SetPinMode configures the GPIO pins set in the pins bit mask.
+func (d *Device) SetPinMode(pins uint32, mode PinMode) error {
+	switch mode {
+	case PinOutput:
+		return d.write32(baseGPIO, gpioDirSet, pins)
+	case PinInput:
+		if err := d.write32(baseGPIO, gpioDirClr, pins); err != nil {
+			return err
+		}
+		return d.write32(baseGPIO, gpioPullEnClr, pins)
+	default:
+		if err := d.write32(baseGPIO, gpioDirClr, pins); err != nil {
+			return err
+		}
+		if err := d.write32(baseGPIO, gpioPullEnSet, pins); err != nil {
+			return err
+		}
+		// the output level selects the pull direction
+		if mode == PinInputPullup {
+			return d.write32(baseGPIO, gpioBulkSet, pins)
+		}
+		return d.write32(baseGPIO, gpioBulkClr, pins)
+	}
+}
+
+// DigitalWrite sets the output pins in the pins bit mask high or low.
+func (d *Device) DigitalWrite(pins uint32, high bool) error {
+	if high {
+		return d.write32(baseGPIO, gpioBulkSet, pins)
+	}
+	return d.write32(baseGPIO, gpioBulkClr, pins)
+}
+
+// DigitalRead returns the levels of the pins in the pins bit mask, a bit is
+// set for each pin that is high.
+func (d *Device) DigitalRead(pins uint32) (uint32, error) {
+	v, err := d.read32(baseGPIO, gpioBulk)
+	return v & pins, err
+}
+
+// SetGPIOInterrupts enables or disables the interrupt output for changes of
+// the pins in the pins bit mask.
+func (d *Device) SetGPIOInterrupts(pins uint32, enable bool) error {
+	if enable {
+		return d.write32(baseGPIO, gpioIntEnSet, pins)
+	}
+	return d.write32(baseGPIO, gpioIntEnClr, pins)
+}
+
+// GPIOInterruptFlags returns the pins that changed since the last call, which
+// also clears the interrupt.
+func (d *Device) GPIOInterruptFlags() (uint32, error) {
+	return d.read32(baseGPIO, gpioIntFlag)
+}
+
+// AnalogRead returns the 10-bit ADC value of a pin. On SAMD09 boards, as
+// found by Configure, only pins 2 to 5 have an ADC channel.
+func (d *Device) AnalogRead(pin uint8) (uint16, error) {
+	channel := pin
+	if d.hwID == HardwareIDSAMD09 {
+		// pins 2 to 5 are ADC channels 0 to 3
+		if pin < 2 || pin > 5 {
+			return 0, errNoADCChannel
+		}
+		channel = pin - 2
+	}
+	buf := d.buf[:2]
+	err := d.readDelay(baseADC, adcChannelOffset+channel, buf, d.ReadDelay+500*time.Microsecond)
+	return uint16(buf[0])<<8 | uint16(buf[1]), err
+}
+
+// TouchRead returns the capacitive touch value of a pin, which increases when
+// touched. The soil moisture sensor reports the moisture this way on pin 0.
+func (d *Device) TouchRead(pin uint8) (uint16, error) {
+	buf := d.buf[:2]
+	var err error
+	// the measurement takes a few milliseconds, and the firmware returns
+	// 0xFFFF while it is busy
+	for i := 0; i < 5; i++ {
+		err = d.readDelay(baseTouch, touchChannelOffset+pin, buf, 3*time.Millisecond+time.Duration(i)*time.Millisecond)
+		if err == nil && (buf[0] != 0xFF || buf[1] != 0xFF) {
+			break
+		}
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), err
+}
@@ -0,0 +1,88 @@
+package seesaw
+
+// Edge is a key transition reported by the keypad module.
+type Edge uint8
+
+const (
+	EdgeHigh    Edge = 0 // the key is released
+	EdgeLow     Edge = 1 // the key is pressed
+	EdgeFalling Edge = 2 // the key was pressed
+	EdgeRising  Edge = 3 // the key was released
+)
+
+// KeyEvent is a key transition read from the keypad FIFO.
+type KeyEvent struct {
+	Key  uint8
+	Edge Edge
+}
+
+// SetKeypadEvent enables or disables reporting the given edge of a key.
+func (d *Device) SetKeypadEvent(key uint8, edge Edge, enable bool) error {
+	state := uint8(1) << (edge + 1)
+	if enable {
+		state |= 1
+	}
+	return d.Write(baseKeypad, keypadEvent, []byte{key, state})
+}
+
+// SetKeypadInterrupt enables or disables the interrupt output when events
+// are waiting in the FIFO.
+func (d *Device) SetKeypadInterrupt(enable bool) error {
+	if enable {
+		return d.Write(baseKeypad, keypadIntEnSet, []byte{1})
+	}
+	return d.Write(baseKeypad, keypadIntEnClr, []byte{1})
+}
+
+// ReadKeypadEvents reads waiting key events into events and returns how many
+// were read.
+func (d *Device) ReadKeypadEvents(events []KeyEvent) (int, error) {
+	buf := d.buf[:1]
+	if err := d.Read(baseKeypad, keypadCount, buf); err != nil {
+		return 0, err
+	}
+	n := int(buf[0])
+	if n > len(events) {
+		n = len(events)
+	}
+	if n > len(d.buf) {
+		n = len(d.buf)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	buf = d.buf[:n]
+	if err := d.Read(baseKeypad, keypadFIFO, buf); err != nil {
+		return 0, err
+	}
+	for i, b := range buf {
+		events[i] = KeyEvent{Key: b >> 2, Edge: Edge(b & 3)}
+	}
+	return n, nil
+}
+
+// EncoderPosition returns the position of a rotary encoder.
+func (d *Device) EncoderPosition(encoder uint8) (int32, error) {
+	v, err := d.read32(baseEncoder, encoderPosition+encoder)
+	return int32(v), err
+}
+
+// SetEncoderPosition sets the position of a rotary encoder.
+func (d *Device) SetEncoderPosition(encoder uint8, pos int32) error {
+	return d.write32(baseEncoder, encoderPosition+encoder, uint32(pos))
+}
+
+// EncoderDelta returns how far a rotary encoder moved since the last call.
+func (d *Device) EncoderDelta(encoder uint8) (int32, error) {
+	v, err := d.read32(baseEncoder, encoderDelta+encoder)
+	return int32(v), err
+}
+
+// SetEncoderInterrupt enables or disables the interrupt output when a rotary
+// encoder moves.
+func (d *Device) SetEncoderInterrupt(encoder uint8, enable bool) error {
+	if enable {
+		return d.Write(baseEncoder, encoderIntEnSet+encoder, []byte{1})
+	}
+	return d.Write(baseEncoder, encoderIntEnClr+encoder, []byte{1})
+}
@@ -0,0 +1,42 @@
+package seesaw
+
+import "image/color"
+
+// maxNeoPixelWrite is the largest chunk of pixel data the firmware accepts in
+// one write, after the 2 byte offset.
+const maxNeoPixelWrite = 30
+
+// NeoPixelConfigure sets the seesaw pin driving a strip of count GRB pixels.
+func (d *Device) NeoPixelConfigure(pin uint8, count int) error {
+	if err := d.Write(baseNeoPixel, neoPixelSpeed, []byte{1}); err != nil { // 800kHz
+		return err
+	}
+	n := count * 3
+	if err := d.Write(baseNeoPixel, neoPixelBufLength, []byte{byte(n >> 8), byte(n)}); err != nil {
+		return err
+	}
+	return d.Write(baseNeoPixel, neoPixelPin, []byte{pin})
+}
+
+// NeoPixelWriteColors sends the colors to the pixel buffer of the seesaw and
+// shows them.
+func (d *Device) NeoPixelWriteColors(colors []color.RGBA) error {
+	var chunk [2 + maxNeoPixelWrite]byte
+	offset := 0
+	for len(colors) > 0 {
+		n := 2
+		for len(colors) > 0 && n+3 <= len(chunk) {
+			c := colors[0]
+			chunk[n], chunk[n+1], chunk[n+2] = c.G, c.R, c.B
+			n += 3
+			colors = colors[1:]
+		}
+		chunk[0] = byte(offset >> 8)
+		chunk[1] = byte(offset)
+		if err := d.Write(baseNeoPixel, neoPixelBuf, chunk[:n]); err != nil {
+			return err
+		}
+		offset += n - 2
+	}
+	return d.Write(baseNeoPixel, neoPixelShow, nil)
+}
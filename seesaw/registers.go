@@ -0,0 +1,90 @@
+package seesaw
+
+// Default I2C addresses of some seesaw boards.
+const (
+	Address          = 0x49 // ATtiny817 and SAMD09 breakouts, ANO rotary encoder
+	AddressSoil      = 0x36 // capacitive soil moisture sensor
+	AddressNeoKey1x4 = 0x30
+)
+
+// Module base registers.
+const (
+	baseStatus    = 0x00
+	baseGPIO      = 0x01
+	baseADC       = 0x09
+	baseNeoPixel  = 0x0E
+	baseTouch     = 0x0F
+	baseKeypad    = 0x10
+	baseEncoder   = 0x11
+	baseInterrupt = 0x0B
+)
+
+// Status module functions.
+const (
+	statusHWID    = 0x01
+	statusVersion = 0x02
+	statusOptions = 0x03
+	statusTemp    = 0x04
+	statusSWRST   = 0x7F
+)
+
+// Hardware IDs reported by the status module.
+const (
+	HardwareIDSAMD09     = 0x55
+	HardwareIDATtiny806  = 0x84
+	HardwareIDATtiny807  = 0x85
+	HardwareIDATtiny816  = 0x86
+	HardwareIDATtiny817  = 0x87
+	HardwareIDATtiny1616 = 0x88
+	HardwareIDATtiny1617 = 0x89
+)
+
+// GPIO module functions.
+const (
+	gpioDirSet    = 0x02
+	gpioDirClr    = 0x03
+	gpioBulk      = 0x04
+	gpioBulkSet   = 0x05
+	gpioBulkClr   = 0x06
+	gpioIntEnSet  = 0x08
+	gpioIntEnClr  = 0x09
+	gpioIntFlag   = 0x0A
+	gpioPullEnSet = 0x0B
+	gpioPullEnClr = 0x0C
+)
+
+// ADC module functions.
+const (
+	adcChannelOffset = 0x07
+)
+
+// NeoPixel module functions.
+const (
+	neoPixelPin       = 0x01
+	neoPixelSpeed     = 0x02
+	neoPixelBufLength = 0x03
+	neoPixelBuf       = 0x04
+	neoPixelShow      = 0x05
+)
+
+// Touch module functions.
+const (
+	touchChannelOffset = 0x10
+)
+
+// Keypad module functions.
+const (
+	keypadEvent    = 0x01
+	keypadIntEnSet = 0x02
+	keypadIntEnClr = 0x03
+	keypadCount    = 0x04
+	keypadFIFO     = 0x10
+)
+
+// Encoder module functions, the encoder number is added to them.
+const (
+	encoderIntEnSet = 0x10
+	encoderIntEnClr = 0x20
+	encoderPosition = 0x30
+	encoderDelta    = 0x40
+)
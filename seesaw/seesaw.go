@@ -0,0 +1,151 @@
+// Package seesaw implements a driver for the Adafruit seesaw firmware, which
+// turns a small microcontroller into an I2C peripheral with GPIO, ADC,
+// NeoPixel, capacitive touch, keypad and rotary encoder modules. Many
+// breakouts are built on it, like the ANO rotary encoder, the NeoKey keypads
+// and the capacitive soil moisture sensor.
+//
+// Protocol: https://learn.adafruit.com/adafruit-seesaw-atsamd09-breakout/reading-and-writing-data
+//
+// Each module is addressed by a base register and a function register. Reads
+// need some processing time on the seesaw between the request and the data,
+// set by ReadDelay.
+package seesaw // import "tinygo.org/x/drivers/seesaw"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
+)
+
+var (
+	errUnknownHardware = errors.New("seesaw: unknown hardware ID")
+	errNoADCChannel    = errors.New("seesaw: pin has no ADC channel")
+	errWriteTooLong    = errors.New("seesaw: data too long to write")
+)
+
+// Module bits returned by Options.
+const (
+	ModuleGPIO     = 1 << baseGPIO
+	ModuleADC      = 1 << baseADC
+	ModuleNeoPixel = 1 << baseNeoPixel
+	ModuleTouch    = 1 << baseTouch
+	ModuleKeypad   = 1 << baseKeypad
+	ModuleEncoder  = 1 << baseEncoder
+)
+
+// Device wraps an I2C connection to a seesaw device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+
+	// ReadDelay is the time between requesting and reading data. Defaults
+	// to 250µs, some boards need more.
+	ReadDelay time.Duration
+
+	buf  [34]byte
+	hwID uint8 // hardware ID found by Configure
+}
+
+// New creates a new seesaw connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:       bus,
+		Address:   Address,
+		ReadDelay: 250 * time.Microsecond,
+	}
+}
+
+// Configure resets the seesaw and checks that it responds with a known
+// hardware ID.
+func (d *Device) Configure() error {
+	if err := d.SoftReset(); err != nil {
+		return err
+	}
+	// the firmware takes a moment to restart
+	time.Sleep(10 * time.Millisecond)
+	id, err := d.HardwareID()
+	if err != nil {
+		return err
+	}
+	switch id {
+	case HardwareIDSAMD09, HardwareIDATtiny806, HardwareIDATtiny807, HardwareIDATtiny816,
+		HardwareIDATtiny817, HardwareIDATtiny1616, HardwareIDATtiny1617:
+		d.hwID = id
+		return nil
+	}
+	return errUnknownHardware
+}
+
+// SoftReset restarts the seesaw firmware, resetting all modules.
+func (d *Device) SoftReset() error {
+	return d.Write(baseStatus, statusSWRST, []byte{0xFF})
+}
+
+// HardwareID returns the chip the seesaw firmware runs on, one of the
+// HardwareID constants.
+func (d *Device) HardwareID() (uint8, error) {
+	buf := d.buf[:1]
+	err := d.Read(baseStatus, statusHWID, buf)
+	return buf[0], err
+}
+
+// Version returns the product code of the board (for example 4991 for the
+// ANO rotary encoder) and the firmware date code.
+func (d *Device) Version() (product uint16, date uint16, err error) {
+	v, err := d.read32(baseStatus, statusVersion)
+	return uint16(v >> 16), uint16(v), err
+}
+
+// Options returns the modules compiled into the firmware, as a combination of
+// the Module bits.
+func (d *Device) Options() (uint32, error) {
+	return d.read32(baseStatus, statusOptions)
+}
+
+// ReadTemperature returns the temperature of the seesaw chip. Only SAMD09
+// based boards support it.
+func (d *Device) ReadTemperature() (units.MilliCelsius, error) {
+	v, err := d.read32(baseStatus, statusTemp)
+	// 16.16 fixed point degrees
+	return units.MilliCelsius(int64(v&0x3FFFFFFF) * 1000 >> 16), err
+}
+
+// Read reads len(buf) bytes from a module function register.
+func (d *Device) Read(base, function uint8, buf []byte) error {
+	return d.readDelay(base, function, buf, d.ReadDelay)
+}
+
+func (d *Device) readDelay(base, function uint8, buf []byte, delay time.Duration) error {
+	if err := d.bus.Tx(d.Address, []byte{base, function}, nil); err != nil {
+		return err
+	}
+	time.Sleep(delay)
+	return d.bus.Tx(d.Address, nil, buf)
+}
+
+// Write writes data to a module function register, up to 32 bytes.
+func (d *Device) Write(base, function uint8, data []byte) error {
+	if 2+len(data) > len(d.buf) {
+		return errWriteTooLong
+	}
+	buf := d.buf[:2+len(data)]
+	buf[0] = base
+	buf[1] = function
+	copy(buf[2:], data)
+	return d.bus.Tx(d.Address, buf, nil)
+}
+
+func (d *Device) read32(base, function uint8) (uint32, error) {
+	buf := d.buf[:4]
+	err := d.Read(base, function, buf)
+	return uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3]), err
+}
+
+func (d *Device) write32(base, function uint8, v uint32) error {
+	return d.Write(base, function, []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+}
@@ -0,0 +1,176 @@
+package seesaw
+
+import (
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+type T = tester.Transaction
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{baseStatus, statusSWRST, 0xFF}},
+		T{W: []byte{baseStatus, statusHWID}},
+		T{R: []byte{HardwareIDATtiny817}},
+		T{W: []byte{baseStatus, statusVersion}},
+		T{R: []byte{0x13, 0x7F, 0x5A, 0x21}},
+	)
+
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+	product, date, err := d.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(product, qt.Equals, uint16(4991))
+	c.Assert(date, qt.Equals, uint16(0x5A21))
+	dev.AssertDone()
+}
+
+func TestConfigureUnknown(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Responses = []byte{0x12}
+
+	d := New(bus)
+	c.Assert(d.Configure(), qt.Equals, errUnknownHardware)
+}
+
+func TestGPIO(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{baseGPIO, gpioDirClr, 0, 0, 0x01, 0x00}},
+		T{W: []byte{baseGPIO, gpioPullEnSet, 0, 0, 0x01, 0x00}},
+		T{W: []byte{baseGPIO, gpioBulkSet, 0, 0, 0x01, 0x00}},
+		T{W: []byte{baseGPIO, gpioBulk}},
+		T{R: []byte{0, 0, 0x01, 0x20}},
+		T{W: []byte{baseADC, adcChannelOffset + 3}},
+		T{R: []byte{0x02, 0x10}},
+	)
+
+	d := New(bus)
+	c.Assert(d.SetPinMode(1<<8, PinInputPullup), qt.IsNil)
+	v, err := d.DigitalRead(1<<8 | 1<<0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, uint32(1<<8))
+	a, err := d.AnalogRead(3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(a, qt.Equals, uint16(0x210))
+	dev.AssertDone()
+}
+
+func TestAnalogReadSAMD09(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{baseStatus, statusSWRST, 0xFF}},
+		T{W: []byte{baseStatus, statusHWID}},
+		T{R: []byte{HardwareIDSAMD09}},
+		T{W: []byte{baseADC, adcChannelOffset + 1}},
+		T{R: []byte{0x03, 0xFF}},
+	)
+
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+	a, err := d.AnalogRead(3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(a, qt.Equals, uint16(0x3FF))
+	_, err = d.AnalogRead(6)
+	c.Assert(err, qt.Equals, errNoADCChannel)
+	dev.AssertDone()
+}
+
+func TestWriteTooLong(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+
+	d := New(bus)
+	c.Assert(d.Write(baseNeoPixel, 0, make([]byte, 33)), qt.Equals, errWriteTooLong)
+	dev.AssertDone()
+}
+
+func TestTouchRetry(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, AddressSoil)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{baseTouch, touchChannelOffset}},
+		T{R: []byte{0xFF, 0xFF}},
+		T{W: []byte{baseTouch, touchChannelOffset}},
+		T{R: []byte{0x01, 0xF4}},
+	)
+
+	d := New(bus)
+	d.Address = AddressSoil
+	v, err := d.TouchRead(0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, uint16(500))
+	dev.AssertDone()
+}
+
+func TestNeoPixel(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+
+	d := New(bus)
+	c.Assert(d.NeoPixelConfigure(6, 12), qt.IsNil)
+	colors := make([]color.RGBA, 12)
+	colors[11] = color.RGBA{R: 1, G: 2, B: 3}
+	c.Assert(d.NeoPixelWriteColors(colors), qt.IsNil)
+
+	log := dev.Log
+	c.Assert(log, qt.HasLen, 3+3)
+	c.Assert(log[1].W, qt.DeepEquals, []byte{baseNeoPixel, neoPixelBufLength, 0, 36})
+	c.Assert(log[2].W, qt.DeepEquals, []byte{baseNeoPixel, neoPixelPin, 6})
+	// 36 bytes of pixel data are split in 30 and 6 bytes
+	c.Assert(log[3].W[2:4], qt.DeepEquals, []byte{0, 0})
+	c.Assert(log[3].W, qt.HasLen, 2+2+30)
+	c.Assert(log[4].W, qt.DeepEquals, []byte{baseNeoPixel, neoPixelBuf, 0, 30, 0, 0, 0, 2, 1, 3})
+	c.Assert(log[5].W, qt.DeepEquals, []byte{baseNeoPixel, neoPixelShow})
+}
+
+func TestKeypadAndEncoder(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, AddressNeoKey1x4)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{baseKeypad, keypadEvent, 5, 1<<(EdgeFalling+1) | 1}},
+		T{W: []byte{baseKeypad, keypadCount}},
+		T{R: []byte{2}},
+		T{W: []byte{baseKeypad, keypadFIFO}},
+		T{R: []byte{5<<2 | byte(EdgeFalling), 5<<2 | byte(EdgeRising)}},
+		T{W: []byte{baseEncoder, encoderPosition}},
+		T{R: []byte{0xFF, 0xFF, 0xFF, 0xFD}},
+	)
+
+	d := New(bus)
+	d.Address = AddressNeoKey1x4
+	c.Assert(d.SetKeypadEvent(5, EdgeFalling, true), qt.IsNil)
+	events := make([]KeyEvent, 4)
+	n, err := d.ReadKeypadEvents(events)
+	c.Assert(err, qt.IsNil)
+	c.Assert(events[:n], qt.DeepEquals, []KeyEvent{{5, EdgeFalling}, {5, EdgeRising}})
+	pos, err := d.EncoderPosition(0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pos, qt.Equals, int32(-3))
+	dev.AssertDone()
+}
@@ -0,0 +1,15 @@
+package sgp30
+
+const (
+	// Address is the I2C address, it is fixed.
+	Address = 0x58
+
+	CmdInitAirQuality    = 0x2003
+	CmdMeasureAirQuality = 0x2008
+	CmdGetBaseline       = 0x2015
+	CmdSetBaseline       = 0x201E
+	CmdSetHumidity       = 0x2061
+	CmdMeasureRaw        = 0x2050
+	CmdGetFeatureSet     = 0x202F
+	CmdSerialNumber      = 0x3682
+)
@@ -0,0 +1,227 @@
+// Package sgp30 provides a driver for the Sensirion SGP30 air quality
+// sensor, which measures total volatile organic compounds (TVOC) and an
+// equivalent CO2 concentration (CO2eq).
+//
+// Datasheet: https://sensirion.com/media/documents/984E0DD5/61644B8B/Sensirion_Gas_Sensors_Datasheet_SGP30.pdf
+//
+// The sensor runs a dynamic baseline compensation that needs MeasureAirQuality
+// to be called every second. The baseline it learns is lost on power down, so
+// it should be saved with Baseline about once an hour and restored with
+// SetBaseline after Configure. Without a saved baseline, the sensor needs 12
+// hours of operation before its baseline is valid.
+package sgp30 // import "tinygo.org/x/drivers/sgp30"
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
+)
+
+var (
+	errCRC              = errors.New("sgp30: CRC mismatch")
+	errBaselineNotReady = errors.New("sgp30: baseline not valid yet")
+	errOutOfRange       = errors.New("sgp30: value out of range")
+)
+
+// BaselineWarmup is how long the sensor must run without a restored baseline
+// before Baseline returns a valid baseline.
+const BaselineWarmup = 12 * time.Hour
+
+// Baseline holds the compensation values of the baseline algorithm.
+type Baseline struct {
+	CO2eq uint16
+	TVOC  uint16
+}
+
+type Device struct {
+	bus     drivers.I2C
+	tx      [8]byte
+	rx      [9]byte
+	Address uint16
+
+	// started is when the baseline algorithm was initialized, baselineSet
+	// is set when a baseline was restored since then.
+	started     time.Time
+	baselineSet bool
+	now         func() time.Time
+}
+
+// New returns SGP30 device for the provided I2C bus.
+func New(i2c drivers.I2C) *Device {
+	return &Device{
+		bus:     i2c,
+		Address: Address,
+		now:     time.Now,
+	}
+}
+
+// Configure initializes the air quality measurement. For the first 15
+// seconds MeasureAirQuality returns fixed values of 400ppm CO2eq and 0ppb
+// TVOC.
+func (d *Device) Configure() error {
+	if err := d.sendCommand(CmdInitAirQuality, 10*time.Millisecond); err != nil {
+		return err
+	}
+	d.started = d.now()
+	d.baselineSet = false
+	return nil
+}
+
+// Connected returns whether the sensor responds with a valid serial number.
+func (d *Device) Connected() bool {
+	_, err := d.SerialNumber()
+	return err == nil
+}
+
+// SerialNumber returns the 48-bit serial number of the sensor.
+func (d *Device) SerialNumber() (uint64, error) {
+	if err := d.readWords(CmdSerialNumber, time.Millisecond, d.rx[:9]); err != nil {
+		return 0, err
+	}
+	r := d.rx[:]
+	return uint64(r[0])<<40 | uint64(r[1])<<32 | uint64(r[3])<<24 | uint64(r[4])<<16 | uint64(r[6])<<8 | uint64(r[7]), nil
+}
+
+// MeasureAirQuality returns the CO2eq concentration in ppm and the TVOC
+// concentration in ppb. It must be called every second to keep the baseline
+// compensation working.
+func (d *Device) MeasureAirQuality() (co2eq, tvoc uint16, err error) {
+	if err := d.readWords(CmdMeasureAirQuality, 12*time.Millisecond, d.rx[:6]); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint16(d.rx[0:2]), binary.BigEndian.Uint16(d.rx[3:5]), nil
+}
+
+// MeasureRaw returns the raw H2 and ethanol signals.
+func (d *Device) MeasureRaw() (h2, ethanol uint16, err error) {
+	if err := d.readWords(CmdMeasureRaw, 25*time.Millisecond, d.rx[:6]); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint16(d.rx[0:2]), binary.BigEndian.Uint16(d.rx[3:5]), nil
+}
+
+// Baseline returns the current baseline, to be stored and restored with
+// SetBaseline after the next power up. It returns an error until a baseline
+// was restored or the sensor has run for BaselineWarmup since Configure.
+// A stored baseline older than 7 days should not be restored.
+func (d *Device) Baseline() (Baseline, error) {
+	if !d.BaselineValid() {
+		return Baseline{}, errBaselineNotReady
+	}
+	if err := d.readWords(CmdGetBaseline, 10*time.Millisecond, d.rx[:6]); err != nil {
+		return Baseline{}, err
+	}
+	return Baseline{
+		CO2eq: binary.BigEndian.Uint16(d.rx[0:2]),
+		TVOC:  binary.BigEndian.Uint16(d.rx[3:5]),
+	}, nil
+}
+
+// BaselineValid returns whether Baseline returns a valid baseline.
+func (d *Device) BaselineValid() bool {
+	return d.baselineSet || (!d.started.IsZero() && d.now().Sub(d.started) >= BaselineWarmup)
+}
+
+// SetBaseline restores a baseline saved by Baseline. It must be called after
+// Configure.
+func (d *Device) SetBaseline(b Baseline) error {
+	// the TVOC baseline is sent first
+	if err := d.sendCommandWithValues(CmdSetBaseline, b.TVOC, b.CO2eq); err != nil {
+		return err
+	}
+	d.baselineSet = true
+	return nil
+}
+
+// SetAbsoluteHumidity sets the absolute humidity in mg/m³ (up to 255999) used
+// to compensate the measurements. Zero disables the compensation.
+func (d *Device) SetAbsoluteHumidity(mgPerM3 uint32) error {
+	if mgPerM3 >= 256000 {
+		return errOutOfRange
+	}
+	// 8.8 fixed point g/m³
+	v := uint16((mgPerM3*256 + 500) / 1000)
+	if v == 0 && mgPerM3 != 0 {
+		// zero would disable the compensation
+		v = 1
+	}
+	return d.sendCommandWithValues(CmdSetHumidity, v)
+}
+
+// SetHumidity compensates the measurements for the humidity from a
+// temperature and a relative humidity in hundredths of a percent, as read
+// from a separate humidity sensor.
+func (d *Device) SetHumidity(temp units.MilliCelsius, humidity int32) error {
+	return d.SetAbsoluteHumidity(AbsoluteHumidity(temp, humidity))
+}
+
+// AbsoluteHumidity returns the absolute humidity in mg/m³ for a temperature
+// and a relative humidity in hundredths of a percent.
+func AbsoluteHumidity(temp units.MilliCelsius, humidity int32) uint32 {
+	if humidity <= 0 {
+		return 0
+	}
+	t := float64(temp) / 1000
+	rh := float64(humidity) / 100
+	// Magnus formula, from the SGP30 driver integration guide
+	ah := 216.7 * (rh / 100 * 6.112 * math.Exp(17.62*t/(243.12+t)) / (273.15 + t))
+	return uint32(ah*1000 + 0.5)
+}
+
+func (d *Device) sendCommand(command uint16, delay time.Duration) error {
+	binary.BigEndian.PutUint16(d.tx[0:], command)
+	if err := d.bus.Tx(d.Address, d.tx[0:2], nil); err != nil {
+		return err
+	}
+	time.Sleep(delay)
+	return nil
+}
+
+func (d *Device) sendCommandWithValues(command uint16, values ...uint16) error {
+	binary.BigEndian.PutUint16(d.tx[0:], command)
+	n := 2
+	for _, v := range values {
+		binary.BigEndian.PutUint16(d.tx[n:], v)
+		d.tx[n+2] = crc8(d.tx[n : n+2])
+		n += 3
+	}
+	if err := d.bus.Tx(d.Address, d.tx[0:n], nil); err != nil {
+		return err
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func (d *Device) readWords(command uint16, delay time.Duration, result []byte) error {
+	if err := d.sendCommand(command, delay); err != nil {
+		return err
+	}
+	if err := d.bus.Tx(d.Address, nil, result); err != nil {
+		return err
+	}
+	for i := 0; i+2 < len(result); i += 3 {
+		if crc8(result[i:i+2]) != result[i+2] {
+			return errCRC
+		}
+	}
+	return nil
+}
+
+func crc8(buf []byte) uint8 {
+	var crc uint8 = 0xff
+	for _, b := range buf {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = (crc << 1) ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
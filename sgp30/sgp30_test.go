@@ -0,0 +1,106 @@
+package sgp30
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+type T = tester.Transaction
+
+// words encodes values as big-endian words followed by their CRC.
+func words(values ...uint16) []byte {
+	var b []byte
+	for _, v := range values {
+		w := []byte{byte(v >> 8), byte(v)}
+		b = append(b, w[0], w[1], crc8(w))
+	}
+	return b
+}
+
+func TestMeasure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{0x20, 0x03}},
+		T{W: []byte{0x20, 0x08}},
+		T{R: words(450, 12)},
+		T{W: []byte{0x20, 0x08}},
+		T{R: []byte{0x01, 0xC2, 0x00, 0, 12, crc8([]byte{0, 12})}},
+	)
+
+	d := New(bus)
+	c.Assert(d.Configure(), qt.IsNil)
+	co2eq, tvoc, err := d.MeasureAirQuality()
+	c.Assert(err, qt.IsNil)
+	c.Assert(co2eq, qt.Equals, uint16(450))
+	c.Assert(tvoc, qt.Equals, uint16(12))
+	_, _, err = d.MeasureAirQuality()
+	c.Assert(err, qt.Equals, errCRC)
+	dev.AssertDone()
+}
+
+func TestBaseline(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		T{W: []byte{0x20, 0x03}},
+		T{W: []byte{0x20, 0x15}},
+		T{R: words(0x8F3D, 0x9A2B)},
+		T{W: []byte{0x20, 0x03}},
+		T{W: append([]byte{0x20, 0x1E}, words(0x9A2B, 0x8F3D)...)},
+		T{W: []byte{0x20, 0x15}},
+		T{R: words(0x8F3D, 0x9A2B)},
+	)
+
+	now := time.Unix(0, 0)
+	d := New(bus)
+	d.now = func() time.Time { return now }
+	c.Assert(d.Configure(), qt.IsNil)
+
+	// a fresh sensor needs 12 hours to learn its baseline
+	now = now.Add(BaselineWarmup - time.Second)
+	_, err := d.Baseline()
+	c.Assert(err, qt.Equals, errBaselineNotReady)
+	now = now.Add(time.Second)
+	b, err := d.Baseline()
+	c.Assert(err, qt.IsNil)
+	c.Assert(b, qt.Equals, Baseline{CO2eq: 0x8F3D, TVOC: 0x9A2B})
+
+	// a restored baseline is valid right away
+	c.Assert(d.Configure(), qt.IsNil)
+	c.Assert(d.BaselineValid(), qt.IsFalse)
+	c.Assert(d.SetBaseline(b), qt.IsNil)
+	b2, err := d.Baseline()
+	c.Assert(err, qt.IsNil)
+	c.Assert(b2, qt.Equals, b)
+	dev.AssertDone()
+}
+
+func TestHumidity(t *testing.T) {
+	c := qt.New(t)
+	// 25°C at 50% is about 11.5 g/m³
+	ah := AbsoluteHumidity(25000, 5000)
+	c.Assert(ah > 11400 && ah < 11600, qt.IsTrue, qt.Commentf("%d", ah))
+
+	bus := tester.NewI2CBus(c)
+	dev := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(dev)
+	dev.Expect(
+		// 11.5 g/m³ in 8.8 fixed point
+		T{W: append([]byte{0x20, 0x61}, words(0x0B80)...)},
+		T{W: append([]byte{0x20, 0x61}, words(0)...)},
+	)
+	d := New(bus)
+	c.Assert(d.SetAbsoluteHumidity(11500), qt.IsNil)
+	c.Assert(d.SetAbsoluteHumidity(0), qt.IsNil)
+	c.Assert(d.SetAbsoluteHumidity(300000), qt.Equals, errOutOfRange)
+	dev.AssertDone()
+}
@@ -80,6 +80,13 @@ func (d *Device) Read32Input() (uint32, error) {
 	return d.readInput(THIRTYTWO_BITS), nil
 }
 
+// ReadInput loads the parallel inputs and returns all of them, for any number
+// of cascaded registers up to 32 bits. The inputs of the register connected to
+// the microcontroller are in the most significant bits.
+func (d *Device) ReadInput() uint32 {
+	return d.readInput(d.bits)
+}
+
 // Get the pin's state for a specific ShiftPin.
 // Read{8|16|32}Input should be called before to update the state. Read{8|16|32}Input updates
 // all the pins, no need to call it for each pin individually.
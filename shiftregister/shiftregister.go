@@ -14,11 +14,21 @@ const (
 	THIRTYTWO_BITS NumberBit = 32
 )
 
+// PWM is the interface necessary for dimming the outputs through the OE pin.
+type PWM interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (channel uint8, err error)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
 // Device holds pin number
 type Device struct {
 	latch, clock, out machine.Pin // IC wiring
 	bits              NumberBit   // Pin number
 	mask              uint32      // keep all pins state
+	pwm               PWM         // drives OE for dimming, may be nil
+	oeChannel         uint8
 }
 
 // ShiftPin is the implementation of the ShiftPin interface.
@@ -60,9 +70,50 @@ func (d *Device) WriteMask(mask uint32) {
 	d.latch.High()
 }
 
+// Mask returns the state of all outputs as last written.
+func (d *Device) Mask() uint32 {
+	return d.mask
+}
+
+// SetPins sets the outputs for which mask is high to their respective values
+// in pins, leaving the others unchanged.
+func (d *Device) SetPins(pins, mask uint32) {
+	d.WriteMask(d.mask&^mask | pins&mask)
+}
+
+// ConfigureDimming drives the active-low OE pin of the register(s) with PWM
+// to dim all outputs at once. The PWM must not be configured yet.
+func (d *Device) ConfigureDimming(pwm PWM, oe machine.Pin) error {
+	err := pwm.Configure(machine.PWMConfig{
+		Period: 1e9 / 1000, // 1kHz, well above visible flicker
+	})
+	if err != nil {
+		return err
+	}
+	ch, err := pwm.Channel(oe)
+	if err != nil {
+		return err
+	}
+	d.pwm = pwm
+	d.oeChannel = ch
+	d.SetBrightness(255)
+	return nil
+}
+
+// SetBrightness sets the brightness of all outputs, from 0 (off) to 255
+// (fully on). ConfigureDimming must be called first.
+func (d *Device) SetBrightness(brightness uint8) {
+	if d.pwm == nil {
+		return
+	}
+	top := d.pwm.Top()
+	// OE is active-low, so the outputs are on while the PWM output is low.
+	d.pwm.Set(d.oeChannel, top-uint32(uint64(top)*uint64(brightness)/255))
+}
+
 // GetShiftPin return an individually addressable pin
 func (d *Device) GetShiftPin(pin int) *ShiftPin {
-	if pin < 0 || pin > int(d.bits) {
+	if pin < 0 || pin >= int(d.bits) {
 		panic("invalid pin number")
 	}
 	return &ShiftPin{
@@ -91,3 +142,13 @@ func (p ShiftPin) High() {
 func (p ShiftPin) Low() {
 	p.Set(false)
 }
+
+// Toggle inverts the value of this register pin.
+func (p ShiftPin) Toggle() {
+	p.Set(p.d.mask&p.mask == 0)
+}
+
+// Get returns the value last written to this register pin.
+func (p ShiftPin) Get() bool {
+	return p.d.mask&p.mask != 0
+}
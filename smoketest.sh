@@ -20,6 +20,7 @@ tinygo build -size short -o ./build/test.hex -target=trinket-m0 ./examples/bmp38
 tinygo build -size short -o ./build/test.hex -target=bluepill ./examples/ds1307/sram/main.go
 tinygo build -size short -o ./build/test.hex -target=bluepill ./examples/ds1307/time/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/ds3231/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/ds3231/alarm/
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/easystepper/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/espat/espconsole/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/espat/esphub/main.go
@@ -29,7 +30,9 @@ tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/flash/c
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/gc9a01/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/gps/i2c/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/gps/uart/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/gps/pps/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/hcsr04/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/hcsr04/scheduler/
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/hd44780/customchar/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/hd44780/text/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/hd44780i2c/main.go
@@ -48,8 +51,14 @@ tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/l
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mag3110/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mcp23017/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mcp23017-multiple/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mcp23017-interrupt/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mcp3008/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mcp2515/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/mcp251xfd/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/modbus/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/dmx512/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/dali/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/fan/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/microbitmatrix/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit-v2 ./examples/microbitmatrix/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/mma8653/main.go
@@ -95,6 +104,8 @@ tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/l
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/l9110x/simple/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/l9110x/speed/main.go
 tinygo build -size short -o ./build/test.hex -target=nucleo-f103rb ./examples/shiftregister/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/shiftregister/dimming/
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/soilmoisture/main.go
 tinygo build -size short -o ./build/test.hex -target=hifive1b ./examples/ssd1351/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/lis2mdl/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/max72xx/main.go
@@ -104,6 +115,8 @@ tinygo build -size short -o ./build/test.hex -target=xiao ./examples/pcf8563/ala
 tinygo build -size short -o ./build/test.hex -target=xiao ./examples/pcf8563/clkout/
 tinygo build -size short -o ./build/test.hex -target=xiao ./examples/pcf8563/time/
 tinygo build -size short -o ./build/test.hex -target=xiao ./examples/pcf8563/timer/
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/pcf8574/main.go
+tinygo build -size short -o ./build/test.hex -target=xiao ./examples/rv3028/main.go
 tinygo build -size short -o ./build/test.hex -target=pico ./examples/qmi8658c/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/ina260/main.go
 tinygo build -size short -o ./build/test.hex -target=nucleo-l432kc ./examples/aht20/main.go
@@ -119,6 +132,7 @@ tinygo build -size short -o ./build/test.elf -target=m5stack-core2 ./examples/ft
 tinygo build -size short -o ./build/test.hex -target=nucleo-wl55jc ./examples/sx126x/lora_rxtx/
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/ssd1289/main.go
 tinygo build -size short -o ./build/test.hex -target=pico ./examples/irremote/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/irsender/main.go
 tinygo build -size short -o ./build/test.hex -target=badger2040 ./examples/uc8151/main.go
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/scd4x/main.go
 tinygo build -size short -o ./build/test.uf2 -target=circuitplay-express ./examples/makeybutton/main.go
@@ -126,4 +140,19 @@ tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/d
 tinygo build -size short -o ./build/test.hex -target=nucleo-wl55jc ./examples/lora/lorawan/atcmd/
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/as560x/main.go
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/mpu6886/main.go
-tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/ttp229/main.go
\ No newline at end of file
+tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/ttp229/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/fingerprint/main.go
+tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/i2crecover/
+tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/events/
+tinygo build -size short -o ./build/test.hex -target=feather-nrf52840 ./examples/probe/
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/tca9548a/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/softspi/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/tcs34725/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/seesaw/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/tlc5947/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/tlc59711/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/scd30/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/cap1188/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/sgp30/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-m4 ./examples/datalogger/main.go
+tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/st7565/main.go
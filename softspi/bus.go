@@ -0,0 +1,115 @@
+// Package softspi implements the drivers.SPI interface by bit-banging GPIO
+// pins, for devices that can't be connected to a hardware SPI peripheral
+// because it is in use or the pins don't support it. All four modes and both
+// bit orders are supported. The chip select pin is handled by the drivers, as
+// with machine.SPI.
+//
+// The maximum speed depends on the CPU and is much lower than that of a
+// hardware peripheral, usually a few hundred kHz to a few MHz.
+package softspi // import "tinygo.org/x/drivers/softspi"
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errInvalidMode = errors.New("softspi: invalid mode")
+	errLength      = errors.New("softspi: write and read buffers differ in length")
+)
+
+// pin is a GPIO pin, implemented by machine.Pin.
+type pin interface {
+	Set(high bool)
+	Get() bool
+}
+
+// bus clocks bits in and out over the pins.
+type bus struct {
+	sck, sdo, sdi pin // sdo and sdi are nil when not used
+	cpol, cpha    bool
+	lsbFirst      bool
+	half          time.Duration // half a clock period
+	wait          func(time.Duration)
+}
+
+// setMode sets the clock polarity and phase of an SPI mode 0-3.
+func (b *bus) setMode(mode uint8) error {
+	if mode > 3 {
+		return errInvalidMode
+	}
+	b.cpol = mode&2 != 0
+	b.cpha = mode&1 != 0
+	return nil
+}
+
+// transfer sends and receives a byte.
+func (b *bus) transfer(w byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		mask := byte(0x80) >> i
+		if b.lsbFirst {
+			mask = 1 << i
+		}
+		if b.cpha {
+			// data changes on the leading edge, sampled on the trailing edge
+			b.sck.Set(!b.cpol)
+			b.setSDO(w&mask != 0)
+			b.delay()
+			b.sck.Set(b.cpol)
+			if b.getSDI() {
+				r |= mask
+			}
+			b.delay()
+		} else {
+			// data is set up before the leading edge, sampled on it
+			b.setSDO(w&mask != 0)
+			b.delay()
+			b.sck.Set(!b.cpol)
+			if b.getSDI() {
+				r |= mask
+			}
+			b.delay()
+			b.sck.Set(b.cpol)
+		}
+	}
+	return r
+}
+
+func (b *bus) setSDO(high bool) {
+	if b.sdo != nil {
+		b.sdo.Set(high)
+	}
+}
+
+func (b *bus) getSDI() bool {
+	return b.sdi != nil && b.sdi.Get()
+}
+
+func (b *bus) delay() {
+	if b.half != 0 {
+		b.wait(b.half)
+	}
+}
+
+// tx transfers the buffers like drivers.SPI.Tx.
+func (b *bus) tx(w, r []byte) error {
+	switch {
+	case w == nil:
+		for i := range r {
+			r[i] = b.transfer(0)
+		}
+	case r == nil:
+		for _, data := range w {
+			b.transfer(data)
+		}
+	default:
+		if len(w) != len(r) {
+			return errLength
+		}
+		for i, data := range w {
+			r[i] = b.transfer(data)
+		}
+	}
+	return nil
+}
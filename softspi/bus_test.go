@@ -0,0 +1,107 @@
+package softspi
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeDevice samples SDO and drives SDI on the clock edges of an SPI mode.
+type fakeDevice struct {
+	cpol, cpha bool
+	sck        bool
+	sdo, sdi   bool
+	in         []bool // bits sampled from SDO
+	out        []bool // bits to send on SDI
+}
+
+func newFakeDevice(mode uint8, out []bool) *fakeDevice {
+	d := &fakeDevice{cpol: mode&2 != 0, cpha: mode&1 != 0, out: out}
+	d.sck = d.cpol
+	if !d.cpha {
+		// the first bit is presented before the first clock edge
+		d.shift()
+	}
+	return d
+}
+
+func (d *fakeDevice) shift() {
+	if len(d.out) > 0 {
+		d.sdi = d.out[0]
+		d.out = d.out[1:]
+	}
+}
+
+type fakeSCK struct{ d *fakeDevice }
+
+func (p fakeSCK) Set(high bool) {
+	d := p.d
+	if high == d.sck {
+		return
+	}
+	d.sck = high
+	leading := high != d.cpol
+	if leading != d.cpha {
+		d.in = append(d.in, d.sdo)
+	} else {
+		d.shift()
+	}
+}
+
+func (p fakeSCK) Get() bool { return p.d.sck }
+
+type fakeSDO struct{ d *fakeDevice }
+
+func (p fakeSDO) Set(high bool) { p.d.sdo = high }
+func (p fakeSDO) Get() bool     { return p.d.sdo }
+
+type fakeSDI struct{ d *fakeDevice }
+
+func (p fakeSDI) Set(bool)  {}
+func (p fakeSDI) Get() bool { return p.d.sdi }
+
+func bits(b byte, lsbFirst bool) []bool {
+	var s []bool
+	for i := 0; i < 8; i++ {
+		if lsbFirst {
+			s = append(s, b&(1<<i) != 0)
+		} else {
+			s = append(s, b&(0x80>>i) != 0)
+		}
+	}
+	return s
+}
+
+func TestTransfer(t *testing.T) {
+	for mode := uint8(0); mode < 4; mode++ {
+		for _, lsbFirst := range []bool{false, true} {
+			c := qt.New(t)
+			d := newFakeDevice(mode, append(bits(0x3C, lsbFirst), bits(0x81, lsbFirst)...))
+			b := bus{sck: fakeSCK{d}, sdo: fakeSDO{d}, sdi: fakeSDI{d}, lsbFirst: lsbFirst}
+			c.Assert(b.setMode(mode), qt.IsNil)
+
+			r := make([]byte, 2)
+			c.Assert(b.tx([]byte{0xA6, 0x01}, r), qt.IsNil)
+			c.Assert(r, qt.DeepEquals, []byte{0x3C, 0x81}, qt.Commentf("mode %d, lsbFirst %v", mode, lsbFirst))
+			c.Assert(d.in, qt.DeepEquals, append(bits(0xA6, lsbFirst), bits(0x01, lsbFirst)...))
+			c.Assert(d.sck, qt.Equals, d.cpol) // clock is left idle
+		}
+	}
+}
+
+func TestTx(t *testing.T) {
+	c := qt.New(t)
+	d := newFakeDevice(0, bits(0x42, false))
+	var waited time.Duration
+	b := bus{sck: fakeSCK{d}, sdi: fakeSDI{d}, half: time.Microsecond, wait: func(d time.Duration) { waited += d }}
+
+	// read only, without SDO
+	r := []byte{0}
+	c.Assert(b.tx(nil, r), qt.IsNil)
+	c.Assert(r[0], qt.Equals, byte(0x42))
+	c.Assert(waited, qt.Equals, 16*time.Microsecond)
+
+	c.Assert(b.tx([]byte{1, 2}, []byte{0}), qt.Equals, errLength)
+	c.Assert(b.setMode(4), qt.Equals, errInvalidMode)
+}
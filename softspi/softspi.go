@@ -0,0 +1,89 @@
+//go:build tinygo
+
+package softspi
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/delay"
+)
+
+// Config holds the configuration of the bus, like machine.SPIConfig.
+type Config struct {
+	// Frequency of the clock in Hz. Zero toggles the pins as fast as
+	// possible.
+	Frequency uint32
+
+	// SCK is the clock pin. SDO or SDI may be machine.NoPin for devices
+	// that only receive or only send.
+	SCK machine.Pin
+	SDO machine.Pin
+	SDI machine.Pin
+
+	// LSBFirst sends the least significant bit of each byte first.
+	LSBFirst bool
+
+	// Mode is the SPI mode 0-3, selecting the clock polarity and phase.
+	Mode uint8
+
+	// Delay is half a clock period. It overrides Frequency when set.
+	Delay time.Duration
+}
+
+// SPI is a bit-banged SPI bus.
+type SPI struct {
+	bus bus
+}
+
+// New returns a new software SPI bus. It must be configured before use.
+func New() *SPI {
+	return &SPI{}
+}
+
+// Configure sets up the pins and the clock polarity, phase and speed.
+func (s *SPI) Configure(config Config) error {
+	b := bus{
+		lsbFirst: config.LSBFirst,
+		half:     config.Delay,
+		wait:     sleep,
+	}
+	if err := b.setMode(config.Mode); err != nil {
+		return err
+	}
+	if b.half == 0 && config.Frequency != 0 {
+		b.half = time.Second / time.Duration(2*config.Frequency)
+	}
+
+	config.SCK.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	config.SCK.Set(b.cpol)
+	b.sck = config.SCK
+	if config.SDO != machine.NoPin {
+		config.SDO.Configure(machine.PinConfig{Mode: machine.PinOutput})
+		config.SDO.Low()
+		b.sdo = config.SDO
+	}
+	if config.SDI != machine.NoPin {
+		config.SDI.Configure(machine.PinConfig{Mode: machine.PinInput})
+		b.sdi = config.SDI
+	}
+
+	s.bus = b
+	return nil
+}
+
+// Tx transmits w and receives into r at the same time, see drivers.SPI.
+func (s *SPI) Tx(w, r []byte) error {
+	return s.bus.tx(w, r)
+}
+
+// Transfer writes a single byte and returns the byte received at the same
+// time.
+func (s *SPI) Transfer(b byte) (byte, error) {
+	return s.bus.transfer(b), nil
+}
+
+// sleep busy-waits, as the bit times are too short for time.Sleep.
+func sleep(d time.Duration) {
+	delay.Sleep(d)
+}
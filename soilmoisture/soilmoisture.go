@@ -0,0 +1,180 @@
+// Package soilmoisture provides a driver for capacitive soil moisture sensors
+// with an analog output, such as the common "Capacitive Soil Moisture Sensor
+// v1.2" boards.
+//
+// The output voltage falls as the moisture around the probe rises. It is
+// converted to a percentage using two calibration points: the reading with
+// the probe in dry air (0%) and submerged in water (100%). Calibration values
+// can be saved and restored, for example to flash, so that the probe only
+// has to be calibrated once.
+package soilmoisture // import "tinygo.org/x/drivers/soilmoisture"
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errInvalidCalibration = errors.New("soilmoisture: invalid calibration data")
+
+// ADC is an analog input. It is implemented by the machine.ADC type, which
+// must be configured before use.
+type ADC interface {
+	Get() uint16
+}
+
+// Calibration holds the raw readings in dry air and in water.
+type Calibration struct {
+	Air   uint16
+	Water uint16
+}
+
+// DefaultCalibration is a rough calibration for a v1.2 sensor powered from
+// 3.3V. Calibrate each probe for accurate readings.
+var DefaultCalibration = Calibration{Air: 52000, Water: 24000}
+
+// MarshalBinary encodes the calibration into 4 bytes.
+func (c Calibration) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint16(b[0:], c.Air)
+	binary.LittleEndian.PutUint16(b[2:], c.Water)
+	return b, nil
+}
+
+// UnmarshalBinary decodes a calibration encoded by MarshalBinary. It fails
+// if the data is not a usable calibration, for example erased flash.
+func (c *Calibration) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return errInvalidCalibration
+	}
+	cal := Calibration{
+		Air:   binary.LittleEndian.Uint16(b[0:]),
+		Water: binary.LittleEndian.Uint16(b[2:]),
+	}
+	if !cal.valid() {
+		return errInvalidCalibration
+	}
+	*c = cal
+	return nil
+}
+
+func (c Calibration) valid() bool {
+	return c.Air != c.Water && c.Air != 0xFFFF && c.Water != 0xFFFF
+}
+
+// Percent converts a raw reading to a moisture percentage from 0 to 100.
+func (c Calibration) Percent(raw uint16) uint8 {
+	span := int32(c.Air) - int32(c.Water)
+	if span == 0 {
+		return 0
+	}
+	p := (int32(c.Air) - int32(raw)) * 100 / span
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return uint8(p)
+}
+
+// Config holds the sensor configuration.
+type Config struct {
+	// Calibration restores a saved calibration. Defaults to
+	// DefaultCalibration.
+	Calibration Calibration
+
+	// Samples is the number of ADC readings averaged for each
+	// measurement. Defaults to 16.
+	Samples int
+
+	// SampleInterval is the delay between readings, to average out the
+	// ripple of the sensor's oscillator. Defaults to no delay.
+	SampleInterval time.Duration
+
+	// Save is called after CalibrateAir or CalibrateWater with the new
+	// calibration so it can be persisted.
+	Save func(Calibration) error
+}
+
+// Device is a capacitive soil moisture sensor.
+type Device struct {
+	adc      ADC
+	cal      Calibration
+	samples  int
+	interval time.Duration
+	save     func(Calibration) error
+}
+
+// New returns a new soil moisture sensor on the given ADC channel.
+func New(adc ADC) Device {
+	return Device{
+		adc:     adc,
+		cal:     DefaultCalibration,
+		samples: 16,
+	}
+}
+
+// Configure sets up the sensor.
+func (d *Device) Configure(cfg Config) {
+	if cfg.Calibration.valid() {
+		d.cal = cfg.Calibration
+	}
+	if cfg.Samples > 0 {
+		d.samples = cfg.Samples
+	}
+	d.interval = cfg.SampleInterval
+	d.save = cfg.Save
+}
+
+// ReadRaw returns the average of the configured number of ADC readings.
+func (d *Device) ReadRaw() uint16 {
+	var sum uint32
+	for i := 0; i < d.samples; i++ {
+		if i > 0 && d.interval > 0 {
+			time.Sleep(d.interval)
+		}
+		sum += uint32(d.adc.Get())
+	}
+	return uint16(sum / uint32(d.samples))
+}
+
+// ReadPercent returns the moisture as a percentage from 0 (dry air) to 100
+// (water).
+func (d *Device) ReadPercent() uint8 {
+	return d.cal.Percent(d.ReadRaw())
+}
+
+// Calibration returns the calibration in use.
+func (d *Device) Calibration() Calibration {
+	return d.cal
+}
+
+// SetCalibration replaces the calibration.
+func (d *Device) SetCalibration(cal Calibration) error {
+	if !cal.valid() {
+		return errInvalidCalibration
+	}
+	d.cal = cal
+	return nil
+}
+
+// CalibrateAir takes a reading with the probe dry in air as the 0% point.
+func (d *Device) CalibrateAir() error {
+	d.cal.Air = d.ReadRaw()
+	return d.saveCalibration()
+}
+
+// CalibrateWater takes a reading with the probe submerged in water up to the
+// line as the 100% point.
+func (d *Device) CalibrateWater() error {
+	d.cal.Water = d.ReadRaw()
+	return d.saveCalibration()
+}
+
+func (d *Device) saveCalibration() error {
+	if d.save == nil {
+		return nil
+	}
+	return d.save(d.cal)
+}
@@ -0,0 +1,65 @@
+package soilmoisture
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// fakeADC returns the readings in turn, repeating the last one.
+type fakeADC struct {
+	values []uint16
+}
+
+func (a *fakeADC) Get() uint16 {
+	v := a.values[0]
+	if len(a.values) > 1 {
+		a.values = a.values[1:]
+	}
+	return v
+}
+
+func TestPercent(t *testing.T) {
+	c := qt.New(t)
+	cal := Calibration{Air: 50000, Water: 20000}
+	c.Assert(cal.Percent(50000), qt.Equals, uint8(0))
+	c.Assert(cal.Percent(35000), qt.Equals, uint8(50))
+	c.Assert(cal.Percent(20000), qt.Equals, uint8(100))
+	c.Assert(cal.Percent(60000), qt.Equals, uint8(0))
+	c.Assert(cal.Percent(10000), qt.Equals, uint8(100))
+}
+
+func TestCalibrate(t *testing.T) {
+	c := qt.New(t)
+	adc := &fakeADC{values: []uint16{49000, 51000}}
+	var saved []Calibration
+	d := New(adc)
+	d.Configure(Config{
+		Samples: 2,
+		Save: func(cal Calibration) error {
+			saved = append(saved, cal)
+			return nil
+		},
+	})
+	c.Assert(d.CalibrateAir(), qt.IsNil)
+	adc.values = []uint16{21000}
+	c.Assert(d.CalibrateWater(), qt.IsNil)
+	c.Assert(saved, qt.DeepEquals, []Calibration{
+		{Air: 50000, Water: 24000},
+		{Air: 50000, Water: 21000},
+	})
+	adc.values = []uint16{35500}
+	c.Assert(d.ReadPercent(), qt.Equals, uint8(50))
+}
+
+func TestMarshalCalibration(t *testing.T) {
+	c := qt.New(t)
+	b, err := Calibration{Air: 50000, Water: 20000}.MarshalBinary()
+	c.Assert(err, qt.IsNil)
+	var cal Calibration
+	c.Assert(cal.UnmarshalBinary(b), qt.IsNil)
+	c.Assert(cal, qt.Equals, Calibration{Air: 50000, Water: 20000})
+
+	// erased flash
+	c.Assert(cal.UnmarshalBinary([]byte{0xFF, 0xFF, 0xFF, 0xFF}), qt.Equals, errInvalidCalibration)
+}
@@ -11,3 +11,44 @@ type SPI interface {
 	// If you want to transfer multiple bytes, it is more efficient to use Tx instead.
 	Transfer(b byte) (byte, error)
 }
+
+// AsyncSPI is an optional interface for SPI buses that can transfer in the
+// background, usually with DMA. Drivers that send large buffers check for it
+// with a type assertion, so that rendering or other work can overlap with the
+// transfer. Use StartTx and WaitTx to fall back to a blocking Tx on other
+// buses.
+type AsyncSPI interface {
+	SPI
+
+	// StartTx starts a transfer like Tx and returns without waiting for it
+	// to complete. The buffers must not be modified (w) or read (r), and the
+	// bus must not be used, until the transfer is complete.
+	StartTx(w, r []byte) error
+
+	// IsBusy reports whether the transfer started by StartTx is still in
+	// progress.
+	IsBusy() bool
+
+	// Wait blocks until the transfer started by StartTx is complete and
+	// returns its error, if any. Implementations should yield to other
+	// goroutines while waiting.
+	Wait() error
+}
+
+// StartTx starts a transfer in the background if bus implements AsyncSPI, or
+// does a blocking Tx otherwise. It must be followed by WaitTx before the
+// buffers or the bus are used again.
+func StartTx(bus SPI, w, r []byte) error {
+	if async, ok := bus.(AsyncSPI); ok {
+		return async.StartTx(w, r)
+	}
+	return bus.Tx(w, r)
+}
+
+// WaitTx waits for a transfer started by StartTx to complete.
+func WaitTx(bus SPI) error {
+	if async, ok := bus.(AsyncSPI); ok {
+		return async.Wait()
+	}
+	return nil
+}
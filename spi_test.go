@@ -0,0 +1,65 @@
+package drivers_test
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+// syncSPI records blocking transfers.
+type syncSPI struct {
+	tx int
+}
+
+func (s *syncSPI) Tx(w, r []byte) error {
+	s.tx++
+	return nil
+}
+
+func (s *syncSPI) Transfer(b byte) (byte, error) {
+	return 0, nil
+}
+
+// asyncSPI completes transfers when Wait is called.
+type asyncSPI struct {
+	syncSPI
+	pending []byte
+	sent    []byte
+	err     error
+}
+
+func (s *asyncSPI) StartTx(w, r []byte) error {
+	s.pending = w
+	return nil
+}
+
+func (s *asyncSPI) IsBusy() bool {
+	return s.pending != nil
+}
+
+func (s *asyncSPI) Wait() error {
+	s.sent = append(s.sent, s.pending...)
+	s.pending = nil
+	return s.err
+}
+
+func TestStartTxFallback(t *testing.T) {
+	c := qt.New(t)
+	bus := &syncSPI{}
+	c.Assert(drivers.StartTx(bus, []byte{1, 2}, nil), qt.IsNil)
+	c.Assert(bus.tx, qt.Equals, 1)
+	c.Assert(drivers.WaitTx(bus), qt.IsNil)
+}
+
+func TestStartTxAsync(t *testing.T) {
+	c := qt.New(t)
+	bus := &asyncSPI{err: errors.New("dma error")}
+	c.Assert(drivers.StartTx(bus, []byte{1, 2}, nil), qt.IsNil)
+	c.Assert(bus.tx, qt.Equals, 0)
+	c.Assert(bus.IsBusy(), qt.IsTrue)
+	c.Assert(drivers.WaitTx(bus), qt.ErrorMatches, "dma error")
+	c.Assert(bus.IsBusy(), qt.IsFalse)
+	c.Assert(bus.sent, qt.DeepEquals, []byte{1, 2})
+}
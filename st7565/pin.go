@@ -0,0 +1,20 @@
+//go:build tinygo
+
+package st7565
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers"
+)
+
+// New creates a new ST7565 or UC1701 connection. The SPI bus must already be
+// configured in mode 0 (mode 3 also works), at up to 20MHz.
+func New(bus drivers.SPI, dcPin, rstPin, csPin machine.Pin) *Device {
+	dcPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	rstPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	csPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	csPin.High()
+	rstPin.High()
+	return newDevice(bus, dcPin, rstPin, csPin)
+}
@@ -0,0 +1,34 @@
+package st7565
+
+// Commands
+const (
+	DISPLAY_OFF      = 0xAE
+	DISPLAY_ON       = 0xAF
+	SET_START_LINE   = 0x40 // | line
+	SET_PAGE         = 0xB0 // | page
+	SET_COLUMN_HIGH  = 0x10 // | column >> 4
+	SET_COLUMN_LOW   = 0x00 // | column & 0x0F
+	ADC_NORMAL       = 0xA0
+	ADC_REVERSE      = 0xA1
+	DISPLAY_NORMAL   = 0xA6
+	DISPLAY_REVERSE  = 0xA7
+	ALL_POINTS_OFF   = 0xA4
+	ALL_POINTS_ON    = 0xA5
+	BIAS_9           = 0xA2 // 1/9 bias
+	BIAS_7           = 0xA3 // 1/7 bias
+	RESET            = 0xE2
+	COM_NORMAL       = 0xC0
+	COM_REVERSE      = 0xC8
+	POWER_CONTROL    = 0x28 // | booster, regulator, follower
+	RESISTOR_RATIO   = 0x20 // | ratio
+	ELECTRONIC_VOL   = 0x81 // followed by the volume
+	BOOSTER_RATIO    = 0xF8 // followed by the ratio
+	NOP              = 0xE3
+	POWER_BOOSTER    = 0x04
+	POWER_REGULATOR  = 0x02
+	POWER_FOLLOWER   = 0x01
+	POWER_ALL        = POWER_BOOSTER | POWER_REGULATOR | POWER_FOLLOWER
+	MAX_CONTRAST     = 0x3F
+	MAX_RESISTOR     = 0x07
+	CONTROLLER_WIDTH = 132 // columns of the display RAM
+)
@@ -0,0 +1,314 @@
+// Package st7565 implements a driver for 128x64 monochrome graphic LCDs with
+// the ST7565 controller or the compatible UC1701, which are readable in
+// sunlight and draw very little power.
+//
+// Datasheets:
+// https://cdn-shop.adafruit.com/datasheets/ST7565.pdf
+// https://www.buydisplay.com/download/ic/UC1701.pdf
+//
+// Display only sends the parts of the buffer that changed since the last
+// call, which keeps updates of small areas fast.
+package st7565 // import "tinygo.org/x/drivers/st7565"
+
+import (
+	"errors"
+	"image/color"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+var errBufferSize = errors.New("st7565: wrong buffer size")
+
+// Bias is the LCD bias ratio, which depends on the panel.
+type Bias uint8
+
+const (
+	Bias9 Bias = iota // 1/9 bias, the default
+	Bias7             // 1/7 bias
+)
+
+// Config is the configuration for the display.
+type Config struct {
+	// Width and Height of the panel, default 128x64.
+	Width  int16
+	Height int16
+
+	// Contrast (electronic volume, 0 to MAX_CONTRAST). Defaults to 0x18.
+	Contrast uint8
+
+	// ResistorRatio sets the internal regulator resistor ratio (0 to
+	// MAX_RESISTOR), the coarse contrast setting. Defaults to 6, some
+	// UC1701 panels need 5.
+	ResistorRatio uint8
+
+	Bias Bias
+
+	// FlipX and FlipY mirror the display horizontally and vertically, for
+	// panels mounted differently. Both together rotate it by 180°.
+	FlipX bool
+	FlipY bool
+
+	// ColumnOffset is added to the column address. The controller RAM is
+	// 132 columns wide, panels that use the right end of it with FlipX
+	// need an offset of 4.
+	ColumnOffset uint8
+}
+
+// pin is a control output.
+type pin interface {
+	High()
+	Low()
+}
+
+// dirty is the range of columns in a page that changed, lo > hi if none.
+type dirty struct {
+	lo, hi int16
+}
+
+// Device wraps an SPI connection.
+type Device struct {
+	bus    drivers.SPI
+	dcPin  pin
+	rstPin pin
+	csPin  pin
+
+	buffer []byte
+	dirty  []dirty
+	width  int16
+	height int16
+	offset uint8
+	cmdbuf [2]byte
+
+	sleep func(time.Duration)
+}
+
+func newDevice(bus drivers.SPI, dcPin, rstPin, csPin pin) *Device {
+	return &Device{
+		bus:    bus,
+		dcPin:  dcPin,
+		rstPin: rstPin,
+		csPin:  csPin,
+		sleep:  time.Sleep,
+	}
+}
+
+// Configure initializes the display.
+func (d *Device) Configure(cfg Config) {
+	d.width = 128
+	d.height = 64
+	if cfg.Width != 0 {
+		d.width = cfg.Width
+	}
+	if cfg.Height != 0 {
+		d.height = cfg.Height
+	}
+	if cfg.Contrast == 0 {
+		cfg.Contrast = 0x18
+	}
+	if cfg.ResistorRatio == 0 {
+		cfg.ResistorRatio = 6
+	}
+	d.offset = cfg.ColumnOffset
+	d.buffer = make([]byte, int(d.width)*int((d.height+7)/8))
+	d.dirty = make([]dirty, (d.height+7)/8)
+
+	d.rstPin.Low()
+	d.sleep(time.Millisecond)
+	d.rstPin.High()
+	d.sleep(time.Millisecond)
+
+	if cfg.Bias == Bias7 {
+		d.Command(BIAS_7)
+	} else {
+		d.Command(BIAS_9)
+	}
+	if cfg.FlipX {
+		d.Command(ADC_REVERSE)
+	} else {
+		d.Command(ADC_NORMAL)
+	}
+	if cfg.FlipY {
+		d.Command(COM_REVERSE)
+	} else {
+		d.Command(COM_NORMAL)
+	}
+	d.Command(SET_START_LINE)
+
+	// turn the power circuits on one after the other, as in the
+	// datasheet's initialization sequence
+	d.Command(POWER_CONTROL | POWER_BOOSTER)
+	d.sleep(50 * time.Millisecond)
+	d.Command(POWER_CONTROL | POWER_BOOSTER | POWER_REGULATOR)
+	d.sleep(50 * time.Millisecond)
+	d.Command(POWER_CONTROL | POWER_ALL)
+	d.sleep(10 * time.Millisecond)
+
+	d.SetResistorRatio(cfg.ResistorRatio)
+	d.SetContrast(cfg.Contrast)
+	d.Command(ALL_POINTS_OFF)
+	d.Command(DISPLAY_NORMAL)
+	d.ClearDisplay()
+	d.Command(DISPLAY_ON)
+}
+
+// SetContrast sets the electronic volume, 0 to MAX_CONTRAST.
+func (d *Device) SetContrast(contrast uint8) {
+	if contrast > MAX_CONTRAST {
+		contrast = MAX_CONTRAST
+	}
+	d.tx([]byte{ELECTRONIC_VOL, contrast}, true)
+}
+
+// SetResistorRatio sets the regulator resistor ratio, 0 to MAX_RESISTOR.
+func (d *Device) SetResistorRatio(ratio uint8) {
+	d.Command(RESISTOR_RATIO | ratio&MAX_RESISTOR)
+}
+
+// SetInverted shows pixels that are off as dark.
+func (d *Device) SetInverted(inverted bool) {
+	if inverted {
+		d.Command(DISPLAY_REVERSE)
+	} else {
+		d.Command(DISPLAY_NORMAL)
+	}
+}
+
+// Sleep turns the display off and enters the power save mode, or wakes it
+// up. The display RAM is kept.
+func (d *Device) Sleep(sleep bool) error {
+	if sleep {
+		// display off followed by all points on enters power save
+		if err := d.command(DISPLAY_OFF); err != nil {
+			return err
+		}
+		return d.command(ALL_POINTS_ON)
+	}
+	if err := d.command(ALL_POINTS_OFF); err != nil {
+		return err
+	}
+	return d.command(DISPLAY_ON)
+}
+
+// SetScroll sets the display line shown at the top of the screen.
+func (d *Device) SetScroll(line int16) {
+	d.Command(SET_START_LINE | uint8(line)&0x3F)
+}
+
+// ClearBuffer clears the image buffer.
+func (d *Device) ClearBuffer() {
+	for i := range d.buffer {
+		d.buffer[i] = 0
+	}
+	d.markAll()
+}
+
+// ClearDisplay clears the image buffer and the display.
+func (d *Device) ClearDisplay() {
+	d.ClearBuffer()
+	d.Display()
+}
+
+// Display sends the changed parts of the buffer to the screen.
+func (d *Device) Display() error {
+	for page := range d.dirty {
+		r := d.dirty[page]
+		if r.lo > r.hi {
+			continue
+		}
+		col := uint8(r.lo) + d.offset
+		for _, cmd := range [...]uint8{SET_PAGE | uint8(page), SET_COLUMN_HIGH | col>>4, SET_COLUMN_LOW | col&0x0F} {
+			if err := d.command(cmd); err != nil {
+				return err
+			}
+		}
+		start := page*int(d.width) + int(r.lo)
+		if err := d.tx(d.buffer[start:start+int(r.hi-r.lo)+1], false); err != nil {
+			// the page is sent again by the next call
+			return err
+		}
+		d.dirty[page] = dirty{lo: d.width, hi: -1}
+	}
+	return nil
+}
+
+// SetPixel enables or disables a pixel in the buffer. color.RGBA{0, 0, 0,
+// 255} is off, anything else turns the pixel on.
+func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
+	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+		return
+	}
+	page := y / 8
+	i := int(x) + int(page)*int(d.width)
+	old := d.buffer[i]
+	if c.R != 0 || c.G != 0 || c.B != 0 {
+		d.buffer[i] |= 1 << uint8(y%8)
+	} else {
+		d.buffer[i] &^= 1 << uint8(y%8)
+	}
+	if d.buffer[i] != old {
+		d.mark(page, x, x)
+	}
+}
+
+// GetPixel returns if the specified pixel is on (true) or off (false).
+func (d *Device) GetPixel(x int16, y int16) bool {
+	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+		return false
+	}
+	return d.buffer[int(x)+int(y/8)*int(d.width)]>>uint8(y%8)&1 == 1
+}
+
+// SetBuffer changes the whole buffer at once. It holds a byte per column
+// of 8 pixels, page after page.
+func (d *Device) SetBuffer(buffer []byte) error {
+	if len(buffer) != len(d.buffer) {
+		return errBufferSize
+	}
+	copy(d.buffer, buffer)
+	d.markAll()
+	return nil
+}
+
+// Size returns the current size of the display.
+func (d *Device) Size() (w, h int16) {
+	return d.width, d.height
+}
+
+// Command sends a command to the display.
+func (d *Device) Command(command uint8) {
+	d.command(command)
+}
+
+func (d *Device) command(command uint8) error {
+	d.cmdbuf[0] = command
+	return d.tx(d.cmdbuf[:1], true)
+}
+
+func (d *Device) mark(page, lo, hi int16) {
+	r := &d.dirty[page]
+	if lo < r.lo {
+		r.lo = lo
+	}
+	if hi > r.hi {
+		r.hi = hi
+	}
+}
+
+func (d *Device) markAll() {
+	for page := range d.dirty {
+		d.dirty[page] = dirty{lo: 0, hi: d.width - 1}
+	}
+}
+
+func (d *Device) tx(data []byte, isCommand bool) error {
+	if isCommand {
+		d.dcPin.Low()
+	} else {
+		d.dcPin.High()
+	}
+	d.csPin.Low()
+	err := d.bus.Tx(data, nil)
+	d.csPin.High()
+	return err
+}
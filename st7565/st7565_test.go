@@ -0,0 +1,130 @@
+package st7565
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type fakePin struct{ high bool }
+
+func (p *fakePin) High() { p.high = true }
+func (p *fakePin) Low()  { p.high = false }
+
+type write struct {
+	Command bool
+	Data    []byte
+}
+
+// fakeSPI records the writes with the level of the D/C pin.
+type fakeSPI struct {
+	dc     *fakePin
+	writes []write
+	err    error // returned by Tx
+}
+
+func (s *fakeSPI) Tx(w, r []byte) error {
+	s.writes = append(s.writes, write{!s.dc.high, append([]byte(nil), w...)})
+	return s.err
+}
+
+func (s *fakeSPI) Transfer(b byte) (byte, error) {
+	return 0, s.Tx([]byte{b}, nil)
+}
+
+func newTestDevice(cfg Config) (*Device, *fakeSPI) {
+	dc := &fakePin{}
+	bus := &fakeSPI{dc: dc}
+	d := newDevice(bus, dc, &fakePin{}, &fakePin{})
+	d.sleep = func(time.Duration) {}
+	d.Configure(cfg)
+	bus.writes = nil
+	return d, bus
+}
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	dc := &fakePin{}
+	bus := &fakeSPI{dc: dc}
+	d := newDevice(bus, dc, &fakePin{}, &fakePin{})
+	d.sleep = func(time.Duration) {}
+	d.Configure(Config{Bias: Bias7, FlipX: true, Contrast: 0x20, ResistorRatio: 5})
+
+	var cmds []byte
+	data := 0
+	for _, w := range bus.writes {
+		if w.Command {
+			cmds = append(cmds, w.Data...)
+		} else {
+			data += len(w.Data)
+		}
+	}
+	c.Assert(cmds[:9], qt.DeepEquals, []byte{
+		BIAS_7, ADC_REVERSE, COM_NORMAL, SET_START_LINE,
+		POWER_CONTROL | 4, POWER_CONTROL | 6, POWER_CONTROL | 7,
+		RESISTOR_RATIO | 5, ELECTRONIC_VOL,
+	})
+	c.Assert(cmds[len(cmds)-1], qt.Equals, uint8(DISPLAY_ON))
+	// the whole display is cleared
+	c.Assert(data, qt.Equals, 128*64/8)
+}
+
+func TestPartialUpdate(t *testing.T) {
+	c := qt.New(t)
+	d, bus := newTestDevice(Config{ColumnOffset: 4})
+
+	c.Assert(d.Display(), qt.IsNil)
+	c.Assert(bus.writes, qt.HasLen, 0) // nothing changed
+
+	on := color.RGBA{255, 255, 255, 255}
+	d.SetPixel(10, 9, on)
+	d.SetPixel(12, 15, on)
+	d.SetPixel(0, 0, color.RGBA{0, 0, 0, 255}) // already off
+	c.Assert(d.GetPixel(12, 15), qt.IsTrue)
+	c.Assert(d.Display(), qt.IsNil)
+	c.Assert(bus.writes, qt.DeepEquals, []write{
+		{true, []byte{SET_PAGE | 1}},
+		{true, []byte{SET_COLUMN_HIGH | 0}},
+		{true, []byte{SET_COLUMN_LOW | 14}},
+		{false, []byte{0x02, 0x00, 0x80}},
+	})
+
+	bus.writes = nil
+	c.Assert(d.Display(), qt.IsNil)
+	c.Assert(bus.writes, qt.HasLen, 0)
+
+	c.Assert(d.SetBuffer(make([]byte, 10)), qt.Equals, errBufferSize)
+
+	// bus errors are returned, and the page is sent again
+	d.SetPixel(10, 9, color.RGBA{0, 0, 0, 255})
+	bus.err = errors.New("bus error")
+	c.Assert(d.Display(), qt.Equals, bus.err)
+	c.Assert(d.Sleep(true), qt.Equals, bus.err)
+	bus.err = nil
+	bus.writes = nil
+	c.Assert(d.Display(), qt.IsNil)
+	c.Assert(bus.writes, qt.HasLen, 4)
+}
+
+func TestSetBuffer(t *testing.T) {
+	c := qt.New(t)
+	d, bus := newTestDevice(Config{})
+	c.Assert(d.SetBuffer(make([]byte, 1023)), qt.Equals, errBufferSize)
+
+	buffer := make([]byte, 128*64/8)
+	buffer[0] = 0x81
+	c.Assert(d.SetBuffer(buffer), qt.IsNil)
+	c.Assert(d.GetPixel(0, 0), qt.IsTrue)
+	c.Assert(d.GetPixel(0, 7), qt.IsTrue)
+	c.Assert(d.Display(), qt.IsNil)
+	data := 0
+	for _, w := range bus.writes {
+		if !w.Command {
+			data += len(w.Data)
+		}
+	}
+	c.Assert(data, qt.Equals, len(buffer))
+}
@@ -51,6 +51,7 @@ type Device struct {
 	vSyncLines      int16
 	cmdBuf          [1]byte
 	buf             [6]byte
+	busy            bool // transfer started by StartDrawRGBBitmap8
 }
 
 // Config is the configuration for the display
@@ -201,8 +202,9 @@ func (d *Device) sendCommand(command uint8, data []byte) error {
 }
 
 // startWrite must be called at the beginning of all exported methods to set the
-// chip select pin low.
+// chip select pin low. It waits for a pending background transfer first.
 func (d *Device) startWrite() {
+	d.Wait()
 	if d.csPin != machine.NoPin {
 		d.csPin.Low()
 	}
@@ -366,6 +368,38 @@ func (d *Device) DrawRGBBitmap8(x, y int16, data []uint8, w, h int16) error {
 	return nil
 }
 
+// StartDrawRGBBitmap8 starts copying an RGB bitmap to the display at the given
+// coordinates like DrawRGBBitmap8, but returns as soon as the transfer has
+// started if the SPI bus implements drivers.AsyncSPI. The next frame can be
+// rendered into another buffer in the meantime; data must not be modified
+// until Wait returns. Any other method waits for the transfer to complete.
+func (d *Device) StartDrawRGBBitmap8(x, y int16, data []uint8, w, h int16) error {
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errOutOfBounds
+	}
+	d.startWrite()
+	d.setWindow(x, y, w, h)
+	if err := drivers.StartTx(d.bus, data, nil); err != nil {
+		d.endWrite()
+		return err
+	}
+	d.busy = true
+	return nil
+}
+
+// Wait blocks until the transfer started by StartDrawRGBBitmap8 is complete.
+func (d *Device) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	err := drivers.WaitTx(d.bus)
+	d.endWrite()
+	return err
+}
+
 // FillRectangleWithBuffer fills buffer with a rectangle at a given coordinates.
 func (d *Device) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
 	i, j := d.Size()
@@ -536,6 +570,12 @@ func (d *Device) Sleep(sleepEnabled bool) error {
 	return nil
 }
 
+// WakeLatency returns the time the panel needs after leaving sleep mode before
+// it may be put to sleep again.
+func (d *Device) WakeLatency() time.Duration {
+	return 120 * time.Millisecond
+}
+
 // InvertColors inverts the colors of the screen
 func (d *Device) InvertColors(invert bool) {
 	d.startWrite()
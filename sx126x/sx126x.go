@@ -137,6 +137,20 @@ func (d *Device) SetStandby() {
 	d.ExecSetCommand(SX126X_CMD_SET_STANDBY, []uint8{SX126X_STANDBY_RC})
 }
 
+// Sleep sets the device in SLEEP mode with warm start, so its configuration is
+// retained, or wakes it up to STANDBY mode. It implements
+// drivers.PowerManaged.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.SetSleep()
+	} else {
+		// the falling edge of NSS wakes the device, SetStandby waits until
+		// it is ready
+		d.SetStandby()
+	}
+	return nil
+}
+
 // SetFs sets the device in frequency synthesis mode where the PLL is locked to the carrier frequency.
 func (d *Device) SetFs() {
 	d.ExecSetCommand(SX126X_CMD_SET_FS, []uint8{})
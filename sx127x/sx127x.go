@@ -119,6 +119,17 @@ func (d *Device) SetOpMode(mode uint8) {
 	d.WriteRegister(SX127X_REG_OP_MODE, new)
 }
 
+// Sleep sets the device in SLEEP mode, where the registers are retained, or
+// wakes it up to STANDBY mode. It implements drivers.PowerManaged.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.SetOpMode(SX127X_OPMODE_SLEEP)
+	} else {
+		d.SetOpMode(SX127X_OPMODE_STANDBY)
+	}
+	return nil
+}
+
 // SetOpMode changes the sx1276 mode
 func (d *Device) SetOpModeLora() {
 	d.WriteRegister(SX127X_REG_OP_MODE, SX127X_OPMODE_LORA)
@@ -0,0 +1,11 @@
+package tca9548a
+
+// Address is the default I2C address, with A0-A2 tied low. The address can be
+// set up to 0x77 with the address pins.
+const Address = 0x70
+
+// Number of downstream channels.
+const (
+	ChannelsTCA9548A = 8
+	ChannelsPCA9546  = 4
+)
@@ -0,0 +1,142 @@
+// Package tca9548a implements a driver for the TCA9548A 8-channel and PCA9546
+// 4-channel I2C multiplexers.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/tca9548a.pdf
+//
+// The multiplexer connects the upstream bus to any combination of downstream
+// channels, selected by writing a single control byte. Channel returns a
+// drivers.I2C for one downstream channel that selects it before each
+// transaction, so devices with the same fixed address can be connected to
+// different channels and used with their regular drivers:
+//
+//	mux := tca9548a.New(machine.I2C0)
+//	left := bme280.New(mux.Channel(0))
+//	right := bme280.New(mux.Channel(1))
+package tca9548a // import "tinygo.org/x/drivers/tca9548a"
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers"
+)
+
+var (
+	errInvalidChannel = errors.New("tca9548a: invalid channel")
+	errAddressInUse   = errors.New("tca9548a: address is used by the multiplexer")
+)
+
+// Device wraps an I2C connection to a TCA9548A or PCA9546 device.
+type Device struct {
+	bus      drivers.I2C
+	Address  uint16
+	Channels uint8 // number of downstream channels
+
+	selected uint8 // channels enabled in the control register
+	known    bool  // whether selected matches the control register
+	buf      [1]byte
+}
+
+// New creates a new TCA9548A connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:      bus,
+		Address:  Address,
+		Channels: ChannelsTCA9548A,
+	}
+}
+
+// NewPCA9546 creates a new PCA9546 connection, which only has 4 channels.
+func NewPCA9546(bus drivers.I2C) Device {
+	d := New(bus)
+	d.Channels = ChannelsPCA9546
+	return d
+}
+
+// Select enables the downstream channels set in mask (bit 0 is channel 0) and
+// disables the others. Nothing is written if the mask is already selected.
+func (d *Device) Select(mask uint8) error {
+	if mask>>d.Channels != 0 {
+		return errInvalidChannel
+	}
+	if d.known && d.selected == mask {
+		return nil
+	}
+	d.buf[0] = mask
+	err := d.bus.Tx(d.Address, d.buf[:], nil)
+	d.selected = mask
+	d.known = err == nil
+	return err
+}
+
+// SelectChannel enables a single downstream channel.
+func (d *Device) SelectChannel(channel uint8) error {
+	if channel >= d.Channels {
+		return errInvalidChannel
+	}
+	return d.Select(1 << channel)
+}
+
+// Disable disconnects all downstream channels.
+func (d *Device) Disable() error {
+	return d.Select(0)
+}
+
+// Selected reads the control register and returns the enabled channels.
+func (d *Device) Selected() (uint8, error) {
+	err := d.bus.Tx(d.Address, nil, d.buf[:])
+	if err != nil {
+		d.known = false
+		return 0, err
+	}
+	d.selected = d.buf[0]
+	d.known = true
+	return d.selected, nil
+}
+
+// Invalidate forgets which channels are selected, so the next transaction
+// writes the control register again. Call it after the multiplexer was reset
+// or reconfigured by something other than this driver.
+func (d *Device) Invalidate() {
+	d.known = false
+}
+
+// Channel returns the bus behind the given downstream channel. It panics if
+// the channel does not exist.
+func (d *Device) Channel(channel uint8) *Channel {
+	if channel >= d.Channels {
+		panic(errInvalidChannel)
+	}
+	return &Channel{mux: d, mask: 1 << channel}
+}
+
+// Channel is one downstream bus of the multiplexer. It implements drivers.I2C
+// and enables only its own channel before each transaction.
+type Channel struct {
+	mux  *Device
+	mask uint8
+}
+
+// Tx implements drivers.I2C.
+func (c *Channel) Tx(addr uint16, w, r []byte) error {
+	if addr == c.mux.Address {
+		return errAddressInUse
+	}
+	err := c.mux.Select(c.mask)
+	if err != nil {
+		return err
+	}
+	return c.mux.bus.Tx(addr, w, r)
+}
+
+// ReadRegister implements drivers.I2C.
+func (c *Channel) ReadRegister(addr uint8, r uint8, buf []byte) error {
+	return c.Tx(uint16(addr), []byte{r}, buf)
+}
+
+// WriteRegister implements drivers.I2C.
+func (c *Channel) WriteRegister(addr uint8, r uint8, buf []byte) error {
+	return c.Tx(uint16(addr), append([]byte{r}, buf...), nil)
+}
@@ -0,0 +1,66 @@
+package tca9548a
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestChannel(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	mux := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(mux)
+	sensor := bus.NewDevice(0x76)
+	sensor.Registers[0xD0] = 0x60
+
+	d := New(bus)
+	ch0 := d.Channel(0)
+	ch5 := d.Channel(5)
+
+	mux.Expect(tester.Transaction{W: []byte{0x01}})
+	buf := []byte{0}
+	c.Assert(ch0.ReadRegister(0x76, 0xD0, buf), qt.IsNil)
+	c.Assert(buf[0], qt.Equals, byte(0x60))
+
+	// Same channel again, the control register is not rewritten.
+	c.Assert(ch0.WriteRegister(0x76, 0xF4, []byte{0x27}), qt.IsNil)
+	c.Assert(sensor.Registers[0xF4], qt.Equals, uint8(0x27))
+
+	mux.Expect(tester.Transaction{W: []byte{0x20}})
+	c.Assert(ch5.Tx(0x76, []byte{0xD0}, buf), qt.IsNil)
+
+	// After Invalidate the selection is written again.
+	d.Invalidate()
+	mux.Expect(tester.Transaction{W: []byte{0x20}})
+	c.Assert(ch5.Tx(0x76, []byte{0xD0}, buf), qt.IsNil)
+	mux.AssertDone()
+
+	c.Assert(ch0.Tx(Address, []byte{0}, nil), qt.Equals, errAddressInUse)
+}
+
+func TestSelect(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	mux := tester.NewI2CDeviceScript(c, Address)
+	bus.AddDevice(mux)
+
+	d := NewPCA9546(bus)
+	c.Assert(d.SelectChannel(4), qt.Equals, errInvalidChannel)
+	c.Assert(d.Select(0x10), qt.Equals, errInvalidChannel)
+	c.Assert(func() { d.Channel(4) }, qt.PanicMatches, "tca9548a: invalid channel")
+
+	mux.Expect(
+		tester.Transaction{W: []byte{0x05}},
+		tester.Transaction{R: []byte{0x05}},
+		tester.Transaction{W: []byte{0x00}},
+	)
+	c.Assert(d.Select(0x05), qt.IsNil)
+	sel, err := d.Selected()
+	c.Assert(err, qt.IsNil)
+	c.Assert(sel, qt.Equals, uint8(0x05))
+	c.Assert(d.Disable(), qt.IsNil)
+	mux.AssertDone()
+}
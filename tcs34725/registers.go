@@ -0,0 +1,45 @@
+package tcs34725
+
+// Address is the I2C address of the TCS34725.
+const Address = 0x29
+
+// Command register bits, sent before each register address.
+const (
+	cmdSelect        = 0x80
+	cmdAutoIncrement = 0x20
+	cmdClearInt      = 0x66 // special function: clear the RGBC interrupt
+)
+
+// Registers.
+const (
+	regENABLE  = 0x00
+	regATIME   = 0x01
+	regWTIME   = 0x03
+	regAILTL   = 0x04
+	regAIHTL   = 0x06
+	regPERS    = 0x0C
+	regCONFIG  = 0x0D
+	regCONTROL = 0x0F
+	regID      = 0x12
+	regSTATUS  = 0x13
+	regCDATAL  = 0x14
+)
+
+// ENABLE bits.
+const (
+	enablePON  = 0x01
+	enableAEN  = 0x02
+	enableAIEN = 0x10
+)
+
+// STATUS bits.
+const (
+	statusAVALID = 0x01
+	statusAINT   = 0x10
+)
+
+// Values of the ID register.
+const (
+	idTCS34725 = 0x44 // also TCS34721
+	idTCS34727 = 0x4D // also TCS34723
+)
@@ -0,0 +1,278 @@
+// Package tcs34725 implements a driver for the TCS34725 color light-to-digital
+// converter, which measures red, green, blue and clear (unfiltered) light.
+//
+// Datasheet: https://cdn-shop.adafruit.com/datasheets/TCS34725.pdf
+// Lux and color temperature: https://ams.com/documents/20143/80162/TCS34xx_AN000517_1-00.pdf (DN40)
+package tcs34725 // import "tinygo.org/x/drivers/tcs34725"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/units"
+)
+
+var (
+	errNotFound           = errors.New("tcs34725: device not found")
+	errInvalidIntegration = errors.New("tcs34725: integration time out of range")
+	errSaturated          = errors.New("tcs34725: sensor saturated")
+)
+
+// Gain of the analog front end.
+type Gain uint8
+
+const (
+	Gain1X  Gain = 0
+	Gain4X  Gain = 1
+	Gain16X Gain = 2
+	Gain60X Gain = 3
+)
+
+// factor returns the gain as a multiplier.
+func (g Gain) factor() float32 {
+	return [...]float32{1, 4, 16, 60}[g&3]
+}
+
+// cycle is the duration of one integration cycle.
+const cycle = 2400 * time.Microsecond
+
+// Config holds the measurement settings.
+type Config struct {
+	// IntegrationTime between 2.4ms and 614.4ms, in steps of 2.4ms. Longer
+	// times are more sensitive. Defaults to 24ms.
+	IntegrationTime time.Duration
+
+	// Gain defaults to Gain1X.
+	Gain Gain
+}
+
+// Device wraps an I2C connection to a TCS34725 device.
+type Device struct {
+	bus     drivers.I2C
+	Address uint16
+	cycles  int // integration cycles, 1-256
+	gain    Gain
+	buf     [8]byte
+}
+
+// New creates a new TCS34725 connection. The I2C bus must already be
+// configured.
+//
+// This function only creates the Device object, it does not touch the device.
+func New(bus drivers.I2C) Device {
+	return Device{
+		bus:     bus,
+		Address: Address,
+		cycles:  10,
+	}
+}
+
+// Connected returns whether a TCS34725 has been found.
+func (d *Device) Connected() bool {
+	id, err := d.readReg(regID)
+	return err == nil && (id == idTCS34725 || id == idTCS34727)
+}
+
+// Configure sets the integration time and gain and starts continuous
+// measurements.
+func (d *Device) Configure(cfg Config) error {
+	if !d.Connected() {
+		return errNotFound
+	}
+	if cfg.IntegrationTime == 0 {
+		cfg.IntegrationTime = 24 * time.Millisecond
+	}
+	if err := d.SetIntegrationTime(cfg.IntegrationTime); err != nil {
+		return err
+	}
+	if err := d.SetGain(cfg.Gain); err != nil {
+		return err
+	}
+	return d.Sleep(false)
+}
+
+// Sleep stops measurements and powers down the oscillator when sleepEnabled
+// is true, and powers up and restarts measurements otherwise.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	enable, err := d.readReg(regENABLE)
+	if err != nil {
+		return err
+	}
+	if sleepEnabled {
+		return d.writeReg(regENABLE, enable&^(enablePON|enableAEN))
+	}
+	if err := d.writeReg(regENABLE, enable|enablePON); err != nil {
+		return err
+	}
+	// the oscillator needs 2.4ms to start before measuring
+	time.Sleep(cycle)
+	return d.writeReg(regENABLE, enable|enablePON|enableAEN)
+}
+
+// WakeLatency returns the time until the first measurement is available
+// after waking up.
+func (d *Device) WakeLatency() time.Duration {
+	return cycle + d.IntegrationTime()
+}
+
+// SetGain sets the gain of the analog front end.
+func (d *Device) SetGain(gain Gain) error {
+	d.gain = gain & 3
+	return d.writeReg(regCONTROL, uint8(d.gain))
+}
+
+// SetIntegrationTime sets the time over which each measurement integrates
+// light, rounded to a multiple of 2.4ms.
+func (d *Device) SetIntegrationTime(t time.Duration) error {
+	cycles := int((t + cycle/2) / cycle)
+	if cycles < 1 || cycles > 256 {
+		return errInvalidIntegration
+	}
+	d.cycles = cycles
+	return d.writeReg(regATIME, uint8(256-cycles))
+}
+
+// IntegrationTime returns the integration time of each measurement.
+func (d *Device) IntegrationTime() time.Duration {
+	return time.Duration(d.cycles) * cycle
+}
+
+// maxCount returns the value of a saturated channel.
+func (d *Device) maxCount() uint16 {
+	if d.cycles >= 64 {
+		return 65535
+	}
+	return uint16(d.cycles * 1024)
+}
+
+// DataReady returns whether the channels have completed an integration cycle
+// since the ADC was enabled. Reading the channels doesn't clear it, so it
+// stays true once the first measurement is available.
+func (d *Device) DataReady() (bool, error) {
+	status, err := d.readReg(regSTATUS)
+	return status&statusAVALID != 0, err
+}
+
+// ReadRaw returns the red, green, blue and clear channel counts of the last
+// measurement.
+func (d *Device) ReadRaw() (r, g, b, c uint16, err error) {
+	buf := d.buf[:8]
+	err = d.bus.Tx(d.Address, []byte{cmdSelect | cmdAutoIncrement | regCDATAL}, buf)
+	if err != nil {
+		return
+	}
+	c = uint16(buf[0]) | uint16(buf[1])<<8
+	r = uint16(buf[2]) | uint16(buf[3])<<8
+	g = uint16(buf[4]) | uint16(buf[5])<<8
+	b = uint16(buf[6]) | uint16(buf[7])<<8
+	return
+}
+
+// SetInterruptThresholds sets the clear channel limits outside of which an
+// interrupt is raised, once persistence consecutive measurements are out of
+// range. The persistence is rounded up to one of 0-3 or a multiple of 5 up
+// to 60, 0 raises an interrupt after every measurement.
+func (d *Device) SetInterruptThresholds(low, high uint16, persistence uint8) error {
+	buf := []byte{cmdSelect | cmdAutoIncrement | regAILTL, byte(low), byte(low >> 8), byte(high), byte(high >> 8)}
+	if err := d.bus.Tx(d.Address, buf, nil); err != nil {
+		return err
+	}
+	return d.writeReg(regPERS, persistenceFilter(persistence))
+}
+
+// persistenceFilter returns the APERS value for a number of measurements.
+func persistenceFilter(n uint8) uint8 {
+	if n <= 3 {
+		return n
+	}
+	if n >= 60 {
+		return 15
+	}
+	return (n+4)/5 + 3
+}
+
+// EnableInterrupt enables or disables the interrupt output. The INT pin is
+// open drain and active low.
+func (d *Device) EnableInterrupt(enable bool) error {
+	reg, err := d.readReg(regENABLE)
+	if err != nil {
+		return err
+	}
+	if enable {
+		reg |= enableAIEN
+	} else {
+		reg &^= enableAIEN
+	}
+	return d.writeReg(regENABLE, reg)
+}
+
+// InterruptPending returns whether the clear channel is outside of the
+// thresholds.
+func (d *Device) InterruptPending() (bool, error) {
+	status, err := d.readReg(regSTATUS)
+	return status&statusAINT != 0, err
+}
+
+// ClearInterrupt clears the interrupt, releasing the INT pin.
+func (d *Device) ClearInterrupt() error {
+	return d.bus.Tx(d.Address, []byte{cmdSelect | cmdClearInt}, nil)
+}
+
+// Coefficients of the DN40 lux and color temperature calculations for open
+// air, without glass over the sensor.
+const (
+	coefR     = 0.136
+	coefG     = 1.000
+	coefB     = -0.444
+	deviceFac = 310 // DF, device and glass factor
+	ctCoef    = 3810
+	ctOffset  = 1391
+)
+
+// irCompensate removes the infrared component from the color channels.
+func irCompensate(r, g, b, c uint16) (r1, g1, b1 float32) {
+	ir := (int32(r) + int32(g) + int32(b) - int32(c)) / 2
+	if ir < 0 {
+		ir = 0
+	}
+	return float32(int32(r) - ir), float32(int32(g) - ir), float32(int32(b) - ir)
+}
+
+// Lux returns the illuminance for channel counts read with the current gain
+// and integration time. It fails if the clear channel is saturated.
+func (d *Device) Lux(r, g, b, c uint16) (units.MilliLux, error) {
+	if c >= d.maxCount() {
+		return 0, errSaturated
+	}
+	r1, g1, b1 := irCompensate(r, g, b, c)
+	g2 := coefR*r1 + coefG*g1 + coefB*b1
+	cpl := float32(d.cycles) * 2.4 * d.gain.factor() / deviceFac
+	lux := g2 / cpl
+	if lux < 0 {
+		lux = 0
+	}
+	return units.MilliLux(lux * 1000), nil
+}
+
+// ColorTemperature returns the correlated color temperature in kelvin of the
+// channel counts, or 0 if it can't be computed because there is no red light.
+func ColorTemperature(r, g, b, c uint16) uint32 {
+	r1, _, b1 := irCompensate(r, g, b, c)
+	if r1 <= 0 {
+		return 0
+	}
+	return uint32(ctCoef*b1/r1 + ctOffset)
+}
+
+// readReg reads a single register.
+func (d *Device) readReg(reg uint8) (uint8, error) {
+	buf := d.buf[:1]
+	err := d.bus.Tx(d.Address, []byte{cmdSelect | reg}, buf)
+	return buf[0], err
+}
+
+// writeReg writes a single register.
+func (d *Device) writeReg(reg, value uint8) error {
+	return d.bus.Tx(d.Address, []byte{cmdSelect | reg, value}, nil)
+}
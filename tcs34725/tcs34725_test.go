@@ -0,0 +1,93 @@
+package tcs34725
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestConfigure(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(Address)
+	fake.Registers[cmdSelect|regID] = idTCS34725
+
+	d := New(bus)
+	c.Assert(d.Configure(Config{IntegrationTime: 154 * time.Millisecond, Gain: Gain16X}), qt.IsNil)
+	c.Assert(fake.Registers[cmdSelect|regATIME], qt.Equals, uint8(0xC0)) // 64 cycles
+	c.Assert(fake.Registers[cmdSelect|regCONTROL], qt.Equals, uint8(Gain16X))
+	c.Assert(fake.Registers[cmdSelect|regENABLE], qt.Equals, uint8(enablePON|enableAEN))
+	c.Assert(d.IntegrationTime(), qt.Equals, 153600*time.Microsecond)
+
+	c.Assert(d.Sleep(true), qt.IsNil)
+	c.Assert(fake.Registers[cmdSelect|regENABLE], qt.Equals, uint8(0))
+
+	c.Assert(d.SetIntegrationTime(time.Second), qt.Equals, errInvalidIntegration)
+}
+
+func TestNotFound(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	bus.NewDevice(Address)
+	d := New(bus)
+	c.Assert(d.Configure(Config{}), qt.Equals, errNotFound)
+}
+
+func TestReadRaw(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(Address)
+	copy(fake.Registers[cmdSelect|cmdAutoIncrement|regCDATAL:], []byte{0x10, 0x27, 0xE8, 0x03, 0xD0, 0x07, 0xB8, 0x0B})
+
+	d := New(bus)
+	r, g, b, clear, err := d.ReadRaw()
+	c.Assert(err, qt.IsNil)
+	c.Assert([]uint16{r, g, b, clear}, qt.DeepEquals, []uint16{1000, 2000, 3000, 10000})
+}
+
+func TestInterrupt(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewI2CBus(c)
+	fake := bus.NewDevice(Address)
+	d := New(bus)
+
+	c.Assert(d.SetInterruptThresholds(0x0102, 0xA0B0, 12), qt.IsNil)
+	c.Assert(fake.Registers[cmdSelect|cmdAutoIncrement|regAILTL:][:4], qt.DeepEquals, []uint8{0x02, 0x01, 0xB0, 0xA0})
+	c.Assert(fake.Registers[cmdSelect|regPERS], qt.Equals, uint8(6)) // 15 cycles
+	c.Assert(d.EnableInterrupt(true), qt.IsNil)
+	c.Assert(fake.Registers[cmdSelect|regENABLE], qt.Equals, uint8(enableAIEN))
+
+	fake.Registers[cmdSelect|regSTATUS] = statusAINT | statusAVALID
+	pending, err := d.InterruptPending()
+	c.Assert(err, qt.IsNil)
+	c.Assert(pending, qt.IsTrue)
+}
+
+func TestPersistenceFilter(t *testing.T) {
+	c := qt.New(t)
+	for n, want := range map[uint8]uint8{0: 0, 3: 3, 4: 4, 5: 4, 6: 5, 10: 5, 55: 14, 60: 15, 200: 15} {
+		c.Assert(persistenceFilter(n), qt.Equals, want, qt.Commentf("%d", n))
+	}
+}
+
+func TestLuxAndColorTemperature(t *testing.T) {
+	c := qt.New(t)
+	d := New(nil)
+	d.cycles = 64
+	d.gain = Gain4X
+
+	// no infrared: R+G+B == C
+	lux, err := d.Lux(1000, 2000, 1000, 4000)
+	c.Assert(err, qt.IsNil)
+	// G" = 136 + 2000 - 444 = 1692, CPL = 153.6*4/310
+	c.Assert(lux.Lux() > 853 && lux.Lux() < 854, qt.IsTrue)
+
+	_, err = d.Lux(0, 0, 0, 65535)
+	c.Assert(err, qt.Equals, errSaturated)
+
+	c.Assert(ColorTemperature(1000, 2000, 1000, 4000), qt.Equals, uint32(3810+1391))
+	c.Assert(ColorTemperature(0, 10, 10, 20), qt.Equals, uint32(0))
+}
@@ -53,9 +53,25 @@ func (d *I2CDevice8) WriteRegister(r uint8, buf []byte) error {
 	return nil
 }
 
-// Tx implements I2C.Tx.
-func (bus *I2CDevice8) Tx(w, r []byte) error {
-	// TODO: implement this
+// Tx implements I2C.Tx. The first byte written selects the register, the
+// remaining bytes are written to it and r is read from it, like
+// WriteRegister and ReadRegister.
+func (d *I2CDevice8) Tx(w, r []byte) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	if len(w) == 0 {
+		d.c.Fatalf("register read/write without register address")
+	}
+	reg := w[0]
+	if len(w) > 1 {
+		d.assertRegisterRange(reg, w[1:])
+		copy(d.Registers[reg:], w[1:])
+	}
+	if len(r) > 0 {
+		d.assertRegisterRange(reg, r)
+		copy(r, d.Registers[reg:])
+	}
 	return nil
 }
 
@@ -0,0 +1,60 @@
+package tester
+
+// Pin is a mock GPIO pin for drivers that take pins as functions or
+// interfaces. It records every level set by the driver and returns the level
+// set by the test from Get.
+type Pin struct {
+	// Level is the current level of the pin, set by the driver when used as
+	// an output or by the test when used as an input.
+	Level bool
+
+	// History records every level set by the driver, in order.
+	History []bool
+
+	// Inputs holds levels returned by Get in order, before falling back to
+	// Level once exhausted.
+	Inputs []bool
+}
+
+// NewPin returns a new mock pin with the given initial level.
+func NewPin(level bool) *Pin {
+	return &Pin{Level: level}
+}
+
+// Set sets the level of the pin.
+func (p *Pin) Set(high bool) {
+	p.Level = high
+	p.History = append(p.History, high)
+}
+
+// High sets the pin high.
+func (p *Pin) High() {
+	p.Set(true)
+}
+
+// Low sets the pin low.
+func (p *Pin) Low() {
+	p.Set(false)
+}
+
+// Get returns the next scripted input level, or the current level.
+func (p *Pin) Get() bool {
+	if len(p.Inputs) > 0 {
+		p.Level = p.Inputs[0]
+		p.Inputs = p.Inputs[1:]
+	}
+	return p.Level
+}
+
+// Pulses returns the number of rising edges recorded in History.
+func (p *Pin) Pulses() int {
+	n := 0
+	last := false
+	for _, level := range p.History {
+		if level && !last {
+			n++
+		}
+		last = level
+	}
+	return n
+}
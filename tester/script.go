@@ -0,0 +1,114 @@
+package tester
+
+import "bytes"
+
+// Transaction is a single bus transaction: the bytes written by the driver
+// and the bytes returned to it.
+type Transaction struct {
+	W []byte
+	R []byte
+}
+
+// Script holds the expected transactions of a scripted mock and records the
+// ones that actually happened. It is embedded in I2CDeviceScript and SPIBus.
+type Script struct {
+	c Failer
+
+	// Expected holds the transactions the driver must perform, in order.
+	// If empty, any transaction is accepted and answered from Responses.
+	Expected []Transaction
+
+	// Responses holds the bytes returned by reads that are not matched
+	// against Expected, consumed in order. Reads beyond it return zeros.
+	Responses []byte
+
+	// Log records every transaction that happened, with the bytes returned.
+	Log []Transaction
+
+	// If Err is non-nil, it will be returned as the error from all methods.
+	Err error
+}
+
+// tx checks a transaction against the script, fills r and records it.
+func (s *Script) tx(w, r []byte) error {
+	if s.Err != nil {
+		return s.Err
+	}
+	if len(s.Expected) > 0 {
+		exp := s.Expected[0]
+		s.Expected = s.Expected[1:]
+		if !bytes.Equal(w, exp.W) {
+			s.c.Fatalf("unexpected write %#x, expected %#x", w, exp.W)
+		}
+		if len(r) != len(exp.R) {
+			s.c.Fatalf("unexpected read of %d bytes after %#x, expected %d", len(r), w, len(exp.R))
+		}
+		copy(r, exp.R)
+	} else {
+		n := copy(r, s.Responses)
+		s.Responses = s.Responses[n:]
+		for i := n; i < len(r); i++ {
+			r[i] = 0
+		}
+	}
+	s.Log = append(s.Log, Transaction{W: append([]byte(nil), w...), R: append([]byte(nil), r...)})
+	return nil
+}
+
+// Expect appends transactions to the script.
+func (s *Script) Expect(t ...Transaction) {
+	s.Expected = append(s.Expected, t...)
+}
+
+// AssertDone fails if some expected transactions did not happen.
+func (s *Script) AssertDone() {
+	if len(s.Expected) > 0 {
+		s.c.Fatalf("%d expected transactions did not happen, next: write %#x", len(s.Expected), s.Expected[0].W)
+	}
+}
+
+// Written returns all bytes written so far, concatenated.
+func (s *Script) Written() []byte {
+	var b []byte
+	for _, t := range s.Log {
+		b = append(b, t.W...)
+	}
+	return b
+}
+
+// I2CDeviceScript is a mock I2C device that checks the driver's transactions
+// against a script of expected writes and canned reads, or records them when
+// no script is given. Register reads and writes are treated as transactions
+// with the register address as the first byte written.
+type I2CDeviceScript struct {
+	Script
+	addr uint8
+}
+
+// NewI2CDeviceScript returns a new scripted mock I2C device.
+func NewI2CDeviceScript(c Failer, addr uint8) *I2CDeviceScript {
+	return &I2CDeviceScript{
+		Script: Script{c: c},
+		addr:   addr,
+	}
+}
+
+// Addr returns the Device address.
+func (d *I2CDeviceScript) Addr() uint8 {
+	return d.addr
+}
+
+// ReadRegister implements I2C.ReadRegister.
+func (d *I2CDeviceScript) ReadRegister(r uint8, buf []byte) error {
+	return d.tx([]byte{r}, buf)
+}
+
+// WriteRegister implements I2C.WriteRegister.
+func (d *I2CDeviceScript) WriteRegister(r uint8, buf []byte) error {
+	return d.tx(append([]byte{r}, buf...), nil)
+}
+
+// Tx implements I2C.Tx.
+func (d *I2CDeviceScript) Tx(w, r []byte) error {
+	return d.tx(w, r)
+}
@@ -0,0 +1,86 @@
+package tester
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestI2CDeviceScriptExpect(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDeviceScript(c, 0x29)
+	bus.AddDevice(d)
+
+	d.Expect(
+		Transaction{W: []byte{0x00, 0x16}, R: []byte{0x01}},
+		Transaction{W: []byte{0x20, 0xAB}},
+	)
+	buf := []byte{0}
+	c.Assert(bus.Tx(0x29, []byte{0x00, 0x16}, buf), qt.IsNil)
+	c.Assert(buf[0], qt.Equals, uint8(0x01))
+	c.Assert(bus.WriteRegister(0x29, 0x20, []byte{0xAB}), qt.IsNil)
+	d.AssertDone()
+	c.Assert(d.Log, qt.HasLen, 2)
+}
+
+func TestI2CDeviceScriptRecord(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := NewI2CDeviceScript(c, 0x29)
+	bus.AddDevice(d)
+
+	d.Responses = []byte{0x12, 0x34}
+	buf := []byte{0, 0, 0}
+	c.Assert(bus.ReadRegister(0x29, 0x05, buf), qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0x12, 0x34, 0x00})
+	c.Assert(d.Written(), qt.DeepEquals, []byte{0x05})
+
+	d.Err = errors.New("nack")
+	c.Assert(bus.ReadRegister(0x29, 0x05, buf), qt.ErrorMatches, "nack")
+}
+
+func TestTx8(t *testing.T) {
+	c := qt.New(t)
+	bus := NewI2CBus(c)
+	d := bus.NewDevice(8)
+
+	c.Assert(bus.Tx(8, []byte{3, 0x12, 0x34}, nil), qt.IsNil)
+	c.Assert(d.Registers[3:5], qt.DeepEquals, []byte{0x12, 0x34})
+
+	buf := []byte{0}
+	c.Assert(bus.Tx(8, []byte{4}, buf), qt.IsNil)
+	c.Assert(buf[0], qt.Equals, uint8(0x34))
+}
+
+func TestSPIBus(t *testing.T) {
+	c := qt.New(t)
+	bus := NewSPIBus(c)
+	bus.Expect(
+		Transaction{W: []byte{0x9F}, R: []byte{0x00}},
+		Transaction{W: []byte{0x00, 0x00}, R: []byte{0xEF, 0x40}},
+	)
+	r, err := bus.Transfer(0x9F)
+	c.Assert(err, qt.IsNil)
+	c.Assert(r, qt.Equals, uint8(0))
+	buf := make([]byte, 2)
+	c.Assert(bus.Tx(nil, buf), qt.IsNil)
+	c.Assert(buf, qt.DeepEquals, []byte{0xEF, 0x40})
+	bus.AssertDone()
+}
+
+func TestPin(t *testing.T) {
+	c := qt.New(t)
+	p := NewPin(false)
+	p.High()
+	p.Low()
+	p.Set(true)
+	c.Assert(p.History, qt.DeepEquals, []bool{true, false, true})
+	c.Assert(p.Pulses(), qt.Equals, 2)
+
+	p.Inputs = []bool{false, true}
+	c.Assert(p.Get(), qt.IsFalse)
+	c.Assert(p.Get(), qt.IsTrue)
+	c.Assert(p.Get(), qt.IsTrue)
+}
@@ -0,0 +1,34 @@
+package tester
+
+// SPIBus is a mock SPI bus that checks the driver's transfers against a
+// script of expected writes and canned reads, or records them when no script
+// is given. Each Tx and Transfer call is one transaction.
+type SPIBus struct {
+	Script
+}
+
+// NewSPIBus returns a new mock SPI bus that uses c to flag errors.
+func NewSPIBus(c Failer) *SPIBus {
+	return &SPIBus{
+		Script: Script{c: c},
+	}
+}
+
+// Tx implements SPI.Tx.
+func (s *SPIBus) Tx(w, r []byte) error {
+	if w != nil && r != nil && len(w) != len(r) {
+		s.c.Fatalf("spi transfer with different buffer lengths (%d, %d)", len(w), len(r))
+	}
+	if w == nil {
+		// receive only, while sending zeros
+		w = make([]byte, len(r))
+	}
+	return s.tx(w, r)
+}
+
+// Transfer implements SPI.Transfer.
+func (s *SPIBus) Transfer(b byte) (byte, error) {
+	var r [1]byte
+	err := s.tx([]byte{b}, r[:])
+	return r[0], err
+}
@@ -1,6 +1,10 @@
-// Package tester contains mock structs to make it easier to test I2C devices.
+// Package tester contains mock structs to make it easier to test drivers on the
+// host.
 //
-// TODO: info on how to use this.
+// I2CBus holds mock I2C devices: I2CDevice8 and I2CDevice16 model register
+// based devices, I2CDeviceCmd models command/response devices, and
+// I2CDeviceScript checks transactions against a script. SPIBus is a scripted
+// mock SPI bus and Pin a mock GPIO pin.
 package tester // import "tinygo.org/x/drivers/tester"
 
 // Failer is used by the I2CDevice type to abort when it's used in
@@ -0,0 +1,18 @@
+//go:build tinygo
+
+package tlc5947
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers"
+)
+
+// New returns a driver for chained TLC5947 devices (1 for a single device).
+// The SPI bus must already be configured in mode 0, the latch pin is
+// configured as output.
+func New(bus drivers.SPI, latch machine.Pin, chained int) *Device {
+	latch.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	latch.Low()
+	return newDevice(bus, latch, chained)
+}
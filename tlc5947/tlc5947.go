@@ -0,0 +1,116 @@
+// Package tlc5947 implements a driver for the TI TLC5947 24-channel, 12-bit
+// constant-current PWM LED driver. Several devices can be chained by
+// connecting DOUT of one to DIN of the next.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/tlc5947.pdf
+//
+// The grayscale data is shifted in over SPI (SCK and SDO only) and only
+// takes effect when the latch pin is pulsed, so all channels of the chain
+// change at the same time.
+package tlc5947 // import "tinygo.org/x/drivers/tlc5947"
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+const (
+	// Channels is the number of channels of one device.
+	Channels = 24
+
+	// MaxValue is the largest grayscale value of a channel.
+	MaxValue = 0xFFF
+
+	// bytesPerDevice is the size of the grayscale shift register.
+	bytesPerDevice = Channels * 12 / 8
+)
+
+// pin is the latch output.
+type pin interface {
+	High()
+	Low()
+}
+
+// Device is a chain of TLC5947 devices.
+type Device struct {
+	bus   drivers.SPI
+	latch pin
+	gs    []uint16 // grayscale values, channel 0 of the first device first
+	buf   []byte
+}
+
+func newDevice(bus drivers.SPI, latch pin, chained int) *Device {
+	if chained < 1 {
+		chained = 1
+	}
+	return &Device{
+		bus:   bus,
+		latch: latch,
+		gs:    make([]uint16, chained*Channels),
+		buf:   make([]byte, chained*bytesPerDevice),
+	}
+}
+
+// NumChannels returns the number of channels of the whole chain.
+func (d *Device) NumChannels() int {
+	return len(d.gs)
+}
+
+// Set sets the grayscale value (0 to MaxValue) of a channel. Channels of the
+// second device in the chain start at 24. The change takes effect at the next
+// Update.
+func (d *Device) Set(channel int, value uint16) {
+	if channel < 0 || channel >= len(d.gs) {
+		return
+	}
+	if value > MaxValue {
+		value = MaxValue
+	}
+	d.gs[channel] = value
+}
+
+// Get returns the grayscale value of a channel.
+func (d *Device) Get(channel int) uint16 {
+	if channel < 0 || channel >= len(d.gs) {
+		return 0
+	}
+	return d.gs[channel]
+}
+
+// SetAll sets all channels to the same grayscale value.
+func (d *Device) SetAll(value uint16) {
+	for i := range d.gs {
+		d.Set(i, value)
+	}
+}
+
+// SetRGB sets an RGB LED connected to channels 3*led (red), 3*led+1 (green)
+// and 3*led+2 (blue). The alpha value is ignored.
+func (d *Device) SetRGB(led int, c color.RGBA) {
+	d.Set(3*led, scale(c.R))
+	d.Set(3*led+1, scale(c.G))
+	d.Set(3*led+2, scale(c.B))
+}
+
+// scale converts an 8-bit color value to 12 bits.
+func scale(v uint8) uint16 {
+	return uint16(v)<<4 | uint16(v)>>4
+}
+
+// Update shifts the grayscale values out to the chain and latches them.
+func (d *Device) Update() error {
+	// The last channel of the last device is shifted out first, two 12-bit
+	// values are packed in three bytes.
+	b := d.buf[:0]
+	for i := len(d.gs) - 1; i > 0; i -= 2 {
+		hi, lo := d.gs[i], d.gs[i-1]
+		b = append(b, byte(hi>>4), byte(hi<<4)|byte(lo>>8), byte(lo))
+	}
+	if err := d.bus.Tx(b, nil); err != nil {
+		return err
+	}
+	d.latch.High()
+	d.latch.Low()
+	return nil
+}
@@ -0,0 +1,52 @@
+package tlc5947
+
+import (
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+// fakeLatch records the data written to bus before every rising edge.
+type fakeLatch struct {
+	bus     *tester.SPIBus
+	high    bool
+	latched [][]byte
+}
+
+func (l *fakeLatch) High() {
+	l.high = true
+	l.latched = append(l.latched, l.bus.Written())
+	l.bus.Log = nil
+}
+
+func (l *fakeLatch) Low() { l.high = false }
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	latch := &fakeLatch{bus: bus}
+	d := newDevice(bus, latch, 2)
+	c.Assert(d.NumChannels(), qt.Equals, 48)
+
+	d.Set(0, 0x123)
+	d.Set(1, 0x456)
+	d.Set(47, 0xFFFF) // clamped
+	d.SetRGB(8, color.RGBA{R: 0xFF, G: 0x80})
+	d.Set(48, 1) // out of range, ignored
+	c.Assert(d.Get(47), qt.Equals, uint16(MaxValue))
+	c.Assert(d.Get(25), qt.Equals, uint16(0x808))
+	c.Assert(d.Update(), qt.IsNil)
+
+	c.Assert(latch.latched, qt.HasLen, 1)
+	c.Assert(latch.high, qt.IsFalse)
+	out := latch.latched[0]
+	c.Assert(out, qt.HasLen, 2*36)
+	// channel 47 first, channel 0 last
+	c.Assert(out[:3], qt.DeepEquals, []byte{0xFF, 0xF0, 0x00})
+	c.Assert(out[len(out)-3:], qt.DeepEquals, []byte{0x45, 0x61, 0x23})
+	// channels 25 and 24 of the second device
+	c.Assert(out[33:36], qt.DeepEquals, []byte{0x80, 0x8F, 0xFF})
+}
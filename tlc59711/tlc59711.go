@@ -0,0 +1,138 @@
+// Package tlc59711 implements a driver for the TI TLC59711 12-channel, 16-bit
+// constant-current PWM LED driver with global brightness control. Several
+// devices can be chained by connecting SDTO and SCKO of one to SDTI and SCKI
+// of the next.
+//
+// Datasheet: https://www.ti.com/lit/ds/symlink/tlc59711.pdf
+//
+// The device has no latch pin: the data of the whole chain is latched when
+// the clock stays idle for 8 periods after the last bit, so all channels
+// change at the same time.
+package tlc59711 // import "tinygo.org/x/drivers/tlc59711"
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+const (
+	// Channels is the number of channels of one device.
+	Channels = 12
+
+	// MaxValue is the largest grayscale value of a channel.
+	MaxValue = 0xFFFF
+
+	// MaxBrightness is the largest global brightness correction value.
+	MaxBrightness = 0x7F
+
+	// bytesPerDevice is the size of the shift register: 32 bits of command
+	// and function data followed by the grayscale values.
+	bytesPerDevice = 4 + Channels*2
+)
+
+// Function control bits, written with every update.
+const (
+	cmdWrite = 0x25
+	fcOUTTMG = 1 << 4 // latch on the rising edge of the grayscale clock
+	fcEXTGCK = 1 << 3 // use SCKI as grayscale clock
+	fcTMGRST = 1 << 2 // reset the grayscale counter on latch
+	fcDSPRPT = 1 << 1 // repeat the PWM cycle
+	fcBLANK  = 1 << 0 // turn all outputs off
+)
+
+// Device is a chain of TLC59711 devices.
+type Device struct {
+	bus drivers.SPI
+	gs  []uint16 // grayscale values, channel 0 of the first device first
+
+	// Blank turns all outputs off at the next Update.
+	Blank bool
+
+	bcR, bcG, bcB uint8
+	buf           []byte
+}
+
+// New returns a driver for chained TLC59711 devices (1 for a single device).
+// The SPI bus must already be configured in mode 0, at 10MHz or less.
+func New(bus drivers.SPI, chained int) *Device {
+	if chained < 1 {
+		chained = 1
+	}
+	return &Device{
+		bus: bus,
+		gs:  make([]uint16, chained*Channels),
+		bcR: MaxBrightness,
+		bcG: MaxBrightness,
+		bcB: MaxBrightness,
+		buf: make([]byte, chained*bytesPerDevice),
+	}
+}
+
+// NumChannels returns the number of channels of the whole chain.
+func (d *Device) NumChannels() int {
+	return len(d.gs)
+}
+
+// SetBrightness sets the global brightness correction (0 to MaxBrightness)
+// of the red (R0, R1, R2, R3), green and blue output groups of all devices.
+// The change takes effect at the next Update.
+func (d *Device) SetBrightness(r, g, b uint8) {
+	d.bcR = r & MaxBrightness
+	d.bcG = g & MaxBrightness
+	d.bcB = b & MaxBrightness
+}
+
+// Set sets the grayscale value of a channel. Channels of the second device in
+// the chain start at 12. The change takes effect at the next Update.
+func (d *Device) Set(channel int, value uint16) {
+	if channel < 0 || channel >= len(d.gs) {
+		return
+	}
+	d.gs[channel] = value
+}
+
+// Get returns the grayscale value of a channel.
+func (d *Device) Get(channel int) uint16 {
+	if channel < 0 || channel >= len(d.gs) {
+		return 0
+	}
+	return d.gs[channel]
+}
+
+// SetAll sets all channels to the same grayscale value.
+func (d *Device) SetAll(value uint16) {
+	for i := range d.gs {
+		d.gs[i] = value
+	}
+}
+
+// SetRGB sets an RGB LED connected to channels 3*led (red), 3*led+1 (green)
+// and 3*led+2 (blue). The alpha value is ignored.
+func (d *Device) SetRGB(led int, c color.RGBA) {
+	d.Set(3*led, uint16(c.R)*0x101)
+	d.Set(3*led+1, uint16(c.G)*0x101)
+	d.Set(3*led+2, uint16(c.B)*0x101)
+}
+
+// Update shifts the command, brightness and grayscale values out to the
+// chain. They are latched by all devices when the clock stops.
+func (d *Device) Update() error {
+	fc := uint32(fcOUTTMG | fcTMGRST | fcDSPRPT)
+	if d.Blank {
+		fc |= fcBLANK
+	}
+	cmd := uint32(cmdWrite)<<26 | fc<<21 | uint32(d.bcB)<<14 | uint32(d.bcG)<<7 | uint32(d.bcR)
+
+	// The last device of the chain is shifted out first, each starting with
+	// its command word and channel 11.
+	b := d.buf[:0]
+	for dev := len(d.gs)/Channels - 1; dev >= 0; dev-- {
+		b = append(b, byte(cmd>>24), byte(cmd>>16), byte(cmd>>8), byte(cmd))
+		for ch := Channels - 1; ch >= 0; ch-- {
+			v := d.gs[dev*Channels+ch]
+			b = append(b, byte(v>>8), byte(v))
+		}
+	}
+	return d.bus.Tx(b, nil)
+}
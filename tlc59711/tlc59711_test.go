@@ -0,0 +1,40 @@
+package tlc59711
+
+import (
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestUpdate(t *testing.T) {
+	c := qt.New(t)
+	bus := tester.NewSPIBus(c)
+	d := New(bus, 2)
+	c.Assert(d.NumChannels(), qt.Equals, 24)
+
+	d.Set(0, 0x1234)
+	d.SetRGB(4, color.RGBA{R: 0xFF, B: 0x01})
+	d.SetBrightness(0x7F, 0x40, 0xFF)
+	c.Assert(d.Get(12), qt.Equals, uint16(0xFFFF))
+	c.Assert(d.Update(), qt.IsNil)
+
+	out := bus.Written()
+	c.Assert(out, qt.HasLen, 2*28)
+	// write command, OUTTMG, TMGRST, DSPRPT, BC blue 0x7F, green 0x40, red 0x7F
+	cmd := []byte{0x96, 0xDF, 0xE0, 0x7F}
+	c.Assert(out[:4], qt.DeepEquals, cmd)
+	c.Assert(out[28:32], qt.DeepEquals, cmd)
+	// second device first: channel 14 (blue of LED 4) first
+	c.Assert(out[4:6], qt.DeepEquals, []byte{0, 0})
+	c.Assert(out[22:28], qt.DeepEquals, []byte{0x01, 0x01, 0, 0, 0xFF, 0xFF})
+	// first device last: channel 0 last
+	c.Assert(out[54:], qt.DeepEquals, []byte{0x12, 0x34})
+
+	bus.Log = nil
+	d.Blank = true
+	c.Assert(d.Update(), qt.IsNil)
+	c.Assert(bus.Written()[1]&0x20, qt.Equals, byte(0x20))
+}
@@ -0,0 +1,63 @@
+package uartbuf
+
+import "sync/atomic"
+
+// Ring is a fixed-size byte queue with a single producer, usually an interrupt
+// handler calling Put, and a single consumer reading it from the main
+// program. It can be used as the Source of a Reader for serial inputs that
+// are not buffered by the machine package, like a software UART.
+type Ring struct {
+	buf     []byte
+	mask    uint32
+	head    uint32 // next byte to write, only changed by Put
+	tail    uint32 // next byte to read, only changed by Read
+	dropped uint32
+}
+
+// NewRing returns a ring buffer holding at least size bytes. The size is
+// rounded up to a power of two.
+func NewRing(size int) *Ring {
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	return &Ring{buf: make([]byte, n), mask: uint32(n - 1)}
+}
+
+// Put adds a byte to the buffer. It is safe to call from an interrupt
+// handler. If the buffer is full the byte is dropped and false is returned.
+func (r *Ring) Put(b byte) bool {
+	head := atomic.LoadUint32(&r.head)
+	if head-atomic.LoadUint32(&r.tail) > r.mask {
+		atomic.AddUint32(&r.dropped, 1)
+		return false
+	}
+	r.buf[head&r.mask] = b
+	atomic.StoreUint32(&r.head, head+1)
+	return true
+}
+
+// Buffered returns the number of bytes in the buffer.
+func (r *Ring) Buffered() int {
+	return int(atomic.LoadUint32(&r.head) - atomic.LoadUint32(&r.tail))
+}
+
+// Read reads up to len(p) buffered bytes. It does not wait for more data, so
+// it may return 0 bytes.
+func (r *Ring) Read(p []byte) (int, error) {
+	tail := atomic.LoadUint32(&r.tail)
+	n := int(atomic.LoadUint32(&r.head) - tail)
+	if n > len(p) {
+		n = len(p)
+	}
+	for i := 0; i < n; i++ {
+		p[i] = r.buf[(tail+uint32(i))&r.mask]
+	}
+	atomic.StoreUint32(&r.tail, tail+uint32(n))
+	return n, nil
+}
+
+// Dropped returns the number of bytes dropped because the buffer was full.
+func (r *Ring) Dropped() uint32 {
+	return atomic.LoadUint32(&r.dropped)
+}
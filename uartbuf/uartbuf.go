@@ -0,0 +1,253 @@
+// Package uartbuf buffers a serial input and splits it into lines, delimited
+// records or binary packets, with timeouts. It replaces the read loops that
+// UART drivers would otherwise each implement on top of Buffered and Read.
+//
+//	rx := uartbuf.NewReader(uart, 128)
+//	rx.Timeout = time.Second
+//	line, err := rx.ReadLine()
+package uartbuf // import "tinygo.org/x/drivers/uartbuf"
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	// ErrTimeout is returned when not enough data arrived within the
+	// timeout. The data received so far stays buffered for the next read.
+	ErrTimeout = errors.New("uartbuf: timeout")
+
+	// ErrBufferFull is returned by ReadUntil and ReadLine when the buffer
+	// fills up before the delimiter is found.
+	ErrBufferFull = errors.New("uartbuf: buffer full")
+
+	// ErrPacketTooLong is returned by ReadPacket when a packet does not
+	// fit in the buffer.
+	ErrPacketTooLong = errors.New("uartbuf: packet too long")
+)
+
+// Source is a serial input that buffers received data, like machine.UART,
+// drivers.UART or Ring. Read must not block.
+type Source interface {
+	io.Reader
+	Buffered() int
+}
+
+// Reader buffers the data of a Source. The slices returned by its methods
+// point into the buffer and are only valid until the next call.
+type Reader struct {
+	src  Source
+	buf  []byte
+	r, w int // buffered data is buf[r:w]
+
+	// Timeout is how long a call waits for data. Zero waits forever.
+	Timeout time.Duration
+
+	// Poll is how long to sleep while waiting for data. Defaults to 1ms.
+	Poll time.Duration
+}
+
+// NewReader returns a Reader with a buffer of the given size, which limits the
+// length of lines and packets.
+func NewReader(src Source, size int) *Reader {
+	return &Reader{
+		src:  src,
+		buf:  make([]byte, size),
+		Poll: time.Millisecond,
+	}
+}
+
+// Buffered returns the number of bytes that can be read without waiting.
+func (r *Reader) Buffered() int {
+	return r.w - r.r + r.src.Buffered()
+}
+
+// Read reads up to len(p) bytes that are already available, without waiting.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.r == r.w {
+		return r.src.Read(p)
+	}
+	n := copy(p, r.buf[r.r:r.w])
+	r.r += n
+	return n, nil
+}
+
+// Reset discards all buffered data, including the data buffered by the
+// source.
+func (r *Reader) Reset() {
+	r.r, r.w = 0, 0
+	for r.src.Buffered() > 0 {
+		if n, err := r.src.Read(r.buf); n == 0 || err != nil {
+			break
+		}
+	}
+}
+
+// ReadByte waits for a single byte.
+func (r *Reader) ReadByte() (byte, error) {
+	if err := r.need(1, time.Now()); err != nil {
+		return 0, err
+	}
+	b := r.buf[r.r]
+	r.r++
+	return b, nil
+}
+
+// ReadFull waits until len(p) bytes were read into p.
+func (r *Reader) ReadFull(p []byte) error {
+	start := time.Now()
+	for len(p) > 0 {
+		if r.r == r.w {
+			if err := r.fill(start); err != nil {
+				return err
+			}
+		}
+		n := copy(p, r.buf[r.r:r.w])
+		r.r += n
+		p = p[n:]
+	}
+	return nil
+}
+
+// ReadUntil reads until the first occurrence of delim and returns the data up
+// to and including it. If the buffer fills up first, the buffered data is
+// returned with ErrBufferFull.
+func (r *Reader) ReadUntil(delim byte) ([]byte, error) {
+	return r.readUntil(func(b []byte) int {
+		return bytes.IndexByte(b, delim)
+	})
+}
+
+// ReadUntilAny is like ReadUntil, but stops at the first of any of the bytes
+// in delims.
+func (r *Reader) ReadUntilAny(delims string) ([]byte, error) {
+	return r.readUntil(func(b []byte) int {
+		return bytes.IndexAny(b, delims)
+	})
+}
+
+// readUntil reads until index finds a delimiter in the data.
+func (r *Reader) readUntil(index func([]byte) int) ([]byte, error) {
+	start := time.Now()
+	scanned := 0
+	for {
+		if i := index(r.buf[r.r+scanned : r.w]); i >= 0 {
+			end := r.r + scanned + i + 1
+			data := r.buf[r.r:end]
+			r.r = end
+			return data, nil
+		}
+		scanned = r.w - r.r
+		if scanned == len(r.buf) {
+			data := r.buf[r.r:r.w]
+			r.r = r.w
+			return data, ErrBufferFull
+		}
+		if err := r.fill(start); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ReadLine reads a line terminated by "\n" or "\r\n" and returns it without
+// the line ending.
+func (r *Reader) ReadLine() ([]byte, error) {
+	line, err := r.ReadUntil('\n')
+	if err != nil {
+		return line, err
+	}
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+// Framing describes binary packets that start with a sync pattern and have a
+// header field holding the length of the rest of the packet.
+type Framing struct {
+	// Sync is the pattern at the start of each packet. Data before it is
+	// skipped.
+	Sync []byte
+
+	// LengthOffset is the offset of the length field from the start of
+	// the packet, and LengthSize its size in bytes (1 or 2).
+	LengthOffset int
+	LengthSize   int
+	LittleEndian bool
+
+	// Trailer is the number of bytes after the ones counted by the length
+	// field, usually a checksum.
+	Trailer int
+}
+
+// ReadPacket skips data until the sync pattern of f and returns the packet
+// starting with it. Checksums are not verified. A packet that does not fit in
+// the buffer is skipped and ErrPacketTooLong is returned.
+func (r *Reader) ReadPacket(f Framing) ([]byte, error) {
+	start := time.Now()
+	for {
+		if err := r.need(len(f.Sync), start); err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(r.buf[r.r:r.w], f.Sync) {
+			break
+		}
+		r.r++
+	}
+
+	header := f.LengthOffset + f.LengthSize
+	if err := r.need(header, start); err != nil {
+		return nil, err
+	}
+	length := 0
+	for i := 0; i < f.LengthSize; i++ {
+		shift := uint(i) * 8
+		if !f.LittleEndian {
+			shift = uint(f.LengthSize-1-i) * 8
+		}
+		length |= int(r.buf[r.r+f.LengthOffset+i]) << shift
+	}
+	size := header + length + f.Trailer
+	if size > len(r.buf) {
+		r.r += len(f.Sync) // resynchronize on the next packet
+		return nil, ErrPacketTooLong
+	}
+	if err := r.need(size, start); err != nil {
+		return nil, err
+	}
+	packet := r.buf[r.r : r.r+size]
+	r.r += size
+	return packet, nil
+}
+
+// need waits until at least n bytes are buffered.
+func (r *Reader) need(n int, start time.Time) error {
+	for r.w-r.r < n {
+		if err := r.fill(start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fill waits for data from the source and appends it to the buffer.
+func (r *Reader) fill(start time.Time) error {
+	if r.r == r.w {
+		r.r, r.w = 0, 0
+	} else if r.w == len(r.buf) {
+		r.w = copy(r.buf, r.buf[r.r:r.w])
+		r.r = 0
+	}
+	for r.src.Buffered() == 0 {
+		if r.Timeout != 0 && time.Since(start) >= r.Timeout {
+			return ErrTimeout
+		}
+		time.Sleep(r.Poll)
+	}
+	n, err := r.src.Read(r.buf[r.w:])
+	r.w += n
+	return err
+}
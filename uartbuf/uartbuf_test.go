@@ -0,0 +1,120 @@
+package uartbuf
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func newRing(data string) *Ring {
+	r := NewRing(256)
+	for i := 0; i < len(data); i++ {
+		r.Put(data[i])
+	}
+	return r
+}
+
+func TestRing(t *testing.T) {
+	c := qt.New(t)
+	r := NewRing(3)
+	c.Assert(r.buf, qt.HasLen, 4)
+	for i := byte(0); i < 6; i++ {
+		r.Put(i)
+	}
+	c.Assert(r.Buffered(), qt.Equals, 4)
+	c.Assert(r.Dropped(), qt.Equals, uint32(2))
+
+	p := make([]byte, 3)
+	n, err := r.Read(p)
+	c.Assert(err, qt.IsNil)
+	c.Assert(p[:n], qt.DeepEquals, []byte{0, 1, 2})
+	c.Assert(r.Put(9), qt.IsTrue)
+	n, _ = r.Read(p)
+	c.Assert(p[:n], qt.DeepEquals, []byte{3, 9})
+}
+
+func TestReadLine(t *testing.T) {
+	c := qt.New(t)
+	src := newRing("OK\r\n+CIFSR:STAIP,\"10.0.0.2\"\nrest")
+	r := NewReader(src, 16)
+	r.Timeout = 5 * time.Millisecond
+
+	line, err := r.ReadLine()
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(line), qt.Equals, "OK")
+
+	// longer than the buffer
+	line, err = r.ReadLine()
+	c.Assert(err, qt.Equals, ErrBufferFull)
+	c.Assert(string(line), qt.Equals, "+CIFSR:STAIP,\"10")
+	line, err = r.ReadLine()
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(line), qt.Equals, ".0.0.2\"")
+
+	// incomplete line times out and stays buffered
+	_, err = r.ReadLine()
+	c.Assert(err, qt.Equals, ErrTimeout)
+	src.Put('\n')
+	line, err = r.ReadLine()
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(line), qt.Equals, "rest")
+}
+
+func TestReadUntilAny(t *testing.T) {
+	c := qt.New(t)
+	r := NewReader(newRing("+IPD,5:hello\r\n"), 32)
+	data, err := r.ReadUntilAny("\n:")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Equals, "+IPD,5:")
+	data, err = r.ReadUntil('\n')
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Equals, "hello\r\n")
+}
+
+func TestReadFull(t *testing.T) {
+	c := qt.New(t)
+	src := newRing("abcdefghij")
+	r := NewReader(src, 4)
+	r.Timeout = 5 * time.Millisecond
+
+	b, err := r.ReadByte()
+	c.Assert(err, qt.IsNil)
+	c.Assert(b, qt.Equals, byte('a'))
+	p := make([]byte, 6)
+	c.Assert(r.ReadFull(p), qt.IsNil)
+	c.Assert(string(p), qt.Equals, "bcdefg")
+	c.Assert(r.Buffered(), qt.Equals, 3)
+	c.Assert(r.ReadFull(p), qt.Equals, ErrTimeout)
+}
+
+func TestReadPacket(t *testing.T) {
+	c := qt.New(t)
+	// UBX: sync, class, id, little endian length, payload, 2 byte checksum
+	ubx := Framing{Sync: []byte{0xB5, 0x62}, LengthOffset: 4, LengthSize: 2, LittleEndian: true, Trailer: 2}
+	src := newRing("$GPGLL*77\r\n\xB5\x62\x05\x01\x02\x00\x06\x08\x16\x3f\xB5")
+	r := NewReader(src, 32)
+	r.Timeout = 5 * time.Millisecond
+
+	p, err := r.ReadPacket(ubx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(p, qt.DeepEquals, []byte{0xB5, 0x62, 0x05, 0x01, 0x02, 0x00, 0x06, 0x08, 0x16, 0x3f})
+
+	_, err = r.ReadPacket(ubx)
+	c.Assert(err, qt.Equals, ErrTimeout)
+
+	// big endian length at offset 7, as used by fingerprint modules
+	fp := Framing{Sync: []byte{0xEF, 0x01}, LengthOffset: 7, LengthSize: 2}
+	src = newRing("\x00\xEF\x01\xFF\xFF\xFF\xFF\x07\x00\x03\x00\x00\x0a")
+	r = NewReader(src, 32)
+	p, err = r.ReadPacket(fp)
+	c.Assert(err, qt.IsNil)
+	c.Assert(p, qt.HasLen, 12)
+
+	// too long for the buffer
+	src = newRing("\xEF\x01\xFF\xFF\xFF\xFF\x07\x01\x00")
+	r = NewReader(src, 32)
+	r.Timeout = 5 * time.Millisecond
+	_, err = r.ReadPacket(fp)
+	c.Assert(err, qt.Equals, ErrPacketTooLong)
+}
@@ -0,0 +1,134 @@
+// Package units provides fixed-point integer types for physical quantities
+// returned by drivers. Each type carries its scale in its name, so values
+// don't need floating point on small microcontrollers and can't be mixed up
+// silently.
+//
+// The types are plain integers: they can be compared, added and scaled
+// directly, and converted with e.g. int32(t) where a raw value is needed.
+package units // import "tinygo.org/x/drivers/units"
+
+// MilliCelsius is a temperature in thousandths of a degree Celsius.
+type MilliCelsius int32
+
+// Celsius returns the temperature in degrees Celsius.
+func (t MilliCelsius) Celsius() float32 {
+	return float32(t) / 1000
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit.
+func (t MilliCelsius) Fahrenheit() float32 {
+	return float32(t)*9/5000 + 32
+}
+
+// String formats the temperature as e.g. "21.375 °C".
+func (t MilliCelsius) String() string {
+	return formatFixed(int64(t), 3, " °C")
+}
+
+// MilliPascal is a pressure in thousandths of a pascal.
+type MilliPascal int32
+
+// Hectopascal returns the pressure in hectopascals (millibars).
+func (p MilliPascal) Hectopascal() float32 {
+	return float32(p) / 100000
+}
+
+// String formats the pressure as e.g. "1013.250 hPa".
+func (p MilliPascal) String() string {
+	v := int64(p)
+	if v < 0 {
+		// the sign of pressures down to -99 mPa is lost by the division
+		return "-" + formatFixed(-v/100, 3, " hPa")
+	}
+	return formatFixed(v/100, 3, " hPa")
+}
+
+// MilliLux is an illuminance in thousandths of a lux.
+type MilliLux int32
+
+// Lux returns the illuminance in lux.
+func (l MilliLux) Lux() float32 {
+	return float32(l) / 1000
+}
+
+// String formats the illuminance as e.g. "320.500 lx".
+func (l MilliLux) String() string {
+	return formatFixed(int64(l), 3, " lx")
+}
+
+// MicroVolt is a voltage in millionths of a volt.
+type MicroVolt int32
+
+// Volts returns the voltage in volts.
+func (v MicroVolt) Volts() float32 {
+	return float32(v) / 1e6
+}
+
+// String formats the voltage as e.g. "3.300000 V".
+func (v MicroVolt) String() string {
+	return formatFixed(int64(v), 6, " V")
+}
+
+// MicroAmpere is a current in millionths of an ampere.
+type MicroAmpere int32
+
+// Amperes returns the current in amperes.
+func (a MicroAmpere) Amperes() float32 {
+	return float32(a) / 1e6
+}
+
+// String formats the current as e.g. "0.125000 A".
+func (a MicroAmpere) String() string {
+	return formatFixed(int64(a), 6, " A")
+}
+
+// MicroWatt is a power in millionths of a watt.
+type MicroWatt int32
+
+// Watts returns the power in watts.
+func (w MicroWatt) Watts() float32 {
+	return float32(w) / 1e6
+}
+
+// String formats the power as e.g. "1.500000 W".
+func (w MicroWatt) String() string {
+	return formatFixed(int64(w), 6, " W")
+}
+
+// Millimeter is a distance in millimeters.
+type Millimeter int32
+
+// Meters returns the distance in meters.
+func (d Millimeter) Meters() float32 {
+	return float32(d) / 1000
+}
+
+// String formats the distance as e.g. "1.250 m".
+func (d Millimeter) String() string {
+	return formatFixed(int64(d), 3, " m")
+}
+
+// formatFixed formats v, which has the given number of decimals, followed by
+// unit. It avoids fmt and strconv float formatting to keep binaries small.
+func formatFixed(v int64, decimals int, unit string) string {
+	var buf [24]byte
+	i := len(buf)
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+	for n := 0; n <= decimals || v != 0; n++ {
+		if n == decimals && decimals > 0 {
+			i--
+			buf[i] = '.'
+		}
+		i--
+		buf[i] = byte('0' + v%10)
+		v /= 10
+	}
+	if neg {
+		i--
+		buf[i] = '-'
+	}
+	return string(buf[i:]) + unit
+}
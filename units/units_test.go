@@ -0,0 +1,31 @@
+package units
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestString(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(MilliCelsius(21375).String(), qt.Equals, "21.375 °C")
+	c.Assert(MilliCelsius(-500).String(), qt.Equals, "-0.500 °C")
+	c.Assert(MilliCelsius(0).String(), qt.Equals, "0.000 °C")
+	c.Assert(MilliPascal(101325000).String(), qt.Equals, "1013.250 hPa")
+	c.Assert(MilliPascal(-50).String(), qt.Equals, "-0.000 hPa")
+	c.Assert(MilliPascal(-123456).String(), qt.Equals, "-1.234 hPa")
+	c.Assert(MilliLux(320500).String(), qt.Equals, "320.500 lx")
+	c.Assert(MicroVolt(3300000).String(), qt.Equals, "3.300000 V")
+	c.Assert(MicroAmpere(-125000).String(), qt.Equals, "-0.125000 A")
+	c.Assert(MicroWatt(1500000).String(), qt.Equals, "1.500000 W")
+	c.Assert(Millimeter(1250).String(), qt.Equals, "1.250 m")
+	c.Assert(Millimeter(-2147483648).String(), qt.Equals, "-2147483.648 m")
+}
+
+func TestConversions(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(MilliCelsius(25000).Celsius(), qt.Equals, float32(25))
+	c.Assert(MilliCelsius(100000).Fahrenheit(), qt.Equals, float32(212))
+	c.Assert(MilliPascal(101325000).Hectopascal(), qt.Equals, float32(1013.25))
+	c.Assert(MicroVolt(11980000).Volts(), qt.Equals, float32(11.98))
+}
@@ -0,0 +1,81 @@
+package vl6180x
+
+// InterruptMode selects when the range or ALS interrupt is raised on GPIO1 and
+// reported by InterruptStatus.
+type InterruptMode uint8
+
+const (
+	InterruptDisabled    InterruptMode = 0
+	InterruptLevelLow    InterruptMode = 1 // value below the low threshold
+	InterruptLevelHigh   InterruptMode = 2 // value above the high threshold
+	InterruptOutOfWindow InterruptMode = 3 // value below low or above high threshold
+	InterruptNewSample   InterruptMode = 4 // new sample ready, the default
+)
+
+// SetInterruptMode configures the range and ALS interrupt sources. GPIO1 is
+// configured as an active-low interrupt output by Configure.
+//
+// Threshold interrupts are meant for continuous mode, see
+// StartRangeContinuous and StartALSContinuous. Read and ReadLux wait for a new
+// sample and therefore need InterruptNewSample for their measurement.
+func (d *Device) SetInterruptMode(rangeMode, alsMode InterruptMode) {
+	d.writeReg(SYSTEM_INTERRUPT_CONFIG, uint8(alsMode&0x7)<<3|uint8(rangeMode&0x7))
+}
+
+// SetRangeThresholds sets the low and high range thresholds in mm.
+func (d *Device) SetRangeThresholds(low, high uint8) {
+	d.writeReg(SYSRANGE_THRESH_LOW, low)
+	d.writeReg(SYSRANGE_THRESH_HIGH, high)
+}
+
+// SetALSThresholds sets the low and high ALS thresholds, in raw counts as
+// reported by the sensor before the gain and lux scaling done by ReadLux.
+func (d *Device) SetALSThresholds(low, high uint16) {
+	d.writeReg16Bit(SYSALS_THRESH_LOW, low)
+	d.writeReg16Bit(SYSALS_THRESH_HIGH, high)
+}
+
+// InterruptStatus returns which range and ALS interrupt conditions are
+// pending, using the same values as InterruptMode. InterruptDisabled means
+// that no interrupt is pending.
+func (d *Device) InterruptStatus() (rangeStatus, alsStatus InterruptMode) {
+	status := d.readReg(RESULT_INTERRUPT_STATUS_GPIO)
+	return InterruptMode(status & 0x7), InterruptMode((status >> 3) & 0x7)
+}
+
+// ClearInterrupts clears all pending interrupts and releases GPIO1.
+func (d *Device) ClearInterrupts() {
+	d.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x07)
+}
+
+// ReadRange returns the last range result in mm, without starting a new
+// measurement. Use it in continuous mode once an interrupt is pending.
+func (d *Device) ReadRange() uint8 {
+	return d.readReg(RESULT_RANGE_VAL)
+}
+
+// ReadALS returns the last raw ALS result, without starting a new
+// measurement.
+func (d *Device) ReadALS() uint16 {
+	return d.readReg16Bit(RESULT_ALS_VAL)
+}
+
+// StartALSContinuous starts continuous ALS measurements. The period is
+// rounded to 10ms steps and must be longer than the integration time.
+func (d *Device) StartALSContinuous(periodInMs uint16) {
+	var periodReg uint8
+	if periodInMs > 10 {
+		if periodInMs < 2550 {
+			periodReg = uint8(periodInMs/10) - 1
+		} else {
+			periodReg = 254
+		}
+	}
+	d.writeReg(ALS_INTERMEASUREMENT_PERIOD, periodReg)
+	d.writeReg(SYSALS_START, 0x03)
+}
+
+// StopALSContinuous stops continuous ALS measurements.
+func (d *Device) StopALSContinuous() {
+	d.writeReg(SYSALS_START, 0x01)
+}
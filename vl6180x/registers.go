@@ -7,12 +7,17 @@ const Address = 0x29
 const (
 	CHIP_ID                            = 0xB4
 	WHO_AM_I                           = 0x0000
+	SYSTEM_MODE_GPIO1                  = 0x0011
 	SYSTEM_INTERRUPT_CONFIG            = 0x0014
 	SYSTEM_INTERRUPT_CLEAR             = 0x0015
 	SYSTEM_FRESH_OUT_OF_RESET          = 0x0016
 	SYSRANGE_START                     = 0x0018
+	SYSRANGE_THRESH_HIGH               = 0x0019
+	SYSRANGE_THRESH_LOW                = 0x001A
 	SYSRANGE_PART_TO_PART_RANGE_OFFSET = 0x0024
 	SYSALS_START                       = 0x0038
+	SYSALS_THRESH_HIGH                 = 0x003A
+	SYSALS_THRESH_LOW                  = 0x003C
 	SYSALS_ANALOGUE_GAIN               = 0x003F
 	SYSALS_INTEGRATION_PERIOD_HI       = 0x0040
 	SYSALS_INTEGRATION_PERIOD_LO       = 0x0041
@@ -81,12 +81,12 @@ func (d *Device) Configure(use2v8Mode bool) bool {
 		d.writeReg(0x0030, 0x00)
 
 		// recommended settings
-		d.writeReg(0x0011, 0x10) // enables polling when measurement completes
-		d.writeReg(0x010a, 0x30) // sets averaging sample period
-		d.writeReg(0x003f, 0x46) // sets light and dark gain
-		d.writeReg(0x0031, 0xFF) // sets the # of range measurements for auto calibration
-		d.writeReg(0x0041, 0x63) // sets ALS integration time to 100ms
-		d.writeReg(0x002e, 0x01) // performs a single temperature calibration
+		d.writeReg(SYSTEM_MODE_GPIO1, 0x10) // GPIO1 is an active-low interrupt output
+		d.writeReg(0x010a, 0x30)            // sets averaging sample period
+		d.writeReg(0x003f, 0x46)            // sets light and dark gain
+		d.writeReg(0x0031, 0xFF)            // sets the # of range measurements for auto calibration
+		d.writeReg(0x0041, 0x63)            // sets ALS integration time to 100ms
+		d.writeReg(0x002e, 0x01)            // performs a single temperature calibration
 
 		// optional settings
 		d.writeReg(RANGING_INTERMEASUREMENT_PERIOD, 0x09) // sets ranging inter-measurement period to 100ms
@@ -178,7 +178,7 @@ func (d *Device) ReadLux(gain uint8) (lux uint32) {
 	d.writeReg(SYSTEM_INTERRUPT_CONFIG, reg)
 
 	d.writeReg(SYSALS_INTEGRATION_PERIOD_HI, 0)
-	d.writeReg(SYSALS_INTEGRATION_PERIOD_HI, 100)
+	d.writeReg(SYSALS_INTEGRATION_PERIOD_LO, 100)
 
 	if gain > ALS_GAIN_40 {
 		gain = ALS_GAIN_40
@@ -253,6 +253,13 @@ func (d *Device) readReg(reg uint16) uint8 {
 	return data[0]
 }
 
+// writeReg16Bit sends two bytes to the specified register address
+func (d *Device) writeReg16Bit(reg uint16, value uint16) {
+	msb := byte((reg >> 8) & 0xFF)
+	lsb := byte(reg & 0xFF)
+	d.bus.Tx(d.Address, []byte{msb, lsb, byte(value >> 8), byte(value)}, nil)
+}
+
 // readReg16Bit reads two bytes from the specified address
 // and returns it as a uint16
 func (d *Device) readReg16Bit(reg uint16) uint16 {